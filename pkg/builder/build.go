@@ -47,6 +47,8 @@ type Builder struct {
 	watchRequest   []watchRequest
 	config         *rest.Config
 	ctrl           controller.Controller
+	name           string
+	ctrlOptions    controller.Options
 }
 
 // SimpleController returns a new Builder.
@@ -89,12 +91,30 @@ func (blder *Builder) Owns(apiType runtime.Object) *Builder {
 type watchRequest struct {
 	src          source.Source
 	eventhandler handler.EventHandler
+	predicates   []predicate.Predicate
 }
 
 // Watches exposes the lower-level ControllerManagedBy Watches functions through the builder.  Consider using
-// Owns or For instead of Watches directly.
-func (blder *Builder) Watches(src source.Source, eventhandler handler.EventHandler) *Builder {
-	blder.watchRequest = append(blder.watchRequest, watchRequest{src: src, eventhandler: eventhandler})
+// Owns or For instead of Watches directly.  The events of src are filtered by predicates in addition to the
+// event filters of the builder.
+func (blder *Builder) Watches(src source.Source, eventhandler handler.EventHandler,
+	predicates ...predicate.Predicate) *Builder {
+	blder.watchRequest = append(blder.watchRequest,
+		watchRequest{src: src, eventhandler: eventhandler, predicates: predicates})
+	return blder
+}
+
+// Named sets the name of the Controller, which is used in its logs and metrics.  Defaults to the lowercased
+// kind of the For type suffixed with -application.
+func (blder *Builder) Named(name string) *Builder {
+	blder.name = name
+	return blder
+}
+
+// WithOptions sets the options of the Controller, e.g. its MaxConcurrentReconciles.  The Reconciler of the
+// options is ignored and set to the reconciler passed to Complete.
+func (blder *Builder) WithOptions(options controller.Options) *Builder {
+	blder.ctrlOptions = options
 	return blder
 }
 
@@ -170,7 +190,8 @@ func (blder *Builder) Build(r reconcile.Reconciler) (manager.Manager, error) {
 
 	// Do the watch requests
 	for _, w := range blder.watchRequest {
-		if err := blder.ctrl.Watch(w.src, w.eventhandler, blder.predicates...); err != nil {
+		predicates := append(append([]predicate.Predicate(nil), blder.predicates...), w.predicates...)
+		if err := blder.ctrl.Watch(w.src, w.eventhandler, predicates...); err != nil {
 			return nil, err
 		}
 
@@ -198,6 +219,9 @@ func (blder *Builder) doManager() error {
 }
 
 func (blder *Builder) getControllerName() (string, error) {
+	if blder.name != "" {
+		return blder.name, nil
+	}
 	gvk, err := getGvk(blder.apiType, blder.mgr.GetScheme())
 	if err != nil {
 		return "", err
@@ -211,6 +235,8 @@ func (blder *Builder) doController(r reconcile.Reconciler) error {
 	if err != nil {
 		return err
 	}
-	blder.ctrl, err = newController(name, blder.mgr, controller.Options{Reconciler: r})
+	options := blder.ctrlOptions
+	options.Reconciler = r
+	blder.ctrl, err = newController(name, blder.mgr, options)
 	return err
 }
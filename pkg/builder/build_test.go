@@ -123,6 +123,29 @@ var _ = Describe("application", func() {
 		})
 	})
 
+	Describe("Named and WithOptions", func() {
+		It("should create the controller with the name and options", func() {
+			var name string
+			var options controller.Options
+			newController = func(n string, mgr manager.Manager, o controller.Options) (controller.Controller, error) {
+				name, options = n, o
+				return controller.New(n, mgr, o)
+			}
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = ControllerManagedBy(m).
+				For(&appsv1.ReplicaSet{}).
+				Named("replicasets").
+				WithOptions(controller.Options{MaxConcurrentReconciles: 3}).
+				Complete(noop)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(name).To(Equal("replicasets"))
+			Expect(options.MaxConcurrentReconciles).To(Equal(3))
+			Expect(options.Reconciler).NotTo(BeNil())
+		})
+	})
+
 	Describe("Start with SimpleController", func() {
 		It("should Reconcile Owns objects", func(done Done) {
 			bldr := SimpleController().
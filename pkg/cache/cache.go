@@ -17,10 +17,13 @@ limitations under the License.
 package cache
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -54,6 +57,13 @@ type Informers interface {
 	// of the underlying object.
 	GetInformerForKind(gvk schema.GroupVersionKind) (toolscache.SharedIndexInformer, error)
 
+	// RemoveInformer stops the informer for the given object and removes it from the cache, e.g.
+	// once the CustomResourceDefinition of its kind is deleted, so that it stops watching the
+	// API server and its objects are freed.  Event handlers of the removed informer stop
+	// receiving events, and the next read of the kind creates a new informer.  It is a no-op if
+	// there is no informer for the object.
+	RemoveInformer(ctx context.Context, obj runtime.Object) error
+
 	// Start runs all the informers known to this cache until the given channel is closed.
 	// It blocks.
 	Start(stopCh <-chan struct{}) error
@@ -69,6 +79,15 @@ type Informers interface {
 	IndexField(obj runtime.Object, field string, extractValue client.IndexerFunc) error
 }
 
+// DeferredResolver is implemented by the caches which defer the informers of kinds not served by the
+// API server yet, as allowed by Options.DeferUnknownKinds.
+type DeferredResolver interface {
+	// ResolveDeferred discovers the kinds of the deferred informers again, so that the informers of
+	// the kinds now served start without waiting for the next periodic attempt.  It is called by
+	// crd.Installer once its CustomResourceDefinitions are Established.
+	ResolveDeferred()
+}
+
 // Options are the optional arguments for creating a new InformersMap object
 type Options struct {
 	// Scheme is the scheme to use for mapping objects to GroupVersionKinds
@@ -83,6 +102,41 @@ type Options struct {
 	// Namespace restricts the cache's ListWatch to the desired namespace
 	// Default watches all namespaces
 	Namespace string
+
+	// DeferUnknownKinds, if true, defers starting the informers of kinds that are not served by
+	// the API server, such as kinds whose CustomResourceDefinitions are installed by the operator
+	// at startup, instead of failing to create them.  Deferred informers do not block
+	// WaitForCacheSync, and reads of their kind fail with a KindNotServedError until the kind is
+	// discovered and their informer has synced.  The kind is discovered again periodically, or
+	// when ResolveDeferred is called.  Discovering new kinds requires a Mapper implementing apiutil.ResettableRESTMapper, such
+	// as the default Mapper.
+	DeferUnknownKinds bool
+
+	// ByObject restricts the objects of the type of each key that are cached to those matching
+	// the selectors of its ByObject, e.g. to only cache the Pods owned by the operator in large
+	// clusters.  Objects which don't match the selectors are not found when read from the cache,
+	// and no events are received for them.
+	ByObject map[runtime.Object]ByObject
+
+	// DefaultTransform transforms the objects of the types without a ByObject Transform before
+	// they are cached.  Defaults to caching the objects as they are received.
+	DefaultTransform TransformFunc
+}
+
+// ByObject restricts the objects of a type which are cached.
+type ByObject struct {
+	// Label restricts the cached objects to those matching the label selector.  Defaults to
+	// caching objects regardless of their labels.
+	Label labels.Selector
+
+	// Field restricts the cached objects to those matching the field selector.  Only the fields
+	// supported by the API server for the type may be selected.  Defaults to caching objects
+	// regardless of their fields.
+	Field fields.Selector
+
+	// Transform transforms the objects before they are cached, instead of the DefaultTransform
+	// of the Options.
+	Transform TransformFunc
 }
 
 var defaultResyncTime = 10 * time.Hour
@@ -93,10 +147,63 @@ func New(config *rest.Config, opts Options) (Cache, error) {
 	if err != nil {
 		return nil, err
 	}
-	im := internal.NewInformersMap(config, opts.Scheme, opts.Mapper, *opts.Resync, opts.Namespace)
+	selectors, transforms, err := byGVK(opts)
+	if err != nil {
+		return nil, err
+	}
+	im := internal.NewInformersMap(config, opts.Scheme, opts.Mapper, *opts.Resync, opts.Namespace, opts.DeferUnknownKinds, selectors, transforms)
 	return &informerCache{InformersMap: im}, nil
 }
 
+// BuilderWithOptions returns a function creating a Cache with options, which can be used as the
+// NewCache of the Options of a Manager to configure the cache of the Manager.  The Scheme, Mapper,
+// Resync and Namespace set by the Manager are used unless they are also set in options.
+func BuilderWithOptions(options Options) func(config *rest.Config, opts Options) (Cache, error) {
+	return func(config *rest.Config, opts Options) (Cache, error) {
+		o := options
+		if o.Scheme == nil {
+			o.Scheme = opts.Scheme
+		}
+		if o.Mapper == nil {
+			o.Mapper = opts.Mapper
+		}
+		if o.Resync == nil {
+			o.Resync = opts.Resync
+		}
+		if o.Namespace == "" {
+			o.Namespace = opts.Namespace
+		}
+		o.DeferUnknownKinds = o.DeferUnknownKinds || opts.DeferUnknownKinds
+		if o.ByObject == nil {
+			o.ByObject = opts.ByObject
+		}
+		if o.DefaultTransform == nil {
+			o.DefaultTransform = opts.DefaultTransform
+		}
+		return New(config, o)
+	}
+}
+
+// byGVK maps the types of the ByObject of opts to their kinds.
+func byGVK(opts Options) (internal.SelectorsByGVK, internal.TransformFuncsByGVK, error) {
+	selectors := internal.SelectorsByGVK{}
+	transforms := internal.TransformFuncsByGVK{
+		Default: internal.TransformFunc(opts.DefaultTransform),
+		ByGVK:   map[schema.GroupVersionKind]internal.TransformFunc{},
+	}
+	for obj, by := range opts.ByObject {
+		gvk, err := apiutil.GVKForObject(obj, opts.Scheme)
+		if err != nil {
+			return nil, transforms, fmt.Errorf("unable to get the kind of ByObject type %T: %v", obj, err)
+		}
+		selectors[gvk] = internal.Selector{Label: by.Label, Field: by.Field}
+		if by.Transform != nil {
+			transforms.ByGVK[gvk] = internal.TransformFunc(by.Transform)
+		}
+	}
+	return selectors, transforms, nil
+}
+
 func defaultOpts(config *rest.Config, opts Options) (Options, error) {
 	// Use the default Kubernetes Scheme if unset
 	if opts.Scheme == nil {
@@ -19,13 +19,19 @@ package cache_test
 import (
 	"context"
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	kcorev1 "k8s.io/api/core/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	kscheme "k8s.io/client-go/kubernetes/scheme"
@@ -33,6 +39,8 @@ import (
 
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/crd"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
 )
 
 const testNamespaceOne = "test-namespace-1"
@@ -121,10 +129,25 @@ var _ = Describe("Informer Cache", func() {
 	Describe("as a Reader", func() {
 		Context("with structured objects", func() {
 
+			It("should return all the objects when listing with a limit", func() {
+				By("listing pods with a limit of one")
+				out := kcorev1.PodList{}
+				Expect(informerCache.List(context.Background(), &out, client.Limit(1))).To(Succeed())
+
+				By("verifying that all the pods are returned without a continue token")
+				Expect(len(out.Items)).To(BeNumerically(">=", 3))
+				Expect(out.Continue).To(BeEmpty())
+			})
+
+			It("should return an error when listing with a continue token", func() {
+				out := kcorev1.PodList{}
+				Expect(informerCache.List(context.Background(), &out, client.Continue("token"))).NotTo(Succeed())
+			})
+
 			It("should be able to list objects that haven't been watched previously", func() {
 				By("listing all services in the cluster")
 				listObj := &kcorev1.ServiceList{}
-				Expect(informerCache.List(context.Background(), nil, listObj)).To(Succeed())
+				Expect(informerCache.List(context.Background(), listObj)).To(Succeed())
 
 				By("verifying that the returned list contains the Kubernetes service")
 				// NB: kubernetes default service is automatically created in testenv.
@@ -157,7 +180,7 @@ var _ = Describe("Informer Cache", func() {
 				lo := &client.ListOptions{}
 				lo.InNamespace(testNamespaceTwo)
 				lo.MatchingLabels(map[string]string{"test-label": "test-pod-2"})
-				Expect(informerCache.List(context.Background(), lo, &out)).To(Succeed())
+				Expect(informerCache.List(context.Background(), &out, client.UseListOptions(lo))).To(Succeed())
 
 				By("verifying the returned pods have the correct label")
 				Expect(out.Items).NotTo(BeEmpty())
@@ -176,7 +199,7 @@ var _ = Describe("Informer Cache", func() {
 				labels := map[string]string{"test-label": "test-pod-2"}
 				lo := &client.ListOptions{}
 				lo.MatchingLabels(labels)
-				Expect(informerCache.List(context.Background(), lo, &out)).To(Succeed())
+				Expect(informerCache.List(context.Background(), &out, client.UseListOptions(lo))).To(Succeed())
 
 				By("verifying multiple pods with the same label in different namespaces are returned")
 				Expect(out.Items).NotTo(BeEmpty())
@@ -193,7 +216,7 @@ var _ = Describe("Informer Cache", func() {
 				listObj := &kcorev1.PodList{}
 				lo := &client.ListOptions{}
 				lo.InNamespace(testNamespaceOne)
-				Expect(informerCache.List(context.Background(), lo, listObj)).To(Succeed())
+				Expect(informerCache.List(context.Background(), listObj, client.UseListOptions(lo))).To(Succeed())
 
 				By("verifying that the returned pods are in test-namespace-1")
 				Expect(listObj.Items).NotTo(BeEmpty())
@@ -202,6 +225,67 @@ var _ = Describe("Informer Cache", func() {
 				Expect(actual.Namespace).To(Equal(testNamespaceOne))
 			})
 
+			It("should only cache the objects matching the selectors of their type", func() {
+				By("creating a cache restricting the Pods by label")
+				selectedCache, err := cache.New(cfg, cache.Options{
+					ByObject: map[runtime.Object]cache.ByObject{
+						&kcorev1.Pod{}: {Label: labels.SelectorFromSet(labels.Set{"test-label": "test-pod-2"})},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("running the cache and waiting for it to sync")
+				go func() {
+					defer GinkgoRecover()
+					Expect(selectedCache.Start(stop)).To(Succeed())
+				}()
+				Expect(selectedCache.WaitForCacheSync(stop)).To(BeTrue())
+
+				By("listing pods in all namespaces")
+				out := &kcorev1.PodList{}
+				Expect(selectedCache.List(context.Background(), out)).To(Succeed())
+				Expect(out.Items).Should(HaveLen(1))
+				Expect(out.Items[0].Name).To(Equal("test-pod-2"))
+
+				By("not finding the pods which don't match the selector")
+				key := client.ObjectKey{Namespace: testNamespaceOne, Name: "test-pod-1"}
+				err = selectedCache.Get(context.Background(), key, &kcorev1.Pod{})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			})
+
+			It("should cache the transformed objects", func() {
+				By("creating a cache annotating the objects")
+				annotate := func(value string) cache.TransformFunc {
+					return func(obj runtime.Object) (runtime.Object, error) {
+						pod := obj.(*kcorev1.Pod)
+						pod.Annotations = map[string]string{"transformed": value}
+						return pod, nil
+					}
+				}
+				transformedCache, err := cache.New(cfg, cache.Options{
+					DefaultTransform: func(obj runtime.Object) (runtime.Object, error) {
+						return nil, fmt.Errorf("the ByObject transform should be used")
+					},
+					ByObject: map[runtime.Object]cache.ByObject{
+						&kcorev1.Pod{}: {Transform: annotate("by-object")},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("running the cache and waiting for it to sync")
+				go func() {
+					defer GinkgoRecover()
+					Expect(transformedCache.Start(stop)).To(Succeed())
+				}()
+				Expect(transformedCache.WaitForCacheSync(stop)).To(BeTrue())
+
+				By("reading the transformed pods")
+				out := &kcorev1.Pod{}
+				key := client.ObjectKey{Namespace: testNamespaceOne, Name: "test-pod-1"}
+				Expect(transformedCache.Get(context.Background(), key, out)).To(Succeed())
+				Expect(out.Annotations).To(Equal(map[string]string{"transformed": "by-object"}))
+			})
+
 			It("should be able to restrict cache to a namespace", func() {
 				By("creating a namespaced cache")
 				namespacedCache, err := cache.New(cfg, cache.Options{Namespace: testNamespaceOne})
@@ -216,7 +300,7 @@ var _ = Describe("Informer Cache", func() {
 
 				By("listing pods in all namespaces")
 				out := &kcorev1.PodList{}
-				Expect(namespacedCache.List(context.Background(), nil, out)).To(Succeed())
+				Expect(namespacedCache.List(context.Background(), out)).To(Succeed())
 
 				By("verifying the returned pod is from the watched namespace")
 				Expect(out.Items).NotTo(BeEmpty())
@@ -225,7 +309,7 @@ var _ = Describe("Informer Cache", func() {
 
 				By("listing all namespaces - should still be able to get a cluster-scoped resource")
 				namespaceList := &kcorev1.NamespaceList{}
-				Expect(namespacedCache.List(context.Background(), nil, namespaceList)).To(Succeed())
+				Expect(namespacedCache.List(context.Background(), namespaceList)).To(Succeed())
 
 				By("verifying the namespace list is not empty")
 				Expect(namespaceList.Items).NotTo(BeEmpty())
@@ -267,7 +351,7 @@ var _ = Describe("Informer Cache", func() {
 					Version: "v1",
 					Kind:    "ServiceList",
 				})
-				err := informerCache.List(context.Background(), nil, listObj)
+				err := informerCache.List(context.Background(), listObj)
 				Expect(err).To(Succeed())
 
 				By("verifying that the returned list contains the Kubernetes service")
@@ -311,7 +395,7 @@ var _ = Describe("Informer Cache", func() {
 				lo := &client.ListOptions{}
 				lo.InNamespace(testNamespaceTwo)
 				lo.MatchingLabels(map[string]string{"test-label": "test-pod-2"})
-				err := informerCache.List(context.Background(), lo, &out)
+				err := informerCache.List(context.Background(), &out, client.UseListOptions(lo))
 				Expect(err).To(Succeed())
 
 				By("verifying the returned pods have the correct label")
@@ -336,7 +420,7 @@ var _ = Describe("Informer Cache", func() {
 				labels := map[string]string{"test-label": "test-pod-2"}
 				lo := &client.ListOptions{}
 				lo.MatchingLabels(labels)
-				err := informerCache.List(context.Background(), lo, &out)
+				err := informerCache.List(context.Background(), &out, client.UseListOptions(lo))
 				Expect(err).To(Succeed())
 
 				By("verifying multiple pods with the same label in different namespaces are returned")
@@ -359,7 +443,7 @@ var _ = Describe("Informer Cache", func() {
 				})
 				lo := &client.ListOptions{}
 				lo.InNamespace(testNamespaceOne)
-				err := informerCache.List(context.Background(), lo, listObj)
+				err := informerCache.List(context.Background(), listObj, client.UseListOptions(lo))
 				Expect(err).To(Succeed())
 
 				By("verifying that the returned pods are in test-namespace-1")
@@ -388,7 +472,7 @@ var _ = Describe("Informer Cache", func() {
 					Version: "v1",
 					Kind:    "PodList",
 				})
-				Expect(namespacedCache.List(context.Background(), nil, out)).To(Succeed())
+				Expect(namespacedCache.List(context.Background(), out)).To(Succeed())
 
 				By("verifying the returned pod is from the watched namespace")
 				Expect(out.Items).NotTo(BeEmpty())
@@ -402,7 +486,7 @@ var _ = Describe("Informer Cache", func() {
 					Version: "v1",
 					Kind:    "NamespaceList",
 				})
-				Expect(namespacedCache.List(context.Background(), nil, namespaceList)).To(Succeed())
+				Expect(namespacedCache.List(context.Background(), namespaceList)).To(Succeed())
 
 				By("verifying the namespace list is not empty")
 				Expect(namespaceList.Items).NotTo(BeEmpty())
@@ -491,7 +575,6 @@ var _ = Describe("Informer Cache", func() {
 				close(done)
 			})
 
-			// TODO: Add a test for when GVK is not in Scheme. Does code support informer for unstructured object?
 			It("should be able to get an informer by group/version/kind", func(done Done) {
 				By("getting an shared index informer for gvk = core/v1/pod")
 				gvk := schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Pod"}
@@ -531,6 +614,62 @@ var _ = Describe("Informer Cache", func() {
 				close(done)
 			})
 
+			It("should be able to remove an informer", func(done Done) {
+				By("getting a shared index informer for a pod")
+				sii, err := informerCache.GetInformer(&kcorev1.Pod{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sii.HasSynced()).To(BeTrue())
+
+				By("removing the informer")
+				Expect(informerCache.RemoveInformer(context.Background(), &kcorev1.Pod{})).To(Succeed())
+				_, err = informerCache.(cache.Dumper).Dump(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, false)
+				Expect(err).To(HaveOccurred())
+
+				By("verifying the next read creates a new informer")
+				newSii, err := informerCache.GetInformer(&kcorev1.Pod{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(newSii).NotTo(BeIdenticalTo(sii))
+				Expect(newSii.HasSynced()).To(BeTrue())
+				out := kcorev1.PodList{}
+				Expect(informerCache.List(context.Background(), &out)).To(Succeed())
+				Expect(out.Items).NotTo(BeEmpty())
+
+				By("verifying removing an informer which does not exist is a no-op")
+				Expect(informerCache.RemoveInformer(context.Background(), &kcorev1.ConfigMap{})).To(Succeed())
+				close(done)
+			})
+
+			It("should release a get waiting for an informer which is removed before it syncs", func(done Done) {
+				By("creating a cache whose Pod informer never syncs")
+				// The API server rejects the lists of Pods selected by an unsupported field.
+				unsynced, err := cache.New(cfg, cache.Options{
+					ByObject: map[runtime.Object]cache.ByObject{
+						&kcorev1.Pod{}: {Field: fields.OneTermEqualSelector("spec.unsupported", "value")},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				go func() {
+					defer GinkgoRecover()
+					Expect(unsynced.Start(stop)).To(Succeed())
+				}()
+				Expect(unsynced.WaitForCacheSync(stop)).To(BeTrue())
+
+				By("getting the informer in the background")
+				errs := make(chan error)
+				go func() {
+					_, err := unsynced.GetInformer(&kcorev1.Pod{})
+					errs <- err
+				}()
+				Consistently(errs).ShouldNot(Receive())
+
+				By("removing the informer")
+				Expect(unsynced.RemoveInformer(context.Background(), &kcorev1.Pod{})).To(Succeed())
+
+				By("verifying the get fails")
+				Eventually(errs).Should(Receive(MatchError(ContainSubstring("removed before it synced"))))
+				close(done)
+			}, 10)
+
 			It("should be able to index an object field then retrieve objects by that field", func() {
 				By("creating the cache")
 				informer, err := cache.New(cfg, cache.Options{})
@@ -554,7 +693,7 @@ var _ = Describe("Informer Cache", func() {
 				listObj := &kcorev1.PodList{}
 				lo := &client.ListOptions{}
 				lo.MatchingField("spec.restartPolicy", "OnFailure")
-				Expect(informer.List(context.Background(), lo, listObj)).To(Succeed())
+				Expect(informer.List(context.Background(), listObj, client.UseListOptions(lo))).To(Succeed())
 
 				By("verifying that the returned pods have correct restart policy")
 				Expect(listObj.Items).NotTo(BeEmpty())
@@ -562,6 +701,55 @@ var _ = Describe("Informer Cache", func() {
 				actual := listObj.Items[0]
 				Expect(actual.Name).To(Equal("test-pod-3"))
 			})
+
+			It("should be able to index several object fields then retrieve objects matching all of them", func() {
+				By("creating the cache")
+				informer, err := cache.New(cfg, cache.Options{})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("indexing the restartPolicy and name fields of the Pod object before starting")
+				pod := &kcorev1.Pod{}
+				restartPolicyFunc := func(obj runtime.Object) []string {
+					return []string{string(obj.(*kcorev1.Pod).Spec.RestartPolicy)}
+				}
+				Expect(informer.IndexField(pod, "spec.restartPolicy", restartPolicyFunc)).To(Succeed())
+				nameFunc := func(obj runtime.Object) []string {
+					return []string{obj.(*kcorev1.Pod).Name}
+				}
+				Expect(informer.IndexField(pod, "metadata.name", nameFunc)).To(Succeed())
+
+				By("running the cache and waiting for it to sync")
+				go func() {
+					defer GinkgoRecover()
+					Expect(informer.Start(stop)).To(Succeed())
+				}()
+				Expect(informer.WaitForCacheSync(stop)).NotTo(BeFalse())
+
+				By("listing Pods with restartPolicy Always and name test-pod-2")
+				listObj := &kcorev1.PodList{}
+				lo := client.MatchingFields(fields.Set{
+					"spec.restartPolicy": "Always",
+					"metadata.name":      "test-pod-2",
+				})
+				Expect(informer.List(context.Background(), listObj, lo)).To(Succeed())
+				Expect(listObj.Items).Should(HaveLen(1))
+				Expect(listObj.Items[0].Name).To(Equal("test-pod-2"))
+
+				By("listing Pods with restartPolicy Always and name test-pod-3")
+				lo = client.MatchingFields(fields.Set{
+					"spec.restartPolicy": "Always",
+					"metadata.name":      "test-pod-3",
+				})
+				Expect(informer.List(context.Background(), listObj, lo)).To(Succeed())
+				Expect(listObj.Items).To(BeEmpty())
+
+				By("listing Pods by a field which is not indexed")
+				lo = client.MatchingFields(fields.Set{
+					"spec.restartPolicy": "Always",
+					"spec.nodeName":      "node-1",
+				})
+				Expect(informer.List(context.Background(), listObj, lo)).NotTo(Succeed())
+			})
 		})
 		Context("with unstructured objects", func() {
 			It("should be able to get informer for the object", func(done Done) {
@@ -649,7 +837,7 @@ var _ = Describe("Informer Cache", func() {
 				})
 				lo := &client.ListOptions{}
 				lo.MatchingField("spec.restartPolicy", "OnFailure")
-				err = informer.List(context.Background(), lo, listObj)
+				err = informer.List(context.Background(), listObj, client.UseListOptions(lo))
 				Expect(err).To(Succeed())
 
 				By("verifying that the returned pods have correct restart policy")
@@ -658,6 +846,205 @@ var _ = Describe("Informer Cache", func() {
 				actual := listObj.Items[0]
 				Expect(actual.GetName()).To(Equal("test-pod-3"))
 			}, 3)
+
+			It("should get an unstructured informer by group/version/kind if the kind is not in the Scheme", func() {
+				By("creating a cache with an empty Scheme")
+				informer, err := cache.New(cfg, cache.Options{Scheme: runtime.NewScheme()})
+				Expect(err).NotTo(HaveOccurred())
+
+				gvk := schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Pod"}
+				sii, err := informer.GetInformerForKind(gvk)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sii).NotTo(BeNil())
+
+				By("running the cache and waiting for it to sync")
+				go func() {
+					defer GinkgoRecover()
+					Expect(informer.Start(stop)).To(Succeed())
+				}()
+				Expect(informer.WaitForCacheSync(stop)).To(BeTrue())
+
+				By("reading the Pods as unstructured objects")
+				out := &unstructured.Unstructured{}
+				out.SetGroupVersionKind(gvk)
+				key := client.ObjectKey{Namespace: testNamespaceTwo, Name: "test-pod-2"}
+				Expect(informer.Get(context.Background(), key, out)).To(Succeed())
+				Expect(out.GetName()).To(Equal("test-pod-2"))
+			}, 3)
+		})
+	})
+
+	Describe("BuilderWithOptions", func() {
+		var opts cache.Options
+
+		BeforeEach(func() {
+			opts = cache.Options{Scheme: kscheme.Scheme, Mapper: meta.NewDefaultRESTMapper(nil)}
+		})
+
+		It("should default to the options it is called with", func() {
+			newCache := cache.BuilderWithOptions(cache.Options{
+				ByObject: map[runtime.Object]cache.ByObject{
+					&kcorev1.Pod{}: {Field: fields.OneTermEqualSelector("spec.nodeName", "node")},
+				},
+			})
+			_, err := newCache(cfg, opts)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should prefer its own options", func() {
+			newCache := cache.BuilderWithOptions(cache.Options{
+				Scheme: runtime.NewScheme(),
+				ByObject: map[runtime.Object]cache.ByObject{
+					&kcorev1.Pod{}: {Field: fields.OneTermEqualSelector("spec.nodeName", "node")},
+				},
+			})
+			_, err := newCache(cfg, opts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unable to get the kind of ByObject type *v1.Pod"))
+		})
+	})
+
+	Describe("with kinds which are not served by the API server", func() {
+		var gvk schema.GroupVersionKind
+
+		BeforeEach(func() {
+			gvk = schema.GroupVersionKind{Group: "deferred.example.com", Version: "v1", Kind: "Deferred"}
+		})
+
+		It("should fail to get an informer by default", func() {
+			_, err := informerCache.GetInformerForKind(gvk)
+			Expect(meta.IsNoMatchError(err)).To(BeTrue())
+		})
+
+		It("should defer the informer until the kind is served if asked to", func() {
+			By("creating a cache that defers unknown kinds")
+			deferring, err := cache.New(cfg, cache.Options{DeferUnknownKinds: true})
+			Expect(err).NotTo(HaveOccurred())
+			go func() {
+				defer GinkgoRecover()
+				Expect(deferring.Start(stop)).To(Succeed())
+			}()
+
+			By("getting an informer for the unknown kind")
+			sii, err := deferring.GetInformerForKind(gvk)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sii).NotTo(BeNil())
+			Expect(deferring.WaitForCacheSync(stop)).To(BeTrue())
+
+			By("failing to read the kind until it is served")
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(gvk.GroupVersion().WithKind("DeferredList"))
+			Expect(cache.IsKindNotServedError(deferring.List(context.Background(), list))).To(BeTrue())
+
+			By("installing the CustomResourceDefinition of the kind")
+			crd := &apiextensionsv1beta1.CustomResourceDefinition{
+				ObjectMeta: kmetav1.ObjectMeta{Name: "deferreds.deferred.example.com"},
+				Spec: apiextensionsv1beta1.CustomResourceDefinitionSpec{
+					Group:   gvk.Group,
+					Version: gvk.Version,
+					Scope:   apiextensionsv1beta1.NamespaceScoped,
+					Names: apiextensionsv1beta1.CustomResourceDefinitionNames{
+						Plural: "deferreds",
+						Kind:   gvk.Kind,
+					},
+				},
+			}
+			Expect(envtest.CreateCRDs(cfg, []*apiextensionsv1beta1.CustomResourceDefinition{crd})).To(Succeed())
+			defer func() {
+				cs, err := apiextensionsclientset.NewForConfig(cfg)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cs.ApiextensionsV1beta1().CustomResourceDefinitions().Delete(crd.Name, &kmetav1.DeleteOptions{})).To(Succeed())
+			}()
+
+			By("reading the kind once the informer has started")
+			Eventually(func() error {
+				return deferring.List(context.Background(), list)
+			}, 30*time.Second).Should(Succeed())
+			Expect(sii.HasSynced()).To(BeTrue())
+		}, 40)
+
+		It("should start the informer once a crd.Installer has established the kind", func() {
+			By("creating a cache that defers unknown kinds")
+			deferring, err := cache.New(cfg, cache.Options{DeferUnknownKinds: true})
+			Expect(err).NotTo(HaveOccurred())
+			go func() {
+				defer GinkgoRecover()
+				Expect(deferring.Start(stop)).To(Succeed())
+			}()
+
+			By("watching the unknown kind")
+			installedGVK := schema.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: "Installed"}
+			_, err = deferring.GetInformerForKind(installedGVK)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deferring.WaitForCacheSync(stop)).To(BeTrue())
+
+			By("installing the CustomResourceDefinition of the kind with a crd.Installer")
+			installer := &crd.Installer{CRDs: []*apiextensionsv1beta1.CustomResourceDefinition{{
+				ObjectMeta: kmetav1.ObjectMeta{Name: "installeds.deferred.example.com"},
+				Spec: apiextensionsv1beta1.CustomResourceDefinitionSpec{
+					Group:   installedGVK.Group,
+					Version: installedGVK.Version,
+					Scope:   apiextensionsv1beta1.NamespaceScoped,
+					Names: apiextensionsv1beta1.CustomResourceDefinitionNames{
+						Plural: "installeds",
+						Kind:   installedGVK.Kind,
+					},
+				},
+			}}}
+			Expect(installer.InjectCache(deferring)).To(Succeed())
+			Expect(installer.Install(cfg)).To(Succeed())
+			defer func() {
+				cs, err := apiextensionsclientset.NewForConfig(cfg)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cs.ApiextensionsV1beta1().CustomResourceDefinitions().Delete("installeds.deferred.example.com", &kmetav1.DeleteOptions{})).To(Succeed())
+			}()
+
+			By("reading the kind before the cache discovers it again periodically")
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(installedGVK.GroupVersion().WithKind("InstalledList"))
+			Eventually(func() error {
+				return deferring.List(context.Background(), list)
+			}, 5*time.Second).Should(Succeed())
+		}, 20)
+	})
+
+	Describe("as a Dumper", func() {
+		It("should dump the keys of the objects in an informer store", func() {
+			By("getting the informer for Pods")
+			_, err := informerCache.GetInformer(&kcorev1.Pod{})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("dumping the Pod informer")
+			dumper, ok := informerCache.(cache.Dumper)
+			Expect(ok).To(BeTrue())
+			snapshot, err := dumper.Dump(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("verifying the snapshot")
+			Expect(snapshot.Kind).To(Equal("Pod"))
+			Expect(snapshot.Synced).To(BeTrue())
+			Expect(snapshot.Keys).To(ContainElement(testNamespaceOne + "/test-pod-1"))
+			Expect(snapshot.Keys).To(ContainElement(testNamespaceTwo + "/test-pod-2"))
+			Expect(snapshot.Keys).To(ContainElement(testNamespaceTwo + "/test-pod-3"))
+			Expect(snapshot.Objects).To(BeEmpty())
+		})
+
+		It("should dump the objects in an informer store if asked to", func() {
+			_, err := informerCache.GetInformer(&kcorev1.Pod{})
+			Expect(err).NotTo(HaveOccurred())
+
+			snapshot, err := informerCache.(cache.Dumper).Dump(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(snapshot.Objects).To(HaveLen(len(snapshot.Keys)))
+			for i, obj := range snapshot.Objects {
+				pod := obj.(*kcorev1.Pod)
+				Expect(pod.Namespace + "/" + pod.Name).To(Equal(snapshot.Keys[i]))
+			}
+		})
+
+		It("should return an error if there is no informer for the kind", func() {
+			_, err := informerCache.(cache.Dumper).Dump(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, false)
+			Expect(err).To(HaveOccurred())
 		})
 	})
 })
@@ -0,0 +1,251 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	toolscache "k8s.io/client-go/tools/cache"
+	cachemetrics "sigs.k8s.io/controller-runtime/pkg/cache/internal/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Defaults of the ConsistencyCheckOptions.
+const (
+	defaultConsistencyCheckInterval = 10 * time.Minute
+	defaultConsistencyCheckSample   = 10
+	defaultConsistencyCheckGrace    = 5 * time.Second
+)
+
+// ConsistencyCheckOptions are the options of a ConsistencyChecker.
+type ConsistencyCheckOptions struct {
+	// Objects are the types whose cached objects are checked, e.g. &corev1.Pod{}.  Required.
+	Objects []runtime.Object
+
+	// Scheme is the scheme to use for mapping Objects to GroupVersionKinds.  Defaults to the
+	// Kubernetes client-go scheme.
+	Scheme *runtime.Scheme
+
+	// Interval is the time between two checks.  Defaults to 10 minutes.
+	Interval time.Duration
+
+	// SampleSize is the number of cached objects of each type compared against the API server
+	// on each check.  Defaults to 10.
+	SampleSize int
+
+	// Grace is the time given to a diverging object to catch up with the API server, e.g.
+	// because its watch event is being delivered, before the divergence is reported.
+	// Defaults to 5 seconds.
+	Grace time.Duration
+}
+
+// ConsistencyChecker periodically compares a sample of the objects in a Cache against the
+// objects read from the API server, and records the objects which diverged in the
+// controller_runtime_cache_consistency_divergences_total metric.  It helps diagnosing missed
+// watch events or relist bugs in long-lived operators.  ConsistencyChecker implements the
+// manager Runnable interface, e.g.
+//
+//	checker, err := cache.NewConsistencyChecker(mgr.GetCache(), liveClient, cache.ConsistencyCheckOptions{
+//		Objects: []runtime.Object{&corev1.Pod{}},
+//	})
+//	...
+//	err = mgr.Add(checker)
+type ConsistencyChecker struct {
+	cache  Cache
+	dumper Dumper
+	live   client.Reader
+	scheme *runtime.Scheme
+	gvks   []schema.GroupVersionKind
+
+	interval   time.Duration
+	sampleSize int
+	grace      time.Duration
+}
+
+// NewConsistencyChecker returns a ConsistencyChecker comparing the objects in c against the
+// objects read with live, which must read from the API server.  c must implement Dumper.
+func NewConsistencyChecker(c Cache, live client.Reader, opts ConsistencyCheckOptions) (*ConsistencyChecker, error) {
+	dumper, ok := c.(Dumper)
+	if !ok {
+		return nil, fmt.Errorf("cache %T does not support dumps", c)
+	}
+	if live == nil {
+		return nil, fmt.Errorf("must specify a live Reader")
+	}
+	if len(opts.Objects) == 0 {
+		return nil, fmt.Errorf("must specify the Objects to check")
+	}
+
+	if opts.Scheme == nil {
+		opts.Scheme = scheme.Scheme
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultConsistencyCheckInterval
+	}
+	if opts.SampleSize <= 0 {
+		opts.SampleSize = defaultConsistencyCheckSample
+	}
+	if opts.Grace <= 0 {
+		opts.Grace = defaultConsistencyCheckGrace
+	}
+
+	gvks := make([]schema.GroupVersionKind, 0, len(opts.Objects))
+	for _, obj := range opts.Objects {
+		gvk, err := apiutil.GVKForObject(obj, opts.Scheme)
+		if err != nil {
+			return nil, err
+		}
+		gvks = append(gvks, gvk)
+	}
+
+	return &ConsistencyChecker{
+		cache:      c,
+		dumper:     dumper,
+		live:       live,
+		scheme:     opts.Scheme,
+		gvks:       gvks,
+		interval:   opts.Interval,
+		sampleSize: opts.SampleSize,
+		grace:      opts.Grace,
+	}, nil
+}
+
+// Start implements Runnable.  It checks the cache every Interval once the cache has synced,
+// until stop is closed.
+func (cc *ConsistencyChecker) Start(stop <-chan struct{}) error {
+	if !cc.cache.WaitForCacheSync(stop) {
+		// stop was closed before the cache synced
+		return nil
+	}
+
+	ticker := time.NewTicker(cc.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+			for _, gvk := range cc.gvks {
+				cc.checkKind(gvk, stop)
+			}
+		}
+	}
+}
+
+// checkKind compares a sample of the cached objects of kind gvk against the API server.
+func (cc *ConsistencyChecker) checkKind(gvk schema.GroupVersionKind, stop <-chan struct{}) {
+	snapshot, err := cc.dumper.Dump(gvk, false)
+	if err != nil {
+		// the informer is only created once the objects are read or watched
+		log.V(1).Info("skipping cache consistency check", "kind", gvk, "reason", err.Error())
+		return
+	}
+
+	keys := snapshot.Keys
+	if len(keys) > cc.sampleSize {
+		sample := make([]string, 0, cc.sampleSize)
+		for _, i := range rand.Perm(len(keys))[:cc.sampleSize] {
+			sample = append(sample, keys[i])
+		}
+		keys = sample
+	}
+
+	for _, storeKey := range keys {
+		namespace, name, err := toolscache.SplitMetaNamespaceKey(storeKey)
+		if err != nil {
+			log.Error(err, "unable to check cache consistency", "kind", gvk, "key", storeKey)
+			continue
+		}
+		key := client.ObjectKey{Namespace: namespace, Name: name}
+		reason, err := cc.compare(gvk, key)
+		if err == nil && reason != "" {
+			// give the cache some time to catch up before reporting the divergence
+			select {
+			case <-stop:
+				return
+			case <-time.After(cc.grace):
+			}
+			reason, err = cc.compare(gvk, key)
+		}
+		if err != nil {
+			log.Error(err, "unable to check cache consistency", "kind", gvk, "object", key)
+			continue
+		}
+
+		kind := metrics.LabelValue(metrics.LabelKind, gvk.Kind)
+		cachemetrics.ConsistencyChecks.WithLabelValues(kind).Inc()
+		if reason != "" {
+			cachemetrics.ConsistencyDivergences.WithLabelValues(kind, reason).Inc()
+			log.Info("cached object diverged from the API server", "kind", gvk, "object", key, "reason", reason)
+		}
+	}
+}
+
+// compare reads the object for key from the cache and the API server, and returns the reason
+// why they diverge, or an empty string if they don't.
+func (cc *ConsistencyChecker) compare(gvk schema.GroupVersionKind, key client.ObjectKey) (string, error) {
+	cached, err := cc.scheme.New(gvk)
+	if err != nil {
+		return "", err
+	}
+	live, err := cc.scheme.New(gvk)
+	if err != nil {
+		return "", err
+	}
+
+	cacheErr := cc.cache.Get(context.TODO(), key, cached)
+	if cacheErr != nil && !errors.IsNotFound(cacheErr) {
+		return "", cacheErr
+	}
+	liveErr := cc.live.Get(context.TODO(), key, live)
+	if liveErr != nil && !errors.IsNotFound(liveErr) {
+		return "", liveErr
+	}
+
+	switch {
+	case cacheErr != nil && liveErr != nil:
+		// deleted from both
+		return "", nil
+	case cacheErr != nil:
+		return cachemetrics.ReasonMissing, nil
+	case liveErr != nil:
+		return cachemetrics.ReasonDeleted, nil
+	}
+
+	cachedMeta, err := meta.Accessor(cached)
+	if err != nil {
+		return "", err
+	}
+	liveMeta, err := meta.Accessor(live)
+	if err != nil {
+		return "", err
+	}
+	if cachedMeta.GetResourceVersion() != liveMeta.GetResourceVersion() {
+		return cachemetrics.ReasonResourceVersion, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	cachemetrics "sigs.k8s.io/controller-runtime/pkg/cache/internal/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ConsistencyChecker", func() {
+	pod := func(name, resourceVersion string) *kcorev1.Pod {
+		return &kcorev1.Pod{ObjectMeta: kmetav1.ObjectMeta{
+			Namespace:       "consistency",
+			Name:            name,
+			ResourceVersion: resourceVersion,
+		}}
+	}
+
+	counter := func(c interface{ Write(*dto.Metric) error }) float64 {
+		m := &dto.Metric{}
+		Expect(c.Write(m)).To(Succeed())
+		return m.GetCounter().GetValue()
+	}
+
+	It("should require a cache supporting dumps", func() {
+		_, err := cache.NewConsistencyChecker(&informertest.FakeInformers{}, fake.NewFakeClient(), cache.ConsistencyCheckOptions{
+			Objects: []runtime.Object{&kcorev1.Pod{}},
+		})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should require the objects to check", func() {
+		_, err := cache.NewConsistencyChecker(&dumpingCache{Client: fake.NewFakeClient()}, fake.NewFakeClient(), cache.ConsistencyCheckOptions{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should record the cached objects which diverged from the API server", func(done Done) {
+		checks := cachemetrics.ConsistencyChecks.WithLabelValues("Pod")
+		stale := cachemetrics.ConsistencyDivergences.WithLabelValues("Pod", cachemetrics.ReasonResourceVersion)
+		deleted := cachemetrics.ConsistencyDivergences.WithLabelValues("Pod", cachemetrics.ReasonDeleted)
+		checksBefore, staleBefore, deletedBefore := counter(checks), counter(stale), counter(deleted)
+
+		cached := &dumpingCache{Client: fake.NewFakeClient(pod("same", "1"), pod("stale", "1"), pod("deleted", "1"))}
+		live := fake.NewFakeClient(pod("same", "1"), pod("stale", "2"))
+		checker, err := cache.NewConsistencyChecker(cached, live, cache.ConsistencyCheckOptions{
+			Objects:  []runtime.Object{&kcorev1.Pod{}},
+			Interval: 10 * time.Millisecond,
+			Grace:    time.Millisecond,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		stop := make(chan struct{})
+		defer close(stop)
+		go func() {
+			defer GinkgoRecover()
+			Expect(checker.Start(stop)).To(Succeed())
+		}()
+
+		Eventually(func() float64 { return counter(checks) - checksBefore }).Should(BeNumerically(">=", 3))
+		Eventually(func() float64 { return counter(stale) - staleBefore }).Should(BeNumerically(">=", 1))
+		Eventually(func() float64 { return counter(deleted) - deletedBefore }).Should(BeNumerically(">=", 1))
+		close(done)
+	})
+})
+
+// dumpingCache is a Cache reading from a Client and supporting dumps of the Pods it holds.
+type dumpingCache struct {
+	informertest.FakeInformers
+	client.Client
+}
+
+func (c *dumpingCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	return c.Client.Get(ctx, key, obj)
+}
+
+func (c *dumpingCache) List(ctx context.Context, list runtime.Object, opts ...client.ListOptionFunc) error {
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *dumpingCache) Dump(gvk schema.GroupVersionKind, _ bool) (*cache.Snapshot, error) {
+	pods := &kcorev1.PodList{}
+	if err := c.Client.List(context.TODO(), pods); err != nil {
+		return nil, err
+	}
+	snapshot := &cache.Snapshot{Version: gvk.Version, Kind: gvk.Kind, Synced: true}
+	for _, p := range pods.Items {
+		snapshot.Keys = append(snapshot.Keys, p.Namespace+"/"+p.Name)
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ Dumper = &informerCache{}
+
+// Dumper knows how to take snapshots of the informer stores of a cache, to troubleshoot
+// why an object is or isn't in the cache.
+type Dumper interface {
+	// Dump returns a snapshot of the keys of the objects in the store of the informer for
+	// gvk, and of the objects themselves if includeObjects is true.  It never creates an
+	// informer and returns an error if there is none for gvk.
+	Dump(gvk schema.GroupVersionKind, includeObjects bool) (*Snapshot, error)
+}
+
+// Snapshot is a point in time copy of the contents of an informer store.
+type Snapshot struct {
+	// Group, Version and Kind are the group-version-kind of the objects in the store.
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+
+	// Synced is true if the informer has synced.
+	Synced bool `json:"synced"`
+
+	// Keys are the sorted keys of the objects in the store, i.e. namespace/name for
+	// namespaced objects and name for cluster-scoped objects.
+	Keys []string `json:"keys"`
+
+	// Objects are copies of the objects in the store, in the order of Keys.  They are only
+	// set if the Snapshot was taken with includeObjects.
+	Objects []runtime.Object `json:"objects,omitempty"`
+}
+
+// Dump implements Dumper
+func (ip *informerCache) Dump(gvk schema.GroupVersionKind, includeObjects bool) (*Snapshot, error) {
+	entry, ok := ip.InformersMap.Lookup(gvk)
+	if !ok {
+		return nil, fmt.Errorf("no informer exists for %s", gvk)
+	}
+
+	indexer := entry.Informer.GetIndexer()
+	keys := indexer.ListKeys()
+	sort.Strings(keys)
+	snapshot := &Snapshot{
+		Group:   gvk.Group,
+		Version: gvk.Version,
+		Kind:    gvk.Kind,
+		Synced:  entry.Informer.HasSynced(),
+		Keys:    keys,
+	}
+	if !includeObjects {
+		return snapshot, nil
+	}
+
+	snapshot.Keys = make([]string, 0, len(keys))
+	snapshot.Objects = make([]runtime.Object, 0, len(keys))
+	for _, key := range keys {
+		item, exists, err := indexer.GetByKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			// the object was deleted after the keys were listed
+			continue
+		}
+		obj, isObj := item.(runtime.Object)
+		if !isObj {
+			return nil, fmt.Errorf("cache contained %T, which is not an Object", item)
+		}
+		snapshot.Keys = append(snapshot.Keys, key)
+		snapshot.Objects = append(snapshot.Objects, obj.DeepCopyObject())
+	}
+	return snapshot, nil
+}
@@ -33,11 +33,47 @@ import (
 )
 
 var (
-	_ Informers     = &informerCache{}
-	_ client.Reader = &informerCache{}
-	_ Cache         = &informerCache{}
+	_ Informers        = &informerCache{}
+	_ client.Reader    = &informerCache{}
+	_ Cache            = &informerCache{}
+	_ DeferredResolver = &informerCache{}
 )
 
+// KindNotServedError is returned when reading a kind whose informer was deferred because the kind
+// was not served by the API server, as allowed by Options.DeferUnknownKinds, until the informer has
+// synced.
+type KindNotServedError struct {
+	// GroupVersionKind is the kind which was read.
+	GroupVersionKind schema.GroupVersionKind
+
+	// Err is the error of the last attempt to discover the kind, or nil once the kind is discovered
+	// and its informer is syncing.
+	Err error
+}
+
+// Error implements error
+func (e *KindNotServedError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("kind %s is not served by the API server yet: %v", e.GroupVersionKind, e.Err)
+	}
+	return fmt.Sprintf("the informer of kind %s has not synced yet", e.GroupVersionKind)
+}
+
+// IsKindNotServedError returns true if err is a KindNotServedError.
+func IsKindNotServedError(err error) bool {
+	_, ok := err.(*KindNotServedError)
+	return ok
+}
+
+// notServedErr returns a KindNotServedError if the informer of entry was deferred and has not
+// synced yet.
+func notServedErr(gvk schema.GroupVersionKind, entry *internal.MapEntry) error {
+	if deferred, err := entry.Deferred(); deferred {
+		return &KindNotServedError{GroupVersionKind: gvk, Err: err}
+	}
+	return nil
+}
+
 // informerCache is a Kubernetes Object cache populated from InformersMap.  informerCache wraps an InformersMap.
 type informerCache struct {
 	*internal.InformersMap
@@ -54,11 +90,21 @@ func (ip *informerCache) Get(ctx context.Context, key client.ObjectKey, out runt
 	if err != nil {
 		return err
 	}
+	if err := notServedErr(gvk, cache); err != nil {
+		return err
+	}
 	return cache.Reader.Get(ctx, key, out)
 }
 
-// List implements Reader
-func (ip *informerCache) List(ctx context.Context, opts *client.ListOptions, out runtime.Object) error {
+// List implements Reader.  The cache can't paginate, so it returns all the matching objects
+// regardless of the Limit option, and returns an error if the Continue option is set.
+func (ip *informerCache) List(ctx context.Context, out runtime.Object, opts ...client.ListOptionFunc) error {
+	listOpts := client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	if listOpts.Continue != "" || (listOpts.Raw != nil && listOpts.Raw.Continue != "") {
+		return fmt.Errorf("continue tokens are not supported by the cache")
+	}
+
 	gvk, err := apiutil.GVKForObject(out, ip.Scheme)
 	if err != nil {
 		return err
@@ -94,14 +140,23 @@ func (ip *informerCache) List(ctx context.Context, opts *client.ListOptions, out
 	if err != nil {
 		return err
 	}
+	if err := notServedErr(gvk, cache); err != nil {
+		return err
+	}
 
-	return cache.Reader.List(ctx, opts, out)
+	return cache.Reader.List(ctx, out, client.UseListOptions(&listOpts))
 }
 
-// GetInformerForKind returns the informer for the GroupVersionKind
+// GetInformerForKind returns the informer for the GroupVersionKind.  Kinds that are
+// not registered in the Scheme get an informer for unstructured objects.
 func (ip *informerCache) GetInformerForKind(gvk schema.GroupVersionKind) (cache.SharedIndexInformer, error) {
 	// Map the gvk to an object
 	obj, err := ip.Scheme.New(gvk)
+	if runtime.IsNotRegisteredError(err) {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		obj, err = u, nil
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -125,6 +180,16 @@ func (ip *informerCache) GetInformer(obj runtime.Object) (cache.SharedIndexInfor
 	return i.Informer, err
 }
 
+// RemoveInformer stops the informer for the obj and removes it from the cache
+func (ip *informerCache) RemoveInformer(ctx context.Context, obj runtime.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, ip.Scheme)
+	if err != nil {
+		return err
+	}
+	ip.InformersMap.Remove(gvk, obj)
+	return nil
+}
+
 // IndexField adds an indexer to the underlying cache, using extraction function to get
 // value(s) from the given field.  This index can then be used by passing a field selector
 // to List. For one-to-one compatibility with "normal" field selectors, only return one value.
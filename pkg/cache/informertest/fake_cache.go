@@ -79,6 +79,19 @@ func (c *FakeInformers) GetInformer(obj runtime.Object) (toolscache.SharedIndexI
 	return c.informerFor(gvk, obj)
 }
 
+// RemoveInformer implements Informers
+func (c *FakeInformers) RemoveInformer(ctx context.Context, obj runtime.Object) error {
+	if c.Scheme == nil {
+		c.Scheme = scheme.Scheme
+	}
+	gvks, _, err := c.Scheme.ObjectKinds(obj)
+	if err != nil {
+		return err
+	}
+	delete(c.InformersByGVK, gvks[0])
+	return nil
+}
+
 // WaitForCacheSync implements Informers
 func (c *FakeInformers) WaitForCacheSync(stop <-chan struct{}) bool {
 	if c.Synced == nil {
@@ -136,6 +149,6 @@ func (c *FakeInformers) Get(ctx context.Context, key client.ObjectKey, obj runti
 }
 
 // List implements Cache
-func (c *FakeInformers) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+func (c *FakeInformers) List(ctx context.Context, list runtime.Object, opts ...client.ListOptionFunc) error {
 	return nil
 }
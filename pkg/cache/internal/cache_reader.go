@@ -87,35 +87,35 @@ func (c *CacheReader) Get(_ context.Context, key client.ObjectKey, out runtime.O
 }
 
 // List lists items out of the indexer and writes them to out
-func (c *CacheReader) List(_ context.Context, opts *client.ListOptions, out runtime.Object) error {
+func (c *CacheReader) List(_ context.Context, out runtime.Object, opts ...client.ListOptionFunc) error {
+	listOpts := client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+
 	var objs []interface{}
 	var err error
 
-	if opts != nil && opts.FieldSelector != nil {
-		// TODO(directxman12): support more complicated field selectors by
-		// combining multiple indicies, GetIndexers, etc
-		field, val, requiresExact := requiresExactMatch(opts.FieldSelector)
+	if listOpts.FieldSelector != nil {
+		reqs, requiresExact := requiresExactMatch(listOpts.FieldSelector)
 		if !requiresExact {
 			return fmt.Errorf("non-exact field matches are not supported by the cache")
 		}
-		// list all objects by the field selector.  If this is namespaced and we have one, ask for the
+		// list all objects by the first field of the selector.  If this is namespaced and we have one, ask for the
 		// namespaced index key.  Otherwise, ask for the non-namespaced variant by using the fake "all namespaces"
 		// namespace.
-		objs, err = c.indexer.ByIndex(FieldIndexName(field), KeyToNamespacedKey(opts.Namespace, val))
-	} else if opts != nil && opts.Namespace != "" {
-		objs, err = c.indexer.ByIndex(cache.NamespaceIndex, opts.Namespace)
+		objs, err = c.indexer.ByIndex(FieldIndexName(reqs[0].Field), KeyToNamespacedKey(listOpts.Namespace, reqs[0].Value))
+		if err == nil {
+			// filter the objects by the indices of the remaining fields
+			objs, err = c.filterByIndices(objs, listOpts.Namespace, reqs[1:])
+		}
+	} else if listOpts.Namespace != "" {
+		objs, err = c.indexer.ByIndex(cache.NamespaceIndex, listOpts.Namespace)
 	} else {
 		objs = c.indexer.List()
 	}
 	if err != nil {
 		return err
 	}
-	var labelSel labels.Selector
-	if opts != nil && opts.LabelSelector != nil {
-		labelSel = opts.LabelSelector
-	}
-
-	outItems, err := c.getListItems(objs, labelSel)
+	outItems, err := c.getListItems(objs, listOpts.LabelSelector)
 	if err != nil {
 		return err
 	}
@@ -155,17 +155,64 @@ func objectKeyToStoreKey(k client.ObjectKey) string {
 	return k.Namespace + "/" + k.Name
 }
 
-// requiresExactMatch checks if the given field selector is of the form `k=v` or `k==v`.
-func requiresExactMatch(sel fields.Selector) (field, val string, required bool) {
-	reqs := sel.Requirements()
-	if len(reqs) != 1 {
-		return "", "", false
+// requiresExactMatch checks if the given field selector is made of requirements of the form `k=v` or `k==v`,
+// and returns them.
+func requiresExactMatch(sel fields.Selector) (reqs fields.Requirements, required bool) {
+	reqs = sel.Requirements()
+	if len(reqs) == 0 {
+		return nil, false
+	}
+	for _, req := range reqs {
+		if req.Operator != selection.Equals && req.Operator != selection.DoubleEquals {
+			return nil, false
+		}
+	}
+	return reqs, true
+}
+
+// filterByIndices returns the objects in objs whose index values of the fields of reqs include the
+// values of reqs.
+func (c *CacheReader) filterByIndices(objs []interface{}, namespace string, reqs fields.Requirements) ([]interface{}, error) {
+	if len(reqs) == 0 {
+		return objs, nil
+	}
+	indexers := c.indexer.GetIndexers()
+	indexFuncs := make([]cache.IndexFunc, 0, len(reqs))
+	for _, req := range reqs {
+		indexFunc, ok := indexers[FieldIndexName(req.Field)]
+		if !ok {
+			return nil, fmt.Errorf("index with name %s does not exist", FieldIndexName(req.Field))
+		}
+		indexFuncs = append(indexFuncs, indexFunc)
+	}
+
+	filtered := make([]interface{}, 0, len(objs))
+	for _, obj := range objs {
+		matches := true
+		for i, req := range reqs {
+			vals, err := indexFuncs[i](obj)
+			if err != nil {
+				return nil, err
+			}
+			if !containsString(vals, KeyToNamespacedKey(namespace, req.Value)) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			filtered = append(filtered, obj)
+		}
 	}
-	req := reqs[0]
-	if req.Operator != selection.Equals && req.Operator != selection.DoubleEquals {
-		return "", "", false
+	return filtered, nil
+}
+
+func containsString(vals []string, val string) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
 	}
-	return req.Field, req.Value, true
+	return false
 }
 
 // FieldIndexName constructs the name of the index over the given field,
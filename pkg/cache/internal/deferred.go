@@ -0,0 +1,125 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// deferredResolvePeriod is the minimum time between attempts to discover the kind of a deferred
+// informer, so that the informer retrying its List every second doesn't flood the API server with
+// discovery requests.
+var deferredResolvePeriod = 10 * time.Second
+
+// deferredListWatch creates the ListWatch of an informer whose kind was not served by the API server
+// when the informer was created, such as a kind whose CustomResourceDefinition is installed by the
+// operator at startup.  The List of the informer fails until the kind is discovered, at which point
+// the informer starts like any other.
+type deferredListWatch struct {
+	gvk schema.GroupVersionKind
+	ip  *specificInformersMap
+
+	mu sync.Mutex
+	// lw is the ListWatch of the kind once it is discovered.
+	lw *cache.ListWatch
+	// err is the error of the last attempt to discover the kind, and lastAttempt its time.
+	err         error
+	lastAttempt time.Time
+}
+
+// newDeferredListWatch returns a deferredListWatch for gvk which could not be mapped because of err.
+func newDeferredListWatch(gvk schema.GroupVersionKind, ip *specificInformersMap, err error) *deferredListWatch {
+	return &deferredListWatch{gvk: gvk, ip: ip, err: err, lastAttempt: time.Now()}
+}
+
+// Err returns the error discovering the kind, or nil once it has been discovered.
+func (d *deferredListWatch) Err() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.err
+}
+
+// resolve returns the ListWatch of the kind, trying to discover it again if it was not discovered yet.
+// The mapper is reset before each attempt if it is an apiutil.ResettableRESTMapper.
+func (d *deferredListWatch) resolve() (*cache.ListWatch, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.lw != nil {
+		return d.lw, nil
+	}
+	if time.Since(d.lastAttempt) < deferredResolvePeriod {
+		return nil, d.err
+	}
+
+	if mapper, ok := d.ip.mapper.(apiutil.ResettableRESTMapper); ok {
+		mapper.Reset()
+	}
+	return d.discover()
+}
+
+// resolveNow tries to discover the kind again if it was not discovered yet, regardless of when it
+// was last attempted.  The mapper must have been reset by the caller if the kind was added since.
+func (d *deferredListWatch) resolveNow() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.lw != nil {
+		return nil
+	}
+	_, err := d.discover()
+	return err
+}
+
+// discover creates the ListWatch of the kind if it is discovered.  It must be called with mu held.
+func (d *deferredListWatch) discover() (*cache.ListWatch, error) {
+	d.lastAttempt = time.Now()
+	lw, err := d.ip.createListWatcher(d.gvk, d.ip)
+	if err != nil {
+		d.err = err
+		return nil, err
+	}
+	d.lw, d.err = lw, nil
+	return lw, nil
+}
+
+// ListWatch returns a ListWatch which delegates to the ListWatch of the kind once it is discovered.
+func (d *deferredListWatch) ListWatch() *cache.ListWatch {
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			lw, err := d.resolve()
+			if err != nil {
+				return nil, err
+			}
+			return lw.ListFunc(opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			lw, err := d.resolve()
+			if err != nil {
+				return nil, err
+			}
+			return lw.WatchFunc(opts)
+		},
+	}
+}
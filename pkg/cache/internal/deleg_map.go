@@ -25,6 +25,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
 // InformersMap create and caches Informers for (runtime.Object, schema.GroupVersionKind) pairs.
@@ -45,11 +47,14 @@ func NewInformersMap(config *rest.Config,
 	scheme *runtime.Scheme,
 	mapper meta.RESTMapper,
 	resync time.Duration,
-	namespace string) *InformersMap {
+	namespace string,
+	deferUnknownKinds bool,
+	selectors SelectorsByGVK,
+	transforms TransformFuncsByGVK) *InformersMap {
 
 	return &InformersMap{
-		structured:   newStructuredInformersMap(config, scheme, mapper, resync, namespace),
-		unstructured: newUnstructuredInformersMap(config, scheme, mapper, resync, namespace),
+		structured:   newStructuredInformersMap(config, scheme, mapper, resync, namespace, deferUnknownKinds, selectors, transforms),
+		unstructured: newUnstructuredInformersMap(config, scheme, mapper, resync, namespace, deferUnknownKinds, selectors, transforms),
 
 		Scheme: scheme,
 	}
@@ -71,6 +76,18 @@ func (m *InformersMap) WaitForCacheSync(stop <-chan struct{}) bool {
 	return cache.WaitForCacheSync(stop, syncedFuncs...)
 }
 
+// ResolveDeferred tries to discover the kinds of the informers deferred because their kind was not
+// served by the API server, e.g. once their CustomResourceDefinitions are Established, instead of
+// waiting for the next periodic attempt.  The mapper is reset first if it is an
+// apiutil.ResettableRESTMapper.
+func (m *InformersMap) ResolveDeferred() {
+	if mapper, ok := m.structured.mapper.(apiutil.ResettableRESTMapper); ok {
+		mapper.Reset()
+	}
+	m.structured.resolveDeferred()
+	m.unstructured.resolveDeferred()
+}
+
 // Get will create a new Informer and add it to the map of InformersMap if none exists.  Returns
 // the Informer from the map.
 func (m *InformersMap) Get(gvk schema.GroupVersionKind, obj runtime.Object) (*MapEntry, error) {
@@ -85,12 +102,35 @@ func (m *InformersMap) Get(gvk schema.GroupVersionKind, obj runtime.Object) (*Ma
 	return m.structured.Get(gvk, obj)
 }
 
+// Remove stops the Informer for gvk and obj and removes it from the map of InformersMap, so that
+// the next Get creates a new Informer.  Returns false if there was no Informer for gvk and obj.
+func (m *InformersMap) Remove(gvk schema.GroupVersionKind, obj runtime.Object) bool {
+	_, isUnstructured := obj.(*unstructured.Unstructured)
+	_, isUnstructuredList := obj.(*unstructured.UnstructuredList)
+	isUnstructured = isUnstructured || isUnstructuredList
+
+	if isUnstructured {
+		return m.unstructured.Remove(gvk)
+	}
+
+	return m.structured.Remove(gvk)
+}
+
+// Lookup returns the Informer for gvk from the map of InformersMap, preferring the structured
+// Informer if both a structured and an unstructured Informer exist.  It never creates an Informer.
+func (m *InformersMap) Lookup(gvk schema.GroupVersionKind) (*MapEntry, bool) {
+	if i, ok := m.structured.Lookup(gvk); ok {
+		return i, true
+	}
+	return m.unstructured.Lookup(gvk)
+}
+
 // newStructuredInformersMap creates a new InformersMap for structured objects.
-func newStructuredInformersMap(config *rest.Config, scheme *runtime.Scheme, mapper meta.RESTMapper, resync time.Duration, namespace string) *specificInformersMap {
-	return newSpecificInformersMap(config, scheme, mapper, resync, namespace, createStructuredListWatch)
+func newStructuredInformersMap(config *rest.Config, scheme *runtime.Scheme, mapper meta.RESTMapper, resync time.Duration, namespace string, deferUnknownKinds bool, selectors SelectorsByGVK, transforms TransformFuncsByGVK) *specificInformersMap {
+	return newSpecificInformersMap(config, scheme, mapper, resync, namespace, deferUnknownKinds, selectors, transforms, createStructuredListWatch)
 }
 
 // newUnstructuredInformersMap creates a new InformersMap for unstructured objects.
-func newUnstructuredInformersMap(config *rest.Config, scheme *runtime.Scheme, mapper meta.RESTMapper, resync time.Duration, namespace string) *specificInformersMap {
-	return newSpecificInformersMap(config, scheme, mapper, resync, namespace, createUnstructuredListWatch)
+func newUnstructuredInformersMap(config *rest.Config, scheme *runtime.Scheme, mapper meta.RESTMapper, resync time.Duration, namespace string, deferUnknownKinds bool, selectors SelectorsByGVK, transforms TransformFuncsByGVK) *specificInformersMap {
+	return newSpecificInformersMap(config, scheme, mapper, resync, namespace, deferUnknownKinds, selectors, transforms, createUnstructuredListWatch)
 }
@@ -44,6 +44,9 @@ func newSpecificInformersMap(config *rest.Config,
 	mapper meta.RESTMapper,
 	resync time.Duration,
 	namespace string,
+	deferUnknownKinds bool,
+	selectors SelectorsByGVK,
+	transforms TransformFuncsByGVK,
 	createListWatcher createListWatcherFunc) *specificInformersMap {
 	ip := &specificInformersMap{
 		config:            config,
@@ -55,6 +58,9 @@ func newSpecificInformersMap(config *rest.Config,
 		resync:            resync,
 		createListWatcher: createListWatcher,
 		namespace:         namespace,
+		deferUnknownKinds: deferUnknownKinds,
+		selectors:         selectors,
+		transforms:        transforms,
 	}
 	return ip
 }
@@ -66,6 +72,43 @@ type MapEntry struct {
 
 	// CacheReader wraps Informer and implements the CacheReader interface for a single type
 	Reader CacheReader
+
+	// deferred is set if the kind of the Informer was not served by the API server when the
+	// Informer was created.
+	deferred *deferredListWatch
+
+	// stop is closed to stop the Informer, either when the map is stopped or when the Informer
+	// is removed from the map.
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// run runs the Informer until either the stop channel of the map or of the entry is closed.
+func (e *MapEntry) run(stop <-chan struct{}) {
+	go func() {
+		select {
+		case <-stop:
+			e.close()
+		case <-e.stop:
+		}
+	}()
+	go e.Informer.Run(e.stop)
+}
+
+// close stops the Informer.  It is safe to call more than once.
+func (e *MapEntry) close() {
+	e.stopOnce.Do(func() { close(e.stop) })
+}
+
+// Deferred returns true if the Informer was deferred because its kind was not served by the API
+// server, and has not synced since.  err is the error discovering the kind while it is still not
+// served, or nil once the kind is discovered and the Informer is syncing.  The objects of deferred
+// Informers must not be read, since they are missing from the Informer until it has synced.
+func (e *MapEntry) Deferred() (deferred bool, err error) {
+	if e.deferred == nil || e.Informer.HasSynced() {
+		return false, nil
+	}
+	return true, e.deferred.Err()
 }
 
 // specificInformersMap create and caches Informers for (runtime.Object, schema.GroupVersionKind) pairs.
@@ -109,6 +152,16 @@ type specificInformersMap struct {
 	// namespace is the namespace that all ListWatches are restricted to
 	// default or empty string means all namespaces
 	namespace string
+
+	// deferUnknownKinds defers starting the informers of kinds which are not served by the
+	// API server until they are, instead of failing to create them.
+	deferUnknownKinds bool
+
+	// selectors restrict the objects of each kind which are cached
+	selectors SelectorsByGVK
+
+	// transforms transform the objects of each kind before they are cached
+	transforms TransformFuncsByGVK
 }
 
 // Start calls Run on each of the informers and sets started to true.  Blocks on the stop channel.
@@ -123,7 +176,7 @@ func (ip *specificInformersMap) Start(stop <-chan struct{}) {
 
 		// Start each informer
 		for _, informer := range ip.informersByGVK {
-			go informer.Informer.Run(stop)
+			informer.run(stop)
 		}
 
 		// Set started to true so we immediately start any informers added later.
@@ -132,17 +185,63 @@ func (ip *specificInformersMap) Start(stop <-chan struct{}) {
 	<-stop
 }
 
-// HasSyncedFuncs returns all the HasSynced functions for the informers in this map.
+// HasSyncedFuncs returns all the HasSynced functions for the informers in this map.  The informers
+// deferred because their kind was not served by the API server are left out, so that they don't
+// block waiting for the cache to sync, e.g. while the CustomResourceDefinition of their kind is
+// being installed.
 func (ip *specificInformersMap) HasSyncedFuncs() []cache.InformerSynced {
 	ip.mu.RLock()
 	defer ip.mu.RUnlock()
 	syncedFuncs := make([]cache.InformerSynced, 0, len(ip.informersByGVK))
 	for _, informer := range ip.informersByGVK {
+		if informer.deferred != nil {
+			continue
+		}
 		syncedFuncs = append(syncedFuncs, informer.Informer.HasSynced)
 	}
 	return syncedFuncs
 }
 
+// Lookup returns the Informer for gvk from the map of specificInformersMap, if one exists.
+func (ip *specificInformersMap) Lookup(gvk schema.GroupVersionKind) (*MapEntry, bool) {
+	ip.mu.RLock()
+	defer ip.mu.RUnlock()
+	i, ok := ip.informersByGVK[gvk]
+	return i, ok
+}
+
+// Remove stops the Informer for gvk and removes it from the map of specificInformersMap, if one
+// exists.  Returns false if there was no Informer for gvk.
+func (ip *specificInformersMap) Remove(gvk schema.GroupVersionKind) bool {
+	ip.mu.Lock()
+	defer ip.mu.Unlock()
+	i, ok := ip.informersByGVK[gvk]
+	if !ok {
+		return false
+	}
+	delete(ip.informersByGVK, gvk)
+	i.close()
+	return true
+}
+
+// resolveDeferred tries to discover the kinds of the deferred informers which are not discovered yet,
+// so that they start without waiting for the next periodic attempt.
+func (ip *specificInformersMap) resolveDeferred() {
+	ip.mu.RLock()
+	var deferred []*deferredListWatch
+	for _, i := range ip.informersByGVK {
+		if i.deferred != nil {
+			deferred = append(deferred, i.deferred)
+		}
+	}
+	ip.mu.RUnlock()
+
+	for _, d := range deferred {
+		// The error is kept by d, and returned by the reads of the kind
+		_ = d.resolveNow()
+	}
+}
+
 // Get will create a new Informer and add it to the map of specificInformersMap if none exists.  Returns
 // the Informer from the map.
 func (ip *specificInformersMap) Get(gvk schema.GroupVersionKind, obj runtime.Object) (*MapEntry, error) {
@@ -175,16 +274,24 @@ func (ip *specificInformersMap) Get(gvk schema.GroupVersionKind, obj runtime.Obj
 
 		// Create a NewSharedIndexInformer and add it to the map.
 		var lw *cache.ListWatch
+		var deferred *deferredListWatch
 		lw, err := ip.createListWatcher(gvk, ip)
+		if err != nil && ip.deferUnknownKinds && meta.IsNoMatchError(err) {
+			deferred = newDeferredListWatch(gvk, ip, err)
+			lw, err = deferred.ListWatch(), nil
+		}
 		if err != nil {
 			return nil, err
 		}
+		lw = withTransform(lw, ip.transforms.forGVK(gvk))
 		ni := cache.NewSharedIndexInformer(lw, obj, ip.resync, cache.Indexers{
 			cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
 		})
 		i = &MapEntry{
 			Informer: ni,
 			Reader:   CacheReader{indexer: ni.GetIndexer(), groupVersionKind: gvk},
+			deferred: deferred,
+			stop:     make(chan struct{}),
 		}
 		ip.informersByGVK[gvk] = i
 
@@ -192,8 +299,9 @@ func (ip *specificInformersMap) Get(gvk schema.GroupVersionKind, obj runtime.Obj
 		// TODO(seans): write thorough tests and document what happens here - can you add indexers?
 		// can you add eventhandlers?
 		if ip.started {
-			sync = true
-			go i.Informer.Run(ip.stop)
+			// Deferred Informers can't sync until their kind is served, so don't wait for them.
+			sync = deferred == nil
+			i.run(ip.stop)
 		}
 		return i, nil
 	}()
@@ -203,8 +311,15 @@ func (ip *specificInformersMap) Get(gvk schema.GroupVersionKind, obj runtime.Obj
 
 	if sync {
 		// Wait for it to sync before returning the Informer so that folks don't read from a stale cache.
-		if !cache.WaitForCacheSync(ip.stop, i.Informer.HasSynced) {
-			return nil, fmt.Errorf("failed waiting for %T Informer to sync", obj)
+		// The Informer is stopped both when the map is stopped and when it is removed, so wait on
+		// its own stop channel to be released if it is removed before it syncs.
+		if !cache.WaitForCacheSync(i.stop, i.Informer.HasSynced) {
+			select {
+			case <-ip.stop:
+				return nil, fmt.Errorf("failed waiting for %T Informer to sync", obj)
+			default:
+				return nil, fmt.Errorf("%T Informer was removed before it synced", obj)
+			}
 		}
 	}
 
@@ -233,6 +348,7 @@ func createStructuredListWatch(gvk schema.GroupVersionKind, ip *specificInformer
 	// Create a new ListWatch for the obj
 	return &cache.ListWatch{
 		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			ip.selectors.forGVK(gvk).ApplyToList(&opts)
 			res := listObj.DeepCopyObject()
 			isNamespaceScoped := ip.namespace != "" && mapping.Scope.Name() != meta.RESTScopeNameRoot
 			err := client.Get().NamespaceIfScoped(ip.namespace, isNamespaceScoped).Resource(mapping.Resource.Resource).VersionedParams(&opts, ip.paramCodec).Do().Into(res)
@@ -240,6 +356,7 @@ func createStructuredListWatch(gvk schema.GroupVersionKind, ip *specificInformer
 		},
 		// Setup the watch function
 		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			ip.selectors.forGVK(gvk).ApplyToList(&opts)
 			// Watch needs to be set to true separately
 			opts.Watch = true
 			isNamespaceScoped := ip.namespace != "" && mapping.Scope.Name() != meta.RESTScopeNameRoot
@@ -263,6 +380,7 @@ func createUnstructuredListWatch(gvk schema.GroupVersionKind, ip *specificInform
 	// Create a new ListWatch for the obj
 	return &cache.ListWatch{
 		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			ip.selectors.forGVK(gvk).ApplyToList(&opts)
 			if ip.namespace != "" && mapping.Scope.Name() != meta.RESTScopeNameRoot {
 				return dynamicClient.Resource(mapping.Resource).Namespace(ip.namespace).List(opts)
 			}
@@ -270,6 +388,7 @@ func createUnstructuredListWatch(gvk schema.GroupVersionKind, ip *specificInform
 		},
 		// Setup the watch function
 		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			ip.selectors.forGVK(gvk).ApplyToList(&opts)
 			// Watch needs to be set to true separately
 			opts.Watch = true
 			if ip.namespace != "" && mapping.Scope.Name() != meta.RESTScopeNameRoot {
@@ -0,0 +1,60 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// The values of the reason label of ConsistencyDivergences.
+const (
+	// ReasonDeleted is recorded for cached objects which no longer exist in the API server.
+	ReasonDeleted = "deleted"
+
+	// ReasonMissing is recorded for objects which are no longer in the cache but still exist
+	// in the API server.
+	ReasonMissing = "missing"
+
+	// ReasonResourceVersion is recorded for cached objects whose resourceVersion differs
+	// from the resourceVersion of the object in the API server.
+	ReasonResourceVersion = "resource_version"
+)
+
+var (
+	// ConsistencyChecks is a prometheus counter metrics which holds the total
+	// number of cached objects compared against the API server per kind
+	ConsistencyChecks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_cache_consistency_checks_total",
+		Help: "Total number of cached objects compared against the API server per kind",
+	}, []string{"kind"})
+
+	// ConsistencyDivergences is a prometheus counter metrics which holds the total
+	// number of cached objects which diverged from the API server per kind and
+	// reason, i.e. deleted, missing or resource_version
+	ConsistencyDivergences = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_cache_consistency_divergences_total",
+		Help: "Total number of cached objects which diverged from the API server per kind and reason",
+	}, []string{"kind", "reason"})
+)
+
+func init() {
+	metrics.AddDefaultViews(
+		metrics.View{Collector: ConsistencyChecks},
+		metrics.View{Collector: ConsistencyDivergences},
+	)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// TransformFunc transforms the objects of an informer before they are stored.
+type TransformFunc func(runtime.Object) (runtime.Object, error)
+
+// TransformFuncsByGVK associate a GroupVersionKind to the TransformFunc applied to its objects,
+// with a Default applied to the objects of the other kinds.
+type TransformFuncsByGVK struct {
+	Default TransformFunc
+	ByGVK   map[schema.GroupVersionKind]TransformFunc
+}
+
+// forGVK returns the TransformFunc for gvk, which is nil if the objects of gvk are not transformed.
+func (t TransformFuncsByGVK) forGVK(gvk schema.GroupVersionKind) TransformFunc {
+	if transform, ok := t.ByGVK[gvk]; ok {
+		return transform
+	}
+	return t.Default
+}
+
+// withTransform returns a ListWatch which transforms the objects listed and watched through lw
+// with transform.  It returns lw if transform is nil.
+func withTransform(lw *cache.ListWatch, transform TransformFunc) *cache.ListWatch {
+	if transform == nil {
+		return lw
+	}
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			list, err := lw.ListFunc(opts)
+			if err != nil {
+				return nil, err
+			}
+			items, err := meta.ExtractList(list)
+			if err != nil {
+				return nil, err
+			}
+			for i := range items {
+				if items[i], err = transform(items[i]); err != nil {
+					return nil, err
+				}
+			}
+			if err := meta.SetList(list, items); err != nil {
+				return nil, err
+			}
+			return list, nil
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			w, err := lw.WatchFunc(opts)
+			if err != nil {
+				return nil, err
+			}
+			return watch.Filter(w, func(event watch.Event) (watch.Event, bool) {
+				if event.Type == watch.Error {
+					return event, true
+				}
+				obj, err := transform(event.Object)
+				if err != nil {
+					// Restart the watch, like for any other error decoding the events
+					status := errors.NewInternalError(err).ErrStatus
+					return watch.Event{Type: watch.Error, Object: &status}, true
+				}
+				event.Object = obj
+				return event, true
+			}), nil
+		},
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TransformFunc transforms an object before it is stored in the cache, e.g. to drop large fields
+// which are never read by the operator and reduce the memory used by the cache.  It may modify
+// obj in place.  The objects read from the cache are the transformed objects, so the fields
+// dropped by a TransformFunc are also dropped by Updates of objects read from the cache.
+type TransformFunc func(obj runtime.Object) (runtime.Object, error)
+
+// TransformStripManagedFields returns a TransformFunc removing metadata.managedFields from objects.
+// See client.StripManagedFields.
+func TransformStripManagedFields() TransformFunc {
+	return func(obj runtime.Object) (runtime.Object, error) {
+		client.StripManagedFields(obj)
+		return obj, nil
+	}
+}
+
+// TransformStripAnnotations returns a TransformFunc removing the annotations with the given keys
+// from objects, such as the corev1.LastAppliedConfigAnnotation written by kubectl apply.
+func TransformStripAnnotations(keys ...string) TransformFunc {
+	return func(obj runtime.Object) (runtime.Object, error) {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+		annotations := accessor.GetAnnotations()
+		if len(annotations) == 0 {
+			return obj, nil
+		}
+		for _, key := range keys {
+			delete(annotations, key)
+		}
+		accessor.SetAnnotations(annotations)
+		return obj, nil
+	}
+}
+
+// TransformChain returns a TransformFunc applying each of transforms in turn.
+func TransformChain(transforms ...TransformFunc) TransformFunc {
+	return func(obj runtime.Object) (runtime.Object, error) {
+		for _, transform := range transforms {
+			var err error
+			if obj, err = transform(obj); err != nil {
+				return nil, err
+			}
+		}
+		return obj, nil
+	}
+}
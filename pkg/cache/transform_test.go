@@ -0,0 +1,73 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+var _ = Describe("Transforms", func() {
+	It("should strip the managed fields of unstructured objects", func() {
+		u := &unstructured.Unstructured{}
+		u.SetName("foo")
+		Expect(unstructured.SetNestedSlice(u.Object, []interface{}{"field"}, "metadata", "managedFields")).To(Succeed())
+
+		obj, err := cache.TransformStripManagedFields()(u)
+		Expect(err).NotTo(HaveOccurred())
+		_, found, err := unstructured.NestedSlice(obj.(*unstructured.Unstructured).Object, "metadata", "managedFields")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	It("should strip annotations", func() {
+		pod := &kcorev1.Pod{ObjectMeta: kmetav1.ObjectMeta{Annotations: map[string]string{
+			kcorev1.LastAppliedConfigAnnotation: "{}",
+			"keep":                              "me",
+		}}}
+
+		obj, err := cache.TransformStripAnnotations(kcorev1.LastAppliedConfigAnnotation)(pod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(obj.(*kcorev1.Pod).Annotations).To(Equal(map[string]string{"keep": "me"}))
+
+		obj, err = cache.TransformStripAnnotations("keep")(&kcorev1.Pod{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(obj.(*kcorev1.Pod).Annotations).To(BeEmpty())
+	})
+
+	It("should chain transforms and stop at the first error", func() {
+		var calls []string
+		record := func(name string, err error) cache.TransformFunc {
+			return func(obj runtime.Object) (runtime.Object, error) {
+				calls = append(calls, name)
+				return obj, err
+			}
+		}
+
+		_, err := cache.TransformChain(record("first", nil), record("second", fmt.Errorf("failed")), record("third", nil))(&kcorev1.Pod{})
+		Expect(err).To(MatchError("failed"))
+		Expect(calls).To(Equal([]string{"first", "second"}))
+	})
+})
@@ -0,0 +1,119 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certwatcher_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/util/cert"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+)
+
+var _ = Describe("CertWatcher", func() {
+	var dir, certPath, keyPath string
+
+	writeCert := func(commonName string) {
+		certPEM, keyPEM, err := cert.GenerateSelfSignedCertKey(commonName, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ioutil.WriteFile(certPath, certPEM, 0600)).To(Succeed())
+		Expect(ioutil.WriteFile(keyPath, keyPEM, 0600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "certwatcher")
+		Expect(err).NotTo(HaveOccurred())
+		certPath = path.Join(dir, "tls.crt")
+		keyPath = path.Join(dir, "tls.key")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("should return an error if the certificate cannot be read", func() {
+		_, err := certwatcher.New(certPath, keyPath)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should serve the current certificate", func() {
+		writeCert("foo.default.svc")
+		watcher, err := certwatcher.New(certPath, keyPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		current, err := watcher.GetCertificate(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(current).NotTo(BeNil())
+	})
+
+	It("should serve the certificate read after rotation", func() {
+		writeCert("foo.default.svc")
+		watcher, err := certwatcher.New(certPath, keyPath)
+		Expect(err).NotTo(HaveOccurred())
+		first, err := watcher.GetCertificate(nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		writeCert("bar.default.svc")
+		Expect(watcher.ReadCertificate()).To(Succeed())
+		second, err := watcher.GetCertificate(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second.Certificate).NotTo(Equal(first.Certificate))
+	})
+
+	It("should keep serving the current certificate if it cannot be read", func() {
+		writeCert("foo.default.svc")
+		watcher, err := certwatcher.New(certPath, keyPath)
+		Expect(err).NotTo(HaveOccurred())
+		first, err := watcher.GetCertificate(nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.Remove(keyPath)).To(Succeed())
+		Expect(watcher.ReadCertificate()).NotTo(Succeed())
+		current, err := watcher.GetCertificate(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(current).To(BeIdenticalTo(first))
+	})
+
+	It("should reload the certificate when the files change", func(done Done) {
+		writeCert("foo.default.svc")
+		watcher, err := certwatcher.New(certPath, keyPath)
+		Expect(err).NotTo(HaveOccurred())
+		first, err := watcher.GetCertificate(nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		stop := make(chan struct{})
+		defer close(stop)
+		go func() {
+			defer GinkgoRecover()
+			Expect(watcher.Start(stop)).To(Succeed())
+		}()
+
+		// Keep rotating the certificate, since the watch may not be established immediately.
+		Eventually(func() [][]byte {
+			writeCert("bar.default.svc")
+			current, err := watcher.GetCertificate(nil)
+			Expect(err).NotTo(HaveOccurred())
+			return current.Certificate
+		}, 5, 0.5).ShouldNot(Equal(first.Certificate))
+
+		close(done)
+	}, 10)
+})
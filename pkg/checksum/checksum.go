@@ -0,0 +1,203 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checksum
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Annotation is the pod template annotation Stamp records the checksum in.
+const Annotation = "checksum.controller-runtime.sigs.k8s.io/config"
+
+// ConfigMap returns a checksum of the data of cm.
+func ConfigMap(cm *corev1.ConfigMap) string {
+	h := sha256.New()
+	writeStrings(h, cm.Data)
+	io.WriteString(h, "\n")
+	writeBytes(h, cm.BinaryData)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Secret returns a checksum of the data of s.
+func Secret(s *corev1.Secret) string {
+	h := sha256.New()
+	writeStrings(h, s.StringData)
+	io.WriteString(h, "\n")
+	writeBytes(h, s.Data)
+	io.WriteString(h, "\n")
+	io.WriteString(h, string(s.Type))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ReferencedConfigMaps returns the sorted names of the ConfigMaps referenced by
+// the volumes and containers of spec.
+func ReferencedConfigMaps(spec *corev1.PodSpec) []string {
+	names := map[string]struct{}{}
+	for _, v := range spec.Volumes {
+		if v.ConfigMap != nil {
+			names[v.ConfigMap.Name] = struct{}{}
+		}
+		if v.Projected != nil {
+			for _, src := range v.Projected.Sources {
+				if src.ConfigMap != nil {
+					names[src.ConfigMap.Name] = struct{}{}
+				}
+			}
+		}
+	}
+	for _, c := range containers(spec) {
+		for _, from := range c.EnvFrom {
+			if from.ConfigMapRef != nil {
+				names[from.ConfigMapRef.Name] = struct{}{}
+			}
+		}
+		for _, env := range c.Env {
+			if env.ValueFrom != nil && env.ValueFrom.ConfigMapKeyRef != nil {
+				names[env.ValueFrom.ConfigMapKeyRef.Name] = struct{}{}
+			}
+		}
+	}
+	return sortedKeys(names)
+}
+
+// ReferencedSecrets returns the sorted names of the Secrets referenced by the
+// volumes and containers of spec.  Image pull secrets are not included, since
+// changing them does not require the pods to be restarted.
+func ReferencedSecrets(spec *corev1.PodSpec) []string {
+	names := map[string]struct{}{}
+	for _, v := range spec.Volumes {
+		if v.Secret != nil {
+			names[v.Secret.SecretName] = struct{}{}
+		}
+		if v.Projected != nil {
+			for _, src := range v.Projected.Sources {
+				if src.Secret != nil {
+					names[src.Secret.Name] = struct{}{}
+				}
+			}
+		}
+	}
+	for _, c := range containers(spec) {
+		for _, from := range c.EnvFrom {
+			if from.SecretRef != nil {
+				names[from.SecretRef.Name] = struct{}{}
+			}
+		}
+		for _, env := range c.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+				names[env.ValueFrom.SecretKeyRef.Name] = struct{}{}
+			}
+		}
+	}
+	return sortedKeys(names)
+}
+
+// Compute reads the ConfigMaps and Secrets in namespace referenced by spec and
+// returns a checksum of their contents.  References to objects that do not exist,
+// e.g. optional ones, are included in the checksum as missing, so that the checksum
+// changes when they are created.
+func Compute(ctx context.Context, c client.Reader, namespace string, spec *corev1.PodSpec) (string, error) {
+	h := sha256.New()
+	for _, name := range ReferencedConfigMaps(spec) {
+		cm := &corev1.ConfigMap{}
+		sum, err := read(ctx, c, client.ObjectKey{Namespace: namespace, Name: name}, cm, func() string { return ConfigMap(cm) })
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "configmap/%s=%s\n", name, sum)
+	}
+	for _, name := range ReferencedSecrets(spec) {
+		s := &corev1.Secret{}
+		sum, err := read(ctx, c, client.ObjectKey{Namespace: namespace, Name: name}, s, func() string { return Secret(s) })
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "secret/%s=%s\n", name, sum)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Stamp sets the Annotation of tmpl to the checksum of the ConfigMaps and Secrets in
+// namespace it references, as returned by Compute.
+func Stamp(ctx context.Context, c client.Reader, namespace string, tmpl *corev1.PodTemplateSpec) error {
+	sum, err := Compute(ctx, c, namespace, &tmpl.Spec)
+	if err != nil {
+		return err
+	}
+	if tmpl.Annotations == nil {
+		tmpl.Annotations = map[string]string{}
+	}
+	tmpl.Annotations[Annotation] = sum
+	return nil
+}
+
+// read reads key into obj and returns sum of it, or "missing" if it does not exist.
+func read(ctx context.Context, c client.Reader, key client.ObjectKey, obj runtime.Object, sum func() string) (string, error) {
+	if err := c.Get(ctx, key, obj); err != nil {
+		if errors.IsNotFound(err) {
+			return "missing", nil
+		}
+		return "", err
+	}
+	return sum(), nil
+}
+
+func containers(spec *corev1.PodSpec) []corev1.Container {
+	return append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+}
+
+func writeStrings(w io.Writer, data map[string]string) {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%d:%s%d:%s", len(k), k, len(data[k]), data[k])
+	}
+}
+
+func writeBytes(w io.Writer, data map[string][]byte) {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%d:%s%d:", len(k), k, len(data[k]))
+		w.Write(data[k])
+	}
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checksum_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/checksum"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Checksum", func() {
+	var (
+		cm     *corev1.ConfigMap
+		secret *corev1.Secret
+		tmpl   *corev1.PodTemplateSpec
+		c      client.Client
+	)
+
+	BeforeEach(func() {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "config"},
+			Data:       map[string]string{"key": "value"},
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "creds"},
+			Data:       map[string][]byte{"password": []byte("hunter2")},
+		}
+		tmpl = &corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{{
+					Name: "config",
+					VolumeSource: corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "config"},
+						},
+					},
+				}},
+				Containers: []corev1.Container{{
+					Name: "app",
+					Env: []corev1.EnvVar{{
+						Name: "PASSWORD",
+						ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "creds"},
+							Key:                  "password",
+						}},
+					}},
+					EnvFrom: []corev1.EnvFromSource{{
+						ConfigMapRef: &corev1.ConfigMapEnvSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "optional"},
+						},
+					}},
+				}},
+			},
+		}
+		c = fake.NewFakeClient(cm, secret)
+	})
+
+	Describe("ConfigMap and Secret", func() {
+		It("should return the same checksum for the same data", func() {
+			Expect(checksum.ConfigMap(cm)).To(Equal(checksum.ConfigMap(cm.DeepCopy())))
+			Expect(checksum.Secret(secret)).To(Equal(checksum.Secret(secret.DeepCopy())))
+		})
+
+		It("should return a different checksum when the data changes", func() {
+			changed := cm.DeepCopy()
+			changed.Data["key"] = "other"
+			Expect(checksum.ConfigMap(changed)).NotTo(Equal(checksum.ConfigMap(cm)))
+
+			changedSecret := secret.DeepCopy()
+			changedSecret.Data["password"] = []byte("hunter3")
+			Expect(checksum.Secret(changedSecret)).NotTo(Equal(checksum.Secret(secret)))
+		})
+
+		It("should not be affected by the metadata", func() {
+			changed := cm.DeepCopy()
+			changed.Labels = map[string]string{"foo": "bar"}
+			Expect(checksum.ConfigMap(changed)).To(Equal(checksum.ConfigMap(cm)))
+		})
+	})
+
+	Describe("Referenced", func() {
+		It("should return the ConfigMaps and Secrets referenced by a pod spec", func() {
+			Expect(checksum.ReferencedConfigMaps(&tmpl.Spec)).To(Equal([]string{"config", "optional"}))
+			Expect(checksum.ReferencedSecrets(&tmpl.Spec)).To(Equal([]string{"creds"}))
+		})
+	})
+
+	Describe("Stamp", func() {
+		It("should annotate the template with a checksum of the referenced objects", func() {
+			Expect(checksum.Stamp(context.TODO(), c, "default", tmpl)).To(Succeed())
+			Expect(tmpl.Annotations).To(HaveKey(checksum.Annotation))
+			sum := tmpl.Annotations[checksum.Annotation]
+
+			By("keeping the checksum when nothing changed")
+			Expect(checksum.Stamp(context.TODO(), c, "default", tmpl)).To(Succeed())
+			Expect(tmpl.Annotations[checksum.Annotation]).To(Equal(sum))
+
+			By("changing the checksum when a referenced Secret changes")
+			secret.Data["password"] = []byte("hunter3")
+			Expect(c.Update(context.TODO(), secret)).To(Succeed())
+			Expect(checksum.Stamp(context.TODO(), c, "default", tmpl)).To(Succeed())
+			Expect(tmpl.Annotations[checksum.Annotation]).NotTo(Equal(sum))
+			sum = tmpl.Annotations[checksum.Annotation]
+
+			By("changing the checksum when a missing ConfigMap is created")
+			Expect(c.Create(context.TODO(), &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "optional"},
+			})).To(Succeed())
+			Expect(checksum.Stamp(context.TODO(), c, "default", tmpl)).To(Succeed())
+			Expect(tmpl.Annotations[checksum.Annotation]).NotTo(Equal(sum))
+		})
+	})
+
+	Describe("ReferencingMapper", func() {
+		It("should map a ConfigMap or Secret to the workloads referencing it", func() {
+			Expect(c.Create(context.TODO(), &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+				Spec:       appsv1.DeploymentSpec{Template: *tmpl},
+			})).To(Succeed())
+			Expect(c.Create(context.TODO(), &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "unrelated"},
+			})).To(Succeed())
+
+			m := &checksum.ReferencingMapper{
+				Reader:  c,
+				NewList: func() runtime.Object { return &appsv1.DeploymentList{} },
+			}
+			expected := []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "default", Name: "app"}}}
+			Expect(m.Map(handler.MapObject{Meta: cm, Object: cm})).To(Equal(expected))
+			Expect(m.Map(handler.MapObject{Meta: secret, Object: secret})).To(Equal(expected))
+
+			other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"}}
+			Expect(m.Map(handler.MapObject{Meta: other, Object: other})).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,45 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package checksum implements the "restart on config change" pattern for workloads that
+consume ConfigMaps and Secrets.
+
+Stamp records a checksum of the contents of the ConfigMaps and Secrets referenced by a
+pod template as an annotation on the template, so that the pods are rolled whenever
+that content changes:
+
+	if err := checksum.Stamp(ctx, r.client, deploy.Namespace, &deploy.Spec.Template); err != nil {
+		return reconcile.Result{}, err
+	}
+
+ReferencingMapper maps ConfigMap and Secret events to the workloads that reference them,
+so that they are reconciled, and re-stamped, when their config changes:
+
+	err := c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: &checksum.ReferencingMapper{
+			Reader:  mgr.GetClient(),
+			NewList: func() runtime.Object { return &appsv1.DeploymentList{} },
+		},
+	})
+*/
+package checksum
+
+import (
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var log = logf.KBLog.WithName("checksum")
@@ -0,0 +1,130 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checksum
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ handler.Mapper = &ReferencingMapper{}
+
+// ReferencingMapper is a handler.Mapper that maps a ConfigMap or Secret to Requests for
+// the workloads in its namespace whose pod template references it.
+type ReferencingMapper struct {
+	// Reader is used to list the workloads.
+	Reader client.Reader
+
+	// NewList returns a new empty list of the workloads, e.g. &appsv1.DeploymentList{}.
+	NewList func() runtime.Object
+
+	// PodTemplate returns the pod template of a workload in the list.  If nil,
+	// PodTemplateOf is used.
+	PodTemplate func(runtime.Object) *corev1.PodTemplateSpec
+}
+
+// Map implements handler.Mapper
+func (m *ReferencingMapper) Map(obj handler.MapObject) []reconcile.Request {
+	var referenced func(*corev1.PodSpec) []string
+	switch obj.Object.(type) {
+	case *corev1.ConfigMap:
+		referenced = ReferencedConfigMaps
+	case *corev1.Secret:
+		referenced = ReferencedSecrets
+	default:
+		return nil
+	}
+
+	list := m.NewList()
+	if err := m.Reader.List(context.TODO(), list, client.InNamespace(obj.Meta.GetNamespace())); err != nil {
+		log.Error(err, "unable to list workloads referencing object",
+			"namespace", obj.Meta.GetNamespace(), "name", obj.Meta.GetName())
+		return nil
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		log.Error(err, "unable to extract workloads from list", "list", list)
+		return nil
+	}
+
+	podTemplate := m.PodTemplate
+	if podTemplate == nil {
+		podTemplate = PodTemplateOf
+	}
+	var reqs []reconcile.Request
+	for _, item := range items {
+		tmpl := podTemplate(item)
+		if tmpl == nil {
+			continue
+		}
+		if !contains(referenced(&tmpl.Spec), obj.Meta.GetName()) {
+			continue
+		}
+		accessor, err := meta.Accessor(item)
+		if err != nil {
+			log.Error(err, "unable to get metadata of workload", "workload", item)
+			continue
+		}
+		reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: accessor.GetNamespace(),
+			Name:      accessor.GetName(),
+		}})
+	}
+	return reqs
+}
+
+// PodTemplateOf returns the pod template of the built-in workload obj, or nil if obj is
+// not a Deployment, StatefulSet, DaemonSet, ReplicaSet, Job, ReplicationController or
+// PodTemplate.
+func PodTemplateOf(obj runtime.Object) *corev1.PodTemplateSpec {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return &o.Spec.Template
+	case *appsv1.StatefulSet:
+		return &o.Spec.Template
+	case *appsv1.DaemonSet:
+		return &o.Spec.Template
+	case *appsv1.ReplicaSet:
+		return &o.Spec.Template
+	case *batchv1.Job:
+		return &o.Spec.Template
+	case *corev1.ReplicationController:
+		return o.Spec.Template
+	case *corev1.PodTemplate:
+		return &o.Template
+	default:
+		return nil
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
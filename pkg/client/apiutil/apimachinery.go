@@ -25,19 +25,16 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/restmapper"
 )
 
 // NewDiscoveryRESTMapper constructs a new RESTMapper based on discovery
-// information fetched by a new client with the given config.
+// information fetched by a new client with the given config.  The mapper
+// is a ResettableRESTMapper, so that kinds added to the API server later
+// can be discovered.
 func NewDiscoveryRESTMapper(c *rest.Config) (meta.RESTMapper, error) {
 	// Get a mapper
 	dc := discovery.NewDiscoveryClientForConfigOrDie(c)
-	gr, err := restmapper.GetAPIGroupResources(dc)
-	if err != nil {
-		return nil, err
-	}
-	return restmapper.NewDiscoveryRESTMapper(gr), nil
+	return newDiscoveryRESTMapper(dc)
 }
 
 // GVKForObject finds the GroupVersionKind associated with the given object, if there is only a single such GVK.
@@ -65,8 +62,15 @@ func GVKForObject(obj runtime.Object, scheme *runtime.Scheme) (schema.GroupVersi
 // RESTClientForGVK constructs a new rest.Interface capable of accessing the resource associated
 // with the given GroupVersionKind.
 func RESTClientForGVK(gvk schema.GroupVersionKind, baseConfig *rest.Config, codecs serializer.CodecFactory) (rest.Interface, error) {
+	return RESTClientForGVKWithSerializer(gvk, baseConfig, serializer.DirectCodecFactory{CodecFactory: codecs})
+}
+
+// RESTClientForGVKWithSerializer is like RESTClientForGVK, except that the encoding of the objects
+// sent to and received from the API server is negotiated by the given serializer, which must support
+// the ContentType of baseConfig.
+func RESTClientForGVKWithSerializer(gvk schema.GroupVersionKind, baseConfig *rest.Config, s runtime.NegotiatedSerializer) (rest.Interface, error) {
 	cfg := createRestConfig(gvk, baseConfig)
-	cfg.NegotiatedSerializer = serializer.DirectCodecFactory{CodecFactory: codecs}
+	cfg.NegotiatedSerializer = s
 	return rest.RESTClientFor(cfg)
 }
 
@@ -0,0 +1,165 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiutil
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/restmapper"
+)
+
+// ResettableRESTMapper is a RESTMapper which can be reset to discover the kinds added to the
+// API server after it was created, such as the kinds of new CustomResourceDefinitions.
+type ResettableRESTMapper interface {
+	meta.RESTMapper
+
+	// Reset marks the discovered mappings stale, so that they are discovered again when next used.
+	// The stale mappings keep being used until the kinds are discovered successfully.
+	Reset()
+}
+
+var _ ResettableRESTMapper = &discoveryRESTMapper{}
+
+// discoveryRESTMapper is a RESTMapper based on discovery information, which is discovered
+// again after a Reset.  It keeps serving the mappings of the last successful discovery until
+// a new discovery succeeds, so that a failing discovery doesn't break the mappings of every kind.
+type discoveryRESTMapper struct {
+	client discovery.DiscoveryInterface
+
+	mu       sync.Mutex
+	delegate meta.RESTMapper
+	// resets counts the Resets, so that a discovery started before a Reset doesn't clear stale.
+	resets      int
+	stale       bool
+	discovering bool
+}
+
+// newDiscoveryRESTMapper returns a discoveryRESTMapper which has discovered the API group
+// resources served by client.
+func newDiscoveryRESTMapper(client discovery.DiscoveryInterface) (*discoveryRESTMapper, error) {
+	m := &discoveryRESTMapper{client: client, stale: true}
+	if _, err := m.getDelegate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// getDelegate returns the RESTMapper of the last discovery, discovering the API group
+// resources again if the mapper was reset.  The discovery runs without holding the lock, and
+// the callers concurrent with it are served the RESTMapper of the previous discovery.  If the
+// discovery fails, the previous RESTMapper keeps being served, and the next call discovers again.
+func (m *discoveryRESTMapper) getDelegate() (meta.RESTMapper, error) {
+	m.mu.Lock()
+	delegate, resets := m.delegate, m.resets
+	if !m.stale || (m.discovering && delegate != nil) {
+		m.mu.Unlock()
+		return delegate, nil
+	}
+	m.discovering = true
+	m.mu.Unlock()
+
+	gr, err := restmapper.GetAPIGroupResources(m.client)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.discovering = false
+	if err != nil {
+		if delegate == nil {
+			return nil, err
+		}
+		return delegate, nil
+	}
+	m.delegate = restmapper.NewDiscoveryRESTMapper(gr)
+	if m.resets == resets {
+		m.stale = false
+	}
+	return m.delegate, nil
+}
+
+// Reset implements ResettableRESTMapper
+func (m *discoveryRESTMapper) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resets++
+	m.stale = true
+}
+
+// KindFor implements meta.RESTMapper
+func (m *discoveryRESTMapper) KindFor(resource schema.GroupVersionResource) (schema.GroupVersionKind, error) {
+	delegate, err := m.getDelegate()
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	return delegate.KindFor(resource)
+}
+
+// KindsFor implements meta.RESTMapper
+func (m *discoveryRESTMapper) KindsFor(resource schema.GroupVersionResource) ([]schema.GroupVersionKind, error) {
+	delegate, err := m.getDelegate()
+	if err != nil {
+		return nil, err
+	}
+	return delegate.KindsFor(resource)
+}
+
+// ResourceFor implements meta.RESTMapper
+func (m *discoveryRESTMapper) ResourceFor(input schema.GroupVersionResource) (schema.GroupVersionResource, error) {
+	delegate, err := m.getDelegate()
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return delegate.ResourceFor(input)
+}
+
+// ResourcesFor implements meta.RESTMapper
+func (m *discoveryRESTMapper) ResourcesFor(input schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+	delegate, err := m.getDelegate()
+	if err != nil {
+		return nil, err
+	}
+	return delegate.ResourcesFor(input)
+}
+
+// RESTMapping implements meta.RESTMapper
+func (m *discoveryRESTMapper) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+	delegate, err := m.getDelegate()
+	if err != nil {
+		return nil, err
+	}
+	return delegate.RESTMapping(gk, versions...)
+}
+
+// RESTMappings implements meta.RESTMapper
+func (m *discoveryRESTMapper) RESTMappings(gk schema.GroupKind, versions ...string) ([]*meta.RESTMapping, error) {
+	delegate, err := m.getDelegate()
+	if err != nil {
+		return nil, err
+	}
+	return delegate.RESTMappings(gk, versions...)
+}
+
+// ResourceSingularizer implements meta.RESTMapper
+func (m *discoveryRESTMapper) ResourceSingularizer(resource string) (string, error) {
+	delegate, err := m.getDelegate()
+	if err != nil {
+		return "", err
+	}
+	return delegate.ResourceSingularizer(resource)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiutil
+
+import (
+	"errors"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+var _ = Describe("discoveryRESTMapper", func() {
+	var dc *fakeDiscovery
+	var m *discoveryRESTMapper
+
+	foos := metav1.APIResource{Name: "foos", Kind: "Foo", Namespaced: true}
+	bars := metav1.APIResource{Name: "bars", Kind: "Bar", Namespaced: true}
+	fooGK := schema.GroupKind{Group: "example.com", Kind: "Foo"}
+	barGK := schema.GroupKind{Group: "example.com", Kind: "Bar"}
+
+	BeforeEach(func() {
+		dc = &fakeDiscovery{resources: []metav1.APIResource{foos}}
+		var err error
+		m, err = newDiscoveryRESTMapper(dc)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should fail to be created if the kinds can't be discovered", func() {
+		_, err := newDiscoveryRESTMapper(&fakeDiscovery{err: errors.New("discovery failed")})
+		Expect(err).To(MatchError("discovery failed"))
+	})
+
+	It("should discover the kinds again when next used after a Reset", func() {
+		_, err := m.RESTMapping(barGK)
+		Expect(meta.IsNoMatchError(err)).To(BeTrue())
+
+		dc.setResources([]metav1.APIResource{foos, bars})
+		_, err = m.RESTMapping(barGK)
+		Expect(meta.IsNoMatchError(err)).To(BeTrue())
+
+		m.Reset()
+		mapping, err := m.RESTMapping(barGK)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mapping.Resource.Resource).To(Equal("bars"))
+	})
+
+	It("should keep serving the last discovered kinds until a discovery succeeds", func() {
+		dc.setErr(errors.New("discovery failed"))
+		m.Reset()
+		mapping, err := m.RESTMapping(fooGK)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mapping.Resource.Resource).To(Equal("foos"))
+
+		dc.setResources([]metav1.APIResource{foos, bars})
+		dc.setErr(nil)
+		mapping, err = m.RESTMapping(barGK)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mapping.Resource.Resource).To(Equal("bars"))
+	})
+})
+
+// fakeDiscovery serves the resources of the example.com/v1 group, or fails with err if set.
+type fakeDiscovery struct {
+	discovery.DiscoveryInterface
+
+	mu        sync.Mutex
+	resources []metav1.APIResource
+	err       error
+}
+
+func (d *fakeDiscovery) setResources(resources []metav1.APIResource) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.resources = resources
+}
+
+func (d *fakeDiscovery) setErr(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.err = err
+}
+
+func (d *fakeDiscovery) ServerGroups() (*metav1.APIGroupList, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err != nil {
+		return nil, d.err
+	}
+	version := metav1.GroupVersionForDiscovery{GroupVersion: "example.com/v1", Version: "v1"}
+	return &metav1.APIGroupList{Groups: []metav1.APIGroup{{
+		Name:             "example.com",
+		Versions:         []metav1.GroupVersionForDiscovery{version},
+		PreferredVersion: version,
+	}}}, nil
+}
+
+func (d *fakeDiscovery) ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return &metav1.APIResourceList{GroupVersion: groupVersion, APIResources: d.resources}, nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+// fieldManagerConflictCause is the type of the causes of an apply conflict, one per conflicting field.
+const fieldManagerConflictCause metav1.CauseType = "FieldManagerConflict"
+
+// FieldConflict is a field of an object applied with server-side apply which is owned by another
+// field manager.
+type FieldConflict struct {
+	// Manager is the field manager owning the field.
+	Manager string
+
+	// Field is the path of the field, e.g. ".spec.replicas".
+	Field string
+}
+
+// ConflictError is the error of a server-side apply conflicting with the fields owned by other field
+// managers.  The apierrors helpers, such as IsConflict, work on it as they do on the error it wraps.  The
+// fields owned by each field manager can be inspected further with GetManagedFields.
+type ConflictError struct {
+	// Conflicts are the conflicting fields.
+	Conflicts []FieldConflict
+
+	// Err is the error of the apply.
+	Err error
+
+	status apierrors.APIStatus
+}
+
+// Error implements error
+func (e *ConflictError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error of the apply.
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// Status implements apierrors.APIStatus
+func (e *ConflictError) Status() metav1.Status {
+	return e.status.Status()
+}
+
+// Managers returns the field managers owning the conflicting fields, without duplicates.
+func (e *ConflictError) Managers() []string {
+	var managers []string
+	seen := map[string]bool{}
+	for _, c := range e.Conflicts {
+		if !seen[c.Manager] {
+			seen[c.Manager] = true
+			managers = append(managers, c.Manager)
+		}
+	}
+	return managers
+}
+
+// AsConflictError returns the ConflictError of err if it is the error of a server-side apply conflicting
+// with the fields of other field managers.
+func AsConflictError(err error) (*ConflictError, bool) {
+	var status apierrors.APIStatus
+	for e := err; e != nil; e = unwrap(e) {
+		if conflictErr, ok := e.(*ConflictError); ok {
+			return conflictErr, true
+		}
+		if s, ok := e.(apierrors.APIStatus); ok && status == nil {
+			status = s
+		}
+	}
+	if status == nil || status.Status().Reason != metav1.StatusReasonConflict {
+		return nil, false
+	}
+	details := status.Status().Details
+	if details == nil {
+		return nil, false
+	}
+
+	conflictErr := &ConflictError{Err: err, status: status}
+	for _, cause := range details.Causes {
+		if cause.Type != fieldManagerConflictCause {
+			continue
+		}
+		// The message of the cause is `conflict with "<manager>"`, possibly followed by the apiVersion
+		// the field was set with
+		manager := cause.Message
+		if parts := strings.SplitN(cause.Message, `"`, 3); len(parts) == 3 {
+			manager = parts[1]
+		}
+		conflictErr.Conflicts = append(conflictErr.Conflicts, FieldConflict{Manager: manager, Field: cause.Field})
+	}
+	if len(conflictErr.Conflicts) == 0 {
+		return nil, false
+	}
+	return conflictErr, true
+}
+
+// ConflictStrategy is how ApplyWithStrategy handles the conflicts of a server-side apply with the fields
+// owned by other field managers.  The zero ConflictStrategy returns a ConflictError listing them, so that
+// the caller can decide what to do instead of blanket forcing.
+type ConflictStrategy struct {
+	// Backoff, if set, retries the apply while it conflicts, with backoff between the attempts, for
+	// conflicts expected to be resolved by the other field managers, e.g. while they migrate off the
+	// fields.
+	Backoff *wait.Backoff
+
+	// Force takes the ownership of the conflicting fields from the other field managers, once the
+	// attempts are exhausted if Backoff is set.
+	Force bool
+}
+
+// ApplyWithStrategy patches obj with server-side apply and opts, and handles the conflicts with the
+// fields owned by other field managers according to strategy.  The conflicts which are not resolved are
+// returned as a ConflictError.
+func ApplyWithStrategy(ctx context.Context, c Writer, obj runtime.Object, strategy ConflictStrategy, opts ...PatchOptionFunc) error {
+	apply := func() error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := c.Patch(ctx, obj, Apply, opts...)
+		if conflictErr, ok := AsConflictError(err); ok {
+			return conflictErr
+		}
+		return err
+	}
+
+	err := apply()
+	if _, ok := err.(*ConflictError); ok && strategy.Backoff != nil {
+		err = retry.RetryOnConflict(*strategy.Backoff, apply)
+	}
+	if _, ok := err.(*ConflictError); ok && strategy.Force {
+		return c.Patch(ctx, obj, Apply, append(opts, ForceOwnership)...)
+	}
+	return err
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// applyConflictingClient fails the first conflicts applies which are not forced with an apply conflict.
+type applyConflictingClient struct {
+	client.Client
+	conflicts int
+	applies   int
+	forced    bool
+}
+
+func (c *applyConflictingClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOptionFunc) error {
+	c.applies++
+	patchOpts := (&client.PatchOptions{}).ApplyOptions(opts)
+	c.forced = patchOpts.Force != nil && *patchOpts.Force
+	if !c.forced && c.applies <= c.conflicts {
+		return &apierrors.StatusError{ErrStatus: metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    409,
+			Reason:  metav1.StatusReasonConflict,
+			Message: `Apply failed with 2 conflicts: conflict with "kubectl" using v1: .data.a`,
+			Details: &metav1.StatusDetails{Causes: []metav1.StatusCause{
+				{Type: "FieldManagerConflict", Message: `conflict with "kubectl" using v1`, Field: ".data.a"},
+				{Type: "FieldManagerConflict", Message: `conflict with "kubectl" using v1`, Field: ".data.b"},
+			}},
+		}}
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+var _ = Describe("ApplyWithStrategy", func() {
+	var cl *applyConflictingClient
+	var cm *corev1.ConfigMap
+	backoff := &wait.Backoff{Steps: 3, Duration: time.Millisecond}
+
+	BeforeEach(func() {
+		cl = &applyConflictingClient{Client: fake.NewFakeClient()}
+		cm = &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "a"},
+			Data:       map[string]string{"a": "1", "b": "2"},
+		}
+	})
+
+	It("should return a ConflictError listing the conflicts by default", func() {
+		cl.conflicts = 1
+		err := client.ApplyWithStrategy(context.TODO(), cl, cm, client.ConflictStrategy{}, client.FieldOwner("test"))
+		Expect(apierrors.IsConflict(err)).To(BeTrue())
+		conflictErr, ok := client.AsConflictError(err)
+		Expect(ok).To(BeTrue())
+		Expect(conflictErr.Conflicts).To(Equal([]client.FieldConflict{
+			{Manager: "kubectl", Field: ".data.a"},
+			{Manager: "kubectl", Field: ".data.b"},
+		}))
+		Expect(conflictErr.Managers()).To(Equal([]string{"kubectl"}))
+		Expect(cl.applies).To(Equal(1))
+	})
+
+	It("should force the ownership of the conflicting fields with Force", func() {
+		cl.conflicts = 1
+		Expect(client.ApplyWithStrategy(context.TODO(), cl, cm, client.ConflictStrategy{Force: true})).To(Succeed())
+		Expect(cl.applies).To(Equal(2))
+		Expect(cl.forced).To(BeTrue())
+	})
+
+	It("should retry the conflicting applies with Backoff", func() {
+		cl.conflicts = 2
+		Expect(client.ApplyWithStrategy(context.TODO(), cl, cm, client.ConflictStrategy{Backoff: backoff})).To(Succeed())
+		Expect(cl.applies).To(Equal(3))
+		Expect(cl.forced).To(BeFalse())
+	})
+
+	It("should return the ConflictError once the attempts are exhausted", func() {
+		cl.conflicts = 10
+		err := client.ApplyWithStrategy(context.TODO(), cl, cm, client.ConflictStrategy{Backoff: backoff})
+		_, ok := client.AsConflictError(err)
+		Expect(ok).To(BeTrue())
+		Expect(cl.applies).To(Equal(4))
+	})
+
+	It("should force once the attempts are exhausted with Backoff and Force", func() {
+		cl.conflicts = 10
+		Expect(client.ApplyWithStrategy(context.TODO(), cl, cm, client.ConflictStrategy{Backoff: backoff, Force: true})).To(Succeed())
+		Expect(cl.applies).To(Equal(5))
+		Expect(cl.forced).To(BeTrue())
+	})
+
+	It("should not take other conflicts for apply conflicts", func() {
+		err := apierrors.NewConflict(corev1.Resource("configmaps"), "a", context.Canceled)
+		_, ok := client.AsConflictError(err)
+		Expect(ok).To(BeFalse())
+	})
+})
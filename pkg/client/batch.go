@@ -0,0 +1,78 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// DefaultBatchParallelism is the number of concurrent requests made by BatchGet if
+// no parallelism is specified.
+const DefaultBatchParallelism = 10
+
+// BatchGet retrieves objs[i] for keys[i] from c, making at most parallelism requests
+// concurrently.  It is meant for reconcilers which need to resolve many referenced
+// objects; with a cache-backed Reader the requests are cheap, but with a live Reader
+// they would otherwise be made one at a time.
+//
+// The keys of objects which do not exist are returned in notFound rather than as an error,
+// and the remaining errors are returned as an aggregate.  If parallelism is not positive,
+// DefaultBatchParallelism is used.
+func BatchGet(ctx context.Context, c Reader, keys []ObjectKey, objs []runtime.Object, parallelism int) (notFound []ObjectKey, err error) {
+	if len(keys) != len(objs) {
+		return nil, fmt.Errorf("got %d keys but %d objects", len(keys), len(objs))
+	}
+	if parallelism <= 0 {
+		parallelism = DefaultBatchParallelism
+	}
+
+	var mu sync.Mutex
+	var errs []error
+	missing := make([]bool, len(keys))
+	workqueue.ParallelizeUntil(ctx, parallelism, len(keys), func(i int) {
+		err := c.Get(ctx, keys[i], objs[i])
+		if err == nil {
+			return
+		}
+		if apierrors.IsNotFound(err) {
+			missing[i] = true
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, fmt.Errorf("unable to get %v: %v", keys[i], err))
+	})
+
+	// ParallelizeUntil stops handing out keys once ctx is done
+	if ctx.Err() != nil {
+		errs = append(errs, ctx.Err())
+	}
+
+	for i, m := range missing {
+		if m {
+			notFound = append(notFound, keys[i])
+		}
+	}
+	return notFound, utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("BatchGet", func() {
+	var cl client.Client
+
+	BeforeEach(func() {
+		cl = fake.NewFakeClient(
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "a"}},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "b"}},
+		)
+	})
+
+	It("should get each of the objects", func() {
+		keys := []client.ObjectKey{{Namespace: "default", Name: "a"}, {Namespace: "default", Name: "b"}}
+		objs := []runtime.Object{&corev1.ConfigMap{}, &corev1.ConfigMap{}}
+		notFound, err := client.BatchGet(context.TODO(), cl, keys, objs, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(notFound).To(BeEmpty())
+		Expect(objs[0].(*corev1.ConfigMap).Name).To(Equal("a"))
+		Expect(objs[1].(*corev1.ConfigMap).Name).To(Equal("b"))
+	})
+
+	It("should return the keys of objects which do not exist", func() {
+		keys := []client.ObjectKey{{Namespace: "default", Name: "a"}, {Namespace: "default", Name: "c"}}
+		objs := []runtime.Object{&corev1.ConfigMap{}, &corev1.ConfigMap{}}
+		notFound, err := client.BatchGet(context.TODO(), cl, keys, objs, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(notFound).To(Equal([]client.ObjectKey{{Namespace: "default", Name: "c"}}))
+	})
+
+	It("should return an error if the context is done", func() {
+		keys := []client.ObjectKey{{Namespace: "default", Name: "a"}}
+		objs := []runtime.Object{&corev1.ConfigMap{}}
+		cancelled, cancel := context.WithCancel(context.TODO())
+		cancel()
+		_, err := client.BatchGet(cancelled, cl, keys, objs, 0)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if the number of keys and objects differ", func() {
+		_, err := client.BatchGet(context.TODO(), cl, []client.ObjectKey{{Name: "a"}}, nil, 0)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -28,6 +29,7 @@ import (
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
@@ -38,6 +40,44 @@ type Options struct {
 
 	// Mapper, if provided, will be used to map GroupVersionKinds to Resources
 	Mapper meta.RESTMapper
+
+	// StripManagedFields, if true, removes metadata.managedFields from all objects
+	// returned by Get and List.
+	StripManagedFields bool
+
+	// UncachedObjects are the types that clients reading from a cache, such as the
+	// Manager's default client, must always read from the API server.  It is ignored
+	// by New.
+	UncachedObjects []runtime.Object
+
+	// CacheUnstructured, if true, causes clients reading from a cache, such as the Manager's
+	// default client, to read unstructured objects from the cache too.  It is ignored by New.
+	CacheUnstructured bool
+
+	// QPS and Burst, if provided, configure a rate limiter shared by all the requests of the
+	// client.  Either defaults to the QPS or Burst of the rest.Config.  Without them, requests
+	// are limited by the RateLimiter of the rest.Config, or else separately for each kind by
+	// the QPS and Burst of the rest.Config.
+	QPS   float32
+	Burst int
+
+	// RateLimiter, if provided, is used instead of QPS and Burst to rate limit the requests of
+	// the client.  Pass the same RateLimiter to several clients to bound their combined load
+	// on the API server.  The time requests wait for it is recorded in the
+	// controller_runtime_client_rate_limiter_wait_seconds metric.
+	RateLimiter flowcontrol.RateLimiter
+
+	// Timeout, if provided, is the maximum time each request of the client may take.
+	Timeout time.Duration
+
+	// Serializer, if provided, negotiates the encoding of the typed objects sent to and received
+	// from the API server instead of the serializers of the Scheme, e.g. to enable encodings such
+	// as CBOR once the API server supports them.  It must support the ContentType of the
+	// rest.Config, and should decode the media types of its AcceptContentTypes.  Like the default
+	// serializer, it should not convert objects between versions.  Unstructured objects are always
+	// encoded as JSON.  The content types of the responses of the API server are recorded in the
+	// controller_runtime_client_responses_total metric.
+	Serializer runtime.NegotiatedSerializer
 }
 
 // New returns a new Client using the provided config and Options.
@@ -46,6 +86,9 @@ func New(config *rest.Config, options Options) (Client, error) {
 		return nil, fmt.Errorf("must provide non-nil rest.Config to client.New")
 	}
 
+	config = withRateLimits(config, options)
+	config = withContentTypeMetrics(config)
+
 	// Init a scheme if none provided
 	if options.Scheme == nil {
 		options.Scheme = scheme.Scheme
@@ -65,13 +108,21 @@ func New(config *rest.Config, options Options) (Client, error) {
 		return nil, err
 	}
 
+	codecs := serializer.NewCodecFactory(options.Scheme)
+	if options.Serializer == nil {
+		options.Serializer = serializer.DirectCodecFactory{CodecFactory: codecs}
+	}
+	if err := checkContentType(config, options.Serializer); err != nil {
+		return nil, err
+	}
+
 	c := &client{
 		typedClient: typedClient{
 			cache: clientCache{
 				config:         config,
 				scheme:         options.Scheme,
 				mapper:         options.Mapper,
-				codecs:         serializer.NewCodecFactory(options.Scheme),
+				serializer:     options.Serializer,
 				resourceByType: make(map[reflect.Type]*resourceMeta),
 			},
 			paramCodec: runtime.NewParameterCodec(options.Scheme),
@@ -79,7 +130,10 @@ func New(config *rest.Config, options Options) (Client, error) {
 		unstructuredClient: unstructuredClient{
 			client:     dynamicClient,
 			restMapper: options.Mapper,
+			config:     config,
+			codecs:     codecs,
 		},
+		stripManagedFields: options.StripManagedFields,
 	}
 
 	return c, nil
@@ -92,10 +146,17 @@ var _ Client = &client{}
 type client struct {
 	typedClient        typedClient
 	unstructuredClient unstructuredClient
+
+	// stripManagedFields removes metadata.managedFields from objects returned by Get and List
+	stripManagedFields bool
 }
 
 // Create implements client.Client
 func (c *client) Create(ctx context.Context, obj runtime.Object) error {
+	return c.objectError(OperationCreate, obj, objectKeyFor(obj), c.create(ctx, obj))
+}
+
+func (c *client) create(ctx context.Context, obj runtime.Object) error {
 	_, ok := obj.(*unstructured.Unstructured)
 	if ok {
 		return c.unstructuredClient.Create(ctx, obj)
@@ -105,6 +166,10 @@ func (c *client) Create(ctx context.Context, obj runtime.Object) error {
 
 // Update implements client.Client
 func (c *client) Update(ctx context.Context, obj runtime.Object) error {
+	return c.objectError(OperationUpdate, obj, objectKeyFor(obj), c.update(ctx, obj))
+}
+
+func (c *client) update(ctx context.Context, obj runtime.Object) error {
 	_, ok := obj.(*unstructured.Unstructured)
 	if ok {
 		return c.unstructuredClient.Update(ctx, obj)
@@ -112,8 +177,41 @@ func (c *client) Update(ctx context.Context, obj runtime.Object) error {
 	return c.typedClient.Update(ctx, obj)
 }
 
+// Patch implements client.Client
+func (c *client) Patch(ctx context.Context, obj runtime.Object, patch Patch, opts ...PatchOptionFunc) error {
+	return c.objectError(OperationPatch, obj, objectKeyFor(obj), c.patch(ctx, obj, patch, opts...))
+}
+
+func (c *client) patch(ctx context.Context, obj runtime.Object, patch Patch, opts ...PatchOptionFunc) error {
+	_, ok := obj.(*unstructured.Unstructured)
+	if ok {
+		return c.unstructuredClient.Patch(ctx, obj, patch, opts...)
+	}
+	return c.typedClient.Patch(ctx, obj, patch, opts...)
+}
+
+// DeleteAllOf implements client.Client
+func (c *client) DeleteAllOf(ctx context.Context, obj runtime.Object, opts ...DeleteAllOfOptionFunc) error {
+	deleteAllOfOpts := DeleteAllOfOptions{}
+	deleteAllOfOpts.ApplyOptions(opts)
+	key := ObjectKey{Namespace: deleteAllOfOpts.Namespace}
+	return c.objectError(OperationDeleteAllOf, obj, key, c.deleteAllOf(ctx, obj, opts...))
+}
+
+func (c *client) deleteAllOf(ctx context.Context, obj runtime.Object, opts ...DeleteAllOfOptionFunc) error {
+	_, ok := obj.(*unstructured.Unstructured)
+	if ok {
+		return c.unstructuredClient.DeleteAllOf(ctx, obj, opts...)
+	}
+	return c.typedClient.DeleteAllOf(ctx, obj, opts...)
+}
+
 // Delete implements client.Client
 func (c *client) Delete(ctx context.Context, obj runtime.Object, opts ...DeleteOptionFunc) error {
+	return c.objectError(OperationDelete, obj, objectKeyFor(obj), c.delete(ctx, obj, opts...))
+}
+
+func (c *client) delete(ctx context.Context, obj runtime.Object, opts ...DeleteOptionFunc) error {
 	_, ok := obj.(*unstructured.Unstructured)
 	if ok {
 		return c.unstructuredClient.Delete(ctx, obj, opts...)
@@ -123,20 +221,47 @@ func (c *client) Delete(ctx context.Context, obj runtime.Object, opts ...DeleteO
 
 // Get implements client.Client
 func (c *client) Get(ctx context.Context, key ObjectKey, obj runtime.Object) error {
+	return c.objectError(OperationGet, obj, key, c.get(ctx, key, obj))
+}
+
+func (c *client) get(ctx context.Context, key ObjectKey, obj runtime.Object) error {
 	_, ok := obj.(*unstructured.Unstructured)
 	if ok {
-		return c.unstructuredClient.Get(ctx, key, obj)
+		if err := c.unstructuredClient.Get(ctx, key, obj); err != nil {
+			return err
+		}
+		if c.stripManagedFields {
+			StripManagedFields(obj)
+		}
+		return nil
 	}
 	return c.typedClient.Get(ctx, key, obj)
 }
 
 // List implements client.Client
-func (c *client) List(ctx context.Context, opts *ListOptions, obj runtime.Object) error {
+func (c *client) List(ctx context.Context, obj runtime.Object, opts ...ListOptionFunc) error {
+	listOpts := ListOptions{}
+	listOpts.ApplyOptions(opts)
+	return c.objectError(OperationList, obj, ObjectKey{Namespace: listOpts.Namespace}, c.list(ctx, obj, opts...))
+}
+
+func (c *client) list(ctx context.Context, obj runtime.Object, opts ...ListOptionFunc) error {
 	_, ok := obj.(*unstructured.UnstructuredList)
 	if ok {
-		return c.unstructuredClient.List(ctx, opts, obj)
+		if err := c.unstructuredClient.List(ctx, obj, opts...); err != nil {
+			return err
+		}
+		if c.stripManagedFields {
+			StripManagedFields(obj)
+		}
+		return nil
 	}
-	return c.typedClient.List(ctx, opts, obj)
+	return c.typedClient.List(ctx, obj, opts...)
+}
+
+// objectError wraps the error of operation on obj in an ObjectError.
+func (c *client) objectError(operation Operation, obj runtime.Object, key ObjectKey, err error) error {
+	return newObjectError(operation, obj, key, c.typedClient.cache.scheme, err)
 }
 
 // Status implements client.StatusClient
@@ -154,9 +279,26 @@ var _ StatusWriter = &statusWriter{}
 
 // Update implements client.StatusWriter
 func (sw *statusWriter) Update(ctx context.Context, obj runtime.Object) error {
+	return sw.client.objectError(OperationStatusUpdate, obj, objectKeyFor(obj), sw.update(ctx, obj))
+}
+
+func (sw *statusWriter) update(ctx context.Context, obj runtime.Object) error {
 	_, ok := obj.(*unstructured.Unstructured)
 	if ok {
 		return sw.client.unstructuredClient.UpdateStatus(ctx, obj)
 	}
 	return sw.client.typedClient.UpdateStatus(ctx, obj)
 }
+
+// Patch implements client.StatusWriter
+func (sw *statusWriter) Patch(ctx context.Context, obj runtime.Object, patch Patch, opts ...PatchOptionFunc) error {
+	return sw.client.objectError(OperationStatusPatch, obj, objectKeyFor(obj), sw.patch(ctx, obj, patch, opts...))
+}
+
+func (sw *statusWriter) patch(ctx context.Context, obj runtime.Object, patch Patch, opts ...PatchOptionFunc) error {
+	_, ok := obj.(*unstructured.Unstructured)
+	if ok {
+		return sw.client.unstructuredClient.PatchStatus(ctx, obj, patch, opts...)
+	}
+	return sw.client.typedClient.PatchStatus(ctx, obj, patch, opts...)
+}
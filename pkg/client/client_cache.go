@@ -25,7 +25,6 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
@@ -41,8 +40,8 @@ type clientCache struct {
 	// mapper maps GroupVersionKinds to Resources
 	mapper meta.RESTMapper
 
-	// codecs are used to create a REST client for a gvk
-	codecs serializer.CodecFactory
+	// serializer negotiates the encoding of the objects of the REST client for a gvk
+	serializer runtime.NegotiatedSerializer
 
 	// resourceByType caches type metadata
 	resourceByType map[reflect.Type]*resourceMeta
@@ -62,7 +61,7 @@ func (c *clientCache) newResource(obj runtime.Object) (*resourceMeta, error) {
 		gvk.Kind = gvk.Kind[:len(gvk.Kind)-4]
 	}
 
-	client, err := apiutil.RESTClientForGVK(gvk, c.config, c.codecs)
+	client, err := apiutil.RESTClientForGVKWithSerializer(gvk, c.config, c.serializer)
 	if err != nil {
 		return nil, err
 	}
@@ -19,6 +19,7 @@ package client_test
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"sync/atomic"
 
 	. "github.com/onsi/ginkgo"
@@ -28,8 +29,10 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kscheme "k8s.io/client-go/kubernetes/scheme"
@@ -540,6 +543,85 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Describe("Patch", func() {
+		var mergePatch []byte
+
+		BeforeEach(func() {
+			mergePatch = []byte(`{"metadata":{"annotations":{"foo":"bar"}}}`)
+		})
+
+		Context("with structured objects", func() {
+			It("should patch an existing object from a go struct", func(done Done) {
+				cl, err := client.New(cfg, client.Options{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl).NotTo(BeNil())
+
+				By("initially creating a Deployment")
+				dep, err := clientset.AppsV1().Deployments(ns).Create(dep)
+				Expect(err).NotTo(HaveOccurred())
+
+				By("patching the Deployment")
+				err = cl.Patch(context.TODO(), dep, client.ConstantPatch(types.MergePatchType, mergePatch))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dep.Annotations["foo"]).To(Equal("bar"))
+
+				By("validating patched Deployment has new annotation")
+				actual, err := clientset.AppsV1().Deployments(ns).Get(dep.Name, metav1.GetOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actual).NotTo(BeNil())
+				Expect(actual.Annotations["foo"]).To(Equal("bar"))
+
+				close(done)
+			})
+
+			It("should fail if the object does not exists", func(done Done) {
+				cl, err := client.New(cfg, client.Options{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl).NotTo(BeNil())
+
+				By("patching non-existent object")
+				err = cl.Patch(context.TODO(), dep, client.ConstantPatch(types.MergePatchType, mergePatch))
+				Expect(err).To(HaveOccurred())
+
+				close(done)
+			})
+		})
+
+		Context("with unstructured objects", func() {
+			It("should patch an existing object from an unstructured object", func(done Done) {
+				cl, err := client.New(cfg, client.Options{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl).NotTo(BeNil())
+
+				By("initially creating a Deployment")
+				dep, err := clientset.AppsV1().Deployments(ns).Create(dep)
+				Expect(err).NotTo(HaveOccurred())
+
+				By("patching the Deployment")
+				u := &unstructured.Unstructured{}
+				u.SetGroupVersionKind(schema.GroupVersionKind{
+					Group:   "apps",
+					Kind:    "Deployment",
+					Version: "v1",
+				})
+				u.SetName(dep.Name)
+				u.SetNamespace(dep.Namespace)
+				err = cl.Patch(context.TODO(), u, client.ConstantPatch(types.MergePatchType, mergePatch))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(u.GetAnnotations()["foo"]).To(Equal("bar"))
+				Expect(u.GetUID()).To(Equal(dep.UID))
+
+				By("validating patched Deployment has new annotation")
+				actual, err := clientset.AppsV1().Deployments(ns).Get(dep.Name, metav1.GetOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actual).NotTo(BeNil())
+				Expect(actual.Annotations["foo"]).To(Equal("bar"))
+
+				close(done)
+			})
+		})
+	})
+
 	Describe("StatusClient", func() {
 		Context("with structured objects", func() {
 			It("should update status of an existing object", func(done Done) {
@@ -565,6 +647,31 @@ var _ = Describe("Client", func() {
 				close(done)
 			})
 
+			It("should patch status of an existing object", func(done Done) {
+				cl, err := client.New(cfg, client.Options{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl).NotTo(BeNil())
+
+				By("initially creating a Deployment")
+				dep, err := clientset.AppsV1().Deployments(ns).Create(dep)
+				Expect(err).NotTo(HaveOccurred())
+
+				By("patching the status and spec of Deployment")
+				statusPatch := []byte(`{"spec":{"replicas":5},"status":{"replicas":1}}`)
+				err = cl.Status().Patch(context.TODO(), dep, client.ConstantPatch(types.MergePatchType, statusPatch))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dep.Status.Replicas).To(BeEquivalentTo(1))
+
+				By("validating patched Deployment has new status and unchanged spec")
+				actual, err := clientset.AppsV1().Deployments(ns).Get(dep.Name, metav1.GetOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actual).NotTo(BeNil())
+				Expect(actual.Status.Replicas).To(BeEquivalentTo(1))
+				Expect(*actual.Spec.Replicas).To(BeEquivalentTo(replicaCount))
+
+				close(done)
+			})
+
 			It("should not update spec of an existing object", func(done Done) {
 				cl, err := client.New(cfg, client.Options{})
 				Expect(err).NotTo(HaveOccurred())
@@ -780,6 +887,26 @@ var _ = Describe("Client", func() {
 				close(done)
 			})
 
+			It("should only delete an object matching the UID precondition", func(done Done) {
+				cl, err := client.New(cfg, client.Options{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl).NotTo(BeNil())
+
+				By("initially creating a Deployment")
+				dep, err := clientset.AppsV1().Deployments(ns).Create(dep)
+				Expect(err).NotTo(HaveOccurred())
+
+				By("failing to delete the Deployment with another UID")
+				err = cl.Delete(context.TODO(), dep, client.PreconditionUID("other"))
+				Expect(errors.IsConflict(err)).To(BeTrue())
+
+				By("deleting the Deployment with its UID in the foreground")
+				err = cl.Delete(context.TODO(), dep, client.PreconditionUID(dep.UID), client.PropagationPolicyForeground)
+				Expect(err).NotTo(HaveOccurred())
+
+				close(done)
+			})
+
 			It("should delete an existing object non-namespace object from a go struct", func(done Done) {
 				cl, err := client.New(cfg, client.Options{})
 				Expect(err).NotTo(HaveOccurred())
@@ -918,6 +1045,102 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Describe("DeleteAllOf", func() {
+		var dep2 *appsv1.Deployment
+		BeforeEach(func(done Done) {
+			dep.Labels = map[string]string{"app": "delete-all-of"}
+			dep2 = dep.DeepCopy()
+			dep2.Name = dep.Name + "-kept"
+			dep2.Labels = map[string]string{"app": "kept"}
+			close(done)
+		})
+
+		AfterEach(func(done Done) {
+			deleteDeployment(dep2, ns)
+			close(done)
+		})
+
+		Context("with structured objects", func() {
+			It("should delete all of the objects matching the options", func(done Done) {
+				cl, err := client.New(cfg, client.Options{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl).NotTo(BeNil())
+
+				By("initially creating two Deployments")
+				dep, err := clientset.AppsV1().Deployments(ns).Create(dep)
+				Expect(err).NotTo(HaveOccurred())
+				dep2, err := clientset.AppsV1().Deployments(ns).Create(dep2)
+				Expect(err).NotTo(HaveOccurred())
+
+				By("deleting the Deployments matching the label selector")
+				err = cl.DeleteAllOf(context.TODO(), &appsv1.Deployment{},
+					client.WithListOptions(client.InNamespace(ns), client.MatchingLabels(map[string]string{"app": "delete-all-of"})),
+					client.WithDeleteOptions(client.PropagationPolicy(metav1.DeletePropagationForeground)))
+				Expect(err).NotTo(HaveOccurred())
+
+				By("validating only the matching Deployment no longer exists")
+				Eventually(func() bool {
+					_, err := clientset.AppsV1().Deployments(ns).Get(dep.Name, metav1.GetOptions{})
+					return errors.IsNotFound(err)
+				}).Should(BeTrue())
+				_, err = clientset.AppsV1().Deployments(ns).Get(dep2.Name, metav1.GetOptions{})
+				Expect(err).NotTo(HaveOccurred())
+
+				close(done)
+			}, serverSideTimeoutSeconds)
+
+			It("should fail if the object cannot be mapped to a GVK", func(done Done) {
+				By("creating client with empty Scheme")
+				emptyScheme := runtime.NewScheme()
+				cl, err := client.New(cfg, client.Options{Scheme: emptyScheme})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl).NotTo(BeNil())
+
+				By("deleting the Deployments fails")
+				err = cl.DeleteAllOf(context.TODO(), &appsv1.Deployment{}, client.WithListOptions(client.InNamespace(ns)))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("no kind is registered for the type"))
+
+				close(done)
+			})
+		})
+
+		Context("with unstructured objects", func() {
+			It("should delete all of the objects matching the options", func(done Done) {
+				cl, err := client.New(cfg, client.Options{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl).NotTo(BeNil())
+
+				By("initially creating two Deployments")
+				dep, err := clientset.AppsV1().Deployments(ns).Create(dep)
+				Expect(err).NotTo(HaveOccurred())
+				dep2, err := clientset.AppsV1().Deployments(ns).Create(dep2)
+				Expect(err).NotTo(HaveOccurred())
+
+				By("deleting the Deployments matching the label selector")
+				u := &unstructured.Unstructured{}
+				u.SetGroupVersionKind(schema.GroupVersionKind{
+					Group:   "apps",
+					Kind:    "Deployment",
+					Version: "v1",
+				})
+				err = cl.DeleteAllOf(context.TODO(), u,
+					client.WithListOptions(client.InNamespace(ns), client.MatchingLabels(map[string]string{"app": "delete-all-of"})))
+				Expect(err).NotTo(HaveOccurred())
+
+				By("validating only the matching Deployment no longer exists")
+				Eventually(func() bool {
+					_, err := clientset.AppsV1().Deployments(ns).Get(dep.Name, metav1.GetOptions{})
+					return errors.IsNotFound(err)
+				}).Should(BeTrue())
+				_, err = clientset.AppsV1().Deployments(ns).Get(dep2.Name, metav1.GetOptions{})
+				Expect(err).NotTo(HaveOccurred())
+
+				close(done)
+			}, serverSideTimeoutSeconds)
+		})
+	})
+
 	Describe("Get", func() {
 		Context("with structured objects", func() {
 			It("should fetch an existing object for a go struct", func(done Done) {
@@ -1096,7 +1319,7 @@ var _ = Describe("Client", func() {
 
 				By("listing all objects of that type in the cluster")
 				deps := &appsv1.DeploymentList{}
-				Expect(cl.List(context.Background(), nil, deps)).NotTo(HaveOccurred())
+				Expect(cl.List(context.Background(), deps)).NotTo(HaveOccurred())
 
 				Expect(deps.Items).NotTo(BeEmpty())
 				hasDep := false
@@ -1126,7 +1349,7 @@ var _ = Describe("Client", func() {
 					Kind:    "DeploymentList",
 					Version: "v1",
 				})
-				err = cl.List(context.Background(), nil, deps)
+				err = cl.List(context.Background(), deps)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(deps.Items).NotTo(BeEmpty())
@@ -1149,7 +1372,7 @@ var _ = Describe("Client", func() {
 
 				By("listing all Deployments in the cluster")
 				deps := &appsv1.DeploymentList{}
-				Expect(cl.List(context.Background(), nil, deps)).NotTo(HaveOccurred())
+				Expect(cl.List(context.Background(), deps)).NotTo(HaveOccurred())
 
 				By("validating no Deployments are returned")
 				Expect(deps.Items).To(BeEmpty())
@@ -1207,7 +1430,7 @@ var _ = Describe("Client", func() {
 				labels := map[string]string{"app": "backend"}
 				lo := &client.ListOptions{}
 				lo.MatchingLabels(labels)
-				err = cl.List(context.Background(), lo, deps)
+				err = cl.List(context.Background(), deps, client.UseListOptions(lo))
 				Expect(err).NotTo(HaveOccurred())
 
 				By("only the Deployment with the backend label is returned")
@@ -1268,7 +1491,7 @@ var _ = Describe("Client", func() {
 				deps := &appsv1.DeploymentList{}
 				lo := &client.ListOptions{}
 				lo.InNamespace("test-namespace-1")
-				err = cl.List(context.Background(), lo, deps)
+				err = cl.List(context.Background(), deps, client.UseListOptions(lo))
 				Expect(err).NotTo(HaveOccurred())
 
 				By("only the Deployment in test-namespace-1 is returned")
@@ -1325,7 +1548,7 @@ var _ = Describe("Client", func() {
 				deps := &appsv1.DeploymentList{}
 				lo := &client.ListOptions{}
 				lo.MatchingField("metadata.name", "deployment-backend")
-				err = cl.List(context.Background(), lo, deps)
+				err = cl.List(context.Background(), deps, client.UseListOptions(lo))
 				Expect(err).NotTo(HaveOccurred())
 
 				By("only the Deployment with the backend field is returned")
@@ -1416,7 +1639,7 @@ var _ = Describe("Client", func() {
 				lo := &client.ListOptions{}
 				lo.InNamespace("test-namespace-3")
 				lo.MatchingLabels(labels)
-				err = cl.List(context.Background(), lo, deps)
+				err = cl.List(context.Background(), deps, client.UseListOptions(lo))
 				Expect(err).NotTo(HaveOccurred())
 
 				By("only the Deployment in test-namespace-3 with label app=frontend is returned")
@@ -1464,7 +1687,7 @@ var _ = Describe("Client", func() {
 					Kind:    "DeploymentList",
 					Version: "v1",
 				})
-				err = cl.List(context.Background(), nil, deps)
+				err = cl.List(context.Background(), deps)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(deps.Items).NotTo(BeEmpty())
@@ -1490,7 +1713,7 @@ var _ = Describe("Client", func() {
 					Kind:    "DeploymentList",
 					Version: "v1",
 				})
-				Expect(cl.List(context.Background(), nil, deps)).NotTo(HaveOccurred())
+				Expect(cl.List(context.Background(), deps)).NotTo(HaveOccurred())
 
 				By("validating no Deployments are returned")
 				Expect(deps.Items).To(BeEmpty())
@@ -1549,7 +1772,7 @@ var _ = Describe("Client", func() {
 				})
 				lo := &client.ListOptions{}
 				lo.InNamespace("test-namespace-5")
-				err = cl.List(context.Background(), lo, deps)
+				err = cl.List(context.Background(), deps, client.UseListOptions(lo))
 				Expect(err).NotTo(HaveOccurred())
 
 				By("only the Deployment in test-namespace-5 is returned")
@@ -1611,7 +1834,7 @@ var _ = Describe("Client", func() {
 				})
 				lo := &client.ListOptions{}
 				lo.MatchingField("metadata.name", "deployment-backend")
-				err = cl.List(context.Background(), lo, deps)
+				err = cl.List(context.Background(), deps, client.UseListOptions(lo))
 				Expect(err).NotTo(HaveOccurred())
 
 				By("only the Deployment with the backend field is returned")
@@ -1707,7 +1930,7 @@ var _ = Describe("Client", func() {
 				lo := &client.ListOptions{}
 				lo.InNamespace("test-namespace-7")
 				lo.MatchingLabels(labels)
-				err = cl.List(context.Background(), lo, deps)
+				err = cl.List(context.Background(), deps, client.UseListOptions(lo))
 				Expect(err).NotTo(HaveOccurred())
 
 				By("only the Deployment in test-namespace-7 with label app=frontend is returned")
@@ -1778,6 +2001,87 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Describe("DeleteAllOfOptions", func() {
+		It("should select objects with list options and delete them with delete options", func() {
+			dp := metav1.DeletePropagationForeground
+			do := &client.DeleteAllOfOptions{}
+			do.ApplyOptions([]client.DeleteAllOfOptionFunc{
+				client.WithListOptions(client.InNamespace("default"), client.MatchingLabels(map[string]string{"app": "foo"})),
+				client.WithDeleteOptions(client.PropagationPolicy(dp)),
+			})
+			Expect(do.ListOptions.Namespace).To(Equal("default"))
+			Expect(do.AsListOptions().LabelSelector).To(Equal("app=foo"))
+			Expect(do.AsDeleteOptions().PropagationPolicy).To(Equal(&dp))
+		})
+
+		It("should merge repeated options together", func() {
+			gp := int64(1)
+			do := &client.DeleteAllOfOptions{}
+			do.ApplyOptions([]client.DeleteAllOfOptionFunc{
+				client.WithListOptions(client.InNamespace("default")),
+				client.WithListOptions(client.MatchingField("metadata.name", "foo")),
+				client.WithDeleteOptions(client.GracePeriodSeconds(gp)),
+			})
+			Expect(do.ListOptions.Namespace).To(Equal("default"))
+			Expect(do.AsListOptions().FieldSelector).To(Equal("metadata.name=foo"))
+			Expect(do.GracePeriodSeconds).To(Equal(&gp))
+		})
+	})
+
+	Describe("PatchOptions", func() {
+		It("should allow setting FieldOwner", func() {
+			po := &client.PatchOptions{}
+			client.FieldOwner("my-controller")(po)
+			Expect(po.FieldManager).To(Equal("my-controller"))
+		})
+
+		It("should allow setting ForceOwnership", func() {
+			po := &client.PatchOptions{}
+			client.ForceOwnership(po)
+			Expect(po.Force).NotTo(BeNil())
+			Expect(*po.Force).To(BeTrue())
+		})
+
+		It("should produce no params if nil", func() {
+			var po *client.PatchOptions
+			Expect(po.AsParams()).To(BeEmpty())
+		})
+
+		It("should be converted to patch params", func() {
+			po := &client.PatchOptions{}
+			po.ApplyOptions([]client.PatchOptionFunc{
+				client.FieldOwner("my-controller"),
+				client.ForceOwnership,
+				client.DryRunAll,
+			})
+			Expect(po.AsParams()).To(Equal(url.Values{
+				"fieldManager": []string{"my-controller"},
+				"force":        []string{"true"},
+				"dryRun":       []string{"All"},
+			}))
+		})
+	})
+
+	Describe("Patches", func() {
+		It("should marshal the full object when applying", func() {
+			dep := &appsv1.Deployment{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+			}
+			Expect(client.Apply.Type()).To(Equal(client.ApplyPatchType))
+			data, err := client.Apply.Data(dep)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring(`"apiVersion":"apps/v1"`))
+			Expect(string(data)).To(ContainSubstring(`"kind":"Deployment"`))
+		})
+
+		It("should return the data of a ConstantPatch", func() {
+			p := client.ConstantPatch(types.JSONPatchType, []byte("[]"))
+			Expect(p.Type()).To(Equal(types.JSONPatchType))
+			Expect(p.Data(nil)).To(Equal([]byte("[]")))
+		})
+	})
+
 	Describe("ListOptions", func() {
 		It("should be able to set a LabelSelector", func() {
 			lo := &client.ListOptions{}
@@ -1820,6 +2124,15 @@ var _ = Describe("Client", func() {
 			Expect(lo.FieldSelector.String()).To(Equal("field1=bar"))
 		})
 
+		It("should be able to set MatchingFields", func() {
+			lo := &client.ListOptions{}
+			Expect(lo.FieldSelector).To(BeNil())
+			lo = lo.MatchingFields(fields.Set{"field1": "bar", "field2": "baz"})
+			Expect(lo.FieldSelector.Requirements()).To(HaveLen(2))
+			Expect(lo.FieldSelector.Matches(fields.Set{"field1": "bar", "field2": "baz"})).To(BeTrue())
+			Expect(lo.FieldSelector.Matches(fields.Set{"field1": "bar"})).To(BeFalse())
+		})
+
 		It("should be able to set InNamespace", func() {
 			lo := &client.ListOptions{}
 			lo = lo.InNamespace("test-namespace")
@@ -1828,21 +2141,63 @@ var _ = Describe("Client", func() {
 
 		It("should be created from MatchingLabels", func() {
 			labels := map[string]string{"foo": "bar"}
-			lo := client.MatchingLabels(labels)
-			Expect(lo).NotTo(BeNil())
+			lo := &client.ListOptions{}
+			client.MatchingLabels(labels)(lo)
 			Expect(lo.LabelSelector.String()).To(Equal("foo=bar"))
 		})
 
 		It("should be created from MatchingField", func() {
-			lo := client.MatchingField("field1", "bar")
-			Expect(lo).NotTo(BeNil())
+			lo := &client.ListOptions{}
+			client.MatchingField("field1", "bar")(lo)
 			Expect(lo.FieldSelector.String()).To(Equal("field1=bar"))
 		})
 
+		It("should be created from MatchingFields", func() {
+			lo := &client.ListOptions{}
+			client.MatchingFields(fields.Set{"field1": "bar", "field2": "baz"})(lo)
+			Expect(lo.FieldSelector.Matches(fields.Set{"field1": "bar", "field2": "baz"})).To(BeTrue())
+			Expect(lo.FieldSelector.Matches(fields.Set{"field2": "baz"})).To(BeFalse())
+		})
+
 		It("should be created from InNamespace", func() {
-			lo := client.InNamespace("test")
-			Expect(lo).NotTo(BeNil())
+			lo := &client.ListOptions{}
+			client.InNamespace("test")(lo)
+			Expect(lo.Namespace).To(Equal("test"))
+		})
+
+		It("should be created from Limit and Continue", func() {
+			lo := &client.ListOptions{}
+			Expect(lo.Paginated()).To(BeFalse())
+			lo.ApplyOptions([]client.ListOptionFunc{
+				client.Limit(10),
+				client.Continue("token"),
+			})
+			Expect(lo.Limit).To(Equal(int64(10)))
+			Expect(lo.Continue).To(Equal("token"))
+			Expect(lo.Paginated()).To(BeTrue())
+
+			mlo := lo.AsListOptions()
+			Expect(mlo.Limit).To(Equal(int64(10)))
+			Expect(mlo.Continue).To(Equal("token"))
+		})
+
+		It("should compose functional options", func() {
+			lo := &client.ListOptions{}
+			lo.ApplyOptions([]client.ListOptionFunc{
+				client.InNamespace("test"),
+				client.MatchingLabels(map[string]string{"foo": "bar"}),
+				client.MatchingField("field1", "bar"),
+			})
 			Expect(lo.Namespace).To(Equal("test"))
+			Expect(lo.LabelSelector.String()).To(Equal("foo=bar"))
+			Expect(lo.FieldSelector.String()).To(Equal("field1=bar"))
+		})
+
+		It("should be replaced by UseListOptions", func() {
+			lo := &client.ListOptions{}
+			client.UseListOptions(&client.ListOptions{Namespace: "test", Limit: 5})(lo)
+			Expect(lo.Namespace).To(Equal("test"))
+			Expect(lo.Limit).To(Equal(int64(5)))
 		})
 	})
 })
@@ -1876,6 +2231,75 @@ var _ = Describe("DelegatingReader", func() {
 			Expect(1).To(Equal(clientReader.Called))
 		})
 	})
+	Describe("CacheUnstructured", func() {
+		It("should call cache reader for unstructured objects", func() {
+			cachedReader := &fakeReader{}
+			clientReader := &fakeReader{}
+			dReader := client.DelegatingReader{
+				CacheReader:       cachedReader,
+				ClientReader:      clientReader,
+				CacheUnstructured: true,
+			}
+			var actual unstructured.Unstructured
+			key := client.ObjectKey{Namespace: "ns", Name: "name"}
+			dReader.Get(context.TODO(), key, &actual)
+			var actualList unstructured.UnstructuredList
+			dReader.List(context.Background(), &actualList)
+			Expect(2).To(Equal(cachedReader.Called))
+			Expect(0).To(Equal(clientReader.Called))
+		})
+	})
+	Describe("UncachedObjects", func() {
+		var cachedReader, clientReader *fakeReader
+		var dReader *client.DelegatingReader
+
+		BeforeEach(func() {
+			cachedReader = &fakeReader{}
+			clientReader = &fakeReader{}
+			dReader = &client.DelegatingReader{
+				CacheReader:     cachedReader,
+				ClientReader:    clientReader,
+				Scheme:          kscheme.Scheme,
+				UncachedObjects: []runtime.Object{&corev1.Secret{}},
+			}
+		})
+
+		It("should call client reader when getting an uncached object", func() {
+			var actual corev1.Secret
+			key := client.ObjectKey{Namespace: "ns", Name: "name"}
+			dReader.Get(context.TODO(), key, &actual)
+			Expect(0).To(Equal(cachedReader.Called))
+			Expect(1).To(Equal(clientReader.Called))
+		})
+		It("should call client reader when listing uncached objects", func() {
+			var actual corev1.SecretList
+			dReader.List(context.Background(), &actual)
+			Expect(0).To(Equal(cachedReader.Called))
+			Expect(1).To(Equal(clientReader.Called))
+		})
+		It("should call cache reader for other structured objects", func() {
+			var actual corev1.ConfigMap
+			key := client.ObjectKey{Namespace: "ns", Name: "name"}
+			dReader.Get(context.TODO(), key, &actual)
+			var actualList corev1.ConfigMapList
+			dReader.List(context.Background(), &actualList)
+			Expect(2).To(Equal(cachedReader.Called))
+			Expect(0).To(Equal(clientReader.Called))
+		})
+		It("should call cache reader for objects whose kind ends in List", func() {
+			// A ConfigMap registered as a SecretList kind is not a list of Secrets.
+			scheme := runtime.NewScheme()
+			scheme.AddKnownTypes(corev1.SchemeGroupVersion, &corev1.Secret{})
+			scheme.AddKnownTypeWithName(corev1.SchemeGroupVersion.WithKind("SecretList"), &corev1.ConfigMap{})
+			dReader.Scheme = scheme
+
+			var actual corev1.ConfigMap
+			key := client.ObjectKey{Namespace: "ns", Name: "name"}
+			dReader.Get(context.TODO(), key, &actual)
+			Expect(1).To(Equal(cachedReader.Called))
+			Expect(0).To(Equal(clientReader.Called))
+		})
+	})
 	Describe("List", func() {
 		It("should call cache reader when structured object", func() {
 			cachedReader := &fakeReader{}
@@ -1885,7 +2309,7 @@ var _ = Describe("DelegatingReader", func() {
 				ClientReader: clientReader,
 			}
 			var actual appsv1.DeploymentList
-			dReader.List(context.Background(), nil, &actual)
+			dReader.List(context.Background(), &actual)
 			Expect(1).To(Equal(cachedReader.Called))
 			Expect(0).To(Equal(clientReader.Called))
 
@@ -1899,11 +2323,28 @@ var _ = Describe("DelegatingReader", func() {
 			}
 
 			var actual unstructured.UnstructuredList
-			dReader.List(context.Background(), nil, &actual)
+			dReader.List(context.Background(), &actual)
 			Expect(0).To(Equal(cachedReader.Called))
 			Expect(1).To(Equal(clientReader.Called))
 
 		})
+		It("should call client reader when paginating", func() {
+			cachedReader := &fakeReader{}
+			clientReader := &fakeReader{}
+			dReader := client.DelegatingReader{
+				CacheReader:  cachedReader,
+				ClientReader: clientReader,
+			}
+
+			var actual appsv1.DeploymentList
+			dReader.List(context.Background(), &actual, client.Limit(10))
+			dReader.List(context.Background(), &actual, client.Continue("token"))
+			Expect(0).To(Equal(cachedReader.Called))
+			Expect(2).To(Equal(clientReader.Called))
+
+			dReader.List(context.Background(), &actual, client.InNamespace("ns"))
+			Expect(1).To(Equal(cachedReader.Called))
+		})
 	})
 })
 
@@ -1916,7 +2357,7 @@ func (f *fakeReader) Get(ctx context.Context, key client.ObjectKey, obj runtime.
 	return nil
 }
 
-func (f *fakeReader) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+func (f *fakeReader) List(ctx context.Context, list runtime.Object, opts ...client.ListOptionFunc) error {
 	f.Called = f.Called + 1
 	return nil
 }
@@ -0,0 +1,148 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Operation is the operation of a Client that failed with an ObjectError.
+type Operation string
+
+// The operations of the Client.
+const (
+	OperationGet          Operation = "get"
+	OperationList         Operation = "list"
+	OperationCreate       Operation = "create"
+	OperationUpdate       Operation = "update"
+	OperationPatch        Operation = "patch"
+	OperationDelete       Operation = "delete"
+	OperationDeleteAllOf  Operation = "deleteAllOf"
+	OperationStatusUpdate Operation = "statusUpdate"
+	OperationStatusPatch  Operation = "statusPatch"
+)
+
+// ObjectError is an error of a Client operation on an object, returned by the Clients created by New.
+// Get it from the errors of a Client with AsObjectError to report which object failed, e.g. in logs and
+// metrics.  Its message is the message of the error it wraps, and the helpers of the apierrors
+// package, such as IsNotFound, work on it as they do on the wrapped error.  The errors mapping
+// objects to resources, which the helpers of the meta and runtime packages such as IsNoMatchError
+// check by type, are not wrapped.
+type ObjectError struct {
+	// Operation is the operation that failed.
+	Operation Operation
+
+	// GroupVersionKind is the group-version-kind of the object, or of the list for List.
+	GroupVersionKind schema.GroupVersionKind
+
+	// Key is the namespace and name of the object.  It has no name for List and DeleteAllOf, and
+	// no namespace for cluster-scoped objects or operations across all namespaces.
+	Key ObjectKey
+
+	// Err is the error of the operation.
+	Err error
+}
+
+// Error implements error
+func (e *ObjectError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error of the operation.
+func (e *ObjectError) Unwrap() error {
+	return e.Err
+}
+
+// apiStatusObjectError is an ObjectError wrapping an error of the API server.  It implements
+// apierrors.APIStatus, which the apierrors helpers assert errors to.
+type apiStatusObjectError struct {
+	*ObjectError
+	status apierrors.APIStatus
+}
+
+// Status implements apierrors.APIStatus
+func (e *apiStatusObjectError) Status() metav1.Status {
+	return e.status.Status()
+}
+
+// Unwrap returns the ObjectError.
+func (e *apiStatusObjectError) Unwrap() error {
+	return e.ObjectError
+}
+
+// AsObjectError returns the ObjectError err is or wraps.  It is errors.As for an ObjectError, which isn't
+// available before Go 1.13.
+func AsObjectError(err error) (*ObjectError, bool) {
+	for ; err != nil; err = unwrap(err) {
+		if objErr, ok := err.(*ObjectError); ok {
+			return objErr, true
+		}
+	}
+	return nil, false
+}
+
+// unwrap returns the error wrapped by err, or nil if err doesn't wrap another error.  It is errors.Unwrap,
+// which isn't available before Go 1.13.
+func unwrap(err error) error {
+	u, ok := err.(interface{ Unwrap() error })
+	if !ok {
+		return nil
+	}
+	return u.Unwrap()
+}
+
+// newObjectError wraps the error of operation on obj in an ObjectError.  It returns nil if err is nil,
+// and err if it is already an ObjectError or it is an error mapping obj to a resource.
+func newObjectError(operation Operation, obj runtime.Object, key ObjectKey, scheme *runtime.Scheme, err error) error {
+	if err == nil || isMappingError(err) {
+		return err
+	}
+	if _, ok := AsObjectError(err); ok {
+		return err
+	}
+
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if gvk.Empty() {
+		// Typed objects usually have no TypeMeta
+		gvk, _ = apiutil.GVKForObject(obj, scheme)
+	}
+	objErr := &ObjectError{Operation: operation, GroupVersionKind: gvk, Key: key, Err: err}
+	if status, ok := err.(apierrors.APIStatus); ok {
+		return &apiStatusObjectError{ObjectError: objErr, status: status}
+	}
+	return objErr
+}
+
+// isMappingError returns true if err is an error mapping an object to a resource.
+func isMappingError(err error) bool {
+	return meta.IsNoMatchError(err) || meta.IsAmbiguousError(err) ||
+		runtime.IsNotRegisteredError(err) || runtime.IsMissingKind(err) || runtime.IsMissingVersion(err)
+}
+
+// objectKeyFor returns the key of obj, or an empty key if obj has no metadata.
+func objectKeyFor(obj runtime.Object) ObjectKey {
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return ObjectKey{}
+	}
+	return ObjectKey{Namespace: m.GetNamespace(), Name: m.GetName()}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("ObjectError", func() {
+	var server *httptest.Server
+	var cl client.Client
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			resp.Header().Set("Content-Type", "application/json")
+			resp.WriteHeader(http.StatusNotFound)
+			resp.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404,"message":"not found"}`))
+		}))
+		m := meta.NewDefaultRESTMapper([]schema.GroupVersion{{Version: "v1"}})
+		m.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+		var err error
+		cl, err = client.New(&rest.Config{Host: server.URL}, client.Options{Mapper: m})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should report the operation, kind and key of the failed object", func() {
+		err := cl.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "foo"}, &corev1.Pod{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(err.Error()).To(Equal("not found"))
+
+		objErr, ok := client.AsObjectError(err)
+		Expect(ok).To(BeTrue())
+		Expect(objErr.Operation).To(Equal(client.OperationGet))
+		Expect(objErr.GroupVersionKind).To(Equal(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}))
+		Expect(objErr.Key).To(Equal(client.ObjectKey{Namespace: "default", Name: "foo"}))
+
+		status, ok := objErr.Err.(apierrors.APIStatus)
+		Expect(ok).To(BeTrue())
+		Expect(status.Status().Reason).To(Equal(metav1.StatusReasonNotFound))
+	})
+
+	It("should report the operations on lists and unstructured objects", func() {
+		err := cl.List(context.Background(), &corev1.PodList{}, client.InNamespace("default"))
+		objErr, ok := client.AsObjectError(err)
+		Expect(ok).To(BeTrue())
+		Expect(objErr.Operation).To(Equal(client.OperationList))
+		Expect(objErr.GroupVersionKind).To(Equal(schema.GroupVersionKind{Version: "v1", Kind: "PodList"}))
+		Expect(objErr.Key).To(Equal(client.ObjectKey{Namespace: "default"}))
+
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "Pod"})
+		u.SetNamespace("default")
+		u.SetName("foo")
+		err = cl.Status().Update(context.Background(), u)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		objErr, ok = client.AsObjectError(err)
+		Expect(ok).To(BeTrue())
+		Expect(objErr.Operation).To(Equal(client.OperationStatusUpdate))
+		Expect(objErr.GroupVersionKind).To(Equal(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}))
+		Expect(objErr.Key).To(Equal(client.ObjectKey{Namespace: "default", Name: "foo"}))
+	})
+
+	It("should not be found in errors of other operations", func() {
+		_, ok := client.AsObjectError(nil)
+		Expect(ok).To(BeFalse())
+		_, ok = client.AsObjectError(apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "foo"))
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should not wrap the errors mapping objects to resources", func() {
+		err := cl.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "foo"}, &appsv1.Deployment{})
+		Expect(meta.IsNoMatchError(err)).To(BeTrue())
+	})
+})
@@ -21,16 +21,26 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
+	"sync"
 
+	"github.com/mattbaird/jsonpatch"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/testing"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/patch"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
 
@@ -41,9 +51,13 @@ var (
 type fakeClient struct {
 	tracker testing.ObjectTracker
 	scheme  *runtime.Scheme
+
+	indexesLock sync.RWMutex
+	indexes     map[schema.GroupVersionKind]map[string]client.IndexerFunc
 }
 
 var _ client.Client = &fakeClient{}
+var _ client.FieldIndexer = &fakeClient{}
 
 // NewFakeClient creates a new fake client for testing.
 // You can choose to initialize it with a slice of runtime.Object.
@@ -67,9 +81,32 @@ func NewFakeClientWithScheme(clientScheme *runtime.Scheme, initObjs ...runtime.O
 	return &fakeClient{
 		tracker: tracker,
 		scheme:  clientScheme,
+		indexes: map[schema.GroupVersionKind]map[string]client.IndexerFunc{},
 	}
 }
 
+// IndexField registers an index used by List to match field selectors on field, like the
+// cache of a Manager does.  Controllers selecting objects by indexed fields can be tested by
+// registering the same indexes with the fake client, which is a client.FieldIndexer:
+//
+//	cl := fake.NewFakeClient(initObjs...)
+//	cl.(client.FieldIndexer).IndexField(&corev1.Pod{}, "spec.nodeName", indexByNodeName)
+//
+// The metadata.name and metadata.namespace fields can be selected without an index.
+func (c *fakeClient) IndexField(obj runtime.Object, field string, extractValue client.IndexerFunc) error {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+	c.indexesLock.Lock()
+	defer c.indexesLock.Unlock()
+	if c.indexes[gvk] == nil {
+		c.indexes[gvk] = map[string]client.IndexerFunc{}
+	}
+	c.indexes[gvk][field] = extractValue
+	return nil
+}
+
 func (c *fakeClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
 	gvr, err := getGVRFromObject(obj, c.scheme)
 	if err != nil {
@@ -88,19 +125,22 @@ func (c *fakeClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.
 	return err
 }
 
-func (c *fakeClient) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+func (c *fakeClient) List(ctx context.Context, list runtime.Object, opts ...client.ListOptionFunc) error {
+	listOpts := client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+
 	gvk, err := getGVKFromList(list, c.scheme)
 	if err != nil {
 		// The old fake client required GVK info in Raw.TypeMeta, so check there
 		// before giving up
-		if opts.Raw == nil || opts.Raw.TypeMeta.APIVersion == "" || opts.Raw.TypeMeta.Kind == "" {
+		if listOpts.Raw == nil || listOpts.Raw.TypeMeta.APIVersion == "" || listOpts.Raw.TypeMeta.Kind == "" {
 			return err
 		}
-		gvk = opts.Raw.TypeMeta.GroupVersionKind()
+		gvk = listOpts.Raw.TypeMeta.GroupVersionKind()
 	}
 
 	gvr, _ := meta.UnsafeGuessKindToResource(gvk)
-	o, err := c.tracker.List(gvr, gvk, opts.Namespace)
+	o, err := c.tracker.List(gvr, gvk, listOpts.Namespace)
 	if err != nil {
 		return err
 	}
@@ -109,8 +149,75 @@ func (c *fakeClient) List(ctx context.Context, opts *client.ListOptions, list ru
 		return err
 	}
 	decoder := scheme.Codecs.UniversalDecoder()
-	_, _, err = decoder.Decode(j, nil, list)
-	return err
+	if _, _, err = decoder.Decode(j, nil, list); err != nil {
+		return err
+	}
+	return c.filterList(gvk, list, &listOpts)
+}
+
+// filterList removes the items of list which do not match the label and field selectors of opts.
+func (c *fakeClient) filterList(gvk schema.GroupVersionKind, list runtime.Object, opts *client.ListOptions) error {
+	if opts.LabelSelector == nil && opts.FieldSelector == nil {
+		return nil
+	}
+	objs, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	filtered := make([]runtime.Object, 0, len(objs))
+	for _, obj := range objs {
+		matches, err := c.matches(gvk, obj, opts)
+		if err != nil {
+			return err
+		}
+		if matches {
+			filtered = append(filtered, obj)
+		}
+	}
+	return meta.SetList(list, filtered)
+}
+
+// matches returns whether obj matches the label and field selectors of opts.  Fields are
+// matched through the indexes registered with IndexField.
+func (c *fakeClient) matches(gvk schema.GroupVersionKind, obj runtime.Object, opts *client.ListOptions) (bool, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false, err
+	}
+	if opts.LabelSelector != nil && !opts.LabelSelector.Matches(labels.Set(accessor.GetLabels())) {
+		return false, nil
+	}
+	if opts.FieldSelector == nil {
+		return true, nil
+	}
+
+	c.indexesLock.RLock()
+	defer c.indexesLock.RUnlock()
+	for _, req := range opts.FieldSelector.Requirements() {
+		var values []string
+		switch indexer, ok := c.indexes[gvk][req.Field]; {
+		case ok:
+			values = indexer(obj)
+		case req.Field == "metadata.name":
+			values = []string{accessor.GetName()}
+		case req.Field == "metadata.namespace":
+			values = []string{accessor.GetNamespace()}
+		default:
+			return false, fmt.Errorf("index with name %s does not exist", req.Field)
+		}
+
+		found := false
+		for _, v := range values {
+			if v == req.Value {
+				found = true
+				break
+			}
+		}
+		if found != (req.Operator != selection.NotEquals) {
+			return false, nil
+		}
+	}
+	return true, nil
 }
 
 func (c *fakeClient) Create(ctx context.Context, obj runtime.Object) error {
@@ -134,8 +241,119 @@ func (c *fakeClient) Delete(ctx context.Context, obj runtime.Object, opts ...cli
 	if err != nil {
 		return err
 	}
-	//TODO: implement propagation
-	return c.tracker.Delete(gvr, accessor.GetNamespace(), accessor.GetName())
+	deleteOpts := (&client.DeleteOptions{}).ApplyOptions(opts)
+	return c.deleteObject(gvr, accessor.GetNamespace(), accessor.GetName(), deleteOpts.AsDeleteOptions())
+}
+
+// deleteObject deletes a tracked object if it matches the preconditions of opts.  If opts has a
+// PropagationPolicy, the deletion is propagated to the dependents of the object right away, like
+// the garbage collector would eventually do.  Dependents with other owners are never deleted.
+func (c *fakeClient) deleteObject(gvr schema.GroupVersionResource, namespace, name string, opts *metav1.DeleteOptions) error {
+	tracked, err := c.tracker.Get(gvr, namespace, name)
+	if err != nil {
+		return err
+	}
+	accessor, err := meta.Accessor(tracked)
+	if err != nil {
+		return err
+	}
+	if opts.Preconditions != nil && opts.Preconditions.UID != nil && *opts.Preconditions.UID != accessor.GetUID() {
+		return errors.NewConflict(gvr.GroupResource(), name, fmt.Errorf(
+			"Precondition failed: UID in precondition: %v, UID in object meta: %v", *opts.Preconditions.UID, accessor.GetUID()))
+	}
+	if err := c.tracker.Delete(gvr, namespace, name); err != nil {
+		return err
+	}
+	if opts.PropagationPolicy == nil || accessor.GetUID() == "" {
+		return nil
+	}
+	return c.propagateDeletion(accessor.GetUID(), *opts.PropagationPolicy)
+}
+
+// propagateDeletion deletes or orphans the tracked dependents of the deleted owner with the given uid,
+// according to policy.
+func (c *fakeClient) propagateDeletion(owner types.UID, policy metav1.DeletionPropagation) error {
+	for gvk := range c.scheme.AllKnownTypes() {
+		if gvk.Version == runtime.APIVersionInternal {
+			continue
+		}
+		if list, err := c.scheme.New(gvk.GroupVersion().WithKind(gvk.Kind + "List")); err != nil || !meta.IsListType(list) {
+			continue
+		}
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+		o, err := c.tracker.List(gvr, gvk, "")
+		if err != nil {
+			return err
+		}
+		objs, err := meta.ExtractList(o)
+		if err != nil {
+			return err
+		}
+		for _, item := range objs {
+			accessor, err := meta.Accessor(item)
+			if err != nil {
+				return err
+			}
+			var owners []metav1.OwnerReference
+			for _, ref := range accessor.GetOwnerReferences() {
+				if ref.UID != owner {
+					owners = append(owners, ref)
+				}
+			}
+			if len(owners) == len(accessor.GetOwnerReferences()) {
+				continue
+			}
+
+			if policy != metav1.DeletePropagationOrphan && len(owners) == 0 {
+				err = c.deleteObject(gvr, accessor.GetNamespace(), accessor.GetName(), &metav1.DeleteOptions{PropagationPolicy: &policy})
+			} else {
+				accessor.SetOwnerReferences(owners)
+				err = c.tracker.Update(gvr, item, accessor.GetNamespace())
+			}
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteAllOf deletes the tracked objects of the type of obj matching the options.
+func (c *fakeClient) DeleteAllOf(ctx context.Context, obj runtime.Object, opts ...client.DeleteAllOfOptionFunc) error {
+	deleteAllOfOpts := (&client.DeleteAllOfOptions{}).ApplyOptions(opts)
+
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+	gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+	o, err := c.tracker.List(gvr, gvk, deleteAllOfOpts.ListOptions.Namespace)
+	if err != nil {
+		return err
+	}
+	objs, err := meta.ExtractList(o)
+	if err != nil {
+		return err
+	}
+	for _, item := range objs {
+		matches, err := c.matches(gvk, item, &deleteAllOfOpts.ListOptions)
+		if err != nil {
+			return err
+		}
+		if !matches {
+			continue
+		}
+		accessor, err := meta.Accessor(item)
+		if err != nil {
+			return err
+		}
+		// The object may have been deleted already as a dependent of another one
+		err = c.deleteObject(gvr, accessor.GetNamespace(), accessor.GetName(), deleteAllOfOpts.AsDeleteOptions())
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
 }
 
 func (c *fakeClient) Update(ctx context.Context, obj runtime.Object) error {
@@ -150,6 +368,120 @@ func (c *fakeClient) Update(ctx context.Context, obj runtime.Object) error {
 	return c.tracker.Update(gvr, obj, accessor.GetNamespace())
 }
 
+// Patch applies the patch to the tracked object.  Apply patches are applied as merge patches,
+// and create the object if it does not exist; field ownership is not tracked.
+func (c *fakeClient) Patch(ctx context.Context, obj runtime.Object, p client.Patch, opts ...client.PatchOptionFunc) error {
+	return c.patch(obj, p, opts, false)
+}
+
+// patch applies the patch to the tracked object, or only to its status if statusOnly is set.
+func (c *fakeClient) patch(obj runtime.Object, p client.Patch, opts []client.PatchOptionFunc, statusOnly bool) error {
+	gvr, err := getGVRFromObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	data, err := p.Data(obj)
+	if err != nil {
+		return err
+	}
+	patchOpts := (&client.PatchOptions{}).ApplyOptions(opts)
+
+	exists := true
+	original := []byte("{}")
+	o, err := c.tracker.Get(gvr, accessor.GetNamespace(), accessor.GetName())
+	switch {
+	case errors.IsNotFound(err) && p.Type() == client.ApplyPatchType:
+		exists = false
+	case err != nil:
+		return err
+	default:
+		if original, err = json.Marshal(o); err != nil {
+			return err
+		}
+	}
+
+	patched, err := applyPatch(p.Type(), original, data, obj)
+	if err != nil {
+		return errors.NewBadRequest(err.Error())
+	}
+	if statusOnly {
+		if !exists {
+			return errors.NewNotFound(gvr.GroupResource(), accessor.GetName())
+		}
+		if patched, err = withStatusOf(original, patched); err != nil {
+			return err
+		}
+	}
+
+	// Decode into a zeroed obj so that fields removed by the patch are removed from obj too
+	reflect.Indirect(reflect.ValueOf(obj)).Set(reflect.Zero(reflect.Indirect(reflect.ValueOf(obj)).Type()))
+	decoder := scheme.Codecs.UniversalDecoder()
+	if _, _, err := decoder.Decode(patched, nil, obj); err != nil {
+		return err
+	}
+	if len(patchOpts.DryRun) > 0 {
+		return nil
+	}
+	if !exists {
+		return c.tracker.Create(gvr, obj, accessor.GetNamespace())
+	}
+	return c.tracker.Update(gvr, obj, accessor.GetNamespace())
+}
+
+// applyPatch applies the patch data of type patchType to the JSON document original.  dataStruct
+// is used to look up the patch strategies of strategic merge patches.
+func applyPatch(patchType types.PatchType, original, data []byte, dataStruct runtime.Object) ([]byte, error) {
+	switch patchType {
+	case types.JSONPatchType:
+		var ops []jsonpatch.JsonPatchOperation
+		if err := json.Unmarshal(data, &ops); err != nil {
+			return nil, err
+		}
+		return patch.ApplyJSONPatch(original, ops)
+	case types.MergePatchType, client.ApplyPatchType:
+		return mergeJSON(original, data)
+	case types.StrategicMergePatchType:
+		return strategicpatch.StrategicMergePatch(original, data, dataStruct)
+	default:
+		return nil, fmt.Errorf("unsupported patch type %q", patchType)
+	}
+}
+
+// mergeJSON applies the JSON merge patch data to the JSON document original, as described by RFC 7386.
+func mergeJSON(original, data []byte) ([]byte, error) {
+	var doc, mergePatch interface{}
+	if err := json.Unmarshal(original, &doc); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &mergePatch); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergeValue(doc, mergePatch))
+}
+
+func mergeValue(doc, mergePatch interface{}) interface{} {
+	patchMap, ok := mergePatch.(map[string]interface{})
+	if !ok {
+		return mergePatch
+	}
+	docMap, ok := doc.(map[string]interface{})
+	if !ok {
+		docMap = map[string]interface{}{}
+	}
+	for key, value := range patchMap {
+		if value == nil {
+			delete(docMap, key)
+			continue
+		}
+		docMap[key] = mergeValue(docMap[key], value)
+	}
+	return docMap
+}
+
 func (c *fakeClient) Status() client.StatusWriter {
 	return &fakeStatusWriter{client: c}
 }
@@ -185,8 +517,61 @@ type fakeStatusWriter struct {
 	client *fakeClient
 }
 
+// Update updates only the status of the tracked object, like the status subresource of the
+// API server.  The rest of obj is replaced by the tracked object.
 func (sw *fakeStatusWriter) Update(ctx context.Context, obj runtime.Object) error {
-	// TODO(droot): This results in full update of the obj (spec + status). Need
-	// a way to update status field only.
-	return sw.client.Update(ctx, obj)
+	gvr, err := getGVRFromObject(obj, sw.client.scheme)
+	if err != nil {
+		return err
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	o, err := sw.client.tracker.Get(gvr, accessor.GetNamespace(), accessor.GetName())
+	if err != nil {
+		return err
+	}
+	original, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+	updated, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	j, err := withStatusOf(original, updated)
+	if err != nil {
+		return err
+	}
+
+	reflect.Indirect(reflect.ValueOf(obj)).Set(reflect.Zero(reflect.Indirect(reflect.ValueOf(obj)).Type()))
+	decoder := scheme.Codecs.UniversalDecoder()
+	if _, _, err := decoder.Decode(j, nil, obj); err != nil {
+		return err
+	}
+	return sw.client.tracker.Update(gvr, obj, accessor.GetNamespace())
+}
+
+// Patch applies the patch to the status of the tracked object only, like the status
+// subresource of the API server.
+func (sw *fakeStatusWriter) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOptionFunc) error {
+	return sw.client.patch(obj, patch, opts, true)
+}
+
+// withStatusOf returns the JSON document original with the status of the JSON document updated.
+func withStatusOf(original, updated []byte) ([]byte, error) {
+	var originalMap, updatedMap map[string]interface{}
+	if err := json.Unmarshal(original, &originalMap); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(updated, &updatedMap); err != nil {
+		return nil, err
+	}
+	if status, ok := updatedMap["status"]; ok {
+		originalMap["status"] = status
+	} else {
+		delete(originalMap, "status")
+	}
+	return json.Marshal(originalMap)
 }
@@ -22,6 +22,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -67,9 +68,7 @@ var _ = Describe("Fake client", func() {
 		It("should be able to List", func() {
 			By("Listing all deployments in a namespace")
 			list := &appsv1.DeploymentList{}
-			err := cl.List(nil, &client.ListOptions{
-				Namespace: "ns1",
-			}, list)
+			err := cl.List(nil, list, client.InNamespace("ns1"))
 			Expect(err).To(BeNil())
 			Expect(list.Items).To(HaveLen(1))
 			Expect(list.Items).To(ConsistOf(*dep))
@@ -122,6 +121,97 @@ var _ = Describe("Fake client", func() {
 			Expect(obj).To(Equal(newcm))
 		})
 
+		It("should be able to Patch with a merge patch", func() {
+			By("Patching the configmap")
+			obj := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cm",
+					Namespace: "ns2",
+				},
+			}
+			mergePatch := []byte(`{"data":{"test-key":null,"other-key":"other-value"}}`)
+			err := cl.Patch(nil, obj, client.ConstantPatch(types.MergePatchType, mergePatch))
+			Expect(err).To(BeNil())
+			Expect(obj.Data).To(Equal(map[string]string{"other-key": "other-value"}))
+
+			By("Getting the patched configmap")
+			namespacedName := types.NamespacedName{
+				Name:      "test-cm",
+				Namespace: "ns2",
+			}
+			obj = &corev1.ConfigMap{}
+			err = cl.Get(nil, namespacedName, obj)
+			Expect(err).To(BeNil())
+			Expect(obj.Data).To(Equal(map[string]string{"other-key": "other-value"}))
+		})
+
+		It("should be able to Patch with a JSON patch", func() {
+			By("Patching the configmap")
+			obj := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cm",
+					Namespace: "ns2",
+				},
+			}
+			jsonPatch := []byte(`[{"op":"replace","path":"/data/test-key","value":"new-value"}]`)
+			err := cl.Patch(nil, obj, client.ConstantPatch(types.JSONPatchType, jsonPatch))
+			Expect(err).To(BeNil())
+			Expect(obj.Data).To(Equal(map[string]string{"test-key": "new-value"}))
+		})
+
+		It("should create objects that don't exist when applying", func() {
+			By("Applying a new configmap")
+			newcm := &corev1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "new-test-cm",
+					Namespace: "ns2",
+				},
+				Data: map[string]string{
+					"test-key": "new-value",
+				},
+			}
+			err := cl.Patch(nil, newcm, client.Apply, client.FieldOwner("test"), client.ForceOwnership)
+			Expect(err).To(BeNil())
+
+			By("Getting the applied configmap")
+			namespacedName := types.NamespacedName{
+				Name:      "new-test-cm",
+				Namespace: "ns2",
+			}
+			obj := &corev1.ConfigMap{}
+			err = cl.Get(nil, namespacedName, obj)
+			Expect(err).To(BeNil())
+			Expect(obj.Data).To(Equal(newcm.Data))
+		})
+
+		It("should not persist dry run patches", func() {
+			By("Patching the configmap in dry run mode")
+			obj := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cm",
+					Namespace: "ns2",
+				},
+			}
+			mergePatch := []byte(`{"data":{"test-key":"new-value"}}`)
+			err := cl.Patch(nil, obj, client.ConstantPatch(types.MergePatchType, mergePatch), client.DryRunAll)
+			Expect(err).To(BeNil())
+			Expect(obj.Data).To(Equal(map[string]string{"test-key": "new-value"}))
+
+			By("Getting the unchanged configmap")
+			namespacedName := types.NamespacedName{
+				Name:      "test-cm",
+				Namespace: "ns2",
+			}
+			obj = &corev1.ConfigMap{}
+			err = cl.Get(nil, namespacedName, obj)
+			Expect(err).To(BeNil())
+			Expect(obj).To(Equal(cm))
+		})
+
 		It("should be able to Delete", func() {
 			By("Deleting a deployment")
 			err := cl.Delete(nil, dep)
@@ -129,12 +219,196 @@ var _ = Describe("Fake client", func() {
 
 			By("Listing all deployments in the namespace")
 			list := &appsv1.DeploymentList{}
-			err = cl.List(nil, &client.ListOptions{
-				Namespace: "ns1",
-			}, list)
+			err = cl.List(nil, list, client.InNamespace("ns1"))
 			Expect(err).To(BeNil())
 			Expect(list.Items).To(HaveLen(0))
 		})
+
+		It("should only Delete objects matching the UID precondition", func() {
+			By("Creating a deployment with a UID")
+			owned := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "uid-deployment", Namespace: "ns1", UID: "uid"},
+			}
+			Expect(cl.Create(nil, owned)).To(Succeed())
+
+			By("Failing to delete it with another UID")
+			err := cl.Delete(nil, owned, client.PreconditionUID("other"))
+			Expect(errors.IsConflict(err)).To(BeTrue())
+
+			By("Deleting it with its UID")
+			Expect(cl.Delete(nil, owned, client.PreconditionUID("uid"))).To(Succeed())
+			err = cl.Get(nil, types.NamespacedName{Namespace: "ns1", Name: "uid-deployment"}, &appsv1.Deployment{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should propagate deletions to the dependents according to the PropagationPolicy", func() {
+			owner := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "ns1", UID: "owner-uid"},
+			}
+			ownerRef := metav1.OwnerReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "owner", UID: "owner-uid"}
+			otherRef := metav1.OwnerReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "other", UID: "other-uid"}
+			newDependents := func() {
+				for _, obj := range []runtime.Object{
+					owner.DeepCopy(),
+					&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+						Name: "only-owned", Namespace: "ns1", OwnerReferences: []metav1.OwnerReference{ownerRef},
+					}},
+					&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+						Name: "co-owned", Namespace: "ns1", OwnerReferences: []metav1.OwnerReference{ownerRef, otherRef},
+					}},
+				} {
+					Expect(cl.Create(nil, obj)).To(Succeed())
+				}
+			}
+			get := func(name string) (*corev1.ConfigMap, error) {
+				cm := &corev1.ConfigMap{}
+				return cm, cl.Get(nil, types.NamespacedName{Namespace: "ns1", Name: name}, cm)
+			}
+
+			By("Deleting the owner in the background")
+			newDependents()
+			Expect(cl.Delete(nil, owner, client.PropagationPolicyBackground)).To(Succeed())
+			_, err := get("only-owned")
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			coOwned, err := get("co-owned")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(coOwned.OwnerReferences).To(Equal([]metav1.OwnerReference{otherRef}))
+			Expect(cl.Delete(nil, coOwned)).To(Succeed())
+
+			By("Deleting the owner and orphaning its dependents")
+			newDependents()
+			Expect(cl.Delete(nil, owner, client.PropagationPolicyOrphan)).To(Succeed())
+			onlyOwned, err := get("only-owned")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(onlyOwned.OwnerReferences).To(BeEmpty())
+			coOwned, err = get("co-owned")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(coOwned.OwnerReferences).To(Equal([]metav1.OwnerReference{otherRef}))
+		})
+
+		It("should filter Lists by label selector", func() {
+			By("Creating a labelled deployment")
+			labelled := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "labelled-deployment",
+					Namespace: "ns1",
+					Labels:    map[string]string{"app": "foo"},
+				},
+			}
+			err := cl.Create(nil, labelled)
+			Expect(err).To(BeNil())
+
+			By("Listing the deployments matching the label selector")
+			list := &appsv1.DeploymentList{}
+			err = cl.List(nil, list, client.MatchingLabels(map[string]string{"app": "foo"}))
+			Expect(err).To(BeNil())
+			Expect(list.Items).To(HaveLen(1))
+			Expect(list.Items[0].Name).To(Equal("labelled-deployment"))
+		})
+
+		It("should filter Lists by field selector through the registered indexes", func() {
+			By("Registering an index on the data of configmaps")
+			err := cl.(client.FieldIndexer).IndexField(&corev1.ConfigMap{}, "data.test-key", func(obj runtime.Object) []string {
+				return []string{obj.(*corev1.ConfigMap).Data["test-key"]}
+			})
+			Expect(err).To(BeNil())
+
+			By("Listing the configmaps matching the indexed field")
+			list := &corev1.ConfigMapList{}
+			err = cl.List(nil, list, client.MatchingField("data.test-key", "test-value"))
+			Expect(err).To(BeNil())
+			Expect(list.Items).To(HaveLen(1))
+
+			err = cl.List(nil, list, client.MatchingField("data.test-key", "other-value"))
+			Expect(err).To(BeNil())
+			Expect(list.Items).To(HaveLen(0))
+
+			By("Listing the configmaps not matching the indexed field")
+			lo := &client.ListOptions{}
+			Expect(lo.SetFieldSelector("data.test-key!=other-value")).To(Succeed())
+			err = cl.List(nil, list, client.UseListOptions(lo))
+			Expect(err).To(BeNil())
+			Expect(list.Items).To(HaveLen(1))
+		})
+
+		It("should filter Lists by name and namespace without an index", func() {
+			list := &appsv1.DeploymentList{}
+			err := cl.List(nil, list, client.MatchingFields(map[string]string{
+				"metadata.name":      "test-deployment",
+				"metadata.namespace": "ns1",
+			}))
+			Expect(err).To(BeNil())
+			Expect(list.Items).To(HaveLen(1))
+		})
+
+		It("should fail to List by fields which are not indexed", func() {
+			err := cl.List(nil, &appsv1.DeploymentList{}, client.MatchingField("spec.paused", "true"))
+			Expect(err).To(MatchError("index with name spec.paused does not exist"))
+		})
+
+		It("should only update the status through the status writer", func() {
+			By("Updating the spec and status of the deployment")
+			replicas := int32(3)
+			updated := dep.DeepCopy()
+			updated.Spec.Replicas = &replicas
+			updated.Status.Replicas = 2
+			err := cl.Status().Update(nil, updated)
+			Expect(err).To(BeNil())
+			Expect(updated.Spec.Replicas).To(BeNil())
+
+			By("Getting the deployment")
+			obj := &appsv1.Deployment{}
+			err = cl.Get(nil, types.NamespacedName{Name: "test-deployment", Namespace: "ns1"}, obj)
+			Expect(err).To(BeNil())
+			Expect(obj.Status.Replicas).To(BeEquivalentTo(2))
+			Expect(obj.Spec.Replicas).To(BeNil())
+		})
+
+		It("should only patch the status through the status writer", func() {
+			By("Patching the labels and status of the deployment")
+			obj := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-deployment",
+					Namespace: "ns1",
+				},
+			}
+			err := cl.Status().Patch(nil, obj, client.ConstantPatch(types.MergePatchType,
+				[]byte(`{"metadata":{"labels":{"app":"foo"}},"status":{"replicas":2}}`)))
+			Expect(err).To(BeNil())
+			Expect(obj.Status.Replicas).To(BeEquivalentTo(2))
+			Expect(obj.Labels).To(BeEmpty())
+		})
+
+		It("should fail to update the status of objects which do not exist", func() {
+			missing := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: "ns1"}}
+			err := cl.Status().Update(nil, missing)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should be able to DeleteAllOf", func() {
+			By("Creating a labelled deployment")
+			labelled := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "labelled-deployment",
+					Namespace: "ns1",
+					Labels:    map[string]string{"app": "foo"},
+				},
+			}
+			err := cl.Create(nil, labelled)
+			Expect(err).To(BeNil())
+
+			By("Deleting the deployments matching the label selector")
+			err = cl.DeleteAllOf(nil, &appsv1.Deployment{},
+				client.WithListOptions(client.InNamespace("ns1"), client.MatchingLabels(map[string]string{"app": "foo"})))
+			Expect(err).To(BeNil())
+
+			By("Listing all deployments in the namespace")
+			list := &appsv1.DeploymentList{}
+			err = cl.List(nil, list, client.InNamespace("ns1"))
+			Expect(err).To(BeNil())
+			Expect(list.Items).To(HaveLen(1))
+			Expect(list.Items[0].Name).To(Equal("test-deployment"))
+		})
 	}
 
 	Context("with default scheme.Scheme", func() {
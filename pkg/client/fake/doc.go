@@ -22,6 +22,12 @@ You can create a fake client with optional objects.
 
 	client := NewFakeClient(initObjs...) // initObjs is a slice of runtime.Object
 
-You can invoke the methods defined in the Client interface.
+You can invoke the methods defined in the Client interface.  Lists honor label
+selectors, and field selectors on metadata.name, metadata.namespace and the fields
+indexed with the IndexField method of the client, which implements client.FieldIndexer.
+Updates and patches through Status() only change the status of the tracked objects,
+like the status subresource of the API server.  Deletes honor UID preconditions, and
+propagate to the dependents of the deleted objects right away when a PropagationPolicy
+is set, like the garbage collector eventually would.
 */
 package fake
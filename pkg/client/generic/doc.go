@@ -0,0 +1,30 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package generic provides helpers using type parameters to read typed objects and lists
+without pre-declaring zero values or asserting the type of their items, e.g.
+
+	deploys, err := generic.ListTyped[appsv1.DeploymentList](ctx, c, client.InNamespace("default"))
+	if err != nil {
+		return err
+	}
+	byKey, err := generic.MapList[*appsv1.Deployment](deploys)
+
+Type parameters require Go 1.18, so the helpers are only built by Go 1.18 and later, and the
+rest of controller-runtime doesn't depend on them.
+*/
+package generic
@@ -0,0 +1,61 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetTyped retrieves the object of type T for key, e.g.
+//
+//	deploy, err := generic.GetTyped[appsv1.Deployment](ctx, c, key)
+//
+// returns a *appsv1.Deployment.  T must be a typed object, since unstructured
+// objects need their GroupVersionKind set before they can be read.
+func GetTyped[T any, PT interface {
+	*T
+	runtime.Object
+}](ctx context.Context, c client.Reader, key client.ObjectKey) (PT, error) {
+	obj := PT(new(T))
+	if err := c.Get(ctx, key, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// ListTyped retrieves the list of type T matching opts, e.g.
+//
+//	deploys, err := generic.ListTyped[appsv1.DeploymentList](ctx, c, client.InNamespace("default"))
+//
+// returns a *appsv1.DeploymentList.  T must be a typed list, since unstructured
+// lists need their GroupVersionKind set before they can be read.
+func ListTyped[T any, PT interface {
+	*T
+	runtime.Object
+}](ctx context.Context, c client.Reader, opts ...client.ListOptionFunc) (PT, error) {
+	list := PT(new(T))
+	if err := c.List(ctx, list, opts...); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
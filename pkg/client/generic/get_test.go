@@ -0,0 +1,67 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/generic"
+)
+
+var _ = Describe("Typed helpers", func() {
+	var cl client.Client
+
+	BeforeEach(func() {
+		cl = fake.NewFakeClient(
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "a"}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "b"}},
+		)
+	})
+
+	Describe("GetTyped", func() {
+		It("should return the typed object", func() {
+			deploy, err := generic.GetTyped[appsv1.Deployment](context.TODO(), cl, client.ObjectKey{Namespace: "default", Name: "a"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deploy.Name).To(Equal("a"))
+		})
+
+		It("should return the error if the object cannot be read", func() {
+			deploy, err := generic.GetTyped[appsv1.Deployment](context.TODO(), cl, client.ObjectKey{Namespace: "default", Name: "c"})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			Expect(deploy).To(BeNil())
+		})
+	})
+
+	Describe("ListTyped", func() {
+		It("should return the typed list", func() {
+			deploys, err := generic.ListTyped[appsv1.DeploymentList](context.TODO(), cl, client.InNamespace("default"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deploys.Items).To(HaveLen(1))
+			Expect(deploys.Items[0].Name).To(Equal("a"))
+		})
+	})
+})
@@ -0,0 +1,71 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ItemsOf returns the items of list as a slice of T, e.g.
+//
+//	deploys, err := generic.ItemsOf[*appsv1.Deployment](list)
+//
+// For typed lists the returned pointers refer to the items of list, so changes to
+// them are reflected in list.  ItemsOf returns an error if list is not a list or if
+// its items are not of type T.
+func ItemsOf[T runtime.Object](list runtime.Object) ([]T, error) {
+	objs, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, fmt.Errorf("unable to extract items from %T: %v", list, err)
+	}
+
+	items := make([]T, 0, len(objs))
+	for _, obj := range objs {
+		item, ok := obj.(T)
+		if !ok {
+			var want T
+			return nil, fmt.Errorf("item of %T is %T, not %T", list, obj, want)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// MapList returns the items of list as a map of T keyed by the ObjectKey of each item.
+// It returns an error under the same conditions as ItemsOf, or if an item has no metadata.
+func MapList[T runtime.Object](list runtime.Object) (map[client.ObjectKey]T, error) {
+	items, err := ItemsOf[T](list)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[client.ObjectKey]T, len(items))
+	for _, item := range items {
+		key, err := client.ObjectKeyFromObject(item)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get key of %T: %v", item, err)
+		}
+		m[key] = item
+	}
+	return m, nil
+}
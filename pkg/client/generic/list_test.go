@@ -0,0 +1,89 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/generic"
+)
+
+var _ = Describe("List helpers", func() {
+	var list *appsv1.DeploymentList
+
+	BeforeEach(func() {
+		list = &appsv1.DeploymentList{Items: []appsv1.Deployment{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "a"}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "b"}},
+		}}
+	})
+
+	Describe("ItemsOf", func() {
+		It("should return pointers to the items of a typed list", func() {
+			items, err := generic.ItemsOf[*appsv1.Deployment](list)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(items).To(HaveLen(2))
+			Expect(items[0].Name).To(Equal("a"))
+			Expect(items[1].Name).To(Equal("b"))
+
+			items[0].Labels = map[string]string{"foo": "bar"}
+			Expect(list.Items[0].Labels).To(HaveKeyWithValue("foo", "bar"))
+		})
+
+		It("should return the items of an unstructured list", func() {
+			ul := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{{}}}
+			ul.Items[0].SetName("a")
+			items, err := generic.ItemsOf[*unstructured.Unstructured](ul)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(items).To(HaveLen(1))
+			Expect(items[0].GetName()).To(Equal("a"))
+		})
+
+		It("should return an error if the items are not of the requested type", func() {
+			_, err := generic.ItemsOf[*corev1.Pod](list)
+			Expect(err).To(MatchError(ContainSubstring("not *v1.Pod")))
+		})
+
+		It("should return an error if the object is not a list", func() {
+			_, err := generic.ItemsOf[*corev1.Pod](&corev1.Pod{})
+			Expect(err).To(MatchError(ContainSubstring("unable to extract items from *v1.Pod")))
+		})
+	})
+
+	Describe("MapList", func() {
+		It("should key the items by ObjectKey", func() {
+			m, err := generic.MapList[*appsv1.Deployment](list)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m).To(HaveLen(2))
+			Expect(m).To(HaveKey(client.ObjectKey{Namespace: "default", Name: "a"}))
+			Expect(m[client.ObjectKey{Namespace: "default", Name: "b"}].Name).To(Equal("b"))
+		})
+
+		It("should return an error if the items are not of the requested type", func() {
+			_, err := generic.MapList[*corev1.Pod](list)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,145 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// InterceptorFuncs contains functions which are called in place of the methods of a Client
+// wrapped with WithInterceptors.  Each function is passed the wrapped Client, which it can
+// use to perform the original call, so interceptors may modify the arguments, return early
+// with an error, or act on the result.  Methods whose function is nil are passed straight
+// through to the wrapped Client.
+type InterceptorFuncs struct {
+	Get         func(ctx context.Context, client Client, key ObjectKey, obj runtime.Object) error
+	List        func(ctx context.Context, client Client, list runtime.Object, opts ...ListOptionFunc) error
+	Create      func(ctx context.Context, client Client, obj runtime.Object) error
+	Delete      func(ctx context.Context, client Client, obj runtime.Object, opts ...DeleteOptionFunc) error
+	DeleteAllOf func(ctx context.Context, client Client, obj runtime.Object, opts ...DeleteAllOfOptionFunc) error
+	Update      func(ctx context.Context, client Client, obj runtime.Object) error
+	Patch       func(ctx context.Context, client Client, obj runtime.Object, patch Patch, opts ...PatchOptionFunc) error
+
+	// StatusUpdate and StatusPatch are called in place of the methods of the StatusWriter
+	// returned by Status.  The original call is made through client.Status().
+	StatusUpdate func(ctx context.Context, client Client, obj runtime.Object) error
+	StatusPatch  func(ctx context.Context, client Client, obj runtime.Object, patch Patch, opts ...PatchOptionFunc) error
+}
+
+// WithInterceptors returns a Client which calls funcs around every request made to c, e.g. to
+// inject failures, record custom metrics or enforce policies, without implementing a full Client.
+// When several InterceptorFuncs are given, the first one is outermost: it is called first, and the
+// Client it is passed calls the next one.
+func WithInterceptors(c Client, funcs ...InterceptorFuncs) Client {
+	for i := len(funcs) - 1; i >= 0; i-- {
+		c = &interceptorClient{client: c, funcs: funcs[i]}
+	}
+	return c
+}
+
+var _ Client = &interceptorClient{}
+
+// interceptorClient is a Client which calls a set of InterceptorFuncs in place of its methods.
+type interceptorClient struct {
+	client Client
+	funcs  InterceptorFuncs
+}
+
+// Get implements client.Client
+func (c *interceptorClient) Get(ctx context.Context, key ObjectKey, obj runtime.Object) error {
+	if c.funcs.Get != nil {
+		return c.funcs.Get(ctx, c.client, key, obj)
+	}
+	return c.client.Get(ctx, key, obj)
+}
+
+// List implements client.Client
+func (c *interceptorClient) List(ctx context.Context, list runtime.Object, opts ...ListOptionFunc) error {
+	if c.funcs.List != nil {
+		return c.funcs.List(ctx, c.client, list, opts...)
+	}
+	return c.client.List(ctx, list, opts...)
+}
+
+// Create implements client.Client
+func (c *interceptorClient) Create(ctx context.Context, obj runtime.Object) error {
+	if c.funcs.Create != nil {
+		return c.funcs.Create(ctx, c.client, obj)
+	}
+	return c.client.Create(ctx, obj)
+}
+
+// Delete implements client.Client
+func (c *interceptorClient) Delete(ctx context.Context, obj runtime.Object, opts ...DeleteOptionFunc) error {
+	if c.funcs.Delete != nil {
+		return c.funcs.Delete(ctx, c.client, obj, opts...)
+	}
+	return c.client.Delete(ctx, obj, opts...)
+}
+
+// DeleteAllOf implements client.Client
+func (c *interceptorClient) DeleteAllOf(ctx context.Context, obj runtime.Object, opts ...DeleteAllOfOptionFunc) error {
+	if c.funcs.DeleteAllOf != nil {
+		return c.funcs.DeleteAllOf(ctx, c.client, obj, opts...)
+	}
+	return c.client.DeleteAllOf(ctx, obj, opts...)
+}
+
+// Update implements client.Client
+func (c *interceptorClient) Update(ctx context.Context, obj runtime.Object) error {
+	if c.funcs.Update != nil {
+		return c.funcs.Update(ctx, c.client, obj)
+	}
+	return c.client.Update(ctx, obj)
+}
+
+// Patch implements client.Client
+func (c *interceptorClient) Patch(ctx context.Context, obj runtime.Object, patch Patch, opts ...PatchOptionFunc) error {
+	if c.funcs.Patch != nil {
+		return c.funcs.Patch(ctx, c.client, obj, patch, opts...)
+	}
+	return c.client.Patch(ctx, obj, patch, opts...)
+}
+
+// Status implements client.StatusClient
+func (c *interceptorClient) Status() StatusWriter {
+	return &interceptorStatusWriter{client: c}
+}
+
+// interceptorStatusWriter is a StatusWriter which calls the status InterceptorFuncs of its
+// interceptorClient in place of its methods.
+type interceptorStatusWriter struct {
+	client *interceptorClient
+}
+
+// Update implements client.StatusWriter
+func (sw *interceptorStatusWriter) Update(ctx context.Context, obj runtime.Object) error {
+	if sw.client.funcs.StatusUpdate != nil {
+		return sw.client.funcs.StatusUpdate(ctx, sw.client.client, obj)
+	}
+	return sw.client.client.Status().Update(ctx, obj)
+}
+
+// Patch implements client.StatusWriter
+func (sw *interceptorStatusWriter) Patch(ctx context.Context, obj runtime.Object, patch Patch, opts ...PatchOptionFunc) error {
+	if sw.client.funcs.StatusPatch != nil {
+		return sw.client.funcs.StatusPatch(ctx, sw.client.client, obj, patch, opts...)
+	}
+	return sw.client.client.Status().Patch(ctx, obj, patch, opts...)
+}
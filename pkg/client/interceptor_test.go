@@ -0,0 +1,101 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("WithInterceptors", func() {
+	var cm *corev1.ConfigMap
+	var fakeClient client.Client
+
+	BeforeEach(func() {
+		cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"}}
+		fakeClient = fake.NewFakeClient(cm.DeepCopy())
+	})
+
+	It("should pass calls without an interceptor through to the wrapped client", func() {
+		cl := client.WithInterceptors(fakeClient, client.InterceptorFuncs{})
+
+		Expect(cl.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "cm"}, &corev1.ConfigMap{})).To(Succeed())
+		list := &corev1.ConfigMapList{}
+		Expect(cl.List(context.TODO(), list)).To(Succeed())
+		Expect(list.Items).To(HaveLen(1))
+		Expect(cl.Update(context.TODO(), cm)).To(Succeed())
+		Expect(cl.Status().Update(context.TODO(), cm)).To(Succeed())
+		Expect(cl.Delete(context.TODO(), cm)).To(Succeed())
+		Expect(cl.Create(context.TODO(), cm)).To(Succeed())
+	})
+
+	It("should call interceptors in place of the wrapped client", func() {
+		injected := fmt.Errorf("injected failure")
+		cl := client.WithInterceptors(fakeClient, client.InterceptorFuncs{
+			Update: func(ctx context.Context, c client.Client, obj runtime.Object) error {
+				return injected
+			},
+			StatusUpdate: func(ctx context.Context, c client.Client, obj runtime.Object) error {
+				return injected
+			},
+		})
+
+		Expect(cl.Update(context.TODO(), cm)).To(Equal(injected))
+		Expect(cl.Status().Update(context.TODO(), cm)).To(Equal(injected))
+		Expect(cl.Delete(context.TODO(), cm)).To(Succeed())
+	})
+
+	It("should let interceptors call the wrapped client", func() {
+		var gets int
+		cl := client.WithInterceptors(fakeClient, client.InterceptorFuncs{
+			Get: func(ctx context.Context, c client.Client, key client.ObjectKey, obj runtime.Object) error {
+				gets++
+				return c.Get(ctx, key, obj)
+			},
+		})
+
+		actual := &corev1.ConfigMap{}
+		Expect(cl.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "cm"}, actual)).To(Succeed())
+		Expect(actual.Name).To(Equal("cm"))
+		Expect(gets).To(Equal(1))
+	})
+
+	It("should call the first interceptor outermost", func() {
+		var calls []string
+		record := func(name string) client.InterceptorFuncs {
+			return client.InterceptorFuncs{
+				Create: func(ctx context.Context, c client.Client, obj runtime.Object) error {
+					calls = append(calls, name)
+					return c.Create(ctx, obj)
+				},
+			}
+		}
+		cl := client.WithInterceptors(fakeClient, record("first"), record("second"))
+
+		obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}}
+		Expect(cl.Create(context.TODO(), obj)).To(Succeed())
+		Expect(calls).To(Equal([]string{"first", "second"}))
+	})
+})
@@ -18,6 +18,8 @@ package client
 
 import (
 	"context"
+	"net/url"
+	"strconv"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -51,7 +53,7 @@ type Reader interface {
 	// List retrieves list of objects for a given namespace and list options. On a
 	// successful call, Items field in the list will be populated with the
 	// result returned from the server.
-	List(ctx context.Context, opts *ListOptions, list runtime.Object) error
+	List(ctx context.Context, list runtime.Object, opts ...ListOptionFunc) error
 }
 
 // Writer knows how to create, delete, and update Kubernetes objects.
@@ -62,9 +64,17 @@ type Writer interface {
 	// Delete deletes the given obj from Kubernetes cluster.
 	Delete(ctx context.Context, obj runtime.Object, opts ...DeleteOptionFunc) error
 
+	// DeleteAllOf deletes all objects of the type of obj matching the given options
+	// with a single request.
+	DeleteAllOf(ctx context.Context, obj runtime.Object, opts ...DeleteAllOfOptionFunc) error
+
 	// Update updates the given obj in the Kubernetes cluster. obj must be a
 	// struct pointer so that obj can be updated with the content returned by the Server.
 	Update(ctx context.Context, obj runtime.Object) error
+
+	// Patch patches the given obj in the Kubernetes cluster. obj must be a
+	// struct pointer so that obj can be updated with the content returned by the Server.
+	Patch(ctx context.Context, obj runtime.Object, patch Patch, opts ...PatchOptionFunc) error
 }
 
 // StatusClient knows how to create a client which can update status subresource
@@ -79,6 +89,11 @@ type StatusWriter interface {
 	// given obj. obj must be a struct pointer so that obj can be updated
 	// with the content returned by the Server.
 	Update(ctx context.Context, obj runtime.Object) error
+
+	// Patch patches the given object's subresource. obj must be a struct
+	// pointer so that obj can be updated with the content returned by the
+	// Server.
+	Patch(ctx context.Context, obj runtime.Object, patch Patch, opts ...PatchOptionFunc) error
 }
 
 // Client knows how to perform CRUD operations on Kubernetes objects.
@@ -185,6 +200,139 @@ func PropagationPolicy(p metav1.DeletionPropagation) DeleteOptionFunc {
 	}
 }
 
+// PreconditionUID is a functional option that sets the Preconditions field of a
+// DeleteOptions struct, so that the object is only deleted if it has the given
+// UID, and not if it was deleted and recreated with the same name meanwhile.
+func PreconditionUID(uid types.UID) DeleteOptionFunc {
+	return Preconditions(&metav1.Preconditions{UID: &uid})
+}
+
+var (
+	// PropagationPolicyOrphan is a functional option that orphans the dependents
+	// of the deleted objects, removing the deleted objects from their owner
+	// references instead of deleting them.
+	PropagationPolicyOrphan = PropagationPolicy(metav1.DeletePropagationOrphan)
+
+	// PropagationPolicyBackground is a functional option that deletes the objects
+	// right away, and lets the garbage collector delete their dependents in the
+	// background.
+	PropagationPolicyBackground = PropagationPolicy(metav1.DeletePropagationBackground)
+
+	// PropagationPolicyForeground is a functional option that lets the garbage
+	// collector delete the dependents of the objects which block their owner's
+	// deletion before the objects are deleted.
+	PropagationPolicyForeground = PropagationPolicy(metav1.DeletePropagationForeground)
+)
+
+// DeleteAllOfOptions contains options for deletecollection requests.  The
+// ListOptions select the objects to delete, and the DeleteOptions are applied
+// to each of them.
+type DeleteAllOfOptions struct {
+	ListOptions
+	DeleteOptions
+}
+
+// ApplyOptions executes the given DeleteAllOfOptionFuncs and returns the mutated
+// DeleteAllOfOptions.
+func (o *DeleteAllOfOptions) ApplyOptions(optFuncs []DeleteAllOfOptionFunc) *DeleteAllOfOptions {
+	for _, optFunc := range optFuncs {
+		optFunc(o)
+	}
+	return o
+}
+
+// DeleteAllOfOptionFunc is a function that mutates a DeleteAllOfOptions struct.
+// It implements the functional options pattern.
+type DeleteAllOfOptionFunc func(*DeleteAllOfOptions)
+
+// WithListOptions is a functional option that selects the objects deleted by
+// DeleteAllOf, e.g.
+//
+//	c.DeleteAllOf(ctx, &corev1.Pod{}, client.WithListOptions(client.InNamespace("default"), client.MatchingLabels(labels)))
+func WithListOptions(opts ...ListOptionFunc) DeleteAllOfOptionFunc {
+	return func(o *DeleteAllOfOptions) {
+		o.ListOptions.ApplyOptions(opts)
+	}
+}
+
+// WithDeleteOptions is a functional option that sets the options used by
+// DeleteAllOf to delete each object, e.g. its PropagationPolicy.
+func WithDeleteOptions(opts ...DeleteOptionFunc) DeleteAllOfOptionFunc {
+	return func(o *DeleteAllOfOptions) {
+		o.DeleteOptions.ApplyOptions(opts)
+	}
+}
+
+// PatchOptions contains options for patch requests. It's generally a subset
+// of the patch parameters of the API server.
+type PatchOptions struct {
+	// DryRun, when present, indicates that modifications should not be
+	// persisted. Valid values are "All", which processes all dry run stages.
+	DryRun []string
+
+	// Force is going to "force" Apply requests. It means user will
+	// re-acquire conflicting fields owned by other people. Force must be
+	// unset for non-apply patch requests.
+	Force *bool
+
+	// FieldManager is the name of the user or component submitting
+	// this request.  It is required for Apply requests.
+	FieldManager string
+}
+
+// ApplyOptions executes the given PatchOptionFuncs and returns the mutated
+// PatchOptions.
+func (o *PatchOptions) ApplyOptions(optFuncs []PatchOptionFunc) *PatchOptions {
+	for _, optFunc := range optFuncs {
+		optFunc(o)
+	}
+	return o
+}
+
+// AsParams returns these options as the query parameters of a patch request.
+func (o *PatchOptions) AsParams() url.Values {
+	params := url.Values{}
+	if o == nil {
+		return params
+	}
+	for _, dryRun := range o.DryRun {
+		params.Add("dryRun", dryRun)
+	}
+	if o.Force != nil {
+		params.Set("force", strconv.FormatBool(*o.Force))
+	}
+	if o.FieldManager != "" {
+		params.Set("fieldManager", o.FieldManager)
+	}
+	return params
+}
+
+// PatchOptionFunc is a function that mutates a PatchOptions struct. It implements
+// the functional options pattern. See
+// https://github.com/tmrts/go-patterns/blob/master/idiom/functional-options.md.
+type PatchOptionFunc func(*PatchOptions)
+
+// ForceOwnership is a functional option that forces Apply requests to take
+// ownership of the fields managed by other field managers.
+var ForceOwnership PatchOptionFunc = func(opts *PatchOptions) {
+	force := true
+	opts.Force = &force
+}
+
+// FieldOwner is a functional option that sets the FieldManager field of a
+// PatchOptions struct.
+func FieldOwner(name string) PatchOptionFunc {
+	return func(opts *PatchOptions) {
+		opts.FieldManager = name
+	}
+}
+
+// DryRunAll is a functional option that sets the DryRun field of a
+// PatchOptions struct to process all dry run stages.
+var DryRunAll PatchOptionFunc = func(opts *PatchOptions) {
+	opts.DryRun = []string{metav1.DryRunAll}
+}
+
 // ListOptions contains options for limitting or filtering results.
 // It's generally a subset of metav1.ListOptions, with support for
 // pre-parsed selectors (since generally, selectors will be executed
@@ -202,6 +350,17 @@ type ListOptions struct {
 	// non-namespaced objects, or to list across all namespaces.
 	Namespace string
 
+	// Limit is the maximum number of objects to return.  The remaining
+	// objects are listed by passing the continue token of the returned
+	// list as Continue.  Cache-based implementations do not paginate and
+	// return all the objects.
+	Limit int64
+
+	// Continue is the continue token returned by a previous limited list,
+	// used to retrieve the next page of objects.  It is not supported by
+	// cache-based implementations.
+	Continue string
+
 	// Raw represents raw ListOptions, as passed to the API server.  Note
 	// that these may not be respected by all implementations of interface,
 	// and the LabelSelector and FieldSelector fields are ignored.
@@ -245,9 +404,30 @@ func (o *ListOptions) AsListOptions() *metav1.ListOptions {
 	if o.FieldSelector != nil {
 		o.Raw.FieldSelector = o.FieldSelector.String()
 	}
+	if o.Limit > 0 {
+		o.Raw.Limit = o.Limit
+	}
+	if o.Continue != "" {
+		o.Raw.Continue = o.Continue
+	}
 	return o.Raw
 }
 
+// ApplyOptions executes the given ListOptionFuncs and returns the mutated
+// ListOptions.
+func (o *ListOptions) ApplyOptions(optFuncs []ListOptionFunc) *ListOptions {
+	for _, optFunc := range optFuncs {
+		optFunc(o)
+	}
+	return o
+}
+
+// Paginated returns true if these options ask for a page of the objects,
+// i.e. if Limit or Continue is set.
+func (o *ListOptions) Paginated() bool {
+	return o.Limit > 0 || o.Continue != "" || (o.Raw != nil && (o.Raw.Limit > 0 || o.Raw.Continue != ""))
+}
+
 // MatchingLabels is a convenience function that sets the label selector
 // to match the given labels, and then returns the options.
 // It mutates the list options.
@@ -266,6 +446,15 @@ func (o *ListOptions) MatchingField(name, val string) *ListOptions {
 	return o
 }
 
+// MatchingFields is a convenience function that sets the field selector
+// to match all the given fields, and then returns the options.
+// It mutates the list options.
+func (o *ListOptions) MatchingFields(fls fields.Set) *ListOptions {
+	sel := fields.SelectorFromSet(fls)
+	o.FieldSelector = sel
+	return o
+}
+
 // InNamespace is a convenience function that sets the namespace,
 // and then returns the options. It mutates the list options.
 func (o *ListOptions) InNamespace(ns string) *ListOptions {
@@ -273,20 +462,66 @@ func (o *ListOptions) InNamespace(ns string) *ListOptions {
 	return o
 }
 
-// MatchingLabels is a convenience function that constructs list options
-// to match the given labels.
-func MatchingLabels(lbls map[string]string) *ListOptions {
-	return (&ListOptions{}).MatchingLabels(lbls)
+// ListOptionFunc is a function that mutates a ListOptions struct. It implements
+// the functional options pattern. See
+// https://github.com/tmrts/go-patterns/blob/master/idiom/functional-options.md.
+type ListOptionFunc func(*ListOptions)
+
+// MatchingLabels is a functional option that sets the LabelSelector field of
+// a ListOptions struct to match the given labels.
+func MatchingLabels(lbls map[string]string) ListOptionFunc {
+	return func(opts *ListOptions) {
+		opts.MatchingLabels(lbls)
+	}
+}
+
+// MatchingField is a functional option that sets the FieldSelector field of
+// a ListOptions struct to match the given field.
+func MatchingField(name, val string) ListOptionFunc {
+	return func(opts *ListOptions) {
+		opts.MatchingField(name, val)
+	}
+}
+
+// MatchingFields is a functional option that sets the FieldSelector field of
+// a ListOptions struct to match all the given fields.  When listing from the
+// cache, each of the fields must have been indexed with a FieldIndexer.
+func MatchingFields(fls fields.Set) ListOptionFunc {
+	return func(opts *ListOptions) {
+		opts.MatchingFields(fls)
+	}
+}
+
+// InNamespace is a functional option that sets the Namespace field of a
+// ListOptions struct to list in the given namespace.
+func InNamespace(ns string) ListOptionFunc {
+	return func(opts *ListOptions) {
+		opts.InNamespace(ns)
+	}
+}
+
+// Limit is a functional option that sets the Limit field of a ListOptions
+// struct.  Lists read from a cache ignore it and return all the objects.
+func Limit(limit int64) ListOptionFunc {
+	return func(opts *ListOptions) {
+		opts.Limit = limit
+	}
 }
 
-// MatchingField is a convenience function that constructs list options
-// to match the given field.
-func MatchingField(name, val string) *ListOptions {
-	return (&ListOptions{}).MatchingField(name, val)
+// Continue is a functional option that sets the Continue field of a
+// ListOptions struct.  Lists from a cache do not support it.
+func Continue(token string) ListOptionFunc {
+	return func(opts *ListOptions) {
+		opts.Continue = token
+	}
 }
 
-// InNamespace is a convenience function that constructs list
-// options to list in the given namespace.
-func InNamespace(ns string) *ListOptions {
-	return (&ListOptions{}).InNamespace(ns)
+// UseListOptions is a functional option that replaces the fields of a
+// ListOptions struct with those of newOpts.
+func UseListOptions(newOpts *ListOptions) ListOptionFunc {
+	return func(opts *ListOptions) {
+		if newOpts != nil {
+			*opts = *newOpts
+		}
+	}
 }
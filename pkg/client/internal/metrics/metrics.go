@@ -0,0 +1,51 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// RateLimiterWait is a prometheus histogram metric which holds the time requests
+	// waited for the client-side rate limiter before being sent to the API server
+	RateLimiterWait = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "controller_runtime_client_rate_limiter_wait_seconds",
+		Help:    "Time requests waited for the client-side rate limiter before being sent to the API server",
+		Buckets: []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	})
+
+	// Responses is a prometheus counter metrics which holds the total number of
+	// responses of the API server per content type, i.e. their media type, none
+	// for responses without a content type or unknown if it could not be parsed
+	Responses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_client_responses_total",
+		Help: "Total number of responses of the API server per content type",
+	}, []string{"content_type"})
+
+	// BatchedResponses records Responses in batches once batching is enabled
+	// with metrics.WithBatching
+	BatchedResponses = metrics.NewBatchedCounter(Responses)
+)
+
+func init() {
+	metrics.AddDefaultViews(
+		metrics.View{Collector: RateLimiterWait},
+		metrics.View{Collector: Responses},
+	)
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// StripManagedFields removes metadata.managedFields from obj, or from each item if obj
+// is a list.  Typed objects do not carry managedFields in the API version
+// used by this package (they are dropped when the object is decoded), so only
+// unstructured objects are modified.
+func StripManagedFields(obj runtime.Object) {
+	switch o := obj.(type) {
+	case *unstructured.Unstructured:
+		unstructured.RemoveNestedField(o.Object, "metadata", "managedFields")
+	case *unstructured.UnstructuredList:
+		for i := range o.Items {
+			unstructured.RemoveNestedField(o.Items[i].Object, "metadata", "managedFields")
+		}
+	}
+}
+
+var _ Reader = &ManagedFieldsStrippingReader{}
+
+// ManagedFieldsStrippingReader is a Reader which removes metadata.managedFields
+// from all objects returned by the wrapped Reader.
+type ManagedFieldsStrippingReader struct {
+	Reader Reader
+}
+
+// Get retrieves an obj for a given object key from the Kubernetes Cluster.
+func (r *ManagedFieldsStrippingReader) Get(ctx context.Context, key ObjectKey, obj runtime.Object) error {
+	if err := r.Reader.Get(ctx, key, obj); err != nil {
+		return err
+	}
+	StripManagedFields(obj)
+	return nil
+}
+
+// List retrieves list of objects for a given namespace and list options.
+func (r *ManagedFieldsStrippingReader) List(ctx context.Context, list runtime.Object, opts ...ListOptionFunc) error {
+	if err := r.Reader.List(ctx, list, opts...); err != nil {
+		return err
+	}
+	StripManagedFields(list)
+	return nil
+}
+
+// ManagedFields are the fields of an object owned by a field manager, parsed from an entry of its
+// metadata.managedFields.
+type ManagedFields struct {
+	// Manager is the field manager.
+	Manager string
+
+	// Operation is the operation which set the fields, "Apply" or "Update".
+	Operation string
+
+	// APIVersion is the apiVersion the fields were set with.
+	APIVersion string
+
+	// Time is when the fields were last set, if known.
+	Time *metav1.Time
+
+	// Fields are the paths of the owned fields, sorted, in the format of the fields of a ConflictError,
+	// e.g. ".spec.replicas" or `.spec.containers[name="nginx"].image`.
+	Fields []string
+}
+
+// GetManagedFields parses the metadata.managedFields of obj.  Typed objects do not carry managedFields
+// in the API version used by this package, so obj must be read as an unstructured object.
+func GetManagedFields(obj *unstructured.Unstructured) ([]ManagedFields, error) {
+	entries, _, err := unstructured.NestedSlice(obj.Object, "metadata", "managedFields")
+	if err != nil {
+		return nil, err
+	}
+
+	managed := make([]ManagedFields, 0, len(entries))
+	for _, e := range entries {
+		entry, ok := e.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid managedFields entry %v", e)
+		}
+		m := ManagedFields{}
+		m.Manager, _ = entry["manager"].(string)
+		m.Operation, _ = entry["operation"].(string)
+		m.APIVersion, _ = entry["apiVersion"].(string)
+		if s, ok := entry["time"].(string); ok {
+			if t, err := time.Parse(time.RFC3339, s); err == nil {
+				m.Time = &metav1.Time{Time: t}
+			}
+		}
+
+		// The fields are "fieldsV1" since Kubernetes 1.16, and "fields" before
+		fields, ok := entry["fieldsV1"].(map[string]interface{})
+		if !ok {
+			fields, _ = entry["fields"].(map[string]interface{})
+		}
+		if err := addFieldPaths(&m.Fields, "", fields); err != nil {
+			return nil, fmt.Errorf("invalid fields of manager %q: %v", m.Manager, err)
+		}
+		sort.Strings(m.Fields)
+		managed = append(managed, m)
+	}
+	return managed, nil
+}
+
+// FieldManagers returns the field managers of obj owning field, or a field under it, in the format of the
+// fields of ManagedFields, e.g. ".spec.replicas" for the managers fighting over the replicas of a
+// Deployment.
+func FieldManagers(obj *unstructured.Unstructured, field string) ([]string, error) {
+	managed, err := GetManagedFields(obj)
+	if err != nil {
+		return nil, err
+	}
+	var managers []string
+	for _, m := range managed {
+		for _, f := range m.Fields {
+			if f == field || strings.HasPrefix(f, field+".") || strings.HasPrefix(f, field+"[") {
+				managers = append(managers, m.Manager)
+				break
+			}
+		}
+	}
+	return managers, nil
+}
+
+// addFieldPaths adds the paths of the fields of the serialized field set to paths, prefixed with prefix.
+func addFieldPaths(paths *[]string, prefix string, set map[string]interface{}) error {
+	for key, child := range set {
+		if key == "." {
+			// The element at prefix is owned itself, e.g. an item of a list
+			*paths = append(*paths, prefix)
+			continue
+		}
+		element, err := pathElement(key)
+		if err != nil {
+			return err
+		}
+		children, _ := child.(map[string]interface{})
+		if len(children) == 0 {
+			*paths = append(*paths, prefix+element)
+			continue
+		}
+		if err := addFieldPaths(paths, prefix+element, children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pathElement formats the key of a serialized field set, such as "f:spec" or `k:{"name":"nginx"}`, as an
+// element of a path.
+func pathElement(key string) (string, error) {
+	if len(key) < 2 || key[1] != ':' {
+		return "", fmt.Errorf("invalid field key %q", key)
+	}
+	value := key[2:]
+	switch key[0] {
+	case 'f':
+		return "." + value, nil
+	case 'i':
+		return "[" + value + "]", nil
+	case 'v':
+		return "[=" + value + "]", nil
+	case 'k':
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(value), &fields); err != nil {
+			return "", fmt.Errorf("invalid field key %q: %v", key, err)
+		}
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for i, name := range names {
+			names[i] = name + "=" + string(fields[name])
+		}
+		return "[" + strings.Join(names, ",") + "]", nil
+	default:
+		return "", fmt.Errorf("invalid field key %q", key)
+	}
+}
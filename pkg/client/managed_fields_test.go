@@ -0,0 +1,194 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// managedFieldsReader is a Reader which returns objects with managedFields set.
+type managedFieldsReader struct{}
+
+func (managedFieldsReader) Get(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+	obj.(*unstructured.Unstructured).Object = newManagedObject(key.Name)
+	return nil
+}
+
+func (managedFieldsReader) List(_ context.Context, list runtime.Object, _ ...client.ListOptionFunc) error {
+	l := list.(*unstructured.UnstructuredList)
+	l.Items = []unstructured.Unstructured{{Object: newManagedObject("a")}, {Object: newManagedObject("b")}}
+	return nil
+}
+
+func newManagedObject(name string) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":          name,
+			"managedFields": []interface{}{map[string]interface{}{"manager": "kubectl"}},
+		},
+	}
+}
+
+var _ = Describe("ManagedFieldsStrippingReader", func() {
+	var r client.Reader
+
+	BeforeEach(func() {
+		r = &client.ManagedFieldsStrippingReader{Reader: managedFieldsReader{}}
+	})
+
+	It("should strip managedFields from objects returned by Get", func() {
+		u := &unstructured.Unstructured{}
+		Expect(r.Get(context.TODO(), client.ObjectKey{Name: "a"}, u)).To(Succeed())
+		Expect(u.GetName()).To(Equal("a"))
+		_, found, err := unstructured.NestedFieldNoCopy(u.Object, "metadata", "managedFields")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	It("should strip managedFields from each item returned by List", func() {
+		l := &unstructured.UnstructuredList{}
+		Expect(r.List(context.TODO(), l)).To(Succeed())
+		Expect(l.Items).To(HaveLen(2))
+		for _, item := range l.Items {
+			_, found, err := unstructured.NestedFieldNoCopy(item.Object, "metadata", "managedFields")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		}
+	})
+})
+
+var _ = Describe("GetManagedFields", func() {
+	var obj *unstructured.Unstructured
+
+	BeforeEach(func() {
+		obj = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name": "a",
+				"managedFields": []interface{}{
+					map[string]interface{}{
+						"manager":    "kubectl",
+						"operation":  "Update",
+						"apiVersion": "apps/v1",
+						"time":       "2019-06-01T10:00:00Z",
+						"fieldsType": "FieldsV1",
+						"fieldsV1": map[string]interface{}{
+							"f:spec": map[string]interface{}{
+								"f:replicas": map[string]interface{}{},
+							},
+						},
+					},
+					map[string]interface{}{
+						"manager":    "operator",
+						"operation":  "Apply",
+						"apiVersion": "apps/v1",
+						"fields": map[string]interface{}{
+							"f:metadata": map[string]interface{}{
+								"f:labels": map[string]interface{}{
+									"f:app": map[string]interface{}{},
+								},
+							},
+							"f:spec": map[string]interface{}{
+								"f:template": map[string]interface{}{
+									"f:spec": map[string]interface{}{
+										"f:containers": map[string]interface{}{
+											`k:{"name":"nginx"}`: map[string]interface{}{
+												".":       map[string]interface{}{},
+												"f:image": map[string]interface{}{},
+											},
+										},
+									},
+								},
+								"f:finalizers": map[string]interface{}{
+									`v:"foo"`: map[string]interface{}{},
+								},
+							},
+						},
+					},
+				},
+			},
+		}}
+	})
+
+	It("should parse the fields owned by each manager", func() {
+		managed, err := client.GetManagedFields(obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(managed).To(HaveLen(2))
+
+		Expect(managed[0].Manager).To(Equal("kubectl"))
+		Expect(managed[0].Operation).To(Equal("Update"))
+		Expect(managed[0].APIVersion).To(Equal("apps/v1"))
+		Expect(managed[0].Time).NotTo(BeNil())
+		Expect(managed[0].Time.UTC().Format(time.RFC3339)).To(Equal("2019-06-01T10:00:00Z"))
+		Expect(managed[0].Fields).To(Equal([]string{".spec.replicas"}))
+
+		Expect(managed[1].Manager).To(Equal("operator"))
+		Expect(managed[1].Time).To(BeNil())
+		Expect(managed[1].Fields).To(Equal([]string{
+			".metadata.labels.app",
+			`.spec.finalizers[="foo"]`,
+			`.spec.template.spec.containers[name="nginx"]`,
+			`.spec.template.spec.containers[name="nginx"].image`,
+		}))
+	})
+
+	It("should return the managers owning a field or a field under it", func() {
+		managers, err := client.FieldManagers(obj, ".spec.replicas")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(managers).To(Equal([]string{"kubectl"}))
+
+		managers, err = client.FieldManagers(obj, ".spec")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(managers).To(Equal([]string{"kubectl", "operator"}))
+
+		managers, err = client.FieldManagers(obj, ".spec.template.spec.containers")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(managers).To(Equal([]string{"operator"}))
+
+		managers, err = client.FieldManagers(obj, ".spec.rep")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(managers).To(BeEmpty())
+	})
+
+	It("should return no fields for objects without managedFields", func() {
+		unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+		managed, err := client.GetManagedFields(obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(managed).To(BeEmpty())
+	})
+
+	It("should return an error for invalid fields", func() {
+		Expect(unstructured.SetNestedSlice(obj.Object, []interface{}{
+			map[string]interface{}{
+				"manager":  "kubectl",
+				"fieldsV1": map[string]interface{}{"spec": map[string]interface{}{}},
+			},
+		}, "metadata", "managedFields")).To(Succeed())
+		_, err := client.GetManagedFields(obj)
+		Expect(err).To(MatchError(ContainSubstring(`invalid field key "spec"`)))
+	})
+})
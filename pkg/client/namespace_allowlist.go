@@ -0,0 +1,174 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// NamespaceNotAllowedError is returned by a client created with NewNamespaceAllowlistClient
+// when a request targets a namespace outside of its allowlist.
+type NamespaceNotAllowedError struct {
+	// Namespace is the namespace targeted by the rejected request.  It is empty for
+	// requests against cluster-scoped objects or across all namespaces.
+	Namespace string
+}
+
+// Error implements error.
+func (e *NamespaceNotAllowedError) Error() string {
+	if e.Namespace == "" {
+		return "requests across all namespaces or for cluster-scoped objects are not allowed"
+	}
+	return fmt.Sprintf("namespace %q is not allowed", e.Namespace)
+}
+
+// IsNamespaceNotAllowed returns true if err is a NamespaceNotAllowedError.
+func IsNamespaceNotAllowed(err error) bool {
+	_, ok := err.(*NamespaceNotAllowedError)
+	return ok
+}
+
+// NewNamespaceAllowlistClient returns a Client which rejects any read or write of objects
+// outside of namespaces with a NamespaceNotAllowedError before it reaches c.  This is meant
+// as defense in depth for multi-tenant operators which must never touch other namespaces,
+// even if RBAC would permit it.
+//
+// Requests for cluster-scoped objects and Lists across all namespaces have an empty namespace,
+// and are only allowed if the empty string is included in namespaces.
+func NewNamespaceAllowlistClient(c Client, namespaces ...string) Client {
+	allowed := make(map[string]struct{}, len(namespaces))
+	for _, ns := range namespaces {
+		allowed[ns] = struct{}{}
+	}
+	return &namespaceAllowlistClient{client: c, allowed: allowed}
+}
+
+var _ Client = &namespaceAllowlistClient{}
+
+// namespaceAllowlistClient is a Client which only allows requests within a set of namespaces.
+type namespaceAllowlistClient struct {
+	client  Client
+	allowed map[string]struct{}
+}
+
+// check returns a NamespaceNotAllowedError if namespace is not allowed.
+func (c *namespaceAllowlistClient) check(namespace string) error {
+	if _, ok := c.allowed[namespace]; !ok {
+		return &NamespaceNotAllowedError{Namespace: namespace}
+	}
+	return nil
+}
+
+// checkObject returns a NamespaceNotAllowedError if the namespace of obj is not allowed.
+func (c *namespaceAllowlistClient) checkObject(obj runtime.Object) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	return c.check(accessor.GetNamespace())
+}
+
+// Get implements client.Client
+func (c *namespaceAllowlistClient) Get(ctx context.Context, key ObjectKey, obj runtime.Object) error {
+	if err := c.check(key.Namespace); err != nil {
+		return err
+	}
+	return c.client.Get(ctx, key, obj)
+}
+
+// List implements client.Client
+func (c *namespaceAllowlistClient) List(ctx context.Context, list runtime.Object, opts ...ListOptionFunc) error {
+	listOpts := ListOptions{}
+	listOpts.ApplyOptions(opts)
+	if err := c.check(listOpts.Namespace); err != nil {
+		return err
+	}
+	return c.client.List(ctx, list, opts...)
+}
+
+// Create implements client.Client
+func (c *namespaceAllowlistClient) Create(ctx context.Context, obj runtime.Object) error {
+	if err := c.checkObject(obj); err != nil {
+		return err
+	}
+	return c.client.Create(ctx, obj)
+}
+
+// Delete implements client.Client
+func (c *namespaceAllowlistClient) Delete(ctx context.Context, obj runtime.Object, opts ...DeleteOptionFunc) error {
+	if err := c.checkObject(obj); err != nil {
+		return err
+	}
+	return c.client.Delete(ctx, obj, opts...)
+}
+
+// DeleteAllOf implements client.Client
+func (c *namespaceAllowlistClient) DeleteAllOf(ctx context.Context, obj runtime.Object, opts ...DeleteAllOfOptionFunc) error {
+	deleteAllOfOpts := DeleteAllOfOptions{}
+	deleteAllOfOpts.ApplyOptions(opts)
+	if err := c.check(deleteAllOfOpts.ListOptions.Namespace); err != nil {
+		return err
+	}
+	return c.client.DeleteAllOf(ctx, obj, opts...)
+}
+
+// Update implements client.Client
+func (c *namespaceAllowlistClient) Update(ctx context.Context, obj runtime.Object) error {
+	if err := c.checkObject(obj); err != nil {
+		return err
+	}
+	return c.client.Update(ctx, obj)
+}
+
+// Patch implements client.Client
+func (c *namespaceAllowlistClient) Patch(ctx context.Context, obj runtime.Object, patch Patch, opts ...PatchOptionFunc) error {
+	if err := c.checkObject(obj); err != nil {
+		return err
+	}
+	return c.client.Patch(ctx, obj, patch, opts...)
+}
+
+// Status implements client.StatusClient
+func (c *namespaceAllowlistClient) Status() StatusWriter {
+	return &namespaceAllowlistStatusWriter{client: c}
+}
+
+// namespaceAllowlistStatusWriter is a StatusWriter which only allows requests within the
+// namespaces of its namespaceAllowlistClient.
+type namespaceAllowlistStatusWriter struct {
+	client *namespaceAllowlistClient
+}
+
+// Update implements client.StatusWriter
+func (sw *namespaceAllowlistStatusWriter) Update(ctx context.Context, obj runtime.Object) error {
+	if err := sw.client.checkObject(obj); err != nil {
+		return err
+	}
+	return sw.client.client.Status().Update(ctx, obj)
+}
+
+// Patch implements client.StatusWriter
+func (sw *namespaceAllowlistStatusWriter) Patch(ctx context.Context, obj runtime.Object, patch Patch, opts ...PatchOptionFunc) error {
+	if err := sw.client.checkObject(obj); err != nil {
+		return err
+	}
+	return sw.client.client.Status().Patch(ctx, obj, patch, opts...)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("NamespaceAllowlistClient", func() {
+	var cl client.Client
+	var allowed, denied *corev1.ConfigMap
+
+	BeforeEach(func() {
+		allowed = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "tenant-a"}}
+		denied = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "tenant-b"}}
+		cl = client.NewNamespaceAllowlistClient(fake.NewFakeClient(allowed.DeepCopy(), denied.DeepCopy()), "tenant-a")
+	})
+
+	It("should allow reads in allowed namespaces", func() {
+		cm := &corev1.ConfigMap{}
+		Expect(cl.Get(context.TODO(), client.ObjectKey{Namespace: "tenant-a", Name: "cm"}, cm)).To(Succeed())
+
+		list := &corev1.ConfigMapList{}
+		Expect(cl.List(context.TODO(), list, client.InNamespace("tenant-a"))).To(Succeed())
+		Expect(list.Items).To(HaveLen(1))
+	})
+
+	It("should reject reads outside of allowed namespaces", func() {
+		err := cl.Get(context.TODO(), client.ObjectKey{Namespace: "tenant-b", Name: "cm"}, &corev1.ConfigMap{})
+		Expect(client.IsNamespaceNotAllowed(err)).To(BeTrue())
+
+		err = cl.List(context.TODO(), &corev1.ConfigMapList{}, client.InNamespace("tenant-b"))
+		Expect(client.IsNamespaceNotAllowed(err)).To(BeTrue())
+	})
+
+	It("should reject Lists across all namespaces", func() {
+		err := cl.List(context.TODO(), &corev1.ConfigMapList{})
+		Expect(client.IsNamespaceNotAllowed(err)).To(BeTrue())
+	})
+
+	It("should allow writes in allowed namespaces", func() {
+		Expect(cl.Update(context.TODO(), allowed)).To(Succeed())
+		Expect(cl.Status().Update(context.TODO(), allowed)).To(Succeed())
+		Expect(cl.Delete(context.TODO(), allowed)).To(Succeed())
+		Expect(cl.Create(context.TODO(), allowed)).To(Succeed())
+	})
+
+	It("should reject writes outside of allowed namespaces", func() {
+		Expect(client.IsNamespaceNotAllowed(cl.Create(context.TODO(), denied))).To(BeTrue())
+		Expect(client.IsNamespaceNotAllowed(cl.Update(context.TODO(), denied))).To(BeTrue())
+		Expect(client.IsNamespaceNotAllowed(cl.Status().Update(context.TODO(), denied))).To(BeTrue())
+		Expect(client.IsNamespaceNotAllowed(cl.Delete(context.TODO(), denied))).To(BeTrue())
+	})
+
+	It("should only allow DeleteAllOf in allowed namespaces", func() {
+		Expect(cl.DeleteAllOf(context.TODO(), &corev1.ConfigMap{}, client.WithListOptions(client.InNamespace("tenant-a")))).To(Succeed())
+
+		err := cl.DeleteAllOf(context.TODO(), &corev1.ConfigMap{}, client.WithListOptions(client.InNamespace("tenant-b")))
+		Expect(client.IsNamespaceNotAllowed(err)).To(BeTrue())
+		err = cl.DeleteAllOf(context.TODO(), &corev1.ConfigMap{})
+		Expect(client.IsNamespaceNotAllowed(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ApplyPatchType is the PatchType of server-side apply patches.  The vendored
+// apimachinery predates server-side apply, so it is defined here.
+const ApplyPatchType types.PatchType = "application/apply-patch+yaml"
+
+var (
+	// Apply uses server-side apply semantics to patch the given object.  The
+	// object is sent in full as the configuration applied by the field manager
+	// set with FieldOwner, so it should only contain the fields the controller
+	// has an opinion about.  Apply requires an API server supporting server-side
+	// apply.
+	Apply Patch = applyPatch{}
+
+	// Merge uses the raw object as a merge patch, without modifications.
+	Merge Patch = mergePatch{}
+)
+
+// Patch is a patch that can be applied to a Kubernetes object.
+type Patch interface {
+	// Type is the PatchType of a Patch.
+	Type() types.PatchType
+
+	// Data is the raw data representing the patch.
+	Data(obj runtime.Object) ([]byte, error)
+}
+
+// ConstantPatch constructs a new Patch with the given PatchType and data.
+func ConstantPatch(patchType types.PatchType, data []byte) Patch {
+	return constantPatch{patchType: patchType, data: data}
+}
+
+type constantPatch struct {
+	patchType types.PatchType
+	data      []byte
+}
+
+// Type implements Patch.
+func (s constantPatch) Type() types.PatchType {
+	return s.patchType
+}
+
+// Data implements Patch.
+func (s constantPatch) Data(obj runtime.Object) ([]byte, error) {
+	return s.data, nil
+}
+
+type applyPatch struct{}
+
+// Type implements Patch.
+func (s applyPatch) Type() types.PatchType {
+	return ApplyPatchType
+}
+
+// Data implements Patch.
+func (s applyPatch) Data(obj runtime.Object) ([]byte, error) {
+	// JSON is valid YAML, so the object can be sent as is.
+	return json.Marshal(obj)
+}
+
+type mergePatch struct{}
+
+// Type implements Patch.
+func (s mergePatch) Type() types.PatchType {
+	return types.MergePatchType
+}
+
+// Data implements Patch.
+func (s mergePatch) Data(obj runtime.Object) ([]byte, error) {
+	return json.Marshal(obj)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+	"sigs.k8s.io/controller-runtime/pkg/client/internal/metrics"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// NewRateLimiter returns a token bucket rate limiter allowing qps requests per second with
+// bursts of burst requests.  A non-positive qps or burst defaults to the rest.Config default.
+// The time requests wait for the limiter is recorded in the
+// controller_runtime_client_rate_limiter_wait_seconds metric.
+//
+// Setting the limiter as the RateLimiter of a rest.Config shares it between all the clients
+// created from the config, instead of limiting each of them independently.
+func NewRateLimiter(qps float32, burst int) flowcontrol.RateLimiter {
+	if qps <= 0 {
+		qps = rest.DefaultQPS
+	}
+	if burst <= 0 {
+		burst = rest.DefaultBurst
+	}
+	return ObserveRateLimiter(flowcontrol.NewTokenBucketRateLimiter(qps, burst))
+}
+
+// ObserveRateLimiter returns a rate limiter wrapping l which records the time requests wait
+// for it in the controller_runtime_client_rate_limiter_wait_seconds metric.  It returns l
+// if l already records the time.
+func ObserveRateLimiter(l flowcontrol.RateLimiter) flowcontrol.RateLimiter {
+	if _, ok := l.(*observedRateLimiter); ok {
+		return l
+	}
+	return &observedRateLimiter{RateLimiter: l}
+}
+
+// observedRateLimiter records the time requests wait for a rate limiter.
+type observedRateLimiter struct {
+	flowcontrol.RateLimiter
+}
+
+// Accept implements flowcontrol.RateLimiter
+func (l *observedRateLimiter) Accept() {
+	start := time.Now()
+	l.RateLimiter.Accept()
+	if ctrlmetrics.Sample(ctrlmetrics.ClientRateLimiterWait) {
+		metrics.RateLimiterWait.Observe(time.Since(start).Seconds())
+	}
+}
+
+// withRateLimits returns a copy of config applying the rate limits and timeout of options.
+func withRateLimits(config *rest.Config, options Options) *rest.Config {
+	config = rest.CopyConfig(config)
+	if options.Timeout > 0 {
+		config.Timeout = options.Timeout
+	}
+	switch {
+	case options.RateLimiter != nil:
+		config.RateLimiter = ObserveRateLimiter(options.RateLimiter)
+	case options.QPS > 0 || options.Burst > 0:
+		qps, burst := options.QPS, options.Burst
+		if qps <= 0 {
+			qps = config.QPS
+		}
+		if burst <= 0 {
+			burst = config.Burst
+		}
+		config.RateLimiter = NewRateLimiter(qps, burst)
+	}
+	return config
+}
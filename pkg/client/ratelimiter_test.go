@@ -0,0 +1,63 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("NewRateLimiter", func() {
+	waitSamples := func() uint64 {
+		families, err := metrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+		for _, family := range families {
+			if family.GetName() == "controller_runtime_client_rate_limiter_wait_seconds" {
+				return family.GetMetric()[0].GetHistogram().GetSampleCount()
+			}
+		}
+		Fail("the rate limiter wait metric is not registered")
+		return 0
+	}
+
+	It("should default the QPS and Burst of the limiter", func() {
+		Expect(client.NewRateLimiter(0, 0).QPS()).To(Equal(rest.DefaultQPS))
+		Expect(client.NewRateLimiter(20, 0).QPS()).To(BeEquivalentTo(20))
+	})
+
+	It("should record the time requests wait for the limiter", func() {
+		before := waitSamples()
+		l := client.NewRateLimiter(100, 2)
+		l.Accept()
+		l.Accept()
+		l.Accept()
+		Expect(waitSamples() - before).To(BeEquivalentTo(3))
+	})
+
+	It("should not record the wait time of an observed limiter twice", func() {
+		l := client.ObserveRateLimiter(flowcontrol.NewTokenBucketRateLimiter(100, 2))
+		Expect(client.ObserveRateLimiter(l)).To(BeIdenticalTo(l))
+
+		before := waitSamples()
+		client.ObserveRateLimiter(l).Accept()
+		Expect(waitSamples() - before).To(BeEquivalentTo(1))
+	})
+})
@@ -0,0 +1,85 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+// MutateFunc mutates obj into its desired state.  It is called with the latest version of
+// the object read from the API server, and may be called several times.
+type MutateFunc func(obj runtime.Object) error
+
+// RetryOptions contains options for retrying updates on conflicts.
+type RetryOptions struct {
+	// Backoff is the backoff between attempts, and bounds their number.
+	// It defaults to retry.DefaultBackoff.
+	Backoff wait.Backoff
+}
+
+// ApplyOptions executes the given RetryOptionFuncs and returns the mutated
+// RetryOptions.
+func (o *RetryOptions) ApplyOptions(optFuncs []RetryOptionFunc) *RetryOptions {
+	for _, optFunc := range optFuncs {
+		optFunc(o)
+	}
+	return o
+}
+
+// RetryOptionFunc is a function that mutates a RetryOptions struct.
+type RetryOptionFunc func(*RetryOptions)
+
+// WithBackoff sets the backoff between attempts to update an object.
+func WithBackoff(backoff wait.Backoff) RetryOptionFunc {
+	return func(opts *RetryOptions) {
+		opts.Backoff = backoff
+	}
+}
+
+// UpdateWithRetry reads the object for key into obj, mutates it with mutate and updates it.
+// If the update fails with a conflict the object is read again and mutate is reapplied,
+// with backoff between attempts, so mutate must not depend on the state of previous attempts.
+// The error from the last attempt is returned once the attempts are exhausted.
+func UpdateWithRetry(ctx context.Context, c Client, key ObjectKey, obj runtime.Object, mutate MutateFunc, opts ...RetryOptionFunc) error {
+	return updateWithRetry(ctx, c, c.Update, key, obj, mutate, opts)
+}
+
+// UpdateStatusWithRetry is like UpdateWithRetry, but updates the status subresource of obj.
+func UpdateStatusWithRetry(ctx context.Context, c Client, key ObjectKey, obj runtime.Object, mutate MutateFunc, opts ...RetryOptionFunc) error {
+	return updateWithRetry(ctx, c, c.Status().Update, key, obj, mutate, opts)
+}
+
+func updateWithRetry(ctx context.Context, r Reader, update func(context.Context, runtime.Object) error,
+	key ObjectKey, obj runtime.Object, mutate MutateFunc, optFuncs []RetryOptionFunc) error {
+	opts := (&RetryOptions{Backoff: retry.DefaultBackoff}).ApplyOptions(optFuncs)
+	return retry.RetryOnConflict(opts.Backoff, func() error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.Get(ctx, key, obj); err != nil {
+			return err
+		}
+		if err := mutate(obj); err != nil {
+			return err
+		}
+		return update(ctx, obj)
+	})
+}
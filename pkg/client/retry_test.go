@@ -0,0 +1,123 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// conflictingClient fails the first conflicts updates with a conflict.
+type conflictingClient struct {
+	client.Client
+	conflicts int
+	updates   int
+}
+
+func (c *conflictingClient) Update(ctx context.Context, obj runtime.Object) error {
+	c.updates++
+	if c.updates <= c.conflicts {
+		return apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "a", fmt.Errorf("conflict"))
+	}
+	return c.Client.Update(ctx, obj)
+}
+
+func (c *conflictingClient) Status() client.StatusWriter {
+	return c
+}
+
+var _ = Describe("UpdateWithRetry", func() {
+	var cl *conflictingClient
+	var cm *corev1.ConfigMap
+	key := client.ObjectKey{Namespace: "default", Name: "a"}
+	backoff := client.WithBackoff(wait.Backoff{Steps: 3, Duration: time.Millisecond})
+	var mutations int
+	mutate := func(obj runtime.Object) error {
+		mutations++
+		obj.(*corev1.ConfigMap).Data = map[string]string{"mutations": fmt.Sprint(mutations)}
+		return nil
+	}
+
+	BeforeEach(func() {
+		cl = &conflictingClient{Client: fake.NewFakeClient(
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "a"}},
+		)}
+		cm = &corev1.ConfigMap{}
+		mutations = 0
+	})
+
+	It("should reapply the mutation to the latest object on conflicts", func() {
+		cl.conflicts = 2
+		Expect(client.UpdateWithRetry(context.TODO(), cl, key, cm, mutate, backoff)).To(Succeed())
+		Expect(cl.updates).To(Equal(3))
+
+		actual := &corev1.ConfigMap{}
+		Expect(cl.Get(context.TODO(), key, actual)).To(Succeed())
+		Expect(actual.Data).To(Equal(map[string]string{"mutations": "3"}))
+	})
+
+	It("should return the conflict once the attempts are exhausted", func() {
+		cl.conflicts = 3
+		err := client.UpdateWithRetry(context.TODO(), cl, key, cm, mutate, backoff)
+		Expect(apierrors.IsConflict(err)).To(BeTrue())
+		Expect(cl.updates).To(Equal(3))
+	})
+
+	It("should not retry other errors", func() {
+		err := client.UpdateWithRetry(context.TODO(), cl, key, cm, func(runtime.Object) error {
+			mutations++
+			return fmt.Errorf("unable to mutate")
+		}, backoff)
+		Expect(err).To(MatchError("unable to mutate"))
+		Expect(mutations).To(Equal(1))
+		Expect(cl.updates).To(BeZero())
+	})
+
+	It("should return the error if the object does not exist", func() {
+		err := client.UpdateWithRetry(context.TODO(), cl, client.ObjectKey{Namespace: "default", Name: "b"}, cm, mutate, backoff)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should stop retrying once the context is done", func() {
+		cl.conflicts = 3
+		ctx, cancel := context.WithCancel(context.Background())
+		once := func(obj runtime.Object) error {
+			cancel()
+			return mutate(obj)
+		}
+		Expect(client.UpdateWithRetry(ctx, cl, key, cm, once, backoff)).To(MatchError(context.Canceled))
+		Expect(cl.updates).To(Equal(1))
+	})
+
+	It("should retry updates of the status", func() {
+		cl.conflicts = 1
+		Expect(client.UpdateStatusWithRetry(context.TODO(), cl, key, cm, mutate, backoff)).To(Succeed())
+		Expect(cl.updates).To(Equal(2))
+	})
+})
@@ -0,0 +1,83 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/internal/metrics"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// checkContentType returns an error if the ContentType of config is not supported by s.
+func checkContentType(config *rest.Config, s runtime.NegotiatedSerializer) error {
+	if config.ContentType == "" {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(config.ContentType)
+	if err != nil {
+		return fmt.Errorf("invalid content type %q: %v", config.ContentType, err)
+	}
+	if _, ok := runtime.SerializerInfoForMediaType(s.SupportedMediaTypes(), mediaType); !ok {
+		return fmt.Errorf("content type %q is not supported by the serializer", config.ContentType)
+	}
+	return nil
+}
+
+// withContentTypeMetrics returns a copy of config whose transport records the content type of
+// the responses of the API server.
+func withContentTypeMetrics(config *rest.Config) *rest.Config {
+	config = rest.CopyConfig(config)
+	wrap := config.WrapTransport
+	config.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		if wrap != nil {
+			rt = wrap(rt)
+		}
+		return &contentTypeRoundTripper{RoundTripper: rt}
+	}
+	return config
+}
+
+// contentTypeRoundTripper records the content type of responses by media type, dropping the
+// parameters of the content type to bound the cardinality of the metric.
+type contentTypeRoundTripper struct {
+	http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (rt *contentTypeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rt.RoundTripper.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	mediaType := "none"
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		mediaType = "unknown"
+		if t, _, err := mime.ParseMediaType(contentType); err == nil {
+			mediaType = t
+		}
+	}
+	mediaType = ctrlmetrics.LabelValue(ctrlmetrics.LabelContentType, mediaType)
+	if ctrlmetrics.Sample(ctrlmetrics.ClientResponses, mediaType) {
+		metrics.BatchedResponses.Inc(mediaType)
+	}
+	return resp, nil
+}
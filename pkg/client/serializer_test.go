@@ -0,0 +1,129 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// cborSerializer pretends to encode objects as CBOR, while actually encoding them as JSON.
+type cborSerializer struct {
+	serializer.DirectCodecFactory
+}
+
+func (s cborSerializer) SupportedMediaTypes() []runtime.SerializerInfo {
+	for _, info := range s.DirectCodecFactory.SupportedMediaTypes() {
+		if info.MediaType == runtime.ContentTypeJSON {
+			info.MediaType = "application/cbor"
+			return []runtime.SerializerInfo{info}
+		}
+	}
+	return nil
+}
+
+var _ = Describe("Serializer", func() {
+	var server *httptest.Server
+	var config *rest.Config
+	var mapper meta.RESTMapper
+	var contentType string
+
+	responses := func(contentType string) float64 {
+		families, err := metrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+		for _, family := range families {
+			if family.GetName() != "controller_runtime_client_responses_total" {
+				continue
+			}
+			for _, m := range family.GetMetric() {
+				if m.GetLabel()[0].GetValue() == contentType {
+					return m.GetCounter().GetValue()
+				}
+			}
+		}
+		return 0
+	}
+
+	BeforeEach(func() {
+		contentType = "application/json; charset=utf-8"
+		server = httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+			Expect(req.URL.Path).To(Equal("/api/v1/namespaces/default/pods/foo"))
+			resp.Header().Set("Content-Type", contentType)
+			Expect(json.NewEncoder(resp).Encode(&corev1.Pod{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"},
+			})).To(Succeed())
+		}))
+		config = &rest.Config{Host: server.URL}
+		m := meta.NewDefaultRESTMapper([]schema.GroupVersion{{Version: "v1"}})
+		m.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+		mapper = m
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should record the content type of the responses", func() {
+		cl, err := client.New(config, client.Options{Mapper: mapper})
+		Expect(err).NotTo(HaveOccurred())
+
+		before := responses("application/json")
+		pod := &corev1.Pod{}
+		Expect(cl.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "foo"}, pod)).To(Succeed())
+		Expect(pod.Name).To(Equal("foo"))
+		Expect(responses("application/json") - before).To(Equal(1.0))
+	})
+
+	It("should negotiate the encoding with the given Serializer", func() {
+		contentType = "application/cbor"
+		config.ContentType = "application/cbor"
+		cl, err := client.New(config, client.Options{
+			Mapper:     mapper,
+			Serializer: cborSerializer{serializer.DirectCodecFactory{CodecFactory: serializer.NewCodecFactory(scheme.Scheme)}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		before := responses("application/cbor")
+		pod := &corev1.Pod{}
+		Expect(cl.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "foo"}, pod)).To(Succeed())
+		Expect(pod.Name).To(Equal("foo"))
+		Expect(responses("application/cbor") - before).To(Equal(1.0))
+	})
+
+	It("should return an error if the Serializer does not support the content type", func() {
+		config.ContentType = "application/cbor"
+		_, err := client.New(config, client.Options{Mapper: mapper})
+		Expect(err).To(MatchError(ContainSubstring("not supported by the serializer")))
+	})
+})
@@ -18,9 +18,14 @@ package client
 
 import (
 	"context"
+	"strings"
+	"sync"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
 // DelegatingClient forms an interface Client by composing separate
@@ -33,27 +38,82 @@ type DelegatingClient struct {
 }
 
 // DelegatingReader forms a interface Reader that will cause Get and List
-// requests for unstructured types to use the ClientReader while
-// requests for any other type of object with use the CacheReader.
+// requests for unstructured types (unless CacheUnstructured is set), and for
+// the UncachedObjects types, to use the ClientReader while requests for any
+// other type of object with use the CacheReader.
 type DelegatingReader struct {
 	CacheReader  Reader
 	ClientReader Reader
+
+	// Scheme is used to find the GroupVersionKinds of UncachedObjects and of the
+	// objects being read.  It is required if UncachedObjects is set.
+	Scheme *runtime.Scheme
+
+	// UncachedObjects are the types, such as Secrets, that are always read with the
+	// ClientReader, so that the CacheReader never starts caching them.
+	UncachedObjects []runtime.Object
+
+	// CacheUnstructured, if true, causes unstructured objects to be read with the
+	// CacheReader as well.  A cache backed by informers starts an informer for the
+	// GroupVersionKind of each unstructured object on demand, so that types without
+	// Go types, such as CRDs owned by other operators, are cached too.
+	CacheUnstructured bool
+
+	uncachedOnce sync.Once
+	uncached     map[schema.GroupVersionKind]struct{}
 }
 
 // Get retrieves an obj for a given object key from the Kubernetes Cluster.
 func (d *DelegatingReader) Get(ctx context.Context, key ObjectKey, obj runtime.Object) error {
-	_, isUnstructured := obj.(*unstructured.Unstructured)
-	if isUnstructured {
+	if d.shouldBypassCache(obj) {
 		return d.ClientReader.Get(ctx, key, obj)
 	}
 	return d.CacheReader.Get(ctx, key, obj)
 }
 
 // List retrieves list of objects for a given namespace and list options.
-func (d *DelegatingReader) List(ctx context.Context, opts *ListOptions, list runtime.Object) error {
-	_, isUnstructured := list.(*unstructured.UnstructuredList)
-	if isUnstructured {
-		return d.ClientReader.List(ctx, opts, list)
+// Paginated lists, i.e. lists with a Limit or a Continue token, are always
+// read from the ClientReader since the cache can't paginate.
+func (d *DelegatingReader) List(ctx context.Context, list runtime.Object, opts ...ListOptionFunc) error {
+	if d.shouldBypassCache(list) || (&ListOptions{}).ApplyOptions(opts).Paginated() {
+		return d.ClientReader.List(ctx, list, opts...)
+	}
+	return d.CacheReader.List(ctx, list, opts...)
+}
+
+// shouldBypassCache returns true if obj, or the items of obj if it is a list, must be read
+// with the ClientReader.
+func (d *DelegatingReader) shouldBypassCache(obj runtime.Object) bool {
+	switch obj.(type) {
+	case *unstructured.Unstructured, *unstructured.UnstructuredList:
+		return !d.CacheUnstructured
+	}
+	if len(d.UncachedObjects) == 0 || d.Scheme == nil {
+		return false
+	}
+	d.uncachedOnce.Do(d.initUncached)
+
+	gvk, err := apiutil.GVKForObject(obj, d.Scheme)
+	if err != nil {
+		// Let the CacheReader report the error
+		return false
+	}
+	if meta.IsListType(obj) {
+		gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	}
+	_, ok := d.uncached[gvk]
+	return ok
+}
+
+// initUncached finds the GroupVersionKinds of the UncachedObjects.
+func (d *DelegatingReader) initUncached() {
+	d.uncached = make(map[schema.GroupVersionKind]struct{}, len(d.UncachedObjects))
+	for _, obj := range d.UncachedObjects {
+		gvk, err := apiutil.GVKForObject(obj, d.Scheme)
+		if err != nil {
+			// Types missing from the Scheme can't be read at all, so there is nothing to bypass
+			continue
+		}
+		d.uncached[gvk] = struct{}{}
 	}
-	return d.CacheReader.List(ctx, opts, list)
 }
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
 )
 
 // client is a client.Client that reads and writes directly from/to an API server.  It lazily initializes
@@ -78,6 +79,60 @@ func (c *typedClient) Delete(ctx context.Context, obj runtime.Object, opts ...De
 		Error()
 }
 
+// DeleteAllOf implements client.Client
+func (c *typedClient) DeleteAllOf(ctx context.Context, obj runtime.Object, opts ...DeleteAllOfOptionFunc) error {
+	r, err := c.cache.getResource(obj)
+	if err != nil {
+		return err
+	}
+
+	deleteAllOfOpts := DeleteAllOfOptions{}
+	deleteAllOfOpts.ApplyOptions(opts)
+	return r.Delete().
+		NamespaceIfScoped(deleteAllOfOpts.ListOptions.Namespace, r.isNamespaced()).
+		Resource(r.resource()).
+		VersionedParams(deleteAllOfOpts.AsListOptions(), c.paramCodec).
+		Body(deleteAllOfOpts.AsDeleteOptions()).
+		Context(ctx).
+		Do().
+		Error()
+}
+
+// Patch implements client.Client
+func (c *typedClient) Patch(ctx context.Context, obj runtime.Object, patch Patch, opts ...PatchOptionFunc) error {
+	return c.patch(ctx, obj, patch, "", opts...)
+}
+
+// patch patches obj, or its subResource if subResource is set.
+func (c *typedClient) patch(ctx context.Context, obj runtime.Object, patch Patch, subResource string, opts ...PatchOptionFunc) error {
+	o, err := c.cache.getObjMeta(obj)
+	if err != nil {
+		return err
+	}
+	if patch.Type() == ApplyPatchType {
+		// Applied configurations must carry their apiVersion and kind
+		obj.GetObjectKind().SetGroupVersionKind(o.gvk)
+	}
+	data, err := patch.Data(obj)
+	if err != nil {
+		return err
+	}
+
+	patchOpts := &PatchOptions{}
+	req := o.Patch(patch.Type()).
+		NamespaceIfScoped(o.GetNamespace(), o.isNamespaced()).
+		Resource(o.resource()).
+		Name(o.GetName())
+	if subResource != "" {
+		req = req.SubResource(subResource)
+	}
+	return withPatchParams(req, patchOpts.ApplyOptions(opts)).
+		Body(data).
+		Context(ctx).
+		Do().
+		Into(obj)
+}
+
 // Get implements client.Client
 func (c *typedClient) Get(ctx context.Context, key ObjectKey, obj runtime.Object) error {
 	r, err := c.cache.getResource(obj)
@@ -92,20 +147,18 @@ func (c *typedClient) Get(ctx context.Context, key ObjectKey, obj runtime.Object
 }
 
 // List implements client.Client
-func (c *typedClient) List(ctx context.Context, opts *ListOptions, obj runtime.Object) error {
+func (c *typedClient) List(ctx context.Context, obj runtime.Object, opts ...ListOptionFunc) error {
 	r, err := c.cache.getResource(obj)
 	if err != nil {
 		return err
 	}
-	namespace := ""
-	if opts != nil {
-		namespace = opts.Namespace
-	}
+	listOpts := ListOptions{}
+	listOpts.ApplyOptions(opts)
 	return r.Get().
-		NamespaceIfScoped(namespace, r.isNamespaced()).
+		NamespaceIfScoped(listOpts.Namespace, r.isNamespaced()).
 		Resource(r.resource()).
 		Body(obj).
-		VersionedParams(opts.AsListOptions(), c.paramCodec).
+		VersionedParams(listOpts.AsListOptions(), c.paramCodec).
 		Context(ctx).
 		Do().
 		Into(obj)
@@ -131,3 +184,18 @@ func (c *typedClient) UpdateStatus(ctx context.Context, obj runtime.Object) erro
 		Do().
 		Into(obj)
 }
+
+// PatchStatus used by StatusWriter to patch status.
+func (c *typedClient) PatchStatus(ctx context.Context, obj runtime.Object, patch Patch, opts ...PatchOptionFunc) error {
+	return c.patch(ctx, obj, patch, "status", opts...)
+}
+
+// withPatchParams sets the query parameters of opts on the patch request r.
+func withPatchParams(r *rest.Request, opts *PatchOptions) *rest.Request {
+	for key, values := range opts.AsParams() {
+		for _, value := range values {
+			r = r.Param(key, value)
+		}
+	}
+	return r
+}
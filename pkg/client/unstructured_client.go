@@ -26,7 +26,10 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
 // client is a client.Client that reads and writes directly from/to an API server.  It lazily initializes
@@ -34,6 +37,11 @@ import (
 type unstructuredClient struct {
 	client     dynamic.Interface
 	restMapper meta.RESTMapper
+
+	// config and codecs are used to create REST clients for patch requests, whose
+	// parameters are not all supported by the dynamic client.
+	config *rest.Config
+	codecs serializer.CodecFactory
 }
 
 // Create implements client.Client
@@ -87,6 +95,70 @@ func (uc *unstructuredClient) Delete(_ context.Context, obj runtime.Object, opts
 	return err
 }
 
+// DeleteAllOf implements client.Client
+func (uc *unstructuredClient) DeleteAllOf(_ context.Context, obj runtime.Object, opts ...DeleteAllOfOptionFunc) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unstructured client did not understand object: %T", obj)
+	}
+	deleteAllOfOpts := DeleteAllOfOptions{}
+	deleteAllOfOpts.ApplyOptions(opts)
+	r, err := uc.getResourceInterface(u.GroupVersionKind(), deleteAllOfOpts.ListOptions.Namespace)
+	if err != nil {
+		return err
+	}
+	return r.DeleteCollection(deleteAllOfOpts.AsDeleteOptions(), *deleteAllOfOpts.AsListOptions())
+}
+
+// Patch implements client.Client
+func (uc *unstructuredClient) Patch(ctx context.Context, obj runtime.Object, patch Patch, opts ...PatchOptionFunc) error {
+	return uc.patch(ctx, obj, patch, "", opts...)
+}
+
+// PatchStatus used by StatusWriter to patch status.
+func (uc *unstructuredClient) PatchStatus(ctx context.Context, obj runtime.Object, patch Patch, opts ...PatchOptionFunc) error {
+	return uc.patch(ctx, obj, patch, "status", opts...)
+}
+
+// patch patches obj, or its subResource if subResource is set.
+func (uc *unstructuredClient) patch(ctx context.Context, obj runtime.Object, patch Patch, subResource string, opts ...PatchOptionFunc) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unstructured client did not understand object: %T", obj)
+	}
+	gvk := u.GroupVersionKind()
+	mapping, err := uc.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return err
+	}
+	r, err := apiutil.RESTClientForGVK(gvk, uc.config, uc.codecs)
+	if err != nil {
+		return err
+	}
+	data, err := patch.Data(obj)
+	if err != nil {
+		return err
+	}
+
+	patchOpts := &PatchOptions{}
+	req := r.Patch(patch.Type()).
+		NamespaceIfScoped(u.GetNamespace(), mapping.Scope.Name() != meta.RESTScopeNameRoot).
+		Resource(mapping.Resource.Resource).
+		Name(u.GetName())
+	if subResource != "" {
+		req = req.SubResource(subResource)
+	}
+	raw, err := withPatchParams(req, patchOpts.ApplyOptions(opts)).
+		Body(data).
+		Context(ctx).
+		Do().
+		Raw()
+	if err != nil {
+		return err
+	}
+	return u.UnmarshalJSON(raw)
+}
+
 // Get implements client.Client
 func (uc *unstructuredClient) Get(_ context.Context, key ObjectKey, obj runtime.Object) error {
 	u, ok := obj.(*unstructured.Unstructured)
@@ -106,7 +178,7 @@ func (uc *unstructuredClient) Get(_ context.Context, key ObjectKey, obj runtime.
 }
 
 // List implements client.Client
-func (uc *unstructuredClient) List(_ context.Context, opts *ListOptions, obj runtime.Object) error {
+func (uc *unstructuredClient) List(_ context.Context, obj runtime.Object, opts ...ListOptionFunc) error {
 	u, ok := obj.(*unstructured.UnstructuredList)
 	if !ok {
 		return fmt.Errorf("unstructured client did not understand object: %T", obj)
@@ -115,16 +187,14 @@ func (uc *unstructuredClient) List(_ context.Context, opts *ListOptions, obj run
 	if strings.HasSuffix(gvk.Kind, "List") {
 		gvk.Kind = gvk.Kind[:len(gvk.Kind)-4]
 	}
-	namespace := ""
-	if opts != nil {
-		namespace = opts.Namespace
-	}
-	r, err := uc.getResourceInterface(gvk, namespace)
+	listOpts := ListOptions{}
+	listOpts.ApplyOptions(opts)
+	r, err := uc.getResourceInterface(gvk, listOpts.Namespace)
 	if err != nil {
 		return err
 	}
 
-	i, err := r.List(*opts.AsListOptions())
+	i, err := r.List(*listOpts.AsListOptions())
 	if err != nil {
 		return err
 	}
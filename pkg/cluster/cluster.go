@@ -0,0 +1,217 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Cluster provides the dependencies to interact with a Kubernetes cluster.
+type Cluster interface {
+	// GetConfig returns an initialized Config
+	GetConfig() *rest.Config
+
+	// GetScheme returns an initialized Scheme
+	GetScheme() *runtime.Scheme
+
+	// GetClient returns a client configured with the Config.  It reads from the Cache, and writes to the
+	// API server of the cluster.
+	GetClient() client.Client
+
+	// GetCache returns a cache.Cache of the objects of the cluster
+	GetCache() cache.Cache
+
+	// GetRESTMapper returns a RESTMapper
+	GetRESTMapper() meta.RESTMapper
+
+	// Start starts the Cache of the Cluster, and blocks until stop is closed.  A Cluster is started by the
+	// Manager it is added to.
+	Start(stop <-chan struct{}) error
+}
+
+// Options are the arguments for creating a new Cluster
+type Options struct {
+	// Scheme is the scheme used to resolve runtime.Objects to GroupVersionKinds / Resources
+	// Defaults to the kubernetes/client-go scheme.Scheme
+	Scheme *runtime.Scheme
+
+	// MapperProvider provides the rest mapper used to map go types to Kubernetes APIs
+	MapperProvider func(c *rest.Config) (meta.RESTMapper, error)
+
+	// SyncPeriod determines the minimum frequency at which the objects of the Cache are resynced.
+	// Defaults to the default of the Cache.
+	SyncPeriod *time.Duration
+
+	// Namespace if specified restricts the Cache to watch objects in the desired namespace.
+	// Defaults to all namespaces.
+	Namespace string
+
+	// NewCache is the function that will create the cache of the Cluster.
+	// If not set this will use the default new cache function.
+	NewCache NewCacheFunc
+
+	// NewClient will create the client of the Cluster.
+	// If not set this will create the default DelegatingClient that will
+	// use the cache for reads and the client for writes.
+	NewClient NewClientFunc
+}
+
+// NewCacheFunc allows a user to define how to create a cache
+type NewCacheFunc func(config *rest.Config, opts cache.Options) (cache.Cache, error)
+
+// NewClientFunc allows a user to define how to create a client
+type NewClientFunc func(cache cache.Cache, config *rest.Config, options client.Options) (client.Client, error)
+
+var _ Cluster = &cluster{}
+
+type cluster struct {
+	config *rest.Config
+	scheme *runtime.Scheme
+	cache  cache.Cache
+	client client.Client
+	mapper meta.RESTMapper
+}
+
+// New returns a new Cluster for the cluster of config.
+func New(config *rest.Config, options Options) (Cluster, error) {
+	if config == nil {
+		return nil, fmt.Errorf("must specify Config")
+	}
+	options = setOptionsDefaults(options)
+
+	mapper, err := options.MapperProvider(config)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := options.NewCache(config, cache.Options{
+		Scheme:    options.Scheme,
+		Mapper:    mapper,
+		Resync:    options.SyncPeriod,
+		Namespace: options.Namespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cl, err := options.NewClient(c, config, client.Options{Scheme: options.Scheme, Mapper: mapper})
+	if err != nil {
+		return nil, err
+	}
+
+	return &cluster{
+		config: config,
+		scheme: options.Scheme,
+		cache:  c,
+		client: cl,
+		mapper: mapper,
+	}, nil
+}
+
+// GetConfig implements Cluster
+func (c *cluster) GetConfig() *rest.Config {
+	return c.config
+}
+
+// GetScheme implements Cluster
+func (c *cluster) GetScheme() *runtime.Scheme {
+	return c.scheme
+}
+
+// GetClient implements Cluster
+func (c *cluster) GetClient() client.Client {
+	return c.client
+}
+
+// GetCache implements Cluster
+func (c *cluster) GetCache() cache.Cache {
+	return c.cache
+}
+
+// GetRESTMapper implements Cluster
+func (c *cluster) GetRESTMapper() meta.RESTMapper {
+	return c.mapper
+}
+
+// Start implements Cluster
+func (c *cluster) Start(stop <-chan struct{}) error {
+	return c.cache.Start(stop)
+}
+
+// DefaultNewClient creates the default caching client, which reads from cache, except the
+// options.UncachedObjects, and writes to the API server.
+func DefaultNewClient(cache cache.Cache, config *rest.Config, options client.Options) (client.Client, error) {
+	// Create the Client for Write operations.
+	c, err := client.New(config, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, obj := range options.UncachedObjects {
+		if _, err := apiutil.GVKForObject(obj, options.Scheme); err != nil {
+			return nil, fmt.Errorf("unable to disable caching for %T: %v", obj, err)
+		}
+	}
+
+	var reader client.Reader = &client.DelegatingReader{
+		CacheReader:       cache,
+		ClientReader:      c,
+		Scheme:            options.Scheme,
+		UncachedObjects:   options.UncachedObjects,
+		CacheUnstructured: options.CacheUnstructured,
+	}
+	if options.StripManagedFields {
+		reader = &client.ManagedFieldsStrippingReader{Reader: reader}
+	}
+
+	return &client.DelegatingClient{
+		Reader:       reader,
+		Writer:       c,
+		StatusClient: c,
+	}, nil
+}
+
+// setOptionsDefaults set default values for Options fields
+func setOptionsDefaults(options Options) Options {
+	// Use the Kubernetes client-go scheme if none is specified
+	if options.Scheme == nil {
+		options.Scheme = scheme.Scheme
+	}
+
+	if options.MapperProvider == nil {
+		options.MapperProvider = apiutil.NewDiscoveryRESTMapper
+	}
+
+	if options.NewClient == nil {
+		options.NewClient = DefaultNewClient
+	}
+
+	if options.NewCache == nil {
+		options.NewCache = cache.New
+	}
+
+	return options
+}
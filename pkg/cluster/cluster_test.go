@@ -0,0 +1,111 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+)
+
+var _ = Describe("Cluster", func() {
+	var cfg *rest.Config
+	var mapper meta.RESTMapper
+	var opts cluster.Options
+
+	BeforeEach(func() {
+		cfg = &rest.Config{Host: "https://remote.example.com"}
+		mapper = meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion})
+		opts = cluster.Options{
+			MapperProvider: func(*rest.Config) (meta.RESTMapper, error) { return mapper, nil },
+		}
+	})
+
+	Describe("New", func() {
+		It("should return an error if the Config is not set", func() {
+			_, err := cluster.New(nil, opts)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return the dependencies of the cluster", func() {
+			c, err := cluster.New(cfg, opts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.GetConfig()).To(BeIdenticalTo(cfg))
+			Expect(c.GetScheme()).To(BeIdenticalTo(scheme.Scheme))
+			Expect(c.GetRESTMapper()).To(BeIdenticalTo(mapper))
+			Expect(c.GetCache()).NotTo(BeNil())
+			Expect(c.GetClient()).To(BeAssignableToTypeOf(&client.DelegatingClient{}))
+		})
+
+		It("should create the cache and client with the options", func() {
+			s := runtime.NewScheme()
+			informers := &informertest.FakeInformers{}
+			var cacheOpts cache.Options
+			opts.Scheme = s
+			opts.Namespace = "foo"
+			opts.NewCache = func(config *rest.Config, o cache.Options) (cache.Cache, error) {
+				Expect(config).To(BeIdenticalTo(cfg))
+				cacheOpts = o
+				return informers, nil
+			}
+			opts.NewClient = func(c cache.Cache, config *rest.Config, o client.Options) (client.Client, error) {
+				Expect(c).To(BeIdenticalTo(informers))
+				Expect(o.Scheme).To(BeIdenticalTo(s))
+				Expect(o.Mapper).To(BeIdenticalTo(mapper))
+				return nil, nil
+			}
+
+			c, err := cluster.New(cfg, opts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.GetCache()).To(BeIdenticalTo(informers))
+			Expect(cacheOpts.Scheme).To(BeIdenticalTo(s))
+			Expect(cacheOpts.Mapper).To(BeIdenticalTo(mapper))
+			Expect(cacheOpts.Namespace).To(Equal("foo"))
+		})
+
+		It("should return the errors creating the dependencies", func() {
+			opts.NewCache = func(*rest.Config, cache.Options) (cache.Cache, error) { return nil, fmt.Errorf("expected error") }
+			_, err := cluster.New(cfg, opts)
+			Expect(err).To(MatchError("expected error"))
+
+			opts.MapperProvider = func(*rest.Config) (meta.RESTMapper, error) { return nil, fmt.Errorf("expected error") }
+			_, err = cluster.New(cfg, opts)
+			Expect(err).To(MatchError("expected error"))
+		})
+	})
+
+	Describe("Start", func() {
+		It("should start the cache", func() {
+			informers := &informertest.FakeInformers{Error: fmt.Errorf("expected error")}
+			opts.NewCache = func(*rest.Config, cache.Options) (cache.Cache, error) { return informers, nil }
+			c, err := cluster.New(cfg, opts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Start(make(chan struct{}))).To(MatchError("expected error"))
+		})
+	})
+})
@@ -0,0 +1,41 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package cluster provides the dependencies to interact with a Kubernetes cluster other than the cluster of the
+Manager, e.g. a remote management cluster watched by Controllers acting on a workload cluster.
+
+A Cluster bundles the rest.Config, Scheme, Client, Cache and RESTMapper of a cluster.  It is added to the Manager
+to start its Cache, and the Kinds of the cluster are watched with source.NewKindWithCache:
+
+	remote, err := cluster.New(remoteConfig, cluster.Options{})
+	if err != nil {
+		// handle error
+	}
+	if err := mgr.Add(remote); err != nil {
+		// handle error
+	}
+	err = c.Watch(source.NewKindWithCache(&corev1.Secret{}, remote.GetCache()), &handler.EnqueueRequestForObject{})
+	if err != nil {
+		// handle error
+	}
+
+Clusters can also be discovered dynamically by a Provider, such as the KubeconfigSecretProvider reading the
+kubeconfig Secrets of the Cluster API.  The Provider of a Manager is set with manager.Options.ClusterProvider,
+and engages each Runnable implementing Aware with every cluster as it comes, closing the stop channel the
+Runnable was engaged with once the cluster goes.
+*/
+package cluster
@@ -0,0 +1,208 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var log = logf.KBLog.WithName("cluster")
+
+const (
+	// DefaultKubeconfigKey is the key of the kubeconfig in the data of the Secrets read by a
+	// KubeconfigSecretProvider, if Key is not set.  It is the key used by the Cluster API.
+	DefaultKubeconfigKey = "value"
+	// DefaultKubeconfigSecretInterval is how often the Secrets are listed by a
+	// KubeconfigSecretProvider, if Interval is not set.
+	DefaultKubeconfigSecretInterval = 30 * time.Second
+)
+
+// KubeconfigSecretProvider is a Provider discovering the clusters whose kubeconfigs are stored in
+// Secrets, such as the <cluster>-kubeconfig Secrets of the Cluster API.  Each Secret is a cluster,
+// named by the namespace/name of the Secret.  A cluster whose kubeconfig changes is disengaged and
+// engaged again with a new Cluster.
+type KubeconfigSecretProvider struct {
+	// Client is used to list the Secrets.  It is injected by the Manager if not set.
+	Client client.Reader
+
+	// Namespace if specified restricts the Secrets to the desired namespace.
+	// Defaults to all namespaces.
+	Namespace string
+
+	// Labels if specified restricts the Secrets to those with the labels, e.g.
+	// cluster.x-k8s.io/cluster-name.
+	Labels map[string]string
+
+	// Key is the key of the kubeconfig in the data of the Secrets.
+	// Defaults to DefaultKubeconfigKey.
+	Key string
+
+	// Interval is how often the Secrets are listed.
+	// Defaults to DefaultKubeconfigSecretInterval.
+	Interval time.Duration
+
+	// Options are the Options the Clusters are created with.
+	Options Options
+
+	// NewCluster creates the Cluster of a kubeconfig.  Defaults to New.
+	NewCluster func(config *rest.Config, options Options) (Cluster, error)
+}
+
+var _ Provider = &KubeconfigSecretProvider{}
+var _ inject.Client = &KubeconfigSecretProvider{}
+
+// engagement is a cluster engaged by a KubeconfigSecretProvider.
+type engagement struct {
+	kubeconfig []byte
+	stop       chan struct{}
+}
+
+// InjectClient injects the client into the KubeconfigSecretProvider, unless one was already set.
+func (p *KubeconfigSecretProvider) InjectClient(c client.Client) error {
+	if p.Client == nil {
+		p.Client = c
+	}
+	return nil
+}
+
+func (p *KubeconfigSecretProvider) setDefaults() {
+	if p.Key == "" {
+		p.Key = DefaultKubeconfigKey
+	}
+	if p.Interval == 0 {
+		p.Interval = DefaultKubeconfigSecretInterval
+	}
+	if p.NewCluster == nil {
+		p.NewCluster = New
+	}
+}
+
+// Run implements Provider.  It lists the Secrets every Interval, engaging the cluster of each new
+// Secret and disengaging the cluster of each deleted Secret.  Clusters which cannot be engaged are
+// retried on the next list.
+func (p *KubeconfigSecretProvider) Run(aware Aware, stop <-chan struct{}) error {
+	if p.Client == nil {
+		return errors.New("client must be set in KubeconfigSecretProvider")
+	}
+	p.setDefaults()
+
+	engaged := map[string]*engagement{}
+	defer func() {
+		for name := range engaged {
+			p.disengage(name, engaged)
+		}
+	}()
+
+	p.sync(aware, engaged)
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.sync(aware, engaged)
+		case <-stop:
+			return nil
+		}
+	}
+}
+
+// sync engages and disengages the clusters of engaged to match the listed Secrets.
+func (p *KubeconfigSecretProvider) sync(aware Aware, engaged map[string]*engagement) {
+	opts := []client.ListOptionFunc{client.InNamespace(p.Namespace)}
+	if len(p.Labels) != 0 {
+		opts = append(opts, client.MatchingLabels(p.Labels))
+	}
+	secrets := &corev1.SecretList{}
+	if err := p.Client.List(context.TODO(), secrets, opts...); err != nil {
+		log.Error(err, "unable to list kubeconfig Secrets", "namespace", p.Namespace)
+		return
+	}
+
+	found := map[string]bool{}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		name := types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}.String()
+		found[name] = true
+
+		kubeconfig := secret.Data[p.Key]
+		if e, ok := engaged[name]; ok {
+			if bytes.Equal(e.kubeconfig, kubeconfig) {
+				continue
+			}
+			// The kubeconfig changed, so the cluster is engaged again with a new Cluster
+			p.disengage(name, engaged)
+		}
+		if err := p.engage(aware, name, kubeconfig, engaged); err != nil {
+			log.Error(err, "unable to engage cluster", "cluster", name)
+		}
+	}
+
+	for name := range engaged {
+		if !found[name] {
+			p.disengage(name, engaged)
+		}
+	}
+}
+
+// engage creates and starts the Cluster of kubeconfig, and engages aware with it.
+func (p *KubeconfigSecretProvider) engage(aware Aware, name string, kubeconfig []byte, engaged map[string]*engagement) error {
+	if len(kubeconfig) == 0 {
+		return fmt.Errorf("secret has no %q key", p.Key)
+	}
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+	cl, err := p.NewCluster(config, p.Options)
+	if err != nil {
+		return err
+	}
+
+	stop := make(chan struct{})
+	go func() {
+		if err := cl.Start(stop); err != nil {
+			log.Error(err, "unable to start cluster", "cluster", name)
+		}
+	}()
+	if err := aware.Engage(name, cl, stop); err != nil {
+		close(stop)
+		return err
+	}
+
+	log.Info("Engaged cluster", "cluster", name)
+	engaged[name] = &engagement{kubeconfig: kubeconfig, stop: stop}
+	return nil
+}
+
+// disengage closes the stop channel the cluster name was engaged with.
+func (p *KubeconfigSecretProvider) disengage(name string, engaged map[string]*engagement) {
+	close(engaged[name].stop)
+	delete(engaged, name)
+	log.Info("Disengaged cluster", "cluster", name)
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+)
+
+var _ = Describe("KubeconfigSecretProvider", func() {
+	var c client.Client
+	var provider *cluster.KubeconfigSecretProvider
+	var aware *recordingAware
+	var stop chan struct{}
+	var stopped chan struct{}
+
+	BeforeEach(func() {
+		c = fake.NewFakeClient()
+		aware = &recordingAware{stops: map[string]<-chan struct{}{}}
+		provider = &cluster.KubeconfigSecretProvider{
+			Client:    c,
+			Namespace: "clusters",
+			Interval:  10 * time.Millisecond,
+			Options: cluster.Options{
+				MapperProvider: func(*rest.Config) (meta.RESTMapper, error) { return meta.NewDefaultRESTMapper(nil), nil },
+				NewCache: func(*rest.Config, cache.Options) (cache.Cache, error) {
+					return &informertest.FakeInformers{}, nil
+				},
+			},
+		}
+		stop = make(chan struct{})
+		stopped = make(chan struct{})
+	})
+
+	run := func() {
+		go func() {
+			defer GinkgoRecover()
+			defer close(stopped)
+			Expect(provider.Run(aware, stop)).To(Succeed())
+		}()
+	}
+
+	AfterEach(func() {
+		close(stop)
+		Eventually(stopped).Should(BeClosed())
+	})
+
+	It("should engage the cluster of each Secret", func() {
+		Expect(c.Create(context.TODO(), kubeconfigSecret("clusters", "foo-kubeconfig", "https://foo.example.com"))).To(Succeed())
+		Expect(c.Create(context.TODO(), kubeconfigSecret("other", "bar-kubeconfig", "https://bar.example.com"))).To(Succeed())
+		run()
+
+		Eventually(aware.hosts).Should(Equal(map[string]string{"clusters/foo-kubeconfig": "https://foo.example.com"}))
+		Consistently(aware.hosts).Should(HaveLen(1))
+	})
+
+	It("should only engage the clusters of the Secrets with the Labels", func() {
+		provider.Labels = map[string]string{"cluster.x-k8s.io/cluster-name": "foo"}
+		foo := kubeconfigSecret("clusters", "foo-kubeconfig", "https://foo.example.com")
+		foo.Labels = map[string]string{"cluster.x-k8s.io/cluster-name": "foo"}
+		Expect(c.Create(context.TODO(), foo)).To(Succeed())
+		Expect(c.Create(context.TODO(), kubeconfigSecret("clusters", "bar-kubeconfig", "https://bar.example.com"))).To(Succeed())
+		run()
+
+		Eventually(aware.hosts).Should(Equal(map[string]string{"clusters/foo-kubeconfig": "https://foo.example.com"}))
+		Consistently(aware.hosts).Should(HaveLen(1))
+	})
+
+	It("should disengage the cluster of a deleted Secret", func() {
+		secret := kubeconfigSecret("clusters", "foo-kubeconfig", "https://foo.example.com")
+		Expect(c.Create(context.TODO(), secret)).To(Succeed())
+		run()
+		Eventually(aware.hosts).Should(HaveLen(1))
+		engaged := aware.stop("clusters/foo-kubeconfig")
+
+		Expect(c.Delete(context.TODO(), secret)).To(Succeed())
+		Eventually(engaged).Should(BeClosed())
+	})
+
+	It("should engage a new Cluster when the kubeconfig changes", func() {
+		secret := kubeconfigSecret("clusters", "foo-kubeconfig", "https://foo.example.com")
+		Expect(c.Create(context.TODO(), secret)).To(Succeed())
+		run()
+		Eventually(aware.hosts).Should(HaveLen(1))
+		engaged := aware.stop("clusters/foo-kubeconfig")
+
+		Expect(c.Update(context.TODO(), kubeconfigSecret("clusters", "foo-kubeconfig", "https://new.example.com"))).To(Succeed())
+		Eventually(engaged).Should(BeClosed())
+		Eventually(aware.hosts).Should(Equal(map[string]string{"clusters/foo-kubeconfig": "https://new.example.com"}))
+		Expect(aware.stop("clusters/foo-kubeconfig")).NotTo(BeClosed())
+	})
+
+	It("should retry the clusters which can't be engaged", func() {
+		secret := kubeconfigSecret("clusters", "foo-kubeconfig", "https://foo.example.com")
+		Expect(c.Create(context.TODO(), secret)).To(Succeed())
+		aware.setError(fmt.Errorf("expected error"))
+		run()
+		Eventually(aware.attempts).Should(BeNumerically(">", 1))
+		Expect(aware.hosts()).To(BeEmpty())
+
+		aware.setError(nil)
+		Eventually(aware.hosts).Should(HaveLen(1))
+	})
+
+	It("should not engage the clusters of Secrets without a kubeconfig", func() {
+		secret := kubeconfigSecret("clusters", "foo-kubeconfig", "https://foo.example.com")
+		secret.Data = map[string][]byte{"other": []byte("value")}
+		Expect(c.Create(context.TODO(), secret)).To(Succeed())
+		run()
+		Consistently(aware.attempts).Should(BeZero())
+	})
+
+	It("should disengage every cluster when stopped", func() {
+		Expect(c.Create(context.TODO(), kubeconfigSecret("clusters", "foo-kubeconfig", "https://foo.example.com"))).To(Succeed())
+		run()
+		Eventually(aware.hosts).Should(HaveLen(1))
+		engaged := aware.stop("clusters/foo-kubeconfig")
+
+		close(stop)
+		Eventually(engaged).Should(BeClosed())
+		stop = make(chan struct{})
+	})
+
+	It("should return an error if the Client is not set", func() {
+		provider.Client = nil
+		Expect(provider.Run(aware, stop)).To(HaveOccurred())
+		close(stopped)
+	})
+})
+
+// kubeconfigSecret returns a Secret holding a kubeconfig for the API server at host.
+func kubeconfigSecret(namespace, name, host string) *corev1.Secret {
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: remote
+  cluster:
+    server: %s
+contexts:
+- name: remote
+  context:
+    cluster: remote
+current-context: remote
+`, host)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Data:       map[string][]byte{cluster.DefaultKubeconfigKey: []byte(kubeconfig)},
+	}
+}
+
+// recordingAware is a cluster.Aware recording the clusters it is engaged with.
+type recordingAware struct {
+	mu      sync.Mutex
+	stops   map[string]<-chan struct{}
+	configs map[string]string
+	tries   int
+	err     error
+}
+
+func (a *recordingAware) Engage(name string, cl cluster.Cluster, stop <-chan struct{}) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.tries++
+	if a.err != nil {
+		return a.err
+	}
+	if a.configs == nil {
+		a.configs = map[string]string{}
+	}
+	a.configs[name] = cl.GetConfig().Host
+	a.stops[name] = stop
+	return nil
+}
+
+func (a *recordingAware) setError(err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.err = err
+}
+
+func (a *recordingAware) attempts() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.tries
+}
+
+func (a *recordingAware) stop(name string) <-chan struct{} {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.stops[name]
+}
+
+// hosts returns the hosts of the engaged clusters that have not been disengaged, by name.
+func (a *recordingAware) hosts() map[string]string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	hosts := map[string]string{}
+	for name, host := range a.configs {
+		select {
+		case <-a.stops[name]:
+		default:
+			hosts[name] = host
+		}
+	}
+	return hosts
+}
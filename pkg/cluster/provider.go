@@ -0,0 +1,41 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+// Aware is implemented by the components which act on each of the clusters discovered by a Provider, such
+// as the Runnables of a Manager which run a Controller for every cluster.
+type Aware interface {
+	// Engage starts acting on cl, the cluster called name, until stop is closed once the cluster is gone.
+	// Engage must not block.  If it returns an error the cluster is disengaged.
+	Engage(name string, cl Cluster, stop <-chan struct{}) error
+}
+
+// AwareFunc implements Aware
+type AwareFunc func(name string, cl Cluster, stop <-chan struct{}) error
+
+// Engage implements Aware
+func (f AwareFunc) Engage(name string, cl Cluster, stop <-chan struct{}) error {
+	return f(name, cl, stop)
+}
+
+// Provider discovers clusters dynamically, e.g. from the clusters of the Cluster API.
+type Provider interface {
+	// Run engages aware with each cluster as it is discovered, and closes the stop channel it was engaged
+	// with once the cluster is gone.  Run starts the Cache of each Cluster it engages, and blocks until stop
+	// is closed, after which every cluster is disengaged.
+	Run(aware Aware, stop <-chan struct{}) error
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+// Backoff configures an exponential backoff between the attempts of an operation, such as
+// the retries of client.UpdateWithRetry.
+type Backoff struct {
+	// Duration is the wait before the second attempt.  Defaults to 10ms.
+	Duration *Duration `json:"duration,omitempty"`
+
+	// Factor multiplies the wait after each attempt.  Must be at least 1.  Defaults to 5.
+	Factor *float64 `json:"factor,omitempty"`
+
+	// Jitter randomly adds up to Jitter times the wait to each wait.  Must not be negative.
+	// Defaults to 0.1.
+	Jitter *float64 `json:"jitter,omitempty"`
+
+	// Steps is the number of attempts.  Must be positive.  Defaults to 4.
+	Steps *int `json:"steps,omitempty"`
+}
+
+// Default sets the unset fields of b to the values of retry.DefaultBackoff.
+func (b *Backoff) Default() {
+	def := retry.DefaultBackoff
+	b.Duration = durationOrDefault(b.Duration, def.Duration)
+	if b.Factor == nil {
+		b.Factor = &def.Factor
+	}
+	if b.Jitter == nil {
+		b.Jitter = &def.Jitter
+	}
+	if b.Steps == nil {
+		b.Steps = &def.Steps
+	}
+}
+
+// Validate returns the errors of the set fields of b, which is at path in the configuration.
+func (b *Backoff) Validate(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if b.Duration != nil && b.Duration.Duration < 0 {
+		errs = append(errs, field.Invalid(path.Child("duration"), b.Duration.String(), "must not be negative"))
+	}
+	if b.Factor != nil && *b.Factor < 1 {
+		errs = append(errs, field.Invalid(path.Child("factor"), *b.Factor, "must be at least 1"))
+	}
+	if b.Jitter != nil && *b.Jitter < 0 {
+		errs = append(errs, field.Invalid(path.Child("jitter"), *b.Jitter, "must not be negative"))
+	}
+	if b.Steps != nil && *b.Steps < 1 {
+		errs = append(errs, field.Invalid(path.Child("steps"), *b.Steps, "must be positive"))
+	}
+	return errs
+}
+
+// Backoff returns b as a wait.Backoff.  Unset fields default as in Default.
+func (b Backoff) Backoff() wait.Backoff {
+	b.Default()
+	return wait.Backoff{
+		Duration: b.Duration.Duration,
+		Factor:   *b.Factor,
+		Jitter:   *b.Jitter,
+		Steps:    *b.Steps,
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+)
+
+var _ = Describe("Backoff", func() {
+	It("should default to retry.DefaultBackoff", func() {
+		var b config.Backoff
+		Expect(b.Backoff()).To(Equal(retry.DefaultBackoff))
+
+		b.Default()
+		Expect(b.Duration.Duration).To(Equal(retry.DefaultBackoff.Duration))
+		Expect(*b.Steps).To(Equal(retry.DefaultBackoff.Steps))
+	})
+
+	It("should only default the unset fields", func() {
+		var b config.Backoff
+		Expect(config.Decode([]byte("duration: 1s\nsteps: 10\n"), &b)).To(Succeed())
+		Expect(b.Backoff()).To(Equal(wait.Backoff{
+			Duration: time.Second,
+			Factor:   retry.DefaultBackoff.Factor,
+			Jitter:   retry.DefaultBackoff.Jitter,
+			Steps:    10,
+		}))
+	})
+
+	It("should reject invalid values", func() {
+		var b config.Backoff
+		Expect(config.Decode([]byte("duration: -1s\nfactor: 0.5\njitter: -1\nsteps: 0\n"), &b)).To(Succeed())
+		errs := b.Validate(field.NewPath("backoff"))
+		Expect(errs).To(HaveLen(4))
+		Expect(errs[0].Field).To(Equal("backoff.duration"))
+		Expect(errs.ToAggregate().Error()).To(ContainSubstring("backoff.steps: Invalid value: 0: must be positive"))
+	})
+
+	It("should accept valid values", func() {
+		var b config.Backoff
+		b.Default()
+		Expect(b.Validate(field.NewPath("backoff"))).To(BeEmpty())
+	})
+})
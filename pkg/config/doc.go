@@ -0,0 +1,36 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package config contains types for tuning controllers from configuration files, such as
+the backoff of retries and the rate limiter of controller queues.
+
+The types have optional fields, so that a configuration file only needs to set the
+values it changes.  Decode a file with Decode, which rejects unknown fields, then call
+Default to fill in the unset fields and Validate to reject invalid values before using
+the configuration, e.g.
+
+	var rl config.RateLimiter
+	if err := config.Decode(data, &rl); err != nil {
+		return err
+	}
+	rl.Default()
+	if errs := rl.Validate(field.NewPath("rateLimiter")); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+	queue := workqueue.NewNamedRateLimitingQueue(rl.RateLimiter(), name)
+*/
+package config
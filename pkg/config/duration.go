@@ -0,0 +1,80 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ghodss/yaml"
+)
+
+// Duration is a time.Duration written as a string such as "1m30s" in configuration files.
+// Bare numbers are rejected, since their unit would be ambiguous.
+type Duration struct {
+	time.Duration
+}
+
+// ParseDuration parses s as a Duration, e.g. "1m30s".
+func ParseDuration(s string) (Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return Duration{}, err
+	}
+	return Duration{Duration: d}, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("invalid duration %s: durations must be strings with a unit, such as \"30s\"", b)
+	}
+	parsed, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Duration.String())
+}
+
+// durationOrDefault returns d, or def if d is nil.
+func durationOrDefault(d *Duration, def time.Duration) *Duration {
+	if d != nil {
+		return d
+	}
+	return &Duration{Duration: def}
+}
+
+// Decode decodes the YAML or JSON document data into obj, failing on fields which obj does
+// not have so that misspelled settings are not silently ignored.
+func Decode(data []byte, obj interface{}) error {
+	j, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(j))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(obj)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+)
+
+var _ = Describe("Duration", func() {
+	It("should be read from strings with a unit", func() {
+		var d config.Duration
+		Expect(json.Unmarshal([]byte(`"1m30s"`), &d)).To(Succeed())
+		Expect(d.Duration).To(Equal(90 * time.Second))
+	})
+
+	It("should be written as a string", func() {
+		b, err := json.Marshal(config.Duration{Duration: 90 * time.Second})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal(`"1m30s"`))
+	})
+
+	It("should reject bare numbers", func() {
+		var d config.Duration
+		err := json.Unmarshal([]byte(`30`), &d)
+		Expect(err).To(MatchError(ContainSubstring("durations must be strings with a unit")))
+	})
+
+	It("should reject invalid strings", func() {
+		_, err := config.ParseDuration("30 seconds")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Decode", func() {
+	It("should decode YAML documents", func() {
+		var rl config.RateLimiter
+		Expect(config.Decode([]byte("baseDelay: 10ms\nqps: 50\n"), &rl)).To(Succeed())
+		Expect(rl.BaseDelay.Duration).To(Equal(10 * time.Millisecond))
+		Expect(*rl.QPS).To(Equal(50.0))
+		Expect(rl.MaxDelay).To(BeNil())
+	})
+
+	It("should reject unknown fields", func() {
+		var rl config.RateLimiter
+		err := config.Decode([]byte("baseDelay: 10ms\nqsp: 50\n"), &rl)
+		Expect(err).To(MatchError(ContainSubstring("unknown field \"qsp\"")))
+	})
+})
@@ -0,0 +1,93 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// The defaults of RateLimiter, which are those of workqueue.DefaultControllerRateLimiter.
+const (
+	DefaultBaseDelay = 5 * time.Millisecond
+	DefaultMaxDelay  = 1000 * time.Second
+	DefaultQPS       = 10
+	DefaultBurst     = 100
+)
+
+// RateLimiter configures the rate limiter of a controller queue, which delays the
+// requeues of each item exponentially, and limits the overall rate of requeues.
+type RateLimiter struct {
+	// BaseDelay is the delay of the first requeue of an item.  Defaults to 5ms.
+	BaseDelay *Duration `json:"baseDelay,omitempty"`
+
+	// MaxDelay is the maximum delay of the requeues of an item.  Must not be less than
+	// BaseDelay.  Defaults to 1000s.
+	MaxDelay *Duration `json:"maxDelay,omitempty"`
+
+	// QPS is the overall rate of requeues.  Must be positive.  Defaults to 10.
+	QPS *float64 `json:"qps,omitempty"`
+
+	// Burst is the number of requeues allowed above QPS in bursts.  Must be positive.
+	// Defaults to 100.
+	Burst *int `json:"burst,omitempty"`
+}
+
+// Default sets the unset fields of r to the values of workqueue.DefaultControllerRateLimiter.
+func (r *RateLimiter) Default() {
+	r.BaseDelay = durationOrDefault(r.BaseDelay, DefaultBaseDelay)
+	r.MaxDelay = durationOrDefault(r.MaxDelay, DefaultMaxDelay)
+	if r.QPS == nil {
+		qps := float64(DefaultQPS)
+		r.QPS = &qps
+	}
+	if r.Burst == nil {
+		burst := DefaultBurst
+		r.Burst = &burst
+	}
+}
+
+// Validate returns the errors of the set fields of r, which is at path in the configuration.
+func (r *RateLimiter) Validate(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if r.BaseDelay != nil && r.BaseDelay.Duration < 0 {
+		errs = append(errs, field.Invalid(path.Child("baseDelay"), r.BaseDelay.String(), "must not be negative"))
+	}
+	if r.MaxDelay != nil && r.MaxDelay.Duration < durationOrDefault(r.BaseDelay, DefaultBaseDelay).Duration {
+		errs = append(errs, field.Invalid(path.Child("maxDelay"), r.MaxDelay.String(), "must not be less than baseDelay"))
+	}
+	if r.QPS != nil && *r.QPS <= 0 {
+		errs = append(errs, field.Invalid(path.Child("qps"), *r.QPS, "must be positive"))
+	}
+	if r.Burst != nil && *r.Burst < 1 {
+		errs = append(errs, field.Invalid(path.Child("burst"), *r.Burst, "must be positive"))
+	}
+	return errs
+}
+
+// RateLimiter returns the workqueue.RateLimiter configured by r.  Unset fields default as
+// in Default.
+func (r RateLimiter) RateLimiter() workqueue.RateLimiter {
+	r.Default()
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(r.BaseDelay.Duration, r.MaxDelay.Duration),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(*r.QPS), *r.Burst)},
+	)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+)
+
+var _ = Describe("RateLimiter", func() {
+	It("should default to the default controller rate limiter", func() {
+		var rl config.RateLimiter
+		rl.Default()
+		Expect(rl.BaseDelay.Duration).To(Equal(config.DefaultBaseDelay))
+		Expect(rl.MaxDelay.Duration).To(Equal(config.DefaultMaxDelay))
+		Expect(*rl.QPS).To(BeEquivalentTo(config.DefaultQPS))
+		Expect(*rl.Burst).To(Equal(config.DefaultBurst))
+		Expect(rl.Validate(field.NewPath("rateLimiter"))).To(BeEmpty())
+	})
+
+	It("should delay the requeues of items exponentially up to the max delay", func() {
+		var rl config.RateLimiter
+		Expect(config.Decode([]byte("baseDelay: 1s\nmaxDelay: 3s\n"), &rl)).To(Succeed())
+		limiter := rl.RateLimiter()
+		Expect(limiter.When("item")).To(Equal(time.Second))
+		Expect(limiter.When("item")).To(Equal(2 * time.Second))
+		Expect(limiter.When("item")).To(Equal(3 * time.Second))
+		Expect(limiter.When("other")).To(Equal(time.Second))
+
+		limiter.Forget("item")
+		Expect(limiter.When("item")).To(Equal(time.Second))
+	})
+
+	It("should reject invalid values", func() {
+		var rl config.RateLimiter
+		Expect(config.Decode([]byte("baseDelay: 1s\nmaxDelay: 500ms\nqps: 0\nburst: 0\n"), &rl)).To(Succeed())
+		errs := rl.Validate(field.NewPath("rateLimiter"))
+		Expect(errs).To(HaveLen(3))
+		Expect(errs[0].Field).To(Equal("rateLimiter.maxDelay"))
+		Expect(errs[0].Detail).To(Equal("must not be less than baseDelay"))
+	})
+})
@@ -18,12 +18,15 @@ package controller
 
 import (
 	"fmt"
+	"time"
 
+	"k8s.io/apimachinery/pkg/util/clock"
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/internal/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
@@ -31,10 +34,129 @@ import (
 // Options are the arguments for creating a new Controller
 type Options struct {
 	// MaxConcurrentReconciles is the maximum number of concurrent Reconciles which can be run. Defaults to 1.
+	// Whatever the concurrency, a reconcile.Request is never reconciled by two workers at once: a Request
+	// enqueued again while being reconciled is reconciled again once the first Reconcile returns, even
+	// with a queue constructed by NewQueue which doesn't ensure this.  The saturation of the workers is the
+	// ratio of the controller_runtime_reconcile_active_workers and controller_runtime_reconcile_max_workers
+	// metrics.
 	MaxConcurrentReconciles int
 
 	// Reconciler reconciles an object
 	Reconciler reconcile.Reconciler
+
+	// NamespaceQPS is the maximum number of reconcile.Requests per second which may be enqueued for any
+	// single namespace.  Requests exceeding the limit are delayed rather than dropped.  Defaults to 0,
+	// which disables per-namespace throttling.
+	NamespaceQPS float32
+
+	// NamespaceBurst is the maximum number of reconcile.Requests which may be enqueued for any single
+	// namespace at once when NamespaceQPS is set.  Defaults to 1.
+	NamespaceBurst int
+
+	// NewQueue constructs the queue of the Controller.  The queue may be any implementation of
+	// Queue, such as a priority, persistent or fair queue.  Defaults to
+	// a client-go named rate limiting queue using the RateLimiter.  Use
+	// priorityqueue.NewQueue to reconcile the Requests of some events or objects first.
+	NewQueue NewQueueFunc
+
+	// RateLimiter delays the reconcile.Requests requeued by the Controller, e.g. after errors, in the
+	// default queue.  The ratelimiter package contains common presets.  It can't be set together with
+	// NewQueue, which constructs the rate limiter of its queue.  Defaults to ratelimiter.Default().
+	RateLimiter workqueue.RateLimiter
+
+	// ShutdownPolicy is what the Controller does with its queued reconcile.Requests once it is stopped.
+	// Defaults to ShutdownImmediate.
+	ShutdownPolicy ShutdownPolicy
+
+	// ShutdownTimeout is the maximum time the Controller drains once it is stopped with the
+	// ShutdownDrainWithTimeout ShutdownPolicy.  It is required for that policy and ignored otherwise.
+	ShutdownTimeout time.Duration
+
+	// RequeueAfterJitter is the maximum fraction of the RequeueAfter of a reconcile.Result added at random
+	// to the delay of the requeued reconcile.Request, e.g. 0.1 delays it by up to 10% more, so that the
+	// many objects requeued after the same interval are not all reconciled at once.  It must be between
+	// 0 and 1.  Defaults to 0, which requeues after exactly RequeueAfter.
+	RequeueAfterJitter float64
+
+	// RecoverPanic makes the Controller recover from the panics of the Reconciler, logging the panic with
+	// its stack and counting it in the controller_runtime_reconcile_panics_total metric.  The panicking
+	// reconcile.Request is requeued as if the Reconciler had returned an error.  Defaults to false, which
+	// crashes the process on panics.
+	RecoverPanic bool
+
+	// TrackProvenance makes the Controller record, for every reconcile.Request, the Source and EventHandler
+	// of the watch which last enqueued it.  They are served by the /debug/provenance endpoint of the Manager,
+	// to find out why an object is reconciled constantly.  Defaults to false.
+	TrackProvenance bool
+
+	// LivenessTimeout, if set, adds a healthz check named "controller-<name>" to the Manager, which fails
+	// once the Controller has had queued reconcile.Requests without reconciling any for LivenessTimeout,
+	// e.g. because all its workers are deadlocked, so that the liveness probe restarts the wedged operator.
+	// It must be longer than the longest expected reconcile.  Defaults to 0, which adds no check.
+	LivenessTimeout time.Duration
+
+	// StuckReconcileThreshold, if set, makes the Controller log the stack of the worker of any single
+	// Reconcile which has not returned after StuckReconcileThreshold, and count it in the
+	// controller_runtime_reconcile_stuck_total metric, to find out which call, e.g. to an external service,
+	// a Reconciler is stuck in.  The Reconcile is not interrupted.  Defaults to 0, which disables the watchdog.
+	StuckReconcileThreshold time.Duration
+
+	// LogSuccessEvery samples the info logs of successful reconciles, logging only one in every
+	// LogSuccessEvery of them, to reduce the log volume of controllers reconciling many objects.  Errors
+	// are always logged.  Defaults to 0, which like 1 logs every successful reconcile.
+	LogSuccessEvery int
+
+	// NeedLeaderElection is whether the Controller must only be started once the Manager is elected leader,
+	// so that a single binary can run always-on observers alongside leader-only writers.  Controllers
+	// which don't need leader election are started by every replica as soon as the cache has synced.  It
+	// has no effect if the Manager doesn't use leader election.  Defaults to true.
+	NeedLeaderElection *bool
+
+	// Clock, if set, measures the time of the Controller instead of the wall clock: the delays of the
+	// reconcile.Requests requeued after a RequeueAfter, rate limited or throttled by the NamespaceQPS,
+	// the ShutdownTimeout, the LivenessTimeout and the StuckReconcileThreshold.  Tests set it to a clock.FakeClock, which they step
+	// to exercise the backoff and requeue paths deterministically instead of sleeping.  The rate limiter
+	// of a queue constructed by NewQueue, and a RateLimiter set explicitly, keep measuring the wall clock
+	// unless they are constructed with the Clock, e.g. by ratelimiter.DefaultWithClock.  Defaults to nil,
+	// which uses the wall clock.
+	Clock clock.Clock
+}
+
+// ShutdownPolicy is what a Controller does with its queued reconcile.Requests once it is stopped.  The
+// reconcile.Requests abandoned on shutdown are counted by the controller_runtime_reconcile_abandoned_total
+// metric.
+type ShutdownPolicy string
+
+const (
+	// ShutdownImmediate abandons the queued reconcile.Requests once the Controller is stopped, leaving the
+	// reconcile.Requests in flight to finish.  The objects of the abandoned reconcile.Requests are
+	// reconciled again by the next Controller to start, as the informers list them when they start.
+	ShutdownImmediate ShutdownPolicy = "Immediate"
+
+	// ShutdownDrain finishes the queued and in flight reconcile.Requests once the Controller is stopped
+	// before stopping, while dropping new reconcile.Requests.
+	ShutdownDrain ShutdownPolicy = "Drain"
+
+	// ShutdownDrainWithTimeout is like ShutdownDrain, except that the reconcile.Requests still queued
+	// once the ShutdownTimeout has elapsed are abandoned.
+	ShutdownDrainWithTimeout ShutdownPolicy = "DrainWithTimeout"
+)
+
+// Queue is the queue of reconcile.Requests of a Controller.  It is the client-go rate limiting queue
+// interface, rather than a narrower one owned by the Controller, since the Controller hands its queue
+// to the source.Sources and handler.EventHandlers it watches, which enqueue into a
+// workqueue.RateLimitingInterface.
+type Queue = workqueue.RateLimitingInterface
+
+// NewQueueFunc constructs the queue of the Controller with the given name.
+type NewQueueFunc func(name string) Queue
+
+// newQueueWithRateLimiter returns a NewQueueFunc constructing the default client-go rate limiting
+// queue using rateLimiter.
+func newQueueWithRateLimiter(rateLimiter workqueue.RateLimiter) NewQueueFunc {
+	return func(name string) Queue {
+		return workqueue.NewNamedRateLimitingQueue(rateLimiter, name)
+	}
 }
 
 // Controller implements a Kubernetes API.  A Controller manages a work queue fed reconcile.Requests
@@ -50,8 +172,17 @@ type Controller interface {
 	//
 	// Watch may be provided one or more Predicates to filter events before they are given to the EventHandler.
 	// Events will be passed to the EventHandler iff all provided Predicates evaluate to true.
+	//
+	// Watch may be called after the Controller has started, e.g. to watch the kinds discovered at runtime.
 	Watch(src source.Source, eventhandler handler.EventHandler, predicates ...predicate.Predicate) error
 
+	// Unwatch stops enqueuing reconcile.Requests in response to the events provided by a Source passed to
+	// Watch, either before or after the Controller has started.  The Source must be comparable, such as a
+	// *source.Kind, to be found.  Unwatch does not remove the informer of a *source.Kind from the cache,
+	// which may be shared with other Controllers; use cache.Informers.RemoveInformer for that once no
+	// Controller watches the kind anymore.
+	Unwatch(src source.Source) error
+
 	// Start starts the controller.  Start blocks until stop is closed or a controller has an error starting.
 	Start(stop <-chan struct{}) error
 }
@@ -71,22 +202,102 @@ func New(name string, mgr manager.Manager, options Options) (Controller, error)
 		options.MaxConcurrentReconciles = 1
 	}
 
+	if options.NewQueue != nil && options.RateLimiter != nil {
+		return nil, fmt.Errorf("can't specify both NewQueue and RateLimiter for Controller %s", name)
+	}
+	if options.NewQueue == nil {
+		if options.RateLimiter == nil && options.Clock != nil {
+			options.RateLimiter = ratelimiter.DefaultWithClock(options.Clock)
+		}
+		if options.RateLimiter == nil {
+			options.RateLimiter = ratelimiter.Default()
+		}
+		options.NewQueue = newQueueWithRateLimiter(options.RateLimiter)
+	}
+
+	if options.NamespaceQPS > 0 && options.NamespaceBurst <= 0 {
+		options.NamespaceBurst = 1
+	}
+
+	switch options.ShutdownPolicy {
+	case "":
+		options.ShutdownPolicy = ShutdownImmediate
+	case ShutdownImmediate, ShutdownDrain:
+	case ShutdownDrainWithTimeout:
+		if options.ShutdownTimeout <= 0 {
+			return nil, fmt.Errorf("must specify ShutdownTimeout for the %s ShutdownPolicy", options.ShutdownPolicy)
+		}
+	default:
+		return nil, fmt.Errorf("unknown ShutdownPolicy %q", options.ShutdownPolicy)
+	}
+
+	if options.LogSuccessEvery < 0 {
+		return nil, fmt.Errorf("LogSuccessEvery must not be negative, got %v", options.LogSuccessEvery)
+	}
+
+	if options.StuckReconcileThreshold < 0 {
+		return nil, fmt.Errorf("StuckReconcileThreshold must not be negative, got %v", options.StuckReconcileThreshold)
+	}
+
+	if options.RequeueAfterJitter < 0 || options.RequeueAfterJitter > 1 {
+		return nil, fmt.Errorf("RequeueAfterJitter must be between 0 and 1, got %v", options.RequeueAfterJitter)
+	}
+
 	// Inject dependencies into Reconciler
 	if err := mgr.SetFields(options.Reconciler); err != nil {
 		return nil, err
 	}
 
+	queue := options.NewQueue(name)
+	if queue == nil {
+		return nil, fmt.Errorf("NewQueue returned a nil queue for Controller %s", name)
+	}
+	if options.Clock != nil {
+		// The rate limiter of a queue constructed by NewQueue is unknown, so it rate limits its requests
+		queue = &controller.ClockedQueue{
+			RateLimitingInterface: queue,
+			Clock:                 options.Clock,
+			RateLimiter:           options.RateLimiter,
+		}
+	}
+	if options.NamespaceQPS > 0 {
+		queue = &controller.NamespaceThrottledQueue{
+			RateLimitingInterface: queue,
+			Name:                  name,
+			QPS:                   options.NamespaceQPS,
+			Burst:                 options.NamespaceBurst,
+			Clock:                 options.Clock,
+		}
+	}
+
 	// Create controller with dependencies set
 	c := &controller.Controller{
-		Do:       options.Reconciler,
-		Cache:    mgr.GetCache(),
-		Config:   mgr.GetConfig(),
-		Scheme:   mgr.GetScheme(),
-		Client:   mgr.GetClient(),
-		Recorder: mgr.GetRecorder(name),
-		Queue:    workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), name),
+		Do:                      options.Reconciler,
+		Cache:                   mgr.GetCache(),
+		Config:                  mgr.GetConfig(),
+		Scheme:                  mgr.GetScheme(),
+		Client:                  mgr.GetClient(),
+		Recorder:                mgr.GetEventRecorderFor(name),
+		Queue:                   queue,
 		MaxConcurrentReconciles: options.MaxConcurrentReconciles,
-		Name: name,
+		Name:                    name,
+		DrainOnShutdown:         options.ShutdownPolicy != ShutdownImmediate,
+		RequeueAfterJitter:      options.RequeueAfterJitter,
+		RecoverPanic:            options.RecoverPanic,
+		TrackProvenance:         options.TrackProvenance,
+		LivenessTimeout:         options.LivenessTimeout,
+		StuckReconcileThreshold: options.StuckReconcileThreshold,
+		LogSuccessEvery:         options.LogSuccessEvery,
+		LeaderElected:           options.NeedLeaderElection,
+		Clock:                   options.Clock,
+	}
+	if options.ShutdownPolicy == ShutdownDrainWithTimeout {
+		c.ShutdownTimeout = options.ShutdownTimeout
+	}
+	if options.LivenessTimeout > 0 {
+		if err := mgr.AddHealthzCheck("controller-"+name, c.CheckLiveness); err != nil {
+			return nil, err
+		}
 	}
 
 	// Add the controller as a Manager components
@@ -18,12 +18,18 @@ package controller_test
 
 import (
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	internalcontroller "sigs.k8s.io/controller-runtime/pkg/internal/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 )
@@ -90,9 +96,167 @@ var _ = Describe("controller.Controller", func() {
 
 			close(done)
 		})
+
+		It("should use the queue constructed by NewQueue", func(done Done) {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			var names []string
+			newQueue := func(name string) controller.Queue {
+				names = append(names, name)
+				return workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			}
+			c, err := controller.New("custom-queue", m, controller.Options{Reconciler: rec, NewQueue: newQueue})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c).ToNot(BeNil())
+			Expect(names).To(Equal([]string{"custom-queue"}))
+
+			close(done)
+		})
+
+		It("should return an error if NewQueue returns a nil queue", func(done Done) {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			newQueue := func(string) workqueue.RateLimitingInterface { return nil }
+			c, err := controller.New("nil-queue", m, controller.Options{Reconciler: rec, NewQueue: newQueue})
+			Expect(c).To(BeNil())
+			Expect(err).To(MatchError(ContainSubstring("nil queue")))
+
+			close(done)
+		})
+
+		It("should use the RateLimiter in the default queue", func(done Done) {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			rl := &countingRateLimiter{RateLimiter: ratelimiter.WithMaxDelay(time.Minute)}
+			c, err := controller.New("custom-rate-limiter", m, controller.Options{Reconciler: rec, RateLimiter: rl})
+			Expect(err).NotTo(HaveOccurred())
+			c.(*internalcontroller.Controller).Queue.AddRateLimited("foo")
+			Expect(rl.whens).To(Equal(1))
+
+			close(done)
+		})
+
+		It("should return an error if both NewQueue and RateLimiter are specified", func(done Done) {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("queue-and-rate-limiter", m, controller.Options{
+				Reconciler: rec,
+				NewQueue: func(string) workqueue.RateLimitingInterface {
+					return workqueue.NewRateLimitingQueue(ratelimiter.Default())
+				},
+				RateLimiter: ratelimiter.Default(),
+			})
+			Expect(c).To(BeNil())
+			Expect(err).To(MatchError(ContainSubstring("can't specify both NewQueue and RateLimiter")))
+
+			close(done)
+		})
+
+		It("should return an error if the RequeueAfterJitter is not between 0 and 1", func(done Done) {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("negative-jitter", m, controller.Options{Reconciler: rec, RequeueAfterJitter: -0.1})
+			Expect(c).To(BeNil())
+			Expect(err).To(MatchError(ContainSubstring("RequeueAfterJitter must be between 0 and 1")))
+
+			c, err = controller.New("large-jitter", m, controller.Options{Reconciler: rec, RequeueAfterJitter: 1.5})
+			Expect(c).To(BeNil())
+			Expect(err).To(MatchError(ContainSubstring("RequeueAfterJitter must be between 0 and 1")))
+
+			close(done)
+		})
+
+		It("should return an error if LogSuccessEvery is negative", func(done Done) {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("negative-log-success-every", m, controller.Options{Reconciler: rec, LogSuccessEvery: -1})
+			Expect(c).To(BeNil())
+			Expect(err).To(MatchError(ContainSubstring("LogSuccessEvery must not be negative")))
+
+			close(done)
+		})
+
+		It("should return an error if the StuckReconcileThreshold is negative", func(done Done) {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("negative-stuck-threshold", m, controller.Options{Reconciler: rec, StuckReconcileThreshold: -time.Second})
+			Expect(c).To(BeNil())
+			Expect(err).To(MatchError(ContainSubstring("StuckReconcileThreshold must not be negative")))
+
+			close(done)
+		})
+
+		It("should add a liveness check to the Manager if LivenessTimeout is set", func(done Done) {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = controller.New("liveness", m, controller.Options{Reconciler: rec, LivenessTimeout: time.Minute})
+			Expect(err).NotTo(HaveOccurred())
+			err = m.AddHealthzCheck("controller-liveness", healthz.Ping)
+			Expect(err).To(MatchError(ContainSubstring("already exists")))
+
+			_, err = controller.New("no-liveness", m, controller.Options{Reconciler: rec})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.AddHealthzCheck("controller-no-liveness", healthz.Ping)).To(Succeed())
+
+			close(done)
+		})
+
+		It("should return an error if the ShutdownPolicy is invalid", func(done Done) {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("unknown-policy", m, controller.Options{Reconciler: rec, ShutdownPolicy: "Later"})
+			Expect(c).To(BeNil())
+			Expect(err).To(MatchError(ContainSubstring("unknown ShutdownPolicy")))
+
+			c, err = controller.New("no-timeout", m, controller.Options{
+				Reconciler:     rec,
+				ShutdownPolicy: controller.ShutdownDrainWithTimeout,
+			})
+			Expect(c).To(BeNil())
+			Expect(err).To(MatchError(ContainSubstring("must specify ShutdownTimeout")))
+
+			close(done)
+		})
+
+		It("should measure the delays of the requeued requests with the Clock", func(done Done) {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			fc := clock.NewFakeClock(time.Now())
+			c, err := controller.New("clocked", m, controller.Options{Reconciler: rec, Clock: fc})
+			Expect(err).NotTo(HaveOccurred())
+			q := c.(*internalcontroller.Controller).Queue
+			Expect(q).To(BeAssignableToTypeOf(&internalcontroller.ClockedQueue{}))
+
+			q.AddRateLimited("foo")
+			Expect(q.Len()).To(Equal(0))
+			fc.Step(ratelimiter.DefaultBaseDelay)
+			Eventually(q.Len).Should(Equal(1))
+
+			close(done)
+		})
 	})
 })
 
+type countingRateLimiter struct {
+	workqueue.RateLimiter
+	whens int
+}
+
+func (l *countingRateLimiter) When(item interface{}) time.Duration {
+	l.whens++
+	return l.RateLimiter.When(item)
+}
+
 var _ reconcile.Reconciler = &failRec{}
 var _ inject.Client = &failRec{}
 
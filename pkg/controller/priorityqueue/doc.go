@@ -0,0 +1,33 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package priorityqueue contains a workqueue for Controllers which dequeues the reconcile.Requests with
+the highest priority first, so that e.g. the Requests for deleted objects are reconciled before the
+Requests caused by periodic resyncs of the informers.
+
+Configure a Controller with the queue through its NewQueue option, and wrap the EventHandlers whose
+Requests should be prioritized with WithPriority:
+
+	c, err := controller.New("pod-controller", mgr, controller.Options{
+		Reconciler: r,
+		NewQueue:   priorityqueue.NewQueue,
+	})
+	...
+	err = c.Watch(&source.Kind{Type: &corev1.Pod{}},
+		priorityqueue.WithPriority(&handler.EnqueueRequestForObject{}, priorityqueue.ForDeletes(1)))
+*/
+package priorityqueue
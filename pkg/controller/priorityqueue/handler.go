@@ -0,0 +1,138 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priorityqueue
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+// Funcs return the priority of the requests enqueued for each type of event.  A nil function returns 0.
+type Funcs struct {
+	// CreateFunc returns the priority of the requests enqueued for a CreateEvent.
+	CreateFunc func(event.CreateEvent) int
+
+	// UpdateFunc returns the priority of the requests enqueued for an UpdateEvent.
+	UpdateFunc func(event.UpdateEvent) int
+
+	// DeleteFunc returns the priority of the requests enqueued for a DeleteEvent.
+	DeleteFunc func(event.DeleteEvent) int
+
+	// GenericFunc returns the priority of the requests enqueued for a GenericEvent.
+	GenericFunc func(event.GenericEvent) int
+}
+
+// ForDeletes returns Funcs prioritizing the requests enqueued for DeleteEvents with priority.
+func ForDeletes(priority int) Funcs {
+	return Funcs{
+		DeleteFunc: func(event.DeleteEvent) int { return priority },
+	}
+}
+
+// ForAnnotation returns Funcs prioritizing the requests enqueued for the events of objects with the
+// annotation key with priority.  For UpdateEvents, the annotation of the new object is checked.
+func ForAnnotation(key string, priority int) Funcs {
+	forMeta := func(meta metav1.Object) int {
+		if meta == nil {
+			return 0
+		}
+		if _, ok := meta.GetAnnotations()[key]; ok {
+			return priority
+		}
+		return 0
+	}
+	return Funcs{
+		CreateFunc:  func(e event.CreateEvent) int { return forMeta(e.Meta) },
+		UpdateFunc:  func(e event.UpdateEvent) int { return forMeta(e.MetaNew) },
+		DeleteFunc:  func(e event.DeleteEvent) int { return forMeta(e.Meta) },
+		GenericFunc: func(e event.GenericEvent) int { return forMeta(e.Meta) },
+	}
+}
+
+// WithPriority returns an EventHandler adding the requests enqueued by h for each event with the priority
+// returned by funcs for the event.  The priority is ignored if the queue of the Controller does not
+// implement Interface.
+func WithPriority(h handler.EventHandler, funcs Funcs) handler.EventHandler {
+	return &priorityHandler{handler: h, funcs: funcs}
+}
+
+var _ handler.EventHandler = &priorityHandler{}
+
+type priorityHandler struct {
+	handler handler.EventHandler
+	funcs   Funcs
+}
+
+// Create implements handler.EventHandler
+func (h *priorityHandler) Create(e event.CreateEvent, q workqueue.RateLimitingInterface) {
+	priority := 0
+	if h.funcs.CreateFunc != nil {
+		priority = h.funcs.CreateFunc(e)
+	}
+	h.handler.Create(e, withPriority(q, priority))
+}
+
+// Update implements handler.EventHandler
+func (h *priorityHandler) Update(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	priority := 0
+	if h.funcs.UpdateFunc != nil {
+		priority = h.funcs.UpdateFunc(e)
+	}
+	h.handler.Update(e, withPriority(q, priority))
+}
+
+// Delete implements handler.EventHandler
+func (h *priorityHandler) Delete(e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	priority := 0
+	if h.funcs.DeleteFunc != nil {
+		priority = h.funcs.DeleteFunc(e)
+	}
+	h.handler.Delete(e, withPriority(q, priority))
+}
+
+// Generic implements handler.EventHandler
+func (h *priorityHandler) Generic(e event.GenericEvent, q workqueue.RateLimitingInterface) {
+	priority := 0
+	if h.funcs.GenericFunc != nil {
+		priority = h.funcs.GenericFunc(e)
+	}
+	h.handler.Generic(e, withPriority(q, priority))
+}
+
+// withPriority returns a queue adding items to q with priority.
+func withPriority(q workqueue.RateLimitingInterface, priority int) workqueue.RateLimitingInterface {
+	if priority == 0 {
+		return q
+	}
+	if _, ok := q.(Interface); !ok {
+		return q
+	}
+	return &priorityAdder{RateLimitingInterface: q, priority: priority}
+}
+
+// priorityAdder adds the items added with Add with priority.
+type priorityAdder struct {
+	workqueue.RateLimitingInterface
+	priority int
+}
+
+// Add implements workqueue.Interface
+func (q *priorityAdder) Add(item interface{}) {
+	AddWithPriority(q.RateLimitingInterface, item, q.priority)
+}
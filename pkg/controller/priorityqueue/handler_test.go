@@ -0,0 +1,89 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priorityqueue_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("WithPriority", func() {
+	var q *priorityqueue.Queue
+	var h handler.EventHandler
+
+	BeforeEach(func() {
+		q = priorityqueue.New(workqueue.DefaultControllerRateLimiter())
+	})
+
+	AfterEach(func() {
+		q.ShutDown()
+	})
+
+	podFor := func(name string, annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Annotations: annotations}}
+	}
+
+	requestFor := func(name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}}
+	}
+
+	next := func() interface{} {
+		item, _ := q.Get()
+		q.Done(item)
+		return item
+	}
+
+	It("should prioritize the requests of deletes", func() {
+		h = priorityqueue.WithPriority(&handler.EnqueueRequestForObject{}, priorityqueue.ForDeletes(1))
+		resynced := podFor("resynced", nil)
+		deleted := podFor("deleted", nil)
+		h.Update(event.UpdateEvent{MetaOld: resynced, ObjectOld: resynced, MetaNew: resynced, ObjectNew: resynced}, q)
+		h.Create(event.CreateEvent{Meta: podFor("created", nil), Object: podFor("created", nil)}, q)
+		h.Delete(event.DeleteEvent{Meta: deleted, Object: deleted}, q)
+
+		Expect(next()).To(Equal(requestFor("deleted")))
+		Expect(next()).To(Equal(requestFor("resynced")))
+		Expect(next()).To(Equal(requestFor("created")))
+	})
+
+	It("should prioritize the requests of annotated objects", func() {
+		h = priorityqueue.WithPriority(&handler.EnqueueRequestForObject{}, priorityqueue.ForAnnotation("example.com/urgent", 1))
+		routine := podFor("routine", nil)
+		urgent := podFor("urgent", map[string]string{"example.com/urgent": ""})
+		h.Generic(event.GenericEvent{Meta: routine, Object: routine}, q)
+		h.Create(event.CreateEvent{Meta: urgent, Object: urgent}, q)
+
+		Expect(next()).To(Equal(requestFor("urgent")))
+		Expect(next()).To(Equal(requestFor("routine")))
+	})
+
+	It("should add the requests to queues without priorities", func() {
+		h = priorityqueue.WithPriority(&handler.EnqueueRequestForObject{}, priorityqueue.ForDeletes(1))
+		other := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+		defer other.ShutDown()
+		h.Delete(event.DeleteEvent{Meta: podFor("deleted", nil), Object: podFor("deleted", nil)}, other)
+		Expect(other.Len()).To(Equal(1))
+	})
+})
@@ -0,0 +1,243 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priorityqueue
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Interface is a workqueue.RateLimitingInterface which can add items with a priority.  The items with
+// the highest priority are dequeued first, and items of the same priority in the order they were
+// added.  Items added with Add, AddAfter or AddRateLimited have a priority of 0.
+type Interface interface {
+	workqueue.RateLimitingInterface
+
+	// AddWithPriority adds item with priority.  If item is already queued, its priority is raised to
+	// priority if it is lower.
+	AddWithPriority(item interface{}, priority int)
+}
+
+// AddWithPriority adds item to q with priority if q implements Interface, and adds it with Add otherwise.
+func AddWithPriority(q workqueue.Interface, item interface{}, priority int) {
+	if pq, ok := q.(Interface); ok {
+		pq.AddWithPriority(item, priority)
+		return
+	}
+	q.Add(item)
+}
+
+var _ Interface = &Queue{}
+
+// Queue is a priority workqueue.  Like the client-go workqueues, an item is queued at most once, and is not
+// dequeued again until Done is called for it, even if it is added while being processed.
+type Queue struct {
+	rateLimiter workqueue.RateLimiter
+
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	// items are the queued items.  queued indexes them by their value.
+	items  itemHeap
+	queued map[interface{}]*item
+
+	// processing are the items being processed.  The items added while being processed are queued once
+	// they are done, with the highest priority they were added with.
+	processing map[interface{}]bool
+	readded    map[interface{}]int
+
+	// seq orders the items of the same priority by the time they were added.
+	seq uint64
+
+	shuttingDown bool
+}
+
+// New returns a new Queue rate limiting the items added with AddRateLimited using rateLimiter.
+func New(rateLimiter workqueue.RateLimiter) *Queue {
+	q := &Queue{
+		rateLimiter: rateLimiter,
+		queued:      map[interface{}]*item{},
+		processing:  map[interface{}]bool{},
+		readded:     map[interface{}]int{},
+	}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// NewQueue returns a new Queue using the default controller rate limiter.  It can be used as the NewQueue of
+// the controller.Options.
+func NewQueue(_ string) workqueue.RateLimitingInterface {
+	return New(workqueue.DefaultControllerRateLimiter())
+}
+
+// Add implements workqueue.Interface
+func (q *Queue) Add(item interface{}) {
+	q.AddWithPriority(item, 0)
+}
+
+// AddWithPriority implements Interface
+func (q *Queue) AddWithPriority(value interface{}, priority int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.shuttingDown {
+		return
+	}
+
+	if q.processing[value] {
+		if p, ok := q.readded[value]; !ok || priority > p {
+			q.readded[value] = priority
+		}
+		return
+	}
+	if i, ok := q.queued[value]; ok {
+		if priority > i.priority {
+			i.priority = priority
+			heap.Fix(&q.items, i.index)
+		}
+		return
+	}
+	q.push(value, priority)
+}
+
+// push queues value.  It must be called with mu held.
+func (q *Queue) push(value interface{}, priority int) {
+	q.seq++
+	i := &item{value: value, priority: priority, seq: q.seq}
+	heap.Push(&q.items, i)
+	q.queued[value] = i
+	q.cond.Signal()
+}
+
+// Len implements workqueue.Interface
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// Get implements workqueue.Interface.  It blocks until an item is queued, and returns shutdown once the
+// queue is shut down and empty.
+func (q *Queue) Get() (value interface{}, shutdown bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.items) == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if len(q.items) == 0 {
+		return nil, true
+	}
+
+	i := heap.Pop(&q.items).(*item)
+	delete(q.queued, i.value)
+	q.processing[i.value] = true
+	return i.value, false
+}
+
+// Done implements workqueue.Interface
+func (q *Queue) Done(value interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.processing, value)
+	if priority, ok := q.readded[value]; ok {
+		delete(q.readded, value)
+		q.push(value, priority)
+	}
+}
+
+// ShutDown implements workqueue.Interface.  Items added once the queue is shut down are dropped.
+func (q *Queue) ShutDown() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.shuttingDown = true
+	q.cond.Broadcast()
+}
+
+// ShuttingDown implements workqueue.Interface
+func (q *Queue) ShuttingDown() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.shuttingDown
+}
+
+// AddAfter implements workqueue.DelayingInterface
+func (q *Queue) AddAfter(item interface{}, duration time.Duration) {
+	if duration <= 0 {
+		q.Add(item)
+		return
+	}
+	time.AfterFunc(duration, func() { q.Add(item) })
+}
+
+// AddRateLimited implements workqueue.RateLimitingInterface
+func (q *Queue) AddRateLimited(item interface{}) {
+	q.AddAfter(item, q.rateLimiter.When(item))
+}
+
+// Forget implements workqueue.RateLimitingInterface
+func (q *Queue) Forget(item interface{}) {
+	q.rateLimiter.Forget(item)
+}
+
+// NumRequeues implements workqueue.RateLimitingInterface
+func (q *Queue) NumRequeues(item interface{}) int {
+	return q.rateLimiter.NumRequeues(item)
+}
+
+// item is a queued item.
+type item struct {
+	value    interface{}
+	priority int
+	seq      uint64
+	// index is the index of the item in the heap.
+	index int
+}
+
+// itemHeap implements heap.Interface, ordering items by priority and then by seq.
+type itemHeap []*item
+
+func (h itemHeap) Len() int { return len(h) }
+
+func (h itemHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h itemHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *itemHeap) Push(x interface{}) {
+	i := x.(*item)
+	i.index = len(*h)
+	*h = append(*h, i)
+}
+
+func (h *itemHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	i := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return i
+}
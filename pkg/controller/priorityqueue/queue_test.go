@@ -0,0 +1,126 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priorityqueue_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+)
+
+var _ = Describe("Queue", func() {
+	var q *priorityqueue.Queue
+
+	BeforeEach(func() {
+		q = priorityqueue.New(workqueue.NewItemExponentialFailureRateLimiter(time.Millisecond, time.Second))
+	})
+
+	AfterEach(func() {
+		q.ShutDown()
+	})
+
+	get := func() interface{} {
+		item, shutdown := q.Get()
+		Expect(shutdown).To(BeFalse())
+		q.Done(item)
+		return item
+	}
+
+	It("should dequeue the items with the highest priority first, in the order they were added", func() {
+		q.Add("a")
+		q.AddWithPriority("b", 1)
+		q.Add("c")
+		q.AddWithPriority("d", 1)
+		q.AddWithPriority("e", -1)
+		Expect(q.Len()).To(Equal(5))
+
+		Expect([]interface{}{get(), get(), get(), get(), get()}).To(Equal([]interface{}{"b", "d", "a", "c", "e"}))
+		Expect(q.Len()).To(Equal(0))
+	})
+
+	It("should queue an item once and raise its priority", func() {
+		q.Add("a")
+		q.Add("b")
+		q.Add("b")
+		q.AddWithPriority("b", 2)
+		q.AddWithPriority("b", 1)
+		Expect(q.Len()).To(Equal(2))
+
+		Expect(get()).To(Equal("b"))
+		Expect(get()).To(Equal("a"))
+	})
+
+	It("should queue the items added while being processed once they are done", func() {
+		q.Add("a")
+		item, _ := q.Get()
+		q.AddWithPriority("a", 1)
+		Expect(q.Len()).To(Equal(0))
+
+		q.Add("b")
+		q.Done(item)
+		Expect(q.Len()).To(Equal(2))
+		Expect(get()).To(Equal("a"))
+	})
+
+	It("should block Get until an item is added", func(done Done) {
+		got := make(chan interface{})
+		go func() {
+			defer GinkgoRecover()
+			got <- get()
+		}()
+		Consistently(got).ShouldNot(Receive())
+		q.Add("a")
+		Eventually(got).Should(Receive(Equal("a")))
+		close(done)
+	})
+
+	It("should add items after a delay", func() {
+		q.AddAfter("a", 50*time.Millisecond)
+		Expect(q.Len()).To(Equal(0))
+		Eventually(q.Len).Should(Equal(1))
+
+		q.AddRateLimited("b")
+		Expect(q.NumRequeues("b")).To(Equal(1))
+		Eventually(q.Len).Should(Equal(2))
+		q.Forget("b")
+		Expect(q.NumRequeues("b")).To(Equal(0))
+	})
+
+	It("should return the queued items and then shutdown once shut down", func() {
+		q.Add("a")
+		q.ShutDown()
+		Expect(q.ShuttingDown()).To(BeTrue())
+		q.Add("b")
+
+		Expect(get()).To(Equal("a"))
+		_, shutdown := q.Get()
+		Expect(shutdown).To(BeTrue())
+	})
+
+	It("should add items without priority to other queues", func() {
+		other := workqueue.New()
+		priorityqueue.AddWithPriority(other, "a", 1)
+		Expect(other.Len()).To(Equal(1))
+
+		priorityqueue.AddWithPriority(q, "b", 1)
+		q.Add("c")
+		Expect(get()).To(Equal("b"))
+	})
+})
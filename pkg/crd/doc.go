@@ -0,0 +1,39 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package crd provides a Runnable that installs and upgrades the CustomResourceDefinitions
+shipped with an operator, e.g. CRD manifests embedded in its binary.
+
+	installer := &crd.Installer{Manifests: [][]byte{cronJobCRD}}
+
+	// Install the CRDs before starting the manager, so that the controllers can watch them.
+	if err := installer.Install(mgr.GetConfig()); err != nil {
+		// handle error
+	}
+
+Alternatively the Installer can be added to the Manager, which installs the CRDs when
+it is started.
+
+	err := mgr.Add(installer)
+*/
+package crd
+
+import (
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var log = logf.KBLog.WithName("crd-installer")
@@ -0,0 +1,337 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+)
+
+const (
+	defaultTimeout      = 30 * time.Second
+	defaultPollInterval = 500 * time.Millisecond
+)
+
+// crdGVK is the group-version-kind of the CRDs installed.
+var crdGVK = apiextensionsv1beta1.SchemeGroupVersion.WithKind("CustomResourceDefinition")
+
+// Scheme contains the apiextensions types, in addition to the kubernetes client-go types.
+var Scheme = runtime.NewScheme()
+
+func init() {
+	if err := scheme.AddToScheme(Scheme); err != nil {
+		panic(err)
+	}
+	if err := apiextensionsv1beta1.AddToScheme(Scheme); err != nil {
+		panic(err)
+	}
+}
+
+// Installer creates the CRDs it is given, or updates them if they already exist, and waits
+// for them to be Established.
+//
+// The vendored API machinery predates server-side apply, so existing CRDs are replaced with
+// an update, which keeps the labels, annotations and conversion set on them by others, such
+// as the CA bundle of a conversion webhook injected by a certrotation.Rotator.  An update that would remove a version that objects are still stored in, i.e.
+// one listed in status.storedVersions, is refused, since those objects could no longer be
+// read.  They must be migrated, and the version removed from status.storedVersions, first.
+//
+// Once the CRDs are Established, the informers of their kinds deferred by the cache injected by the
+// Manager, e.g. of the controllers watching them with the Manager's DeferUnknownKinds option, are
+// started without waiting for the cache to discover the kinds periodically.
+type Installer struct {
+	// CRDs are the CRDs to install.
+	CRDs []*apiextensionsv1beta1.CustomResourceDefinition
+
+	// Manifests are YAML or JSON manifests of CRDs to install, in addition to CRDs.  A
+	// manifest may contain multiple YAML documents.
+	Manifests [][]byte
+
+	// Client is used to install the CRDs.  If not set, a client is created from the config
+	// injected by the Manager, with a scheme that contains the apiextensions types.
+	Client client.Client
+
+	// Timeout is how long to wait for the CRDs to be Established.
+	// Defaults to 30 seconds.
+	Timeout time.Duration
+
+	// PollInterval is how often to check whether the CRDs are Established.
+	// Defaults to 500 milliseconds.
+	PollInterval time.Duration
+
+	config *rest.Config
+	cache  cache.Cache
+}
+
+var _ manager.Runnable = &Installer{}
+var _ inject.Config = &Installer{}
+var _ inject.Cache = &Installer{}
+
+// InjectConfig injects the config the client is created from, unless a Client was set.
+func (i *Installer) InjectConfig(config *rest.Config) error {
+	i.config = config
+	return nil
+}
+
+// InjectCache injects the cache whose deferred informers are resolved once the CRDs are Established.
+func (i *Installer) InjectCache(c cache.Cache) error {
+	i.cache = c
+	return nil
+}
+
+// Start installs the CRDs, and then blocks until stop is closed.
+func (i *Installer) Start(stop <-chan struct{}) error {
+	if err := i.Install(i.config); err != nil {
+		return err
+	}
+	<-stop
+	return nil
+}
+
+// Install installs the CRDs and waits for them to be Established.  config is used to
+// create a client if Client is not set.
+func (i *Installer) Install(config *rest.Config) error {
+	if i.Timeout == 0 {
+		i.Timeout = defaultTimeout
+	}
+	if i.PollInterval == 0 {
+		i.PollInterval = defaultPollInterval
+	}
+	if i.Client == nil {
+		if config == nil {
+			return errors.New("either Client or a config must be provided to install CRDs")
+		}
+		c, err := client.New(config, client.Options{Scheme: Scheme})
+		if err != nil {
+			return err
+		}
+		i.Client = c
+	}
+
+	// The CRDs are installed as unstructured, so that the fields the vendored apiextensions API
+	// predates, such as spec.conversion, are kept
+	var crds []*unstructured.Unstructured
+	for _, crd := range i.CRDs {
+		u := &unstructured.Unstructured{}
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(crd)
+		if err != nil {
+			return err
+		}
+		u.SetUnstructuredContent(obj)
+		u.SetGroupVersionKind(crdGVK)
+		crds = append(crds, u)
+	}
+	for _, manifest := range i.Manifests {
+		parsed, err := readManifest(manifest)
+		if err != nil {
+			return err
+		}
+		crds = append(crds, parsed...)
+	}
+
+	for _, crd := range crds {
+		if err := i.apply(crd); err != nil {
+			return fmt.Errorf("unable to install CRD %s: %v", crd.GetName(), err)
+		}
+	}
+	for _, crd := range crds {
+		if err := i.waitForEstablished(crd.GetName()); err != nil {
+			return fmt.Errorf("CRD %s was not established: %v", crd.GetName(), err)
+		}
+	}
+
+	if r, ok := i.cache.(cache.DeferredResolver); ok {
+		r.ResolveDeferred()
+	}
+	return nil
+}
+
+func (i *Installer) apply(crd *unstructured.Unstructured) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(crdGVK)
+	err := i.Client.Get(context.TODO(), types.NamespacedName{Name: crd.GetName()}, existing)
+	if apierrors.IsNotFound(err) {
+		log.Info("creating CRD", "name", crd.GetName())
+		return i.Client.Create(context.TODO(), crd.DeepCopy())
+	}
+	if err != nil {
+		return err
+	}
+
+	versions := versionsOf(crd)
+	stored, _, _ := unstructured.NestedStringSlice(existing.Object, "status", "storedVersions")
+	for _, v := range stored {
+		if !versions.Has(v) {
+			return fmt.Errorf("the update would remove version %s, which objects are still stored in", v)
+		}
+	}
+
+	log.Info("updating CRD", "name", crd.GetName())
+	updated := crd.DeepCopy()
+	updated.SetResourceVersion(existing.GetResourceVersion())
+	if err := carryOver(updated, existing); err != nil {
+		return err
+	}
+	return i.Client.Update(context.TODO(), updated)
+}
+
+// carryOver copies to updated what other controllers set on the existing CRD: its labels and
+// annotations, unless updated sets them too, and its conversion, or the CA bundle of its
+// conversion webhook, e.g. injected by a certrotation.Rotator, unless updated sets them.
+func carryOver(updated, existing *unstructured.Unstructured) error {
+	updated.SetLabels(merge(existing.GetLabels(), updated.GetLabels()))
+	updated.SetAnnotations(merge(existing.GetAnnotations(), updated.GetAnnotations()))
+
+	conversion, found, err := unstructured.NestedMap(existing.Object, "spec", "conversion")
+	if err != nil || !found {
+		return err
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(updated.Object, "spec", "conversion"); !found {
+		return unstructured.SetNestedMap(updated.Object, conversion, "spec", "conversion")
+	}
+	caBundlePath := []string{"spec", "conversion", "webhookClientConfig", "caBundle"}
+	caBundle, found, err := unstructured.NestedString(existing.Object, caBundlePath...)
+	if err != nil || !found {
+		return err
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(updated.Object, caBundlePath[:3]...); !found {
+		return nil
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(updated.Object, caBundlePath...); found {
+		return nil
+	}
+	return unstructured.SetNestedField(updated.Object, caBundle, caBundlePath...)
+}
+
+// merge returns the entries of existing and desired, desired taking precedence.
+func merge(existing, desired map[string]string) map[string]string {
+	if len(existing) == 0 {
+		return desired
+	}
+	merged := make(map[string]string, len(existing)+len(desired))
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for k, v := range desired {
+		merged[k] = v
+	}
+	return merged
+}
+
+func (i *Installer) waitForEstablished(name string) error {
+	return wait.PollImmediate(i.PollInterval, i.Timeout, func() (bool, error) {
+		crd := &apiextensionsv1beta1.CustomResourceDefinition{}
+		if err := i.Client.Get(context.TODO(), types.NamespacedName{Name: name}, crd); err != nil {
+			return false, err
+		}
+		for _, cond := range crd.Status.Conditions {
+			if cond.Type == apiextensionsv1beta1.NamesAccepted && cond.Status == apiextensionsv1beta1.ConditionFalse {
+				return false, fmt.Errorf("names were not accepted: %s", cond.Message)
+			}
+			if cond.Type == apiextensionsv1beta1.Established && cond.Status == apiextensionsv1beta1.ConditionTrue {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
+
+// versionsOf returns the versions of the CRD.
+func versionsOf(crd *unstructured.Unstructured) sets.String {
+	versions := sets.NewString()
+	if v, _, _ := unstructured.NestedString(crd.Object, "spec", "version"); len(v) != 0 {
+		versions.Insert(v)
+	}
+	list, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, item := range list {
+		if v, ok := item.(map[string]interface{}); ok {
+			if name, ok := v["name"].(string); ok {
+				versions.Insert(name)
+			}
+		}
+	}
+	return versions
+}
+
+// ReadManifest returns the CRDs in the YAML or JSON manifest, which may contain multiple
+// YAML documents.  Documents that are not CRDs are skipped.  The fields the vendored
+// apiextensions API predates, such as spec.conversion, are dropped; the Installer keeps them
+// when installing Manifests.
+func ReadManifest(manifest []byte) ([]*apiextensionsv1beta1.CustomResourceDefinition, error) {
+	parsed, err := readManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	crds := make([]*apiextensionsv1beta1.CustomResourceDefinition, 0, len(parsed))
+	for _, u := range parsed {
+		crd := &apiextensionsv1beta1.CustomResourceDefinition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, crd); err != nil {
+			return nil, err
+		}
+		crds = append(crds, crd)
+	}
+	return crds, nil
+}
+
+// readManifest returns the CRDs in the manifest as unstructured.
+func readManifest(manifest []byte) ([]*unstructured.Unstructured, error) {
+	var crds []*unstructured.Unstructured
+	reader := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(manifest)))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return crds, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		crd := &unstructured.Unstructured{}
+		if err := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(doc), len(doc)).Decode(&crd.Object); err != nil {
+			return nil, err
+		}
+		if crd.GetKind() != "CustomResourceDefinition" {
+			continue
+		}
+		if kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind"); kind == "" {
+			continue
+		}
+		crds = append(crds, crd)
+	}
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crd_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/crd"
+)
+
+const manifest = `
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: cronjobs.batch.example.com
+spec:
+  group: batch.example.com
+  names:
+    kind: CronJob
+    plural: cronjobs
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: not-a-crd
+`
+
+const conversionManifest = `
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: cronjobs.batch.example.com
+  labels:
+    app: cronjob
+spec:
+  group: batch.example.com
+  names:
+    kind: CronJob
+    plural: cronjobs
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+  conversion:
+    strategy: Webhook
+    webhookClientConfig:
+      service:
+        namespace: system
+        name: webhook-service
+        path: /convert
+`
+
+var _ = Describe("Installer", func() {
+	var c client.Client
+	key := types.NamespacedName{Name: "cronjobs.batch.example.com"}
+
+	// getUnstructured returns the CRD with the fields the vendored apiextensions API predates.
+	getUnstructured := func() *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("apiextensions.k8s.io/v1beta1")
+		u.SetKind("CustomResourceDefinition")
+		Expect(c.Get(context.TODO(), key, u)).To(Succeed())
+		return u
+	}
+
+	// establish marks the CRD Established once it has been created, like the API server.
+	establish := func(stop <-chan struct{}) {
+		defer GinkgoRecover()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+			// The CRD is updated as unstructured, so that its conversion is kept
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion("apiextensions.k8s.io/v1beta1")
+			obj.SetKind("CustomResourceDefinition")
+			if err := c.Get(context.TODO(), key, obj); err != nil {
+				continue
+			}
+			if conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions"); len(conditions) != 0 {
+				continue
+			}
+			Expect(unstructured.SetNestedSlice(obj.Object, []interface{}{
+				map[string]interface{}{"type": string(apiextensionsv1beta1.Established), "status": string(apiextensionsv1beta1.ConditionTrue)},
+			}, "status", "conditions")).To(Succeed())
+			Expect(c.Update(context.TODO(), obj)).To(Succeed())
+		}
+	}
+
+	BeforeEach(func() {
+		c = fake.NewFakeClientWithScheme(crd.Scheme)
+	})
+
+	It("should read the CRDs from a manifest", func() {
+		crds, err := crd.ReadManifest([]byte(manifest))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(crds).To(HaveLen(1))
+		Expect(crds[0].Name).To(Equal("cronjobs.batch.example.com"))
+		Expect(crds[0].Spec.Versions[0].Name).To(Equal("v1"))
+	})
+
+	It("should create the CRDs and wait for them to be established", func() {
+		stop := make(chan struct{})
+		defer close(stop)
+		go establish(stop)
+
+		i := &crd.Installer{Manifests: [][]byte{[]byte(manifest)}, Client: c, PollInterval: 10 * time.Millisecond}
+		Expect(i.Install(nil)).To(Succeed())
+
+		obj := &apiextensionsv1beta1.CustomResourceDefinition{}
+		Expect(c.Get(context.TODO(), key, obj)).To(Succeed())
+		Expect(obj.Spec.Names.Kind).To(Equal("CronJob"))
+	})
+
+	It("should resolve the deferred informers of the injected cache once the CRDs are established", func() {
+		stop := make(chan struct{})
+		defer close(stop)
+		go establish(stop)
+
+		resolver := &resolvingCache{}
+		i := &crd.Installer{Manifests: [][]byte{[]byte(manifest)}, Client: c, PollInterval: 10 * time.Millisecond}
+		Expect(i.InjectCache(resolver)).To(Succeed())
+		Expect(i.Install(nil)).To(Succeed())
+		Expect(resolver.resolved).To(Equal(1))
+	})
+
+	It("should time out if the CRDs are not established", func() {
+		resolver := &resolvingCache{}
+		i := &crd.Installer{
+			Manifests:    [][]byte{[]byte(manifest)},
+			Client:       c,
+			Timeout:      50 * time.Millisecond,
+			PollInterval: 10 * time.Millisecond,
+		}
+		Expect(i.InjectCache(resolver)).To(Succeed())
+		Expect(i.Install(nil)).To(MatchError(ContainSubstring("was not established")))
+		Expect(resolver.resolved).To(BeZero())
+	})
+
+	It("should keep the conversion of the CRDs in the manifests", func() {
+		stop := make(chan struct{})
+		defer close(stop)
+		go establish(stop)
+
+		i := &crd.Installer{Manifests: [][]byte{[]byte(conversionManifest)}, Client: c, PollInterval: 10 * time.Millisecond}
+		Expect(i.Install(nil)).To(Succeed())
+
+		strategy, _, err := unstructured.NestedString(getUnstructured().Object, "spec", "conversion", "strategy")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strategy).To(Equal("Webhook"))
+	})
+
+	Context("when the CRD already exists with a conversion webhook", func() {
+		BeforeEach(func() {
+			crds, err := crd.ReadManifest([]byte(manifest))
+			Expect(err).NotTo(HaveOccurred())
+			existing := &unstructured.Unstructured{}
+			obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(crds[0])
+			Expect(err).NotTo(HaveOccurred())
+			existing.SetUnstructuredContent(obj)
+			existing.SetAPIVersion("apiextensions.k8s.io/v1beta1")
+			existing.SetKind("CustomResourceDefinition")
+			existing.SetLabels(map[string]string{"app": "old", "team": "batch"})
+			existing.SetAnnotations(map[string]string{"cert-manager.io/inject-ca-from": "system/serving-cert"})
+			Expect(unstructured.SetNestedField(existing.Object, "Webhook", "spec", "conversion", "strategy")).To(Succeed())
+			Expect(unstructured.SetNestedField(existing.Object, "Q0E=", "spec", "conversion", "webhookClientConfig", "caBundle")).To(Succeed())
+			c = fake.NewFakeClientWithScheme(crd.Scheme, existing)
+		})
+
+		It("should keep the injected CA bundle, labels and annotations", func() {
+			stop := make(chan struct{})
+			defer close(stop)
+			go establish(stop)
+
+			i := &crd.Installer{Manifests: [][]byte{[]byte(conversionManifest)}, Client: c, PollInterval: 10 * time.Millisecond}
+			Expect(i.Install(nil)).To(Succeed())
+
+			u := getUnstructured()
+			Expect(u.GetLabels()).To(Equal(map[string]string{"app": "cronjob", "team": "batch"}))
+			Expect(u.GetAnnotations()).To(HaveKeyWithValue("cert-manager.io/inject-ca-from", "system/serving-cert"))
+			caBundle, _, err := unstructured.NestedString(u.Object, "spec", "conversion", "webhookClientConfig", "caBundle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(caBundle).To(Equal("Q0E="))
+			path, _, err := unstructured.NestedString(u.Object, "spec", "conversion", "webhookClientConfig", "service", "path")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(path).To(Equal("/convert"))
+		})
+
+		It("should keep the conversion when the CRDs installed have none", func() {
+			stop := make(chan struct{})
+			defer close(stop)
+			go establish(stop)
+
+			i := &crd.Installer{Manifests: [][]byte{[]byte(manifest)}, Client: c, PollInterval: 10 * time.Millisecond}
+			Expect(i.Install(nil)).To(Succeed())
+
+			caBundle, _, err := unstructured.NestedString(getUnstructured().Object, "spec", "conversion", "webhookClientConfig", "caBundle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(caBundle).To(Equal("Q0E="))
+		})
+	})
+
+	Context("when the CRD already exists", func() {
+		BeforeEach(func() {
+			existing := &apiextensionsv1beta1.CustomResourceDefinition{
+				// The fake client only returns the kind of typed objects stored with it, unlike the API server
+				TypeMeta:   metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1beta1", Kind: "CustomResourceDefinition"},
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+				Spec: apiextensionsv1beta1.CustomResourceDefinitionSpec{
+					Group: "batch.example.com",
+					Names: apiextensionsv1beta1.CustomResourceDefinitionNames{Kind: "CronJob", Plural: "cronjobs"},
+					Versions: []apiextensionsv1beta1.CustomResourceDefinitionVersion{
+						{Name: "v1alpha1", Served: true, Storage: true},
+					},
+				},
+				Status: apiextensionsv1beta1.CustomResourceDefinitionStatus{
+					StoredVersions: []string{"v1alpha1"},
+				},
+			}
+			c = fake.NewFakeClientWithScheme(crd.Scheme, existing)
+		})
+
+		It("should update it when it keeps the stored versions", func() {
+			stop := make(chan struct{})
+			defer close(stop)
+			go establish(stop)
+
+			crds, err := crd.ReadManifest([]byte(manifest))
+			Expect(err).NotTo(HaveOccurred())
+			crds[0].Spec.Versions = append(crds[0].Spec.Versions, apiextensionsv1beta1.CustomResourceDefinitionVersion{
+				Name: "v1alpha1", Served: true,
+			})
+			i := &crd.Installer{CRDs: crds, Client: c, PollInterval: 10 * time.Millisecond}
+			Expect(i.Install(nil)).To(Succeed())
+
+			obj := &apiextensionsv1beta1.CustomResourceDefinition{}
+			Expect(c.Get(context.TODO(), key, obj)).To(Succeed())
+			Expect(obj.Spec.Versions).To(HaveLen(2))
+		})
+
+		It("should refuse to remove a stored version", func() {
+			i := &crd.Installer{Manifests: [][]byte{[]byte(manifest)}, Client: c}
+			Expect(i.Install(nil)).To(MatchError(ContainSubstring("would remove version v1alpha1")))
+		})
+	})
+
+	It("should require a client or a config", func() {
+		Expect((&crd.Installer{}).Install(nil)).To(MatchError(ContainSubstring("either Client or a config")))
+	})
+})
+
+// resolvingCache counts the calls to ResolveDeferred.
+type resolvingCache struct {
+	informertest.FakeInformers
+
+	resolved int
+}
+
+func (c *resolvingCache) ResolveDeferred() {
+	c.resolved++
+}
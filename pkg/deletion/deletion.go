@@ -0,0 +1,251 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var log = logf.KBLog.WithName("deletion")
+
+const (
+	// PolicyAnnotation is the annotation of a parent holding its Policy.
+	PolicyAnnotation = "deletion.controller-runtime.sigs.k8s.io/policy"
+
+	// DefaultFinalizer is the default finalizer holding a parent until its dependents are torn down.
+	DefaultFinalizer = "deletion.controller-runtime.sigs.k8s.io/dependents"
+)
+
+// Policy is what becomes of the dependents of a parent once it is deleted.
+type Policy string
+
+const (
+	// Delete deletes the dependents, and releases the parent once they are all gone.
+	Delete Policy = "Delete"
+
+	// Orphan removes the owner references to the parent from the dependents, so that they are not
+	// garbage collected, and releases the parent.
+	Orphan Policy = "Orphan"
+
+	// Retain keeps the dependents, and holds the parent until its Policy is changed, which protects
+	// both from an accidental deletion.
+	Retain Policy = "Retain"
+)
+
+// Finalizer tears down the dependents of deleted parents according to their Policy.
+type Finalizer struct {
+	// Client updates the parents and their dependents.
+	Client client.Client
+
+	// Dependents returns the dependents of parent, e.g. with ListOwned.
+	Dependents func(ctx context.Context, parent runtime.Object) ([]runtime.Object, error)
+
+	// Name is the finalizer added to the parents.  Defaults to DefaultFinalizer.
+	Name string
+
+	// DefaultPolicy is the Policy of the parents without a PolicyAnnotation.  Defaults to Delete.
+	DefaultPolicy Policy
+}
+
+// Finalize adds the finalizer to parent if it is not being deleted, and otherwise tears down its
+// dependents according to its Policy, removing the finalizer once they are torn down.  It returns
+// whether parent is being deleted, in which case the Reconciler should not reconcile it further.
+func (f *Finalizer) Finalize(ctx context.Context, parent runtime.Object) (bool, error) {
+	accessor, err := meta.Accessor(parent)
+	if err != nil {
+		return false, err
+	}
+	name := f.name()
+
+	if accessor.GetDeletionTimestamp() == nil {
+		if hasFinalizer(accessor, name) {
+			return false, nil
+		}
+		accessor.SetFinalizers(append(accessor.GetFinalizers(), name))
+		return false, f.Client.Update(ctx, parent)
+	}
+	if !hasFinalizer(accessor, name) {
+		return true, nil
+	}
+
+	policy, err := f.PolicyOf(accessor)
+	if err != nil {
+		return true, err
+	}
+	log := log.WithValues("namespace", accessor.GetNamespace(), "name", accessor.GetName(), "policy", policy)
+
+	var done bool
+	switch policy {
+	case Retain:
+		log.V(1).Info("Retaining the parent and its dependents")
+		return true, nil
+	case Orphan:
+		done, err = f.orphan(ctx, parent)
+	default:
+		done, err = f.delete(ctx, parent)
+	}
+	if err != nil || !done {
+		return true, err
+	}
+
+	log.V(1).Info("Releasing the parent")
+	accessor.SetFinalizers(removeFinalizer(accessor.GetFinalizers(), name))
+	if err := f.Client.Update(ctx, parent); err != nil && !errors.IsNotFound(err) {
+		return true, err
+	}
+	return true, nil
+}
+
+// PolicyOf returns the Policy of parent, which is its PolicyAnnotation or the DefaultPolicy.
+func (f *Finalizer) PolicyOf(parent metav1.Object) (Policy, error) {
+	policy, ok := parent.GetAnnotations()[PolicyAnnotation]
+	if !ok {
+		if f.DefaultPolicy == "" {
+			return Delete, nil
+		}
+		return f.DefaultPolicy, nil
+	}
+	switch p := Policy(policy); p {
+	case Delete, Orphan, Retain:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown deletion policy %q of %s/%s", policy, parent.GetNamespace(), parent.GetName())
+	}
+}
+
+// delete deletes the dependents of parent, and returns whether they are all gone.
+func (f *Finalizer) delete(ctx context.Context, parent runtime.Object) (bool, error) {
+	dependents, err := f.Dependents(ctx, parent)
+	if err != nil {
+		return false, err
+	}
+	for _, d := range dependents {
+		accessor, err := meta.Accessor(d)
+		if err != nil {
+			return false, err
+		}
+		if accessor.GetDeletionTimestamp() != nil {
+			continue
+		}
+		if err := f.Client.Delete(ctx, d, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+	}
+	return len(dependents) == 0, nil
+}
+
+// orphan removes the owner references to parent from its dependents, and returns whether they are all
+// orphaned.
+func (f *Finalizer) orphan(ctx context.Context, parent runtime.Object) (bool, error) {
+	parentAccessor, err := meta.Accessor(parent)
+	if err != nil {
+		return false, err
+	}
+	dependents, err := f.Dependents(ctx, parent)
+	if err != nil {
+		return false, err
+	}
+	for _, d := range dependents {
+		accessor, err := meta.Accessor(d)
+		if err != nil {
+			return false, err
+		}
+		refs := accessor.GetOwnerReferences()
+		var kept []metav1.OwnerReference
+		for _, ref := range refs {
+			if ref.UID != parentAccessor.GetUID() {
+				kept = append(kept, ref)
+			}
+		}
+		if len(kept) == len(refs) {
+			continue
+		}
+		accessor.SetOwnerReferences(kept)
+		if err := f.Client.Update(ctx, d); err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (f *Finalizer) name() string {
+	if f.Name == "" {
+		return DefaultFinalizer
+	}
+	return f.Name
+}
+
+// ListOwned lists the objects of list, a List such as appsv1.DeploymentList, in the namespace of parent
+// which have an owner reference to parent.
+func ListOwned(ctx context.Context, c client.Client, parent runtime.Object, list runtime.Object, opts ...client.ListOptionFunc) ([]runtime.Object, error) {
+	accessor, err := meta.Accessor(parent)
+	if err != nil {
+		return nil, err
+	}
+	if ns := accessor.GetNamespace(); ns != "" {
+		opts = append([]client.ListOptionFunc{client.InNamespace(ns)}, opts...)
+	}
+	if err := c.List(ctx, list, opts...); err != nil {
+		return nil, err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	var owned []runtime.Object
+	for _, item := range items {
+		itemAccessor, err := meta.Accessor(item)
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range itemAccessor.GetOwnerReferences() {
+			if ref.UID == accessor.GetUID() {
+				owned = append(owned, item)
+				break
+			}
+		}
+	}
+	return owned, nil
+}
+
+func hasFinalizer(obj metav1.Object, name string) bool {
+	for _, f := range obj.GetFinalizers() {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+func removeFinalizer(finalizers []string, name string) []string {
+	var kept []string
+	for _, f := range finalizers {
+		if f != name {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/deletion"
+)
+
+var _ = Describe("Finalizer", func() {
+	var (
+		ctx       context.Context
+		c         client.Client
+		finalizer *deletion.Finalizer
+		parent    *corev1.ConfigMap
+	)
+
+	dependent := func(name string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "v1", Kind: "ConfigMap", Name: "parent", UID: "parent-uid"},
+				{APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "other-uid"},
+			},
+		}}
+	}
+	getParent := func() *corev1.ConfigMap {
+		cm := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "parent"}, cm)).To(Succeed())
+		return cm
+	}
+	deleteParent := func(policy deletion.Policy) {
+		parent = getParent()
+		now := metav1.NewTime(time.Now())
+		parent.DeletionTimestamp = &now
+		if policy != "" {
+			parent.Annotations = map[string]string{deletion.PolicyAnnotation: string(policy)}
+		}
+		Expect(c.Update(ctx, parent)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		parent = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "parent", UID: "parent-uid"}}
+		c = fake.NewFakeClient(parent, dependent("owned"), &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "unowned"},
+		})
+		finalizer = &deletion.Finalizer{
+			Client: c,
+			Dependents: func(ctx context.Context, parent runtime.Object) ([]runtime.Object, error) {
+				return deletion.ListOwned(ctx, c, parent, &corev1.ConfigMapList{})
+			},
+		}
+
+		deleting, err := finalizer.Finalize(ctx, parent)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleting).To(BeFalse())
+	})
+
+	It("should add the finalizer to live parents", func() {
+		Expect(getParent().Finalizers).To(ConsistOf(deletion.DefaultFinalizer))
+
+		deleting, err := finalizer.Finalize(ctx, getParent())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleting).To(BeFalse())
+		Expect(getParent().Finalizers).To(ConsistOf(deletion.DefaultFinalizer))
+	})
+
+	It("should list the owned dependents", func() {
+		owned, err := deletion.ListOwned(ctx, c, parent, &corev1.ConfigMapList{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(owned).To(HaveLen(1))
+		Expect(owned[0].(*corev1.ConfigMap).Name).To(Equal("owned"))
+	})
+
+	It("should delete the dependents before releasing the parent by default", func() {
+		deleteParent("")
+
+		deleting, err := finalizer.Finalize(ctx, parent)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleting).To(BeTrue())
+		err = c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "owned"}, &corev1.ConfigMap{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		Expect(getParent().Finalizers).To(ConsistOf(deletion.DefaultFinalizer))
+
+		By("releasing the parent once the dependents are gone")
+		deleting, err = finalizer.Finalize(ctx, parent)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleting).To(BeTrue())
+		Expect(getParent().Finalizers).To(BeEmpty())
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "unowned"}, &corev1.ConfigMap{})).To(Succeed())
+	})
+
+	It("should orphan the dependents with the Orphan policy", func() {
+		deleteParent(deletion.Orphan)
+
+		deleting, err := finalizer.Finalize(ctx, parent)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleting).To(BeTrue())
+		Expect(getParent().Finalizers).To(BeEmpty())
+
+		owned := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "owned"}, owned)).To(Succeed())
+		Expect(owned.OwnerReferences).To(HaveLen(1))
+		Expect(owned.OwnerReferences[0].Name).To(Equal("other"))
+	})
+
+	It("should hold the parent and its dependents with the Retain policy", func() {
+		deleteParent(deletion.Retain)
+
+		deleting, err := finalizer.Finalize(ctx, parent)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleting).To(BeTrue())
+		Expect(getParent().Finalizers).To(ConsistOf(deletion.DefaultFinalizer))
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "owned"}, &corev1.ConfigMap{})).To(Succeed())
+	})
+
+	It("should use the DefaultPolicy of the Finalizer", func() {
+		finalizer.DefaultPolicy = deletion.Retain
+		policy, err := finalizer.PolicyOf(parent)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy).To(Equal(deletion.Retain))
+
+		parent.Annotations = map[string]string{deletion.PolicyAnnotation: "Orphan"}
+		policy, err = finalizer.PolicyOf(parent)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy).To(Equal(deletion.Orphan))
+	})
+
+	It("should return an error for an unknown policy", func() {
+		deleteParent("Keep")
+
+		deleting, err := finalizer.Finalize(ctx, parent)
+		Expect(err).To(MatchError(ContainSubstring(`unknown deletion policy "Keep"`)))
+		Expect(deleting).To(BeTrue())
+		Expect(getParent().Finalizers).To(ConsistOf(deletion.DefaultFinalizer))
+	})
+})
@@ -0,0 +1,46 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package deletion implements the teardown of the dependents of a parent object according to a deletion
+policy set by an annotation on the parent, with a finalizer holding the parent until its dependents are
+torn down.
+
+The policy is one of:
+
+	Delete: the dependents are deleted, and the parent is released once they are all gone.
+	Orphan: the owner references to the parent are removed from the dependents, which outlive it.
+	Retain: the dependents are kept, and so is the parent, until the policy is changed.
+
+A Reconciler calls Finalize on every reconcile of the parent, which adds the finalizer to live parents,
+and tears down the dependents of deleted ones:
+
+	finalizer := &deletion.Finalizer{
+		Client: mgr.GetClient(),
+		Dependents: func(ctx context.Context, parent runtime.Object) ([]runtime.Object, error) {
+			return deletion.ListOwned(ctx, mgr.GetClient(), parent, &appsv1.DeploymentList{})
+		},
+	}
+	...
+	deleting, err := finalizer.Finalize(ctx, instance)
+	if err != nil || deleting {
+		return reconcile.Result{}, err
+	}
+
+The Controller should watch the dependents, e.g. with handler.EnqueueRequestForOwner, and the parent, so
+that the parent is reconciled again as its dependents go, or its policy changes.
+*/
+package deletion
@@ -0,0 +1,215 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"text/template"
+)
+
+// DefaultBinaryAssetsURL is the default template of the URL of the archives of the control plane
+// binaries, which is executed with the Version, OS and Arch of the BinaryAssets.
+const DefaultBinaryAssetsURL = "https://storage.googleapis.com/kubebuilder-tools/kubebuilder-tools-{{ .Version }}-{{ .OS }}-{{ .Arch }}.tar.gz"
+
+// assetBinaries are the control plane binaries of the archives.
+var assetBinaries = []string{"kube-apiserver", "etcd", "kubectl"}
+
+// BinaryAssets downloads the control plane binaries of a pinned Kubernetes version, and caches them, so
+// that tests don't need them to be installed beforehand.
+type BinaryAssets struct {
+	// Version is the Kubernetes version of the binaries, e.g. "1.14.1".
+	Version string
+
+	// SHA256 is the hex-encoded SHA-256 checksum of the archive of the binaries, which is verified
+	// before using them.  It is required, so that the Version pins the exact binaries.
+	SHA256 string
+
+	// URL is the template of the URL of the gzipped tar archive of the binaries, which is executed with
+	// the Version, OS and Arch.  The binaries may be in any directory of the archive.  Defaults to
+	// DefaultBinaryAssetsURL.
+	URL string
+
+	// OS and Arch are the platform of the binaries.  Default to the platform of the tests.
+	OS   string
+	Arch string
+
+	// Dir is the directory the binaries are cached in, under a directory per version and platform.
+	// Defaults to controller-runtime/envtest in the user cache directory.
+	Dir string
+
+	// Client downloads the archives.  Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// Fetch returns the directory of the binaries, downloading them if they are not cached yet.
+func (a *BinaryAssets) Fetch() (string, error) {
+	if a.Version == "" || a.SHA256 == "" {
+		return "", fmt.Errorf("must specify the Version and SHA256 of the binary assets")
+	}
+	if err := a.setDefaults(); err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(a.Dir, fmt.Sprintf("%s-%s-%s", a.Version, a.OS, a.Arch))
+	if cached(dir) {
+		return dir, nil
+	}
+
+	archive, err := a.download()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(a.Dir, 0755); err != nil {
+		return "", err
+	}
+	tmp, err := ioutil.TempDir(a.Dir, ".download-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+	if err := extractBinaries(archive, tmp); err != nil {
+		return "", fmt.Errorf("unable to extract the binary assets %s: %v", a.Version, err)
+	}
+
+	// Move the binaries in place at once, so that concurrent tests don't see them partially extracted
+	if err := os.Rename(tmp, dir); err != nil && !cached(dir) {
+		return "", err
+	}
+	return dir, nil
+}
+
+func (a *BinaryAssets) setDefaults() error {
+	if a.Dir == "" {
+		cache, err := os.UserCacheDir()
+		if err != nil {
+			return err
+		}
+		a.Dir = filepath.Join(cache, "controller-runtime", "envtest")
+	}
+	if a.URL == "" {
+		a.URL = DefaultBinaryAssetsURL
+	}
+	if a.OS == "" {
+		a.OS = runtime.GOOS
+	}
+	if a.Arch == "" {
+		a.Arch = runtime.GOARCH
+	}
+	if a.Client == nil {
+		a.Client = http.DefaultClient
+	}
+	return nil
+}
+
+// download downloads the archive of the binaries, and verifies its checksum.
+func (a *BinaryAssets) download() ([]byte, error) {
+	tmpl, err := template.New("url").Parse(a.URL)
+	if err != nil {
+		return nil, err
+	}
+	url := &bytes.Buffer{}
+	if err := tmpl.Execute(url, a); err != nil {
+		return nil, err
+	}
+
+	resp, err := a.Client.Get(url.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to download the binary assets from %s: %s", url, resp.Status)
+	}
+	archive, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(archive)
+	if actual := hex.EncodeToString(sum[:]); actual != a.SHA256 {
+		return nil, fmt.Errorf("checksum mismatch of the binary assets from %s: expected %s, got %s", url, a.SHA256, actual)
+	}
+	return archive, nil
+}
+
+// extractBinaries extracts the binaries of the gzipped tar archive into dir.
+func extractBinaries(archive []byte, dir string) error {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		name := path.Base(header.Name)
+		if header.Typeflag != tar.TypeReg || !isAssetBinary(name) {
+			continue
+		}
+		f, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(f, tr)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if !cached(dir) {
+		return fmt.Errorf("the archive doesn't contain all of %v", assetBinaries)
+	}
+	return nil
+}
+
+// cached returns whether dir contains all the binaries.
+func cached(dir string) bool {
+	for _, binary := range assetBinaries {
+		if _, err := os.Stat(filepath.Join(dir, binary)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func isAssetBinary(name string) bool {
+	for _, binary := range assetBinaries {
+		if name == binary {
+			return true
+		}
+	}
+	return false
+}
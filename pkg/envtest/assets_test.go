@@ -0,0 +1,123 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BinaryAssets", func() {
+	var dir string
+	var archive []byte
+	var requests []string
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "envtest-assets")
+		Expect(err).NotTo(HaveOccurred())
+
+		archive = assetArchive("kubebuilder/bin/etcd", "kubebuilder/bin/kube-apiserver", "kubebuilder/bin/kubectl")
+		requests = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.URL.Path)
+			_, _ = w.Write(archive)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	assets := func() *BinaryAssets {
+		sum := sha256.Sum256(archive)
+		return &BinaryAssets{
+			Version: "1.14.1",
+			SHA256:  hex.EncodeToString(sum[:]),
+			URL:     server.URL + "/tools-{{ .Version }}-{{ .OS }}-{{ .Arch }}.tar.gz",
+			OS:      "linux",
+			Arch:    "amd64",
+			Dir:     dir,
+		}
+	}
+
+	It("should download the binaries, and then reuse them", func() {
+		binDir, err := assets().Fetch()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(binDir).To(Equal(filepath.Join(dir, "1.14.1-linux-amd64")))
+		Expect(requests).To(Equal([]string{"/tools-1.14.1-linux-amd64.tar.gz"}))
+		for _, binary := range []string{"etcd", "kube-apiserver", "kubectl"} {
+			content, err := ioutil.ReadFile(filepath.Join(binDir, binary))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal(binary))
+		}
+
+		binDir, err = assets().Fetch()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(binDir).To(Equal(filepath.Join(dir, "1.14.1-linux-amd64")))
+		Expect(requests).To(HaveLen(1))
+	})
+
+	It("should reject an archive not matching the checksum", func() {
+		a := assets()
+		a.SHA256 = hex.EncodeToString(make([]byte, sha256.Size))
+		_, err := a.Fetch()
+		Expect(err).To(MatchError(ContainSubstring("checksum mismatch")))
+		Expect(filepath.Join(dir, "1.14.1-linux-amd64")).NotTo(BeADirectory())
+	})
+
+	It("should reject an archive missing a binary", func() {
+		archive = assetArchive("kubebuilder/bin/etcd", "kubebuilder/bin/kubectl")
+		_, err := assets().Fetch()
+		Expect(err).To(MatchError(ContainSubstring("doesn't contain all")))
+		Expect(filepath.Join(dir, "1.14.1-linux-amd64")).NotTo(BeADirectory())
+	})
+
+	It("should require the Version and SHA256", func() {
+		_, err := (&BinaryAssets{Version: "1.14.1"}).Fetch()
+		Expect(err).To(MatchError(ContainSubstring("must specify the Version and SHA256")))
+	})
+})
+
+// assetArchive returns a gzipped tar archive of files containing their base name.
+func assetArchive(names ...string) []byte {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	for _, name := range names {
+		content := []byte(filepath.Base(name))
+		Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg})).To(Succeed())
+		_, err := tw.Write(content)
+		Expect(err).NotTo(HaveOccurred())
+	}
+	Expect(tw.Close()).To(Succeed())
+	Expect(gz.Close()).To(Succeed())
+	return buf.Bytes()
+}
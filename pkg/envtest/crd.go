@@ -25,9 +25,11 @@ import (
 	"github.com/ghodss/yaml"
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 )
 
@@ -39,6 +41,14 @@ type CRDInstallOptions struct {
 	// CRDs is a list of CRDs to install
 	CRDs []*apiextensionsv1beta1.CustomResourceDefinition
 
+	// Types is a list of Go types, such as &v1.MyKind{}, whose CRDs are generated by CRDForType and
+	// installed
+	Types []runtime.Object
+
+	// Scheme is the scheme the group, version and kind of the Types are looked up in.  It defaults to
+	// the client-go scheme.
+	Scheme *runtime.Scheme
+
 	// ErrorIfPathMissing will cause an error if a Path does not exist
 	ErrorIfPathMissing bool
 
@@ -61,6 +71,11 @@ func InstallCRDs(config *rest.Config, options CRDInstallOptions) ([]*apiextensio
 		return nil, err
 	}
 
+	// Generate the CRDs of the Go types into options.CRDs
+	if err := generateTypeCRDs(&options); err != nil {
+		return nil, err
+	}
+
 	// Create the CRDs in the apiserver
 	if err := CreateCRDs(config, options.CRDs); err != nil {
 		return options.CRDs, err
@@ -91,6 +106,22 @@ func readCRDFiles(options *CRDInstallOptions) error {
 	return nil
 }
 
+// generateTypeCRDs generates the CRDs of options.Types and adds them to options.CRDs
+func generateTypeCRDs(options *CRDInstallOptions) error {
+	scheme := options.Scheme
+	if scheme == nil {
+		scheme = kscheme.Scheme
+	}
+	for _, obj := range options.Types {
+		crd, err := CRDForType(obj, scheme)
+		if err != nil {
+			return err
+		}
+		options.CRDs = append(options.CRDs, crd)
+	}
+	return nil
+}
+
 // defaultCRDOptions sets the default values for CRDs
 func defaultCRDOptions(o *CRDInstallOptions) {
 	if o.maxTime == 0 {
@@ -0,0 +1,170 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+var (
+	typeMetaType   = reflect.TypeOf(metav1.TypeMeta{})
+	objectMetaType = reflect.TypeOf(metav1.ObjectMeta{})
+	timeType       = reflect.TypeOf(metav1.Time{})
+	marshalerType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// CRDForType generates the CRD of the Go type of obj, whose group, version and kind are looked up in
+// scheme.  The CRD is namespaced, is named after the lowercase plural of the kind, has a validation
+// schema generated from the JSON fields of the type, and enables the status subresource if the type
+// has a Status field.  It can be modified, e.g. to make it cluster-scoped, before being installed.
+func CRDForType(obj runtime.Object, scheme *runtime.Scheme) (*apiextensionsv1beta1.CustomResourceDefinition, error) {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return nil, err
+	}
+	t := reflect.TypeOf(obj)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	plural := pluralize(strings.ToLower(gvk.Kind))
+	crd := &apiextensionsv1beta1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: plural + "." + gvk.Group},
+		Spec: apiextensionsv1beta1.CustomResourceDefinitionSpec{
+			Group:   gvk.Group,
+			Version: gvk.Version,
+			Names: apiextensionsv1beta1.CustomResourceDefinitionNames{
+				Plural:   plural,
+				Singular: strings.ToLower(gvk.Kind),
+				Kind:     gvk.Kind,
+				ListKind: gvk.Kind + "List",
+			},
+			Scope: apiextensionsv1beta1.NamespaceScoped,
+			Validation: &apiextensionsv1beta1.CustomResourceValidation{
+				OpenAPIV3Schema: schemaForType(t, map[reflect.Type]bool{}),
+			},
+		},
+	}
+	if _, ok := t.FieldByName("Status"); ok && t.Kind() == reflect.Struct {
+		crd.Spec.Subresources = &apiextensionsv1beta1.CustomResourceSubresources{
+			Status: &apiextensionsv1beta1.CustomResourceSubresourceStatus{},
+		}
+	}
+	return crd, nil
+}
+
+// schemaForType returns the JSON schema of the values of t.  visiting are the struct types whose schema
+// is being generated, whose recursive fields are left without a schema.
+func schemaForType(t reflect.Type, visiting map[reflect.Type]bool) *apiextensionsv1beta1.JSONSchemaProps {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return &apiextensionsv1beta1.JSONSchemaProps{Type: "string", Format: "date-time"}
+	case t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType):
+		// The JSON of types marshaling themselves, such as resource.Quantity, is unknown
+		return &apiextensionsv1beta1.JSONSchemaProps{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &apiextensionsv1beta1.JSONSchemaProps{Type: "string"}
+	case reflect.Bool:
+		return &apiextensionsv1beta1.JSONSchemaProps{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &apiextensionsv1beta1.JSONSchemaProps{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &apiextensionsv1beta1.JSONSchemaProps{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// Bytes are base64 encoded
+			return &apiextensionsv1beta1.JSONSchemaProps{Type: "string", Format: "byte"}
+		}
+		return &apiextensionsv1beta1.JSONSchemaProps{
+			Type:  "array",
+			Items: &apiextensionsv1beta1.JSONSchemaPropsOrArray{Schema: schemaForType(t.Elem(), visiting)},
+		}
+	case reflect.Map:
+		return &apiextensionsv1beta1.JSONSchemaProps{
+			Type:                 "object",
+			AdditionalProperties: &apiextensionsv1beta1.JSONSchemaPropsOrBool{Allows: true, Schema: schemaForType(t.Elem(), visiting)},
+		}
+	case reflect.Struct:
+		if visiting[t] {
+			return &apiextensionsv1beta1.JSONSchemaProps{}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		schema := &apiextensionsv1beta1.JSONSchemaProps{Type: "object", Properties: map[string]apiextensionsv1beta1.JSONSchemaProps{}}
+		addFieldSchemas(schema, t, visiting)
+		return schema
+	default:
+		return &apiextensionsv1beta1.JSONSchemaProps{}
+	}
+}
+
+// addFieldSchemas adds the schemas of the JSON fields of the struct type t to the properties of schema.
+func addFieldSchemas(schema *apiextensionsv1beta1.JSONSchemaProps, t reflect.Type, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" || field.Type == typeMetaType || field.Type == objectMetaType {
+			// The metadata of the objects is validated by the API server
+			continue
+		}
+		tag := field.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(tag, ",inline") || (field.Anonymous && name == "") {
+			inlined := field.Type
+			for inlined.Kind() == reflect.Ptr {
+				inlined = inlined.Elem()
+			}
+			if inlined.Kind() == reflect.Struct {
+				addFieldSchemas(schema, inlined, visiting)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = *schemaForType(field.Type, visiting)
+	}
+}
+
+// pluralize returns the English plural of the lowercase kind.
+func pluralize(kind string) string {
+	switch {
+	case strings.HasSuffix(kind, "s"), strings.HasSuffix(kind, "x"), strings.HasSuffix(kind, "ch"), strings.HasSuffix(kind, "sh"):
+		return kind + "es"
+	case strings.HasSuffix(kind, "y") && len(kind) > 1 && !strings.ContainsAny(kind[len(kind)-2:len(kind)-1], "aeiou"):
+		return kind[:len(kind)-1] + "ies"
+	default:
+		return kind + "s"
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Policy is a test type whose CRD is generated.
+type Policy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PolicySpec   `json:"spec,omitempty"`
+	Status PolicyStatus `json:"status,omitempty"`
+}
+
+type PolicySpec struct {
+	Rules    []PolicyRule       `json:"rules"`
+	Labels   map[string]string  `json:"labels,omitempty"`
+	Limit    *resource.Quantity `json:"limit,omitempty"`
+	Parent   *PolicySpec        `json:"parent,omitempty"`
+	internal string
+}
+
+type PolicyRule struct {
+	Name     string  `json:"name"`
+	Priority int32   `json:"priority"`
+	Weight   float64 `json:"weight"`
+	Enabled  bool    `json:"enabled"`
+	Ignored  string  `json:"-"`
+}
+
+type PolicyStatus struct {
+	Applied metav1.Time `json:"applied,omitempty"`
+	Data    []byte      `json:"data,omitempty"`
+}
+
+func (p *Policy) DeepCopyObject() runtime.Object {
+	out := *p
+	return &out
+}
+
+var _ = Describe("CRDForType", func() {
+	var s *runtime.Scheme
+
+	BeforeEach(func() {
+		s = runtime.NewScheme()
+		s.AddKnownTypes(schema.GroupVersion{Group: "example.com", Version: "v1alpha1"}, &Policy{})
+	})
+
+	It("should generate the CRD of a Go type", func() {
+		crd, err := CRDForType(&Policy{}, s)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(crd.Name).To(Equal("policies.example.com"))
+		Expect(crd.Spec.Group).To(Equal("example.com"))
+		Expect(crd.Spec.Version).To(Equal("v1alpha1"))
+		Expect(crd.Spec.Names).To(Equal(v1beta1.CustomResourceDefinitionNames{
+			Plural:   "policies",
+			Singular: "policy",
+			Kind:     "Policy",
+			ListKind: "PolicyList",
+		}))
+		Expect(crd.Spec.Scope).To(Equal(v1beta1.NamespaceScoped))
+		Expect(crd.Spec.Subresources.Status).NotTo(BeNil())
+	})
+
+	It("should generate the validation schema from the JSON fields", func() {
+		crd, err := CRDForType(&Policy{}, s)
+		Expect(err).NotTo(HaveOccurred())
+		schema := crd.Spec.Validation.OpenAPIV3Schema
+		Expect(schema.Properties).To(HaveLen(2))
+
+		spec := schema.Properties["spec"]
+		Expect(spec.Type).To(Equal("object"))
+		Expect(spec.Properties).To(HaveLen(4))
+		Expect(spec.Properties["labels"].AdditionalProperties.Schema.Type).To(Equal("string"))
+		Expect(spec.Properties["limit"]).To(Equal(v1beta1.JSONSchemaProps{}))
+		Expect(spec.Properties["parent"].Properties["parent"]).To(Equal(v1beta1.JSONSchemaProps{}))
+
+		rule := spec.Properties["rules"].Items.Schema
+		Expect(rule.Properties).To(Equal(map[string]v1beta1.JSONSchemaProps{
+			"name":     {Type: "string"},
+			"priority": {Type: "integer"},
+			"weight":   {Type: "number"},
+			"enabled":  {Type: "boolean"},
+		}))
+
+		status := schema.Properties["status"]
+		Expect(status.Properties["applied"]).To(Equal(v1beta1.JSONSchemaProps{Type: "string", Format: "date-time"}))
+		Expect(status.Properties["data"]).To(Equal(v1beta1.JSONSchemaProps{Type: "string", Format: "byte"}))
+	})
+
+	It("should return an error if the type is not in the scheme", func() {
+		_, err := CRDForType(&Policy{}, runtime.NewScheme())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should pluralize the kinds", func() {
+		Expect(pluralize("policy")).To(Equal("policies"))
+		Expect(pluralize("gateway")).To(Equal("gateways"))
+		Expect(pluralize("ingress")).To(Equal("ingresses"))
+		Expect(pluralize("pod")).To(Equal("pods"))
+	})
+})
@@ -25,6 +25,7 @@ import (
 	. "github.com/onsi/gomega"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -100,6 +101,23 @@ var _ = Describe("Test", func() {
 			close(done)
 		}, 5)
 
+		It("should install the CRDs of Go types into the cluster", func(done Done) {
+			policyScheme := runtime.NewScheme()
+			policyScheme.AddKnownTypes(schema.GroupVersion{Group: "example.com", Version: "v1alpha1"}, &Policy{})
+			crds, err = InstallCRDs(env.Config, CRDInstallOptions{
+				Types:  []runtime.Object{&Policy{}},
+				Scheme: policyScheme,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			crd := &v1beta1.CustomResourceDefinition{}
+			err = c.Get(context.TODO(), types.NamespacedName{Name: "policies.example.com"}, crd)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(crd.Spec.Names.Kind).To(Equal("Policy"))
+
+			close(done)
+		}, 5)
+
 		It("should not return an not error if the directory doesn't exist", func(done Done) {
 			crds, err = InstallCRDs(env.Config, CRDInstallOptions{Paths: []string{"fake"}})
 			Expect(err).NotTo(HaveOccurred())
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/testing_frameworks/integration"
@@ -44,8 +45,10 @@ const (
 	defaultKubebuilderControlPlaneStopTimeout  = 20 * time.Second
 )
 
-func defaultAssetPath(binary string) string {
-	assetPath := os.Getenv(envKubebuilderPath)
+func defaultAssetPath(assetPath, binary string) string {
+	if assetPath == "" {
+		assetPath = os.Getenv(envKubebuilderPath)
+	}
 	if assetPath == "" {
 		assetPath = defaultKubebuilderPath
 	}
@@ -78,6 +81,14 @@ type Environment struct {
 	// CRDDirectoryPaths is a list of paths containing CRD yaml or json configs.
 	CRDDirectoryPaths []string
 
+	// CRDTypes is a list of Go types, such as &v1.MyKind{}, whose CRDs are generated and installed, for
+	// projects without CRD yaml or json configs.  See CRDForType.
+	CRDTypes []runtime.Object
+
+	// Scheme is the scheme the group, version and kind of the CRDTypes are looked up in.  It defaults
+	// to the client-go scheme.
+	Scheme *runtime.Scheme
+
 	// UseExisting indicates that this environments should use an
 	// existing kubeconfig, instead of trying to stand up a new control plane.
 	// This is useful in cases that need aggregated API servers and the like.
@@ -95,6 +106,11 @@ type Environment struct {
 
 	// KubeAPIServerFlags is the set of flags passed while starting the api server.
 	KubeAPIServerFlags []string
+
+	// BinaryAssets, if set, downloads the control plane binaries of a pinned version, instead of
+	// looking them up in the KUBEBUILDER_ASSETS directory.  The TEST_ASSET_* environment variables
+	// still take precedence.
+	BinaryAssets *BinaryAssets
 }
 
 // Stop stops a running server
@@ -132,15 +148,22 @@ func (te *Environment) Start() (*rest.Config, error) {
 		te.ControlPlane.APIServer = &integration.APIServer{Args: te.getAPIServerFlags()}
 		te.ControlPlane.Etcd = &integration.Etcd{}
 
+		var assetPath string
+		if te.BinaryAssets != nil {
+			var err error
+			if assetPath, err = te.BinaryAssets.Fetch(); err != nil {
+				return nil, fmt.Errorf("failed to fetch the control plane binaries: %v", err)
+			}
+		}
 		if os.Getenv(envKubeAPIServerBin) == "" {
-			te.ControlPlane.APIServer.Path = defaultAssetPath("kube-apiserver")
+			te.ControlPlane.APIServer.Path = defaultAssetPath(assetPath, "kube-apiserver")
 		}
 		if os.Getenv(envEtcdBin) == "" {
-			te.ControlPlane.Etcd.Path = defaultAssetPath("etcd")
+			te.ControlPlane.Etcd.Path = defaultAssetPath(assetPath, "etcd")
 		}
 		if os.Getenv(envKubectlBin) == "" {
 			// we can't just set the path manually (it's behind a function), so set the environment variable instead
-			if err := os.Setenv(envKubectlBin, defaultAssetPath("kubectl")); err != nil {
+			if err := os.Setenv(envKubectlBin, defaultAssetPath(assetPath, "kubectl")); err != nil {
 				return nil, err
 			}
 		}
@@ -164,8 +187,10 @@ func (te *Environment) Start() (*rest.Config, error) {
 	}
 
 	_, err := InstallCRDs(te.Config, CRDInstallOptions{
-		Paths: te.CRDDirectoryPaths,
-		CRDs:  te.CRDs,
+		Paths:  te.CRDDirectoryPaths,
+		CRDs:   te.CRDs,
+		Types:  te.CRDTypes,
+		Scheme: te.Scheme,
 	})
 	return te.Config, err
 }
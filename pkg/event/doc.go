@@ -21,5 +21,10 @@ reconcile.Requests by handler.EventHandler.
 The details of how events are produced and transformed into reconcile.Requests are not something most
 users should need to use or understand.  Instead of working with Events, users should use
 source.Sources and handler.EventHandlers with Controller.Watch.
+
+When built with Go 1.18 or later, TypedCreateEvent[T] and the other typed events carry an object of type T
+instead of a runtime.Object, and predicate.TypedPredicate[T] and handler.TypedEventHandler[T] filter and
+handle them without casting.  predicate.FromTyped and handler.FromTyped adapt those to the untyped
+interfaces accepted by Controller.Watch.
 */
 package event
@@ -0,0 +1,77 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+// TypedCreateEvent is a CreateEvent for an object of type T.  Unlike CreateEvent, T doesn't need to be a
+// Kubernetes object, so typed events may also carry objects originating outside the cluster.
+type TypedCreateEvent[T any] struct {
+	// Object is the object from the event
+	Object T
+}
+
+// TypedUpdateEvent is an UpdateEvent for an object of type T.
+type TypedUpdateEvent[T any] struct {
+	// ObjectOld is the object from the event (before the update)
+	ObjectOld T
+
+	// ObjectNew is the object from the event (after the update)
+	ObjectNew T
+}
+
+// TypedDeleteEvent is a DeleteEvent for an object of type T.
+type TypedDeleteEvent[T any] struct {
+	// Object is the object from the event
+	Object T
+
+	// DeleteStateUnknown is true if the Delete event was missed but we identified the object
+	// as having been deleted.
+	DeleteStateUnknown bool
+}
+
+// TypedGenericEvent is a GenericEvent for an object of type T.
+type TypedGenericEvent[T any] struct {
+	// Object is the object from the event
+	Object T
+}
+
+// TypedCreateEventFrom returns e as a TypedCreateEvent[T], or false if the object of e is not a T.
+func TypedCreateEventFrom[T any](e CreateEvent) (TypedCreateEvent[T], bool) {
+	obj, ok := e.Object.(T)
+	return TypedCreateEvent[T]{Object: obj}, ok
+}
+
+// TypedUpdateEventFrom returns e as a TypedUpdateEvent[T], or false if either object of e is not a T.
+func TypedUpdateEventFrom[T any](e UpdateEvent) (TypedUpdateEvent[T], bool) {
+	oldObj, oldOK := e.ObjectOld.(T)
+	newObj, newOK := e.ObjectNew.(T)
+	return TypedUpdateEvent[T]{ObjectOld: oldObj, ObjectNew: newObj}, oldOK && newOK
+}
+
+// TypedDeleteEventFrom returns e as a TypedDeleteEvent[T], or false if the object of e is not a T.
+func TypedDeleteEventFrom[T any](e DeleteEvent) (TypedDeleteEvent[T], bool) {
+	obj, ok := e.Object.(T)
+	return TypedDeleteEvent[T]{Object: obj, DeleteStateUnknown: e.DeleteStateUnknown}, ok
+}
+
+// TypedGenericEventFrom returns e as a TypedGenericEvent[T], or false if the object of e is not a T.
+func TypedGenericEventFrom[T any](e GenericEvent) (TypedGenericEvent[T], bool) {
+	obj, ok := e.Object.(T)
+	return TypedGenericEvent[T]{Object: obj}, ok
+}
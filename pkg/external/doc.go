@@ -0,0 +1,34 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package external adapts non-Kubernetes entities, such as cloud resources identified by an ID, so that they
+flow through the Sources, Predicates, EventHandlers and queues of a Controller like Kubernetes objects.
+
+An Object is wrapped by Adapt into an Adapter, which implements both runtime.Object and v1.Object, and the
+events of the Objects are sent to the Controller with a source.Channel:
+
+	events := make(chan event.GenericEvent)
+	err := c.Watch(&source.Channel{Source: events}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		// handle error
+	}
+	events <- external.GenericEvent(instance)
+
+The reconcile.Requests are named after the ID of the Objects, and the Object of an event is returned by
+FromObject in Predicates and EventHandlers.
+*/
+package external
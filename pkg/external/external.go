@@ -0,0 +1,118 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Object is a non-Kubernetes entity reconciled by a Controller.
+type Object interface {
+	// GetID returns the ID of the Object, which is the Name of its reconcile.Request.
+	GetID() string
+
+	// GetVersion returns a version of the Object which changes whenever the Object does, so that updates
+	// which didn't change it can be filtered by predicate.ResourceVersionChangedPredicate.  It may be empty.
+	GetVersion() string
+}
+
+// Labeled is implemented by the Objects which have labels, such as the tags of cloud resources, so that
+// they can be filtered by the label Predicates.
+type Labeled interface {
+	// GetLabels returns the labels of the Object.
+	GetLabels() map[string]string
+}
+
+var _ runtime.Object = &Adapter{}
+var _ metav1.Object = &Adapter{}
+
+// Adapter wraps an Object as a runtime.Object and v1.Object.  Its Name is the ID of the Object, and its
+// ResourceVersion the version of the Object.
+type Adapter struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	// Object is the adapted Object.
+	Object Object
+}
+
+// Adapt returns the Adapter of obj.
+func Adapt(obj Object) *Adapter {
+	a := &Adapter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            obj.GetID(),
+			ResourceVersion: obj.GetVersion(),
+		},
+		Object: obj,
+	}
+	if l, ok := obj.(Labeled); ok {
+		a.Labels = l.GetLabels()
+	}
+	return a
+}
+
+// DeepCopyObject implements runtime.Object.  The metadata of the Adapter is copied, but the copy shares
+// the adapted Object.
+func (a *Adapter) DeepCopyObject() runtime.Object {
+	return &Adapter{
+		TypeMeta:   a.TypeMeta,
+		ObjectMeta: *a.ObjectMeta.DeepCopy(),
+		Object:     a.Object,
+	}
+}
+
+// FromObject returns the Object adapted by obj.  It returns false if obj is not an Adapter.
+func FromObject(obj runtime.Object) (Object, bool) {
+	a, ok := obj.(*Adapter)
+	if !ok || a.Object == nil {
+		return nil, false
+	}
+	return a.Object, true
+}
+
+// Request returns the reconcile.Request for obj.
+func Request(obj Object) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Name: obj.GetID()}}
+}
+
+// CreateEvent returns the event.CreateEvent for the creation of obj.
+func CreateEvent(obj Object) event.CreateEvent {
+	a := Adapt(obj)
+	return event.CreateEvent{Meta: a, Object: a}
+}
+
+// UpdateEvent returns the event.UpdateEvent for the update of old to new.
+func UpdateEvent(old, new Object) event.UpdateEvent {
+	o, n := Adapt(old), Adapt(new)
+	return event.UpdateEvent{MetaOld: o, ObjectOld: o, MetaNew: n, ObjectNew: n}
+}
+
+// DeleteEvent returns the event.DeleteEvent for the deletion of obj.
+func DeleteEvent(obj Object) event.DeleteEvent {
+	a := Adapt(obj)
+	return event.DeleteEvent{Meta: a, Object: a}
+}
+
+// GenericEvent returns the event.GenericEvent for obj, which is sent to a Controller with a source.Channel.
+func GenericEvent(obj Object) event.GenericEvent {
+	a := Adapt(obj)
+	return event.GenericEvent{Meta: a, Object: a}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/external"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+type bucket struct {
+	id      string
+	version string
+	tags    map[string]string
+}
+
+func (b *bucket) GetID() string { return b.id }
+
+func (b *bucket) GetVersion() string { return b.version }
+
+func (b *bucket) GetLabels() map[string]string { return b.tags }
+
+var _ = Describe("External", func() {
+	var b *bucket
+	BeforeEach(func() {
+		b = &bucket{id: "bucket-1", version: "3", tags: map[string]string{"team": "foo"}}
+	})
+
+	Describe("Adapt", func() {
+		It("should name the Adapter after the ID of the Object", func() {
+			a := external.Adapt(b)
+			Expect(a.GetName()).To(Equal("bucket-1"))
+			Expect(a.GetNamespace()).To(BeEmpty())
+			Expect(a.GetResourceVersion()).To(Equal("3"))
+			Expect(a.GetLabels()).To(Equal(map[string]string{"team": "foo"}))
+		})
+
+		It("should copy the metadata but not the Object", func() {
+			a := external.Adapt(b)
+			c := a.DeepCopyObject().(*external.Adapter)
+			c.Labels["team"] = "bar"
+			Expect(a.GetLabels()).To(Equal(map[string]string{"team": "foo"}))
+			Expect(c.Object).To(BeIdenticalTo(b))
+		})
+	})
+
+	Describe("FromObject", func() {
+		It("should return the adapted Object", func() {
+			obj, ok := external.FromObject(external.Adapt(b))
+			Expect(ok).To(BeTrue())
+			Expect(obj).To(BeIdenticalTo(b))
+		})
+
+		It("should return false if the object is not an Adapter", func() {
+			_, ok := external.FromObject(&external.Adapter{})
+			Expect(ok).To(BeFalse())
+			_, ok = external.FromObject(nil)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("events", func() {
+		It("should be enqueued as the Request of the Object", func() {
+			q := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
+			h := &handler.EnqueueRequestForObject{}
+			h.Create(external.CreateEvent(b), q)
+			Expect(q.Len()).To(Equal(1))
+			req, _ := q.Get()
+			Expect(req).To(Equal(reconcile.Request{NamespacedName: types.NamespacedName{Name: "bucket-1"}}))
+			Expect(req).To(Equal(external.Request(b)))
+			q.Done(req)
+
+			h.Delete(external.DeleteEvent(b), q)
+			Expect(q.Len()).To(Equal(1))
+		})
+
+		It("should be filtered by Predicates", func() {
+			updated := &bucket{id: b.id, version: b.version, tags: map[string]string{"team": "bar"}}
+			Expect(predicate.ResourceVersionChangedPredicate{}.Update(external.UpdateEvent(b, updated))).To(BeFalse())
+			Expect(predicate.LabelChangedPredicate{}.Update(external.UpdateEvent(b, updated))).To(BeTrue())
+
+			updated.version = "4"
+			Expect(predicate.ResourceVersionChangedPredicate{}.Update(external.UpdateEvent(b, updated))).To(BeTrue())
+		})
+
+		It("should be sent to a Controller with a Channel", func(done Done) {
+			stop := make(chan struct{})
+			defer close(stop)
+			events := make(chan event.GenericEvent)
+			q := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
+			instance := &source.Channel{Source: events}
+			inject.StopChannelInto(stop, instance)
+			Expect(instance.Start(&handler.EnqueueRequestForObject{}, q)).To(Succeed())
+
+			events <- external.GenericEvent(b)
+			req, _ := q.Get()
+			Expect(req).To(Equal(external.Request(b)))
+			close(done)
+		})
+	})
+})
@@ -0,0 +1,95 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var indexLog = logf.KBLog.WithName("eventhandler").WithName("IndexMapper")
+
+var _ Mapper = &IndexMapper{}
+
+// IndexMapper is a Mapper for EnqueueRequestsFromMapFunc that looks up the objects depending on the object of
+// an event in a field index, e.g. the Deployments mounting a ConfigMap.  It maps the object to Requests for the
+// objects of the List type whose Field index holds the name of the object, in the namespace of the object.
+//
+// The Field index must be added to the cache of the Manager with IndexField before the Manager is started.
+type IndexMapper struct {
+	// List is the list type of the dependent objects, e.g. &appsv1.DeploymentList{}.
+	List runtime.Object
+
+	// Field is the name of the field index of the dependent objects.
+	Field string
+
+	// Reader lists the dependent objects.  Defaults to the cache of the Manager, injected by the Controller.
+	Reader client.Reader
+}
+
+// Map implements Mapper
+func (m *IndexMapper) Map(obj MapObject) []reconcile.Request {
+	if obj.Meta == nil || m.Reader == nil {
+		return nil
+	}
+	list := m.List.DeepCopyObject()
+	err := m.Reader.List(context.TODO(), list,
+		client.InNamespace(obj.Meta.GetNamespace()), client.MatchingField(m.Field, obj.Meta.GetName()))
+	if err != nil {
+		indexLog.Error(err, "Could not list the objects of the index", "list type", fmt.Sprintf("%T", m.List),
+			"field", m.Field, "namespace", obj.Meta.GetNamespace(), "name", obj.Meta.GetName())
+		return nil
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		indexLog.Error(err, "Could not extract the objects of the list", "list type", fmt.Sprintf("%T", m.List))
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(items))
+	for _, item := range items {
+		accessor, err := meta.Accessor(item)
+		if err != nil {
+			indexLog.Error(err, "Could not get the metadata of a listed object", "type", fmt.Sprintf("%T", item))
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: accessor.GetNamespace(),
+			Name:      accessor.GetName(),
+		}})
+	}
+	return requests
+}
+
+var _ inject.Cache = &IndexMapper{}
+
+// InjectCache is called by the Controller to provide the cache of the Manager as the Reader, unless one is set.
+func (m *IndexMapper) InjectCache(c cache.Cache) error {
+	if m.Reader == nil {
+		m.Reader = c
+	}
+	return nil
+}
@@ -22,6 +22,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 )
 
 var _ EventHandler = &EnqueueRequestsFromMapFunc{}
@@ -36,6 +37,9 @@ var _ EventHandler = &EnqueueRequestsFromMapFunc{}
 //
 // For UpdateEvents which contain both a new and old object, the transformation function is run on both
 // objects and both sets of Requests are enqueue.
+//
+// The dependencies of the Controller, e.g. its client or cache, are injected into ToRequests, so that it
+// can look up the objects to reconcile.  IndexMapper looks them up in a field index of the cache.
 type EnqueueRequestsFromMapFunc struct {
 	// Mapper transforms the argument into a slice of keys to be reconciled
 	ToRequests Mapper
@@ -62,6 +66,16 @@ func (e *EnqueueRequestsFromMapFunc) Generic(evt event.GenericEvent, q workqueue
 	e.mapAndEnqueue(q, MapObject{Meta: evt.Meta, Object: evt.Object})
 }
 
+var _ inject.Injector = &EnqueueRequestsFromMapFunc{}
+
+// InjectFunc is called by the Controller to inject its dependencies into ToRequests.
+func (e *EnqueueRequestsFromMapFunc) InjectFunc(f inject.Func) error {
+	if f == nil || e.ToRequests == nil {
+		return nil
+	}
+	return f(e.ToRequests)
+}
+
 func (e *EnqueueRequestsFromMapFunc) mapAndEnqueue(q workqueue.RateLimitingInterface, object MapObject) {
 	for _, req := range e.ToRequests.Map(object) {
 		q.Add(req)
@@ -19,6 +19,7 @@ package handler
 import (
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -51,6 +52,10 @@ type EnqueueRequestForOwner struct {
 
 	// groupKind is the cached Group and Kind from OwnerType
 	groupKind schema.GroupKind
+
+	// clusterScoped is true if the OwnerType is cluster-scoped, as resolved by the injected RESTMapper,
+	// in which case the Requests have no Namespace.
+	clusterScoped bool
 }
 
 // Create implements EventHandler
@@ -126,10 +131,11 @@ func (e *EnqueueRequestForOwner) getOwnerReconcileRequest(object metav1.Object)
 		// object in the event.
 		if ref.Kind == e.groupKind.Kind && refGV.Group == e.groupKind.Group {
 			// Match found - add a Request for the object referred to in the OwnerReference
-			result = append(result, reconcile.Request{NamespacedName: types.NamespacedName{
-				Namespace: object.GetNamespace(),
-				Name:      ref.Name,
-			}})
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: ref.Name}}
+			if !e.clusterScoped {
+				request.Namespace = object.GetNamespace()
+			}
+			result = append(result, request)
 		}
 	}
 
@@ -163,3 +169,23 @@ var _ inject.Scheme = &EnqueueRequestForOwner{}
 func (e *EnqueueRequestForOwner) InjectScheme(s *runtime.Scheme) error {
 	return e.parseOwnerTypeGroupKind(s)
 }
+
+var _ inject.Mapper = &EnqueueRequestForOwner{}
+
+// InjectMapper is called by the Controller to provide the rest mapper used by the manager, after the scheme.
+// It resolves whether the OwnerType is cluster-scoped, so that the Requests for cluster-scoped owners of
+// namespaced objects have no Namespace.  Owners whose scope can't be resolved, e.g. because their
+// CustomResourceDefinition is not installed yet, are assumed to be namespaced.
+func (e *EnqueueRequestForOwner) InjectMapper(m meta.RESTMapper) error {
+	if m == nil || e.groupKind.Empty() {
+		return nil
+	}
+	mapping, err := m.RESTMapping(e.groupKind)
+	if err != nil {
+		log.Error(err, "Could not get RESTMapping for OwnerType, assuming it is namespaced",
+			"owner type", fmt.Sprintf("%T", e.OwnerType))
+		return nil
+	}
+	e.clusterScoped = mapping.Scope.Name() == meta.RESTScopeNameRoot
+	return nil
+}
@@ -17,18 +17,27 @@ limitations under the License.
 package handler_test
 
 import (
+	"context"
+	"fmt"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 )
 
 var _ = Describe("Eventhandler", func() {
@@ -187,6 +196,55 @@ var _ = Describe("Eventhandler", func() {
 	})
 
 	Describe("EnqueueRequestsFromMapFunc", func() {
+		It("should inject the dependencies of the Controller into ToRequests.", func() {
+			mapper := &handler.IndexMapper{List: &appsv1.DeploymentList{}, Field: "configMap"}
+			instance := handler.EnqueueRequestsFromMapFunc{ToRequests: mapper}
+
+			c := &informertest.FakeInformers{}
+			Expect(instance.InjectFunc(func(i interface{}) error {
+				_, err := inject.CacheInto(c, i)
+				return err
+			})).To(Succeed())
+			Expect(mapper.Reader).To(Equal(c))
+		})
+
+		It("should enqueue Requests for the objects of the IndexMapper index.", func() {
+			reader := &indexReader{items: []appsv1.Deployment{
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "biz", Name: "foo"}},
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "biz", Name: "bar"}},
+			}}
+			instance := handler.EnqueueRequestsFromMapFunc{ToRequests: &handler.IndexMapper{
+				List:   &appsv1.DeploymentList{},
+				Field:  "configMap",
+				Reader: reader,
+			}}
+
+			cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "biz", Name: "baz"}}
+			instance.Create(event.CreateEvent{Object: cm, Meta: cm.GetObjectMeta()}, q)
+			Expect(reader.options.Namespace).To(Equal("biz"))
+			Expect(reader.options.FieldSelector.String()).To(Equal("configMap=baz"))
+
+			Expect(q.Len()).To(Equal(2))
+			i1, _ := q.Get()
+			i2, _ := q.Get()
+			Expect([]interface{}{i1, i2}).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "biz", Name: "foo"}},
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "biz", Name: "bar"}},
+			))
+		})
+
+		It("should not enqueue Requests if the IndexMapper fails to list.", func() {
+			instance := handler.EnqueueRequestsFromMapFunc{ToRequests: &handler.IndexMapper{
+				List:   &appsv1.DeploymentList{},
+				Field:  "configMap",
+				Reader: &indexReader{err: fmt.Errorf("expected error")},
+			}}
+
+			cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "biz", Name: "baz"}}
+			instance.Create(event.CreateEvent{Object: cm, Meta: cm.GetObjectMeta()}, q)
+			Expect(q.Len()).To(Equal(0))
+		})
+
 		It("should enqueue a Request with the function applied to the CreateEvent.", func() {
 			req := []reconcile.Request{}
 			instance := handler.EnqueueRequestsFromMapFunc{
@@ -342,6 +400,50 @@ var _ = Describe("Eventhandler", func() {
 	})
 
 	Describe("EnqueueRequestForOwner", func() {
+		It("should enqueue a Request without a Namespace for a cluster-scoped Owner.", func() {
+			instance := handler.EnqueueRequestForOwner{
+				OwnerType: &corev1.Node{},
+			}
+			Expect(instance.InjectScheme(scheme.Scheme)).To(Succeed())
+			mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion})
+			mapper.Add(corev1.SchemeGroupVersion.WithKind("Node"), meta.RESTScopeRoot)
+			Expect(instance.InjectMapper(mapper)).To(Succeed())
+
+			pod.OwnerReferences = []metav1.OwnerReference{
+				{
+					Name:       "foo-node",
+					Kind:       "Node",
+					APIVersion: "v1",
+				},
+			}
+			instance.Create(event.CreateEvent{Object: pod, Meta: pod.GetObjectMeta()}, q)
+			Expect(q.Len()).To(Equal(1))
+
+			i, _ := q.Get()
+			Expect(i).To(Equal(reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: "foo-node"}}))
+		})
+
+		It("should assume the Owner is namespaced if the RESTMapper can't map it.", func() {
+			instance := handler.EnqueueRequestForOwner{
+				OwnerType: &appsv1.ReplicaSet{},
+			}
+			Expect(instance.InjectScheme(scheme.Scheme)).To(Succeed())
+			Expect(instance.InjectMapper(meta.NewDefaultRESTMapper(nil))).To(Succeed())
+
+			pod.OwnerReferences = []metav1.OwnerReference{
+				{
+					Name:       "foo-parent",
+					Kind:       "ReplicaSet",
+					APIVersion: "apps/v1",
+				},
+			}
+			instance.Create(event.CreateEvent{Object: pod, Meta: pod.GetObjectMeta()}, q)
+			i, _ := q.Get()
+			Expect(i).To(Equal(reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: pod.GetNamespace(), Name: "foo-parent"}}))
+		})
+
 		It("should enqueue a Request with the Owner of the object in the CreateEvent.", func() {
 			instance := handler.EnqueueRequestForOwner{
 				OwnerType: &appsv1.ReplicaSet{},
@@ -901,3 +1003,20 @@ var _ = Describe("Eventhandler", func() {
 		})
 	})
 })
+
+// indexReader lists items, recording the options of the last List.
+type indexReader struct {
+	client.Reader
+	items   []appsv1.Deployment
+	err     error
+	options *client.ListOptions
+}
+
+func (r *indexReader) List(_ context.Context, list runtime.Object, opts ...client.ListOptionFunc) error {
+	r.options = (&client.ListOptions{}).ApplyOptions(opts)
+	if r.err != nil {
+		return r.err
+	}
+	list.(*appsv1.DeploymentList).Items = r.items
+	return nil
+}
@@ -0,0 +1,124 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// TypedEventHandler enqueues reconcile.Requests in response to events for objects of type T.
+// See EventHandler.
+type TypedEventHandler[T any] interface {
+	// Create is called in response to an create event - e.g. Pod Creation.
+	Create(event.TypedCreateEvent[T], workqueue.RateLimitingInterface)
+
+	// Update is called in response to an update event -  e.g. Pod Updated.
+	Update(event.TypedUpdateEvent[T], workqueue.RateLimitingInterface)
+
+	// Delete is called in response to a delete event - e.g. Pod Deleted.
+	Delete(event.TypedDeleteEvent[T], workqueue.RateLimitingInterface)
+
+	// Generic is called in response to an event of an unknown type or a synthetic event triggered as a cron or
+	// external trigger request - e.g. reconcile Autoscaling, or a Webhook.
+	Generic(event.TypedGenericEvent[T], workqueue.RateLimitingInterface)
+}
+
+// TypedFuncs implements TypedEventHandler.
+type TypedFuncs[T any] struct {
+	// Create is called in response to an add event.  Defaults to no-op.
+	// RateLimitingInterface is used to enqueue reconcile.Requests.
+	CreateFunc func(event.TypedCreateEvent[T], workqueue.RateLimitingInterface)
+
+	// Update is called in response to an update event.  Defaults to no-op.
+	// RateLimitingInterface is used to enqueue reconcile.Requests.
+	UpdateFunc func(event.TypedUpdateEvent[T], workqueue.RateLimitingInterface)
+
+	// Delete is called in response to a delete event.  Defaults to no-op.
+	// RateLimitingInterface is used to enqueue reconcile.Requests.
+	DeleteFunc func(event.TypedDeleteEvent[T], workqueue.RateLimitingInterface)
+
+	// GenericFunc is called in response to a generic event.  Defaults to no-op.
+	// RateLimitingInterface is used to enqueue reconcile.Requests.
+	GenericFunc func(event.TypedGenericEvent[T], workqueue.RateLimitingInterface)
+}
+
+// Create implements TypedEventHandler
+func (h TypedFuncs[T]) Create(e event.TypedCreateEvent[T], q workqueue.RateLimitingInterface) {
+	if h.CreateFunc != nil {
+		h.CreateFunc(e, q)
+	}
+}
+
+// Delete implements TypedEventHandler
+func (h TypedFuncs[T]) Delete(e event.TypedDeleteEvent[T], q workqueue.RateLimitingInterface) {
+	if h.DeleteFunc != nil {
+		h.DeleteFunc(e, q)
+	}
+}
+
+// Update implements TypedEventHandler
+func (h TypedFuncs[T]) Update(e event.TypedUpdateEvent[T], q workqueue.RateLimitingInterface) {
+	if h.UpdateFunc != nil {
+		h.UpdateFunc(e, q)
+	}
+}
+
+// Generic implements TypedEventHandler
+func (h TypedFuncs[T]) Generic(e event.TypedGenericEvent[T], q workqueue.RateLimitingInterface) {
+	if h.GenericFunc != nil {
+		h.GenericFunc(e, q)
+	}
+}
+
+// FromTyped returns an EventHandler calling h for events whose objects are of type T, so that
+// typed handlers can be passed to Controller.Watch.  Events for objects of any other type are
+// dropped.
+func FromTyped[T any](h TypedEventHandler[T]) EventHandler {
+	return typed[T]{h: h}
+}
+
+type typed[T any] struct {
+	h TypedEventHandler[T]
+}
+
+func (t typed[T]) Create(e event.CreateEvent, q workqueue.RateLimitingInterface) {
+	if te, ok := event.TypedCreateEventFrom[T](e); ok {
+		t.h.Create(te, q)
+	}
+}
+
+func (t typed[T]) Update(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	if te, ok := event.TypedUpdateEventFrom[T](e); ok {
+		t.h.Update(te, q)
+	}
+}
+
+func (t typed[T]) Delete(e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	if te, ok := event.TypedDeleteEventFrom[T](e); ok {
+		t.h.Delete(te, q)
+	}
+}
+
+func (t typed[T]) Generic(e event.GenericEvent, q workqueue.RateLimitingInterface) {
+	if te, ok := event.TypedGenericEventFrom[T](e); ok {
+		t.h.Generic(te, q)
+	}
+}
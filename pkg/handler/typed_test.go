@@ -0,0 +1,101 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("TypedEventHandler", func() {
+	var q workqueue.RateLimitingInterface
+	var pod *corev1.Pod
+	var instance handler.EventHandler
+
+	enqueue := func(obj *corev1.Pod, q workqueue.RateLimitingInterface) {
+		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}})
+	}
+
+	BeforeEach(func() {
+		q = controllertest.Queue{Interface: workqueue.New()}
+		pod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "biz", Name: "baz"}}
+		instance = handler.FromTyped[*corev1.Pod](handler.TypedFuncs[*corev1.Pod]{
+			CreateFunc: func(e event.TypedCreateEvent[*corev1.Pod], q workqueue.RateLimitingInterface) {
+				enqueue(e.Object, q)
+			},
+			UpdateFunc: func(e event.TypedUpdateEvent[*corev1.Pod], q workqueue.RateLimitingInterface) {
+				enqueue(e.ObjectNew, q)
+			},
+			DeleteFunc: func(e event.TypedDeleteEvent[*corev1.Pod], q workqueue.RateLimitingInterface) {
+				enqueue(e.Object, q)
+			},
+		})
+	})
+
+	It("should call the typed funcs with the typed objects", func() {
+		instance.Create(event.CreateEvent{Meta: pod, Object: pod}, q)
+		Expect(q.Len()).To(Equal(1))
+		i, _ := q.Get()
+		Expect(i).To(Equal(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "biz", Name: "baz"}}))
+		q.Done(i)
+
+		renamed := pod.DeepCopy()
+		renamed.Name = "qux"
+		instance.Update(event.UpdateEvent{MetaOld: pod, ObjectOld: pod, MetaNew: renamed, ObjectNew: renamed}, q)
+		Expect(q.Len()).To(Equal(1))
+		i, _ = q.Get()
+		Expect(i).To(Equal(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "biz", Name: "qux"}}))
+		q.Done(i)
+	})
+
+	It("should default to a no-op for unset funcs", func() {
+		instance.Generic(event.GenericEvent{Meta: pod, Object: pod}, q)
+		Expect(q.Len()).To(Equal(0))
+	})
+
+	It("should drop events for objects of other types", func() {
+		deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "biz", Name: "baz"}}
+		instance.Create(event.CreateEvent{Meta: deploy, Object: deploy}, q)
+		instance.Delete(event.DeleteEvent{Meta: deploy, Object: deploy}, q)
+		instance.Update(event.UpdateEvent{MetaOld: pod, ObjectOld: pod, MetaNew: deploy, ObjectNew: deploy}, q)
+		Expect(q.Len()).To(Equal(0))
+	})
+
+	It("should accept events for objects that are not Kubernetes objects", func() {
+		type external struct{ ID string }
+		var got []string
+		h := handler.TypedFuncs[external]{
+			GenericFunc: func(e event.TypedGenericEvent[external], _ workqueue.RateLimitingInterface) {
+				got = append(got, e.Object.ID)
+			},
+		}
+		h.Generic(event.TypedGenericEvent[external]{Object: external{ID: "a"}}, q)
+		Expect(got).To(Equal([]string{"a"}))
+	})
+})
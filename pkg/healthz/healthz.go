@@ -0,0 +1,92 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthz
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var log = logf.KBLog.WithName("healthz")
+
+// Checker knows how to perform a health check.  It returns an error if the check fails.
+type Checker func(req *http.Request) error
+
+// Ping is a Checker which always succeeds.  It is useful to check that the probe server is reachable.
+var Ping Checker = func(_ *http.Request) error { return nil }
+
+var _ http.Handler = &Handler{}
+
+// Handler serves the aggregated result of a set of Checkers.  It responds with
+// 200 if all of the checks pass, and 500 otherwise.  A single check may be run
+// by requesting the sub-path with its name, e.g. "/healthz/ping".  The result of
+// each check is listed in the response if any check fails or if the "verbose"
+// query parameter is set.
+type Handler struct {
+	// Checks are the Checkers to run, keyed by name.
+	Checks map[string]Checker
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	names := make([]string, 0, len(h.Checks))
+	if name := strings.Trim(req.URL.Path, "/"); name != "" {
+		if _, found := h.Checks[name]; !found {
+			http.NotFound(resp, req)
+			return
+		}
+		names = append(names, name)
+	} else {
+		for name := range h.Checks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	failed := false
+	var out bytes.Buffer
+	for _, name := range names {
+		if err := h.Checks[name](req); err != nil {
+			log.Info("health check failed", "checker", name, "error", err.Error())
+			fmt.Fprintf(&out, "[-]%s failed: reason withheld\n", name)
+			failed = true
+			continue
+		}
+		fmt.Fprintf(&out, "[+]%s ok\n", name)
+	}
+
+	resp.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	resp.Header().Set("X-Content-Type-Options", "nosniff")
+	if failed {
+		resp.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(&out, "check failed\n")
+		resp.Write(out.Bytes())
+		return
+	}
+
+	if _, verbose := req.URL.Query()["verbose"]; verbose {
+		fmt.Fprintf(&out, "check passed\n")
+		resp.Write(out.Bytes())
+		return
+	}
+	fmt.Fprint(resp, "ok")
+}
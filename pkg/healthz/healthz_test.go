@@ -0,0 +1,78 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthz_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+var _ = Describe("Handler", func() {
+	var h *healthz.Handler
+	var failing error
+
+	BeforeEach(func() {
+		failing = nil
+		h = &healthz.Handler{Checks: map[string]healthz.Checker{
+			"ping": healthz.Ping,
+			"other": func(_ *http.Request) error {
+				return failing
+			},
+		}}
+	})
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest("GET", path, nil))
+		return resp
+	}
+
+	It("should return ok if all checks pass", func() {
+		resp := serve("/")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.String()).To(Equal("ok"))
+	})
+
+	It("should list each check if verbose is set", func() {
+		resp := serve("/?verbose")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.String()).To(Equal("[+]other ok\n[+]ping ok\ncheck passed\n"))
+	})
+
+	It("should return an error if any check fails", func() {
+		failing = errors.New("broken")
+		resp := serve("/")
+		Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+		Expect(resp.Body.String()).To(Equal("[-]other failed: reason withheld\n[+]ping ok\ncheck failed\n"))
+	})
+
+	It("should only run the named check for a sub-path", func() {
+		failing = errors.New("broken")
+		resp := serve("/ping")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+	})
+
+	It("should return not found for an unknown check", func() {
+		resp := serve("/unknown")
+		Expect(resp.Code).To(Equal(http.StatusNotFound))
+	})
+})
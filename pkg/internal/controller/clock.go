@@ -0,0 +1,105 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+)
+
+var _ priorityqueue.Interface = &ClockedQueue{}
+
+// ClockedQueue wraps a RateLimitingInterface so that the delays of the requests added after a delay or
+// rate limited are measured by Clock instead of the wall clock, e.g. by a fake clock which tests step to
+// requeue the requests deterministically instead of sleeping.
+type ClockedQueue struct {
+	workqueue.RateLimitingInterface
+
+	// Clock measures the delays of the requests.
+	Clock clock.Clock
+
+	// RateLimiter delays the requests added with AddRateLimited.  It must be the rate limiter of the
+	// wrapped queue, which forgets the requests.  Defaults to nil, which rate limits the requests with
+	// the wrapped queue.
+	RateLimiter workqueue.RateLimiter
+
+	initOnce  sync.Once
+	closeOnce sync.Once
+	stopped   chan struct{}
+}
+
+// AddWithPriority implements priorityqueue.Interface
+func (q *ClockedQueue) AddWithPriority(item interface{}, priority int) {
+	priorityqueue.AddWithPriority(q.RateLimitingInterface, item, priority)
+}
+
+// AddAfter implements workqueue.DelayingInterface.  The item is added once duration has elapsed on the
+// Clock, unless the queue is shut down before.
+func (q *ClockedQueue) AddAfter(item interface{}, duration time.Duration) {
+	if duration <= 0 {
+		q.RateLimitingInterface.Add(item)
+		return
+	}
+	after := q.Clock.After(duration)
+	stopped := q.stop()
+	go func() {
+		select {
+		case <-after:
+			q.RateLimitingInterface.Add(item)
+		case <-stopped:
+		}
+	}()
+}
+
+// AddRateLimited implements workqueue.RateLimitingInterface
+func (q *ClockedQueue) AddRateLimited(item interface{}) {
+	if q.RateLimiter == nil {
+		q.RateLimitingInterface.AddRateLimited(item)
+		return
+	}
+	q.AddAfter(item, q.RateLimiter.When(item))
+}
+
+// ShutDown implements workqueue.Interface.  The items waiting for their delay are dropped.  The wrapped
+// queue is shut down once, even if ShutDown is called again, e.g. by Drain and then once the Controller
+// stops.
+func (q *ClockedQueue) ShutDown() {
+	q.closeOnce.Do(func() {
+		close(q.stop())
+		q.RateLimitingInterface.ShutDown()
+	})
+}
+
+// stop returns the channel closed once the queue is shut down.
+func (q *ClockedQueue) stop() chan struct{} {
+	q.initOnce.Do(func() {
+		q.stopped = make(chan struct{})
+	})
+	return q.stopped
+}
+
+// clock returns the Clock of the Controller, which defaults to the wall clock.
+func (c *Controller) clock() clock.Clock {
+	if c.Clock == nil {
+		return clock.RealClock{}
+	}
+	return c.Clock
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Clock", func() {
+	var fc *clock.FakeClock
+	var q *ClockedQueue
+	var request = reconcile.Request{
+		NamespacedName: types.NamespacedName{Namespace: "foo", Name: "bar"},
+	}
+
+	BeforeEach(func() {
+		fc = clock.NewFakeClock(time.Now())
+		rateLimiter := workqueue.NewItemExponentialFailureRateLimiter(time.Second, time.Minute)
+		q = &ClockedQueue{
+			RateLimitingInterface: workqueue.NewRateLimitingQueue(rateLimiter),
+			Clock:                 fc,
+			RateLimiter:           rateLimiter,
+		}
+	})
+
+	AfterEach(func() {
+		q.ShutDown()
+	})
+
+	Describe("ClockedQueue", func() {
+		It("should add the requests once their delay has elapsed on the Clock", func() {
+			q.AddAfter(request, time.Hour)
+			Expect(q.Len()).To(Equal(0))
+			fc.Step(59 * time.Minute)
+			Consistently(q.Len, 50*time.Millisecond).Should(Equal(0))
+			fc.Step(time.Minute)
+			Eventually(q.Len).Should(Equal(1))
+		})
+
+		It("should add the rate limited requests after the delay of the RateLimiter", func() {
+			q.AddRateLimited(request)
+			q.AddRateLimited(request)
+			Expect(q.NumRequeues(request)).To(Equal(2))
+			fc.Step(time.Second)
+			Eventually(q.Len).Should(Equal(1))
+
+			q.Forget(request)
+			Expect(q.NumRequeues(request)).To(Equal(0))
+		})
+
+		It("should drop the requests waiting for their delay once shut down", func() {
+			q.AddAfter(request, time.Hour)
+			q.ShutDown()
+			fc.Step(time.Hour)
+			Consistently(q.Len, 50*time.Millisecond).Should(Equal(0))
+		})
+	})
+
+	Describe("Controller", func() {
+		var ctrl *Controller
+		var reconciled chan reconcile.Request
+		var stop chan struct{}
+
+		BeforeEach(func() {
+			stop = make(chan struct{})
+			reconciled = make(chan reconcile.Request)
+			ctrl = &Controller{
+				Name:                    "clocked",
+				MaxConcurrentReconciles: 1,
+				Queue:                   q,
+				Cache:                   &informertest.FakeInformers{},
+				Clock:                   fc,
+			}
+			ctrl.InjectFunc(func(interface{}) error { return nil })
+		})
+
+		AfterEach(func() {
+			close(stop)
+		})
+
+		It("should requeue the requests once their RequeueAfter has elapsed on the Clock", func(done Done) {
+			ctrl.Do = reconcile.Func(func(r reconcile.Request) (reconcile.Result, error) {
+				reconciled <- r
+				return reconcile.Result{RequeueAfter: time.Hour}, nil
+			})
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(stop)).NotTo(HaveOccurred())
+			}()
+			ctrl.Queue.Add(request)
+			Expect(<-reconciled).To(Equal(request))
+
+			Eventually(fc.HasWaiters).Should(BeTrue())
+			Consistently(reconciled, 50*time.Millisecond).ShouldNot(Receive())
+			fc.Step(time.Hour)
+			Expect(<-reconciled).To(Equal(request))
+			close(done)
+		})
+
+		It("should measure the LivenessTimeout on the Clock", func() {
+			ctrl.Started = true
+			ctrl.LivenessTimeout = time.Minute
+			ctrl.Queue.Add(request)
+			Expect(ctrl.CheckLiveness(nil)).To(Succeed())
+			fc.Step(30 * time.Second)
+			Expect(ctrl.CheckLiveness(nil)).To(Succeed())
+			fc.Step(time.Minute)
+			Expect(ctrl.CheckLiveness(nil)).To(MatchError(ContainSubstring("has not reconciled any of its 1 queued requests for 1m30s")))
+		})
+
+		It("should measure the StuckReconcileThreshold on the Clock", func() {
+			ctrl.StuckReconcileThreshold = time.Minute
+			stuck := func() float64 {
+				var m dto.Metric
+				Expect(ctrlmetrics.ReconcileStuck.WithLabelValues("clocked").Write(&m)).To(Succeed())
+				return m.GetCounter().GetValue()
+			}
+			before := stuck()
+
+			stopWatchdog := ctrl.watchReconcile(request)
+			Consistently(stuck, 50*time.Millisecond).Should(Equal(before))
+			fc.Step(time.Minute)
+			Eventually(stuck).Should(Equal(before + 1))
+			stopWatchdog()
+
+			stopWatchdog = ctrl.watchReconcile(request)
+			stopWatchdog()
+			fc.Step(time.Minute)
+			Consistently(stuck, 50*time.Millisecond).Should(Equal(before + 1))
+		})
+	})
+})
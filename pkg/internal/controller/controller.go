@@ -18,10 +18,14 @@ package controller
 
 import (
 	"fmt"
+	"reflect"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/clock"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/rest"
@@ -31,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
@@ -88,10 +93,105 @@ type Controller struct {
 	// Started is true if the Controller has been Started
 	Started bool
 
+	// DrainOnShutdown, if true, makes Start drain the Controller once stop is closed, finishing the queued
+	// and in flight requests before it returns.  Otherwise the queued requests are abandoned once stop is
+	// closed, while the in flight requests are left to finish.
+	DrainOnShutdown bool
+
+	// ShutdownTimeout is the maximum time Start waits for the Controller to drain once stop is closed if
+	// DrainOnShutdown is set.  The requests still queued when it elapses are abandoned.  Defaults to 0,
+	// which waits until the Controller is drained.
+	ShutdownTimeout time.Duration
+
+	// RequeueAfterJitter is the maximum fraction of the Result.RequeueAfter of a request added to its
+	// delay, at random, so that requests requeued after the same duration are spread out.  Defaults to 0,
+	// which requeues requests after exactly their Result.RequeueAfter.
+	RequeueAfterJitter float64
+
+	// RecoverPanic, if true, makes a panicking Reconcile return an error, so that the request is requeued,
+	// instead of crashing the process.  The panic and its stack are logged.
+	RecoverPanic bool
+
+	// TrackProvenance, if true, records the Provenance of every request enqueued by a watch, so that
+	// what keeps enqueuing a request can be queried with Provenance.  A Provenance is kept for every
+	// request ever enqueued, so memory grows with the number of distinct requests.
+	TrackProvenance bool
+
+	// LivenessTimeout is how long the Controller can have queued requests without reconciling any before
+	// CheckLiveness fails.  Defaults to 0, which never fails CheckLiveness.
+	LivenessTimeout time.Duration
+
+	// StuckReconcileThreshold is how long a single Reconcile can run before the stack of its worker is
+	// logged and counted as stuck.  The Reconcile is not interrupted.  Defaults to 0, which never logs.
+	StuckReconcileThreshold time.Duration
+
+	// LogSuccessEvery is the sampling rate of the logs of successful reconciles: only one in every
+	// LogSuccessEvery successful reconciles is logged, while errors are always logged.  Defaults to 0,
+	// which like 1 logs every successful reconcile.
+	LogSuccessEvery int
+
+	// LeaderElected is whether the Controller must only be started once the Manager is elected leader.
+	// Defaults to nil, which like true needs leader election.
+	LeaderElected *bool
+
+	// Clock measures the durations of the Controller, such as the duration of reconciles, the
+	// ShutdownTimeout, the LivenessTimeout and the StuckReconcileThreshold, so that tests can step a fake
+	// clock instead of sleeping.  The delays of the requeued requests are measured by the Queue, e.g. a
+	// ClockedQueue.  Defaults to the wall clock.
+	Clock clock.Clock
+
 	// Recorder is an event recorder for recording Event resources to the
 	// Kubernetes API.
 	Recorder record.EventRecorder
 
+	// inFlight is the number of requests currently being reconciled, and must be accessed atomically.
+	inFlight int32
+
+	// processed is the number of items processed by the workers, and must be accessed atomically.
+	processed uint64
+
+	// succeeded is the number of successful reconciles, and must be accessed atomically.
+	succeeded uint64
+
+	// livenessProcessed and livenessSince are the number of processed items seen by the last call to
+	// CheckLiveness which observed progress, and the time of that call.
+	livenessMu        sync.Mutex
+	livenessProcessed uint64
+	livenessSince     time.Time
+
+	// reconciling are the requests being reconciled by a worker, and requeued the requests dequeued by
+	// another worker while being reconciled, which are added back to the Queue once reconciled.  They
+	// ensure that a request is never reconciled by two workers at once, even if the Queue doesn't.
+	reconcilingMu sync.Mutex
+	reconciling   map[reconcile.Request]bool
+	requeued      map[reconcile.Request]bool
+
+	// provenance is the Provenance of the requests enqueued by a watch if TrackProvenance is set.
+	provenanceMu sync.Mutex
+	provenance   map[reconcile.Request]Provenance
+
+	// drainStart is the UnixNano time at which Drain was first called, or 0 if the Controller is not
+	// draining.  It must be accessed atomically.
+	drainStart int64
+
+	// watches are the queues of the watches of comparable Sources, so that they can be unwatched.
+	watches map[source.Source][]*watchQueue
+
+	// syncingSources are the watched SyncingSources, whose caches are waited for by Start in addition to
+	// the Cache, as they may watch another cluster.
+	syncingSources []source.SyncingSource
+
+	// drainOnce and drainedOnce ensure that the queue is shut down and the drain duration recorded only once.
+	drainOnce   sync.Once
+	drainedOnce sync.Once
+
+	// drained is closed once the Controller has drained.  It is created when Drain is first called.
+	drained chan struct{}
+
+	// abandoning is non-zero once the Controller abandons its queued requests on shutdown, and must be
+	// accessed atomically.
+	abandoning int32
+
 	// TODO(community): Consider initializing a logger with the Controller Name as the tag
 }
 
@@ -119,7 +219,66 @@ func (c *Controller) Watch(src source.Source, evthdler handler.EventHandler, prc
 	}
 
 	log.Info("Starting EventSource", "controller", c.Name, "source", src)
-	return src.Start(evthdler, c.Queue, prct...)
+
+	var q workqueue.RateLimitingInterface = c.Queue
+	if c.TrackProvenance {
+		q = &provenanceQueue{RateLimitingInterface: c.Queue, controller: c, source: src, handler: evthdler}
+	}
+
+	// Only comparable Sources can be unwatched, as they are looked up by equality
+	if !reflect.TypeOf(src).Comparable() {
+		if err := src.Start(evthdler, q, prct...); err != nil {
+			return err
+		}
+		c.addSyncingSource(src)
+		return nil
+	}
+	queue := &watchQueue{RateLimitingInterface: q}
+	if err := src.Start(evthdler, queue, prct...); err != nil {
+		return err
+	}
+	c.addSyncingSource(src)
+	if c.watches == nil {
+		c.watches = map[source.Source][]*watchQueue{}
+	}
+	c.watches[src] = append(c.watches[src], queue)
+	return nil
+}
+
+// addSyncingSource records src if it is a SyncingSource, so that Start waits for it to sync.
+func (c *Controller) addSyncingSource(src source.Source) {
+	if ss, ok := src.(source.SyncingSource); ok {
+		c.syncingSources = append(c.syncingSources, ss)
+	}
+}
+
+// Unwatch implements controller.Controller
+func (c *Controller) Unwatch(src source.Source) error {
+	if !reflect.TypeOf(src).Comparable() {
+		return fmt.Errorf("source %T can't be unwatched because it is not comparable", src)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	queues, ok := c.watches[src]
+	if !ok {
+		return fmt.Errorf("source %v is not watched by controller %s", src, c.Name)
+	}
+	for _, q := range queues {
+		q.stop()
+	}
+	delete(c.watches, src)
+	syncing := c.syncingSources[:0]
+	for _, ss := range c.syncingSources {
+		if ss != src {
+			syncing = append(syncing, ss)
+		}
+	}
+	c.syncingSources = syncing
+
+	log.Info("Stopped EventSource", "controller", c.Name, "source", src)
+	return nil
 }
 
 // Start implements controller.Controller
@@ -145,38 +304,92 @@ func (c *Controller) Start(stop <-chan struct{}) error {
 		c.mu.Unlock()
 		return err
 	}
+	for _, src := range c.syncingSources {
+		if ok := src.WaitForSync(stop); !ok {
+			err := fmt.Errorf("failed to wait for %s caches to sync", c.Name)
+			log.Error(err, "Could not wait for Source to sync", "controller", c.Name, "source", src)
+			c.mu.Unlock()
+			return err
+		}
+	}
 
 	if c.JitterPeriod == 0 {
 		c.JitterPeriod = 1 * time.Second
 	}
 
-	// Launch workers to process resources
+	c.initMetrics()
+
+	// Launch workers to process resources.  They are stopped separately from stop, so that they keep
+	// processing the queued requests while the Controller drains on shutdown.
+	workersStop := make(chan struct{})
+	defer close(workersStop)
 	log.Info("Starting workers", "controller", c.Name, "worker count", c.MaxConcurrentReconciles)
 	for i := 0; i < c.MaxConcurrentReconciles; i++ {
 		// Process work items
 		go wait.Until(func() {
 			for c.processNextWorkItem() {
 			}
-		}, c.JitterPeriod, stop)
+		}, c.JitterPeriod, workersStop)
 	}
 
 	c.Started = true
 	c.mu.Unlock()
 
 	<-stop
+	if c.DrainOnShutdown {
+		c.waitForDrain()
+	}
+	c.abandonQueue()
 	log.Info("Stopping workers", "controller", c.Name)
 	return nil
 }
 
+// waitForDrain drains the Controller and waits until it is drained or ShutdownTimeout has elapsed.
+func (c *Controller) waitForDrain() {
+	c.Drain()
+	var timeout <-chan time.Time
+	if c.ShutdownTimeout > 0 {
+		timer := c.clock().NewTimer(c.ShutdownTimeout)
+		defer timer.Stop()
+		timeout = timer.C()
+	}
+	select {
+	case <-c.drained:
+	case <-timeout:
+		pending, inFlight := c.DrainProgress()
+		log.Info("Timed out draining workers", "controller", c.Name, "pending", pending, "inFlight", inFlight)
+	}
+}
+
+// abandonQueue stops the workers from processing the queued requests and drops them, counting them as
+// abandoned.  The requests being reconciled are left to finish.
+func (c *Controller) abandonQueue() {
+	atomic.StoreInt32(&c.abandoning, 1)
+	c.Queue.ShutDown()
+	abandoned := 0
+	for {
+		obj, shutdown := c.Queue.Get()
+		if shutdown {
+			break
+		}
+		c.Queue.Done(obj)
+		abandoned++
+	}
+	if abandoned > 0 {
+		ctrlmetrics.ReconcileAbandoned.WithLabelValues(c.Name).Add(float64(abandoned))
+		log.Info("Abandoned queued requests", "controller", c.Name, "count", abandoned)
+	}
+}
+
 // processNextWorkItem will read a single work item off the workqueue and
 // attempt to process it, by calling the syncHandler.
 func (c *Controller) processNextWorkItem() bool {
 	// This code copy-pasted from the sample-Controller.
 
 	// Update metrics after processing each item
-	reconcileStartTS := time.Now()
+	reconcileStartTS := c.clock().Now()
 	defer func() {
-		c.updateMetrics(time.Now().Sub(reconcileStartTS))
+		c.updateMetrics(c.clock().Since(reconcileStartTS))
 	}()
 
 	obj, shutdown := c.Queue.Get()
@@ -190,6 +403,20 @@ func (c *Controller) processNextWorkItem() bool {
 		return false
 	}
 
+	if atomic.LoadInt32(&c.abandoning) != 0 {
+		// The Controller is shutting down without draining
+		c.Queue.Done(obj)
+		ctrlmetrics.ReconcileAbandoned.WithLabelValues(c.Name).Inc()
+		return false
+	}
+
+	atomic.AddInt32(&c.inFlight, 1)
+	defer func() {
+		atomic.AddInt32(&c.inFlight, -1)
+		atomic.AddUint64(&c.processed, 1)
+		c.checkDrained()
+	}()
+
 	// We call Done here so the workqueue knows we have finished
 	// processing this item. We also must remember to call Forget if we
 	// do not want this work item being re-queued. For example, we do
@@ -210,21 +437,28 @@ func (c *Controller) processNextWorkItem() bool {
 		return true
 	}
 
+	if !c.startReconcile(req) {
+		// Another worker is reconciling the request, and adds it back to the queue once done
+		return true
+	}
+	defer c.finishReconcile(req)
+
 	// RunInformersAndControllers the syncHandler, passing it the namespace/Name string of the
 	// resource to be synced.
-	if result, err := c.Do.Reconcile(req); err != nil {
+	doStartTS := c.clock().Now()
+	if result, err := c.reconcile(req); err != nil {
 		c.Queue.AddRateLimited(req)
 		log.Error(err, "Reconciler error", "controller", c.Name, "request", req)
 		ctrlmetrics.ReconcileErrors.WithLabelValues(c.Name).Inc()
-		ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, "error").Inc()
+		c.recordResult(req, ctrlmetrics.ResultError, c.clock().Since(doStartTS))
 		return false
 	} else if result.RequeueAfter > 0 {
-		c.Queue.AddAfter(req, result.RequeueAfter)
-		ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, "requeue_after").Inc()
+		c.Queue.AddAfter(req, c.requeueAfter(result))
+		c.recordResult(req, ctrlmetrics.ResultRequeueAfter, c.clock().Since(doStartTS))
 		return true
 	} else if result.Requeue {
 		c.Queue.AddRateLimited(req)
-		ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, "requeue").Inc()
+		c.recordResult(req, ctrlmetrics.ResultRequeue, c.clock().Since(doStartTS))
 		return true
 	}
 
@@ -233,21 +467,211 @@ func (c *Controller) processNextWorkItem() bool {
 	c.Queue.Forget(obj)
 
 	// TODO(directxman12): What does 1 mean?  Do we want level constants?  Do we want levels at all?
-	log.V(1).Info("Successfully Reconciled", "controller", c.Name, "request", req)
+	if c.sampleSuccess() {
+		log.V(1).Info("Successfully Reconciled", "controller", c.Name, "request", req)
+	}
 
-	ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, "success").Inc()
+	c.recordResult(req, ctrlmetrics.ResultSuccess, c.clock().Since(doStartTS))
 	// Return true, don't take a break
 	return true
 }
 
+// sampleSuccess counts a successful reconcile, and returns whether it should be logged according to
+// LogSuccessEvery.  The first successful reconcile is always logged.
+func (c *Controller) sampleSuccess() bool {
+	n := atomic.AddUint64(&c.succeeded, 1)
+	if c.LogSuccessEvery <= 1 {
+		return true
+	}
+	return (n-1)%uint64(c.LogSuccessEvery) == 0
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (c *Controller) NeedLeaderElection() bool {
+	if c.LeaderElected == nil {
+		return true
+	}
+	return *c.LeaderElected
+}
+
+// requeueAfter returns the delay to requeue a request after for result, with the RequeueAfterJitter.
+func (c *Controller) requeueAfter(result reconcile.Result) time.Duration {
+	if c.RequeueAfterJitter <= 0 {
+		return result.RequeueAfter
+	}
+	return wait.Jitter(result.RequeueAfter, c.RequeueAfterJitter)
+}
+
+// reconcile reconciles req with Do, recovering from its panics if RecoverPanic is set.
+func (c *Controller) reconcile(req reconcile.Request) (result reconcile.Result, err error) {
+	if c.RecoverPanic {
+		defer func() {
+			if r := recover(); r != nil {
+				ctrlmetrics.ReconcilePanics.WithLabelValues(c.Name).Inc()
+				log.Error(fmt.Errorf("%v", r), "Observed a panic in Reconciler",
+					"controller", c.Name, "request", req, "stack", string(debug.Stack()))
+				err = fmt.Errorf("panic: %v [recovered]", r)
+			}
+		}()
+	}
+	defer c.watchReconcile(req)()
+	return c.Do.Reconcile(req)
+}
+
+// startReconcile marks req as being reconciled.  It returns false if req is already being
+// reconciled, in which case req is requeued once it has been reconciled.
+func (c *Controller) startReconcile(req reconcile.Request) bool {
+	c.reconcilingMu.Lock()
+	defer c.reconcilingMu.Unlock()
+	if c.reconciling == nil {
+		c.reconciling = map[reconcile.Request]bool{}
+		c.requeued = map[reconcile.Request]bool{}
+	}
+	if c.reconciling[req] {
+		c.requeued[req] = true
+		return false
+	}
+	c.reconciling[req] = true
+	ctrlmetrics.ActiveWorkers.WithLabelValues(c.Name).Set(float64(len(c.reconciling)))
+	return true
+}
+
+// finishReconcile marks req as reconciled, and adds it back to the Queue if it was dequeued while
+// being reconciled.
+func (c *Controller) finishReconcile(req reconcile.Request) {
+	c.reconcilingMu.Lock()
+	delete(c.reconciling, req)
+	requeue := c.requeued[req]
+	delete(c.requeued, req)
+	ctrlmetrics.ActiveWorkers.WithLabelValues(c.Name).Set(float64(len(c.reconciling)))
+	c.reconcilingMu.Unlock()
+
+	if requeue {
+		c.Queue.Add(req)
+	}
+}
+
+// Drain shuts down the Queue so that no new requests are accepted, while the workers keep reconciling the
+// requests that are already queued.  Requeues of drained requests are dropped.  Drain is idempotent.
+func (c *Controller) Drain() {
+	c.drainOnce.Do(func() {
+		log.Info("Draining workers", "controller", c.Name, "pending", c.Queue.Len())
+		c.drained = make(chan struct{})
+		atomic.StoreInt64(&c.drainStart, c.clock().Now().UnixNano())
+		c.Queue.ShutDown()
+		c.checkDrained()
+	})
+}
+
+// DrainProgress returns the number of requests still queued and the number of requests being reconciled.
+func (c *Controller) DrainProgress() (pending, inFlight int) {
+	return c.Queue.Len(), int(atomic.LoadInt32(&c.inFlight))
+}
+
+// Config are the settings of a Controller reported by ReportConfig.
+type Config struct {
+	MaxConcurrentReconciles int     `json:"maxConcurrentReconciles"`
+	Queue                   string  `json:"queue"`
+	NamespaceQPS            float32 `json:"namespaceQPS,omitempty"`
+	NamespaceBurst          int     `json:"namespaceBurst,omitempty"`
+	DrainOnShutdown         bool    `json:"drainOnShutdown,omitempty"`
+	ShutdownTimeout         string  `json:"shutdownTimeout,omitempty"`
+	RequeueAfterJitter      float64 `json:"requeueAfterJitter,omitempty"`
+	RecoverPanic            bool    `json:"recoverPanic,omitempty"`
+	TrackProvenance         bool    `json:"trackProvenance,omitempty"`
+	LivenessTimeout         string  `json:"livenessTimeout,omitempty"`
+	StuckReconcileThreshold string  `json:"stuckReconcileThreshold,omitempty"`
+	LogSuccessEvery         int     `json:"logSuccessEvery,omitempty"`
+	NeedLeaderElection      *bool   `json:"needLeaderElection,omitempty"`
+}
+
+// ReportConfig returns the Name and Config of the Controller.
+func (c *Controller) ReportConfig() (string, interface{}) {
+	config := Config{
+		MaxConcurrentReconciles: c.MaxConcurrentReconciles,
+		DrainOnShutdown:         c.DrainOnShutdown,
+		RequeueAfterJitter:      c.RequeueAfterJitter,
+		RecoverPanic:            c.RecoverPanic,
+		TrackProvenance:         c.TrackProvenance,
+		LogSuccessEvery:         c.LogSuccessEvery,
+		NeedLeaderElection:      c.LeaderElected,
+	}
+	if c.DrainOnShutdown && c.ShutdownTimeout > 0 {
+		config.ShutdownTimeout = c.ShutdownTimeout.String()
+	}
+	if c.LivenessTimeout > 0 {
+		config.LivenessTimeout = c.LivenessTimeout.String()
+	}
+	if c.StuckReconcileThreshold > 0 {
+		config.StuckReconcileThreshold = c.StuckReconcileThreshold.String()
+	}
+	queue := c.Queue
+	if q, ok := queue.(*NamespaceThrottledQueue); ok {
+		queue = q.RateLimitingInterface
+		config.NamespaceQPS = q.QPS
+		config.NamespaceBurst = q.Burst
+	}
+	if q, ok := queue.(*ClockedQueue); ok {
+		queue = q.RateLimitingInterface
+	}
+	config.Queue = fmt.Sprintf("%T", queue)
+	return c.Name, config
+}
+
+// checkDrained records the drain duration once a draining Controller has no queued or in flight requests.
+func (c *Controller) checkDrained() {
+	start := atomic.LoadInt64(&c.drainStart)
+	if start == 0 {
+		return
+	}
+	if pending, inFlight := c.DrainProgress(); pending > 0 || inFlight > 0 {
+		return
+	}
+	c.drainedOnce.Do(func() {
+		d := c.clock().Since(time.Unix(0, start))
+		log.Info("Drained workers", "controller", c.Name, "duration", d)
+		ctrlmetrics.DrainDuration.WithLabelValues(c.Name).Set(d.Seconds())
+		close(c.drained)
+	})
+}
+
 // InjectFunc implement SetFields.Injector
 func (c *Controller) InjectFunc(f inject.Func) error {
 	c.SetFields = f
 	return nil
 }
 
+// recordResult updates the prometheus reconcile result metrics for req, including
+// the per-namespace metrics if namespace tagging is enabled.
+func (c *Controller) recordResult(req reconcile.Request, result string, reconcileTime time.Duration) {
+	ctrlmetrics.BatchedReconcileTotal.Inc(c.Name, result)
+	if ns, ok := metrics.NamespaceTag(req.Namespace); ok {
+		ctrlmetrics.ReconcileNamespaceTotal.WithLabelValues(c.Name, ns, result).Inc()
+		ctrlmetrics.ReconcileNamespaceTime.WithLabelValues(c.Name, ns).Observe(reconcileTime.Seconds())
+	}
+}
+
+// initMetrics initializes the reconcile counters of the controller for every result to zero, so that
+// ratios between results can be computed before each result has been recorded at least once.
+func (c *Controller) initMetrics() {
+	for _, result := range ctrlmetrics.Results {
+		ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, result).Add(0)
+	}
+	ctrlmetrics.ReconcileErrors.WithLabelValues(c.Name).Add(0)
+	if c.RecoverPanic {
+		ctrlmetrics.ReconcilePanics.WithLabelValues(c.Name).Add(0)
+	}
+	if c.StuckReconcileThreshold > 0 {
+		ctrlmetrics.ReconcileStuck.WithLabelValues(c.Name).Add(0)
+	}
+	ctrlmetrics.ActiveWorkers.WithLabelValues(c.Name).Set(0)
+	ctrlmetrics.MaxWorkers.WithLabelValues(c.Name).Set(float64(c.MaxConcurrentReconciles))
+}
+
 // updateMetrics updates prometheus metrics within the controller
 func (c *Controller) updateMetrics(reconcileTime time.Duration) {
 	ctrlmetrics.QueueLength.WithLabelValues(c.Name).Set(float64(c.Queue.Len()))
-	ctrlmetrics.ReconcileTime.WithLabelValues(c.Name).Observe(reconcileTime.Seconds())
+	if metrics.Sample(metrics.ReconcileTime, c.Name) {
+		ctrlmetrics.BatchedReconcileTime.Observe(reconcileTime.Seconds(), c.Name)
+	}
 }
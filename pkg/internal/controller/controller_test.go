@@ -18,6 +18,7 @@ package controller
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	. "github.com/onsi/ginkgo"
@@ -26,16 +27,20 @@ import (
 	dto "github.com/prometheus/client_model/go"
 	"k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile/reconciletest"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
@@ -96,6 +101,32 @@ var _ = Describe("controller", func() {
 			close(done)
 		})
 
+		It("should wait for the caches of the watched Kinds to sync", func(done Done) {
+			synced := false
+			remote := &informertest.FakeInformers{Synced: &synced}
+			Expect(ctrl.Watch(source.NewKindWithCache(&corev1.Pod{}, remote), &handler.EnqueueRequestForObject{})).To(Succeed())
+			ctrl.Name = "foo"
+
+			err := ctrl.Start(stop)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed to wait for foo caches to sync"))
+
+			close(done)
+		})
+
+		It("should not wait for the caches of the unwatched Kinds", func(done Done) {
+			synced := false
+			src := source.NewKindWithCache(&corev1.Pod{}, &informertest.FakeInformers{Synced: &synced})
+			Expect(ctrl.Watch(src, &handler.EnqueueRequestForObject{})).To(Succeed())
+			Expect(ctrl.Unwatch(src)).To(Succeed())
+
+			stopped := make(chan struct{})
+			close(stopped)
+			Expect(ctrl.Start(stopped)).To(Succeed())
+
+			close(done)
+		})
+
 		It("should wait for each informer to sync", func(done Done) {
 			// Use a stopped channel so Start doesn't block
 			stopped := make(chan struct{})
@@ -260,6 +291,446 @@ var _ = Describe("controller", func() {
 		})
 	})
 
+	Describe("Watch after Start", func() {
+		It("should reconcile the requests enqueued for the events of the Source", func(done Done) {
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(stop)).NotTo(HaveOccurred())
+			}()
+			Eventually(func() bool {
+				ctrl.mu.Lock()
+				defer ctrl.mu.Unlock()
+				return ctrl.Started
+			}).Should(BeTrue())
+
+			src := &source.Kind{Type: &corev1.Pod{}}
+			Expect(inject.CacheInto(informers, src)).To(BeTrue())
+			Expect(ctrl.Watch(src, &handler.EnqueueRequestForObject{})).To(Succeed())
+			fakeInformer, err := informers.FakeInformerFor(&corev1.Pod{})
+			Expect(err).NotTo(HaveOccurred())
+			fakeInformer.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"}})
+			Expect(<-reconciled).To(Equal(request))
+			close(done)
+		})
+	})
+
+	Describe("Unwatch", func() {
+		It("should drop the requests enqueued for the events of the Source", func() {
+			var q workqueue.RateLimitingInterface
+			src := &source.Kind{Type: &corev1.Pod{}}
+			Expect(ctrl.Unwatch(src)).To(HaveOccurred())
+
+			Expect(inject.CacheInto(informers, src)).To(BeTrue())
+			Expect(ctrl.Watch(src, handler.Funcs{CreateFunc: func(_ event.CreateEvent, queue workqueue.RateLimitingInterface) {
+				q = queue
+			}})).To(Succeed())
+			fakeInformer, err := informers.FakeInformerFor(&corev1.Pod{})
+			Expect(err).NotTo(HaveOccurred())
+			fakeInformer.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo"}})
+			Expect(q).NotTo(BeNil())
+
+			q.Add(request)
+			Expect(ctrl.Queue.Len()).To(Equal(1))
+
+			By("unwatching the Source")
+			Expect(ctrl.Unwatch(src)).To(Succeed())
+			q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "baz"}})
+			q.AddRateLimited(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "baz"}})
+			q.AddAfter(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "baz"}}, time.Millisecond)
+			Consistently(ctrl.Queue.Len).Should(Equal(1))
+
+			Expect(ctrl.Unwatch(src)).To(HaveOccurred())
+		})
+
+		It("should return an error if the Source is not comparable", func() {
+			src := source.Func(func(handler.EventHandler, workqueue.RateLimitingInterface, ...predicate.Predicate) error {
+				return nil
+			})
+			Expect(ctrl.Watch(src, &handler.EnqueueRequestForObject{})).To(Succeed())
+			Expect(ctrl.Unwatch(src)).To(HaveOccurred())
+		})
+
+		It("should not track the Source if it fails to start", func() {
+			src := &source.Kind{}
+			Expect(ctrl.Watch(src, &handler.EnqueueRequestForObject{})).To(HaveOccurred())
+			Expect(ctrl.Unwatch(src)).To(HaveOccurred())
+		})
+	})
+
+	Describe("Provenance", func() {
+		It("should record the watch which last enqueued a request if TrackProvenance is set", func() {
+			ctrl.TrackProvenance = true
+			var q1, q2 workqueue.RateLimitingInterface
+			src1 := &source.Kind{Type: &corev1.Pod{}}
+			Expect(inject.CacheInto(informers, src1)).To(BeTrue())
+			Expect(ctrl.Watch(src1, handler.Funcs{CreateFunc: func(_ event.CreateEvent, queue workqueue.RateLimitingInterface) {
+				q1 = queue
+			}})).To(Succeed())
+			fakeInformer, err := informers.FakeInformerFor(&corev1.Pod{})
+			Expect(err).NotTo(HaveOccurred())
+			fakeInformer.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo"}})
+			Expect(q1).NotTo(BeNil())
+			src2 := source.Func(func(_ handler.EventHandler, queue workqueue.RateLimitingInterface, _ ...predicate.Predicate) error {
+				q2 = queue
+				return nil
+			})
+			Expect(ctrl.Watch(src2, &handler.EnqueueRequestForObject{})).To(Succeed())
+
+			_, ok := ctrl.Provenance(request)
+			Expect(ok).To(BeFalse())
+
+			By("enqueuing the request from the first watch")
+			q1.Add(request)
+			p, ok := ctrl.Provenance(request)
+			Expect(ok).To(BeTrue())
+			Expect(p.Source).To(Equal(src1.String()))
+			Expect(p.Handler).To(Equal("handler.Funcs"))
+			Expect(p.Count).To(Equal(1))
+			Expect(p.Time).NotTo(BeZero())
+
+			By("enqueuing the request from the second watch")
+			q2.AddRateLimited(request)
+			p, ok = ctrl.Provenance(request)
+			Expect(ok).To(BeTrue())
+			Expect(p.Source).To(Equal(src2.String()))
+			Expect(p.Handler).To(Equal("*handler.EnqueueRequestForObject"))
+			Expect(p.Count).To(Equal(2))
+
+			name, reported, ok := ctrl.ReportProvenance(request.NamespacedName)
+			Expect(ok).To(BeTrue())
+			Expect(name).To(Equal(ctrl.Name))
+			Expect(reported).To(Equal(p))
+		})
+
+		It("should not record the provenance of requests if TrackProvenance is not set", func() {
+			var q workqueue.RateLimitingInterface
+			src := source.Func(func(_ handler.EventHandler, queue workqueue.RateLimitingInterface, _ ...predicate.Predicate) error {
+				q = queue
+				return nil
+			})
+			Expect(ctrl.Watch(src, &handler.EnqueueRequestForObject{})).To(Succeed())
+			q.Add(request)
+			Expect(ctrl.Queue.Len()).To(Equal(1))
+			_, ok := ctrl.Provenance(request)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("NeedLeaderElection", func() {
+		It("should need leader election by default", func() {
+			Expect(ctrl.NeedLeaderElection()).To(BeTrue())
+		})
+
+		It("should not need leader election if LeaderElected is false", func() {
+			needLeaderElection := false
+			ctrl.LeaderElected = &needLeaderElection
+			Expect(ctrl.NeedLeaderElection()).To(BeFalse())
+		})
+	})
+
+	Describe("ReportConfig", func() {
+		It("should report the settings of the Controller", func() {
+			ctrl.Name = "foo"
+			ctrl.MaxConcurrentReconciles = 3
+			name, config := ctrl.ReportConfig()
+			Expect(name).To(Equal("foo"))
+			Expect(config).To(Equal(Config{
+				MaxConcurrentReconciles: 3,
+				Queue:                   "*controllertest.Queue",
+			}))
+		})
+
+		It("should report whether the Controller recovers from panics", func() {
+			ctrl.RecoverPanic = true
+			_, config := ctrl.ReportConfig()
+			Expect(config).To(Equal(Config{
+				MaxConcurrentReconciles: 1,
+				Queue:                   "*controllertest.Queue",
+				RecoverPanic:            true,
+			}))
+		})
+
+		It("should report the StuckReconcileThreshold", func() {
+			ctrl.StuckReconcileThreshold = time.Minute
+			_, config := ctrl.ReportConfig()
+			Expect(config).To(Equal(Config{
+				MaxConcurrentReconciles: 1,
+				Queue:                   "*controllertest.Queue",
+				StuckReconcileThreshold: "1m0s",
+			}))
+		})
+
+		It("should report whether the Controller needs leader election", func() {
+			needLeaderElection := false
+			ctrl.LeaderElected = &needLeaderElection
+			_, config := ctrl.ReportConfig()
+			Expect(config).To(Equal(Config{
+				MaxConcurrentReconciles: 1,
+				Queue:                   "*controllertest.Queue",
+				NeedLeaderElection:      &needLeaderElection,
+			}))
+		})
+
+		It("should report the namespace throttle of the queue", func() {
+			ctrl.Queue = &NamespaceThrottledQueue{RateLimitingInterface: queue, QPS: 5, Burst: 10}
+			_, config := ctrl.ReportConfig()
+			Expect(config).To(Equal(Config{
+				MaxConcurrentReconciles: 1,
+				Queue:                   "*controllertest.Queue",
+				NamespaceQPS:            5,
+				NamespaceBurst:          10,
+			}))
+		})
+	})
+
+	Describe("Drain", func() {
+		It("should finish the queued requests and drop new ones", func(done Done) {
+			ctrl.Name = "drain"
+			other := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "baz"}}
+			ctrl.Queue.Add(request)
+			ctrl.Queue.Add(other)
+
+			ctrl.Drain()
+			pending, inFlight := ctrl.DrainProgress()
+			Expect(pending).To(Equal(2))
+			Expect(inFlight).To(Equal(0))
+
+			By("Dropping requests added after the drain began")
+			ctrl.Queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "new"}})
+			Expect(ctrl.Queue.Len()).To(Equal(2))
+
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(stop)).NotTo(HaveOccurred())
+			}()
+
+			By("Reconciling the queued requests")
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(func() int { _, inFlight := ctrl.DrainProgress(); return inFlight }).Should(Equal(1))
+			Expect(<-reconciled).To(Equal(other))
+
+			By("Recording the drain duration once drained")
+			Eventually(func() []int {
+				pending, inFlight := ctrl.DrainProgress()
+				return []int{pending, inFlight}
+			}).Should(Equal([]int{0, 0}))
+			var drain dto.Metric
+			Eventually(func() error {
+				if err := ctrlmetrics.DrainDuration.WithLabelValues(ctrl.Name).Write(&drain); err != nil {
+					return err
+				}
+				if drain.GetGauge().GetValue() <= 0 {
+					return fmt.Errorf("drain duration not recorded")
+				}
+				return nil
+			}).Should(Succeed())
+
+			close(done)
+		})
+	})
+
+	Describe("Shutdown", func() {
+		var blocked chan struct{}
+
+		BeforeEach(func() {
+			ctrl.Name = "shutdown"
+			ctrlmetrics.ReconcileAbandoned.Reset()
+			blocked = make(chan struct{})
+			ctrl.Do = reconcile.Func(func(r reconcile.Request) (reconcile.Result, error) {
+				<-blocked
+				reconciled <- r
+				return reconcile.Result{}, nil
+			})
+		})
+
+		abandoned := func() float64 {
+			var m dto.Metric
+			Expect(ctrlmetrics.ReconcileAbandoned.WithLabelValues(ctrl.Name).Write(&m)).To(Succeed())
+			return m.GetCounter().GetValue()
+		}
+
+		// startAndStop starts the Controller with a request in flight and two queued requests,
+		// then stops it and returns a channel closed once Start returns.
+		startAndStop := func() chan struct{} {
+			for _, name := range []string{"bar", "baz", "qux"} {
+				ctrl.Queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: name}})
+			}
+			returned := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(stop)).NotTo(HaveOccurred())
+				close(returned)
+			}()
+			Eventually(func() int { _, inFlight := ctrl.DrainProgress(); return inFlight }).Should(Equal(1))
+			close(stop)
+			stop = make(chan struct{})
+			return returned
+		}
+
+		It("should abandon the queued requests by default", func(done Done) {
+			returned := startAndStop()
+			Eventually(returned).Should(BeClosed())
+			Expect(abandoned()).To(Equal(2.0))
+
+			By("leaving the request in flight to finish")
+			close(blocked)
+			Expect(<-reconciled).To(Equal(request))
+			Consistently(reconciled).ShouldNot(Receive())
+			close(done)
+		})
+
+		It("should finish the queued requests before returning if DrainOnShutdown is set", func(done Done) {
+			ctrl.DrainOnShutdown = true
+			returned := startAndStop()
+			Consistently(returned).ShouldNot(BeClosed())
+
+			close(blocked)
+			for i := 0; i < 3; i++ {
+				<-reconciled
+			}
+			Eventually(returned).Should(BeClosed())
+			Expect(abandoned()).To(Equal(0.0))
+			close(done)
+		})
+
+		It("should abandon the requests still queued once the ShutdownTimeout has elapsed", func(done Done) {
+			ctrl.DrainOnShutdown = true
+			ctrl.ShutdownTimeout = 200 * time.Millisecond
+			returned := startAndStop()
+			Eventually(returned).Should(BeClosed())
+			Expect(abandoned()).To(Equal(2.0))
+
+			close(blocked)
+			Expect(<-reconciled).To(Equal(request))
+			close(done)
+		})
+	})
+
+	Describe("CheckLiveness", func() {
+		It("should always succeed if LivenessTimeout is not set", func() {
+			ctrl.Started = true
+			ctrl.Queue.Add(request)
+			Expect(ctrl.CheckLiveness(nil)).To(Succeed())
+			time.Sleep(10 * time.Millisecond)
+			Expect(ctrl.CheckLiveness(nil)).To(Succeed())
+		})
+
+		It("should fail once the queued requests are not reconciled for LivenessTimeout", func(done Done) {
+			ctrl.Name = "liveness"
+			ctrl.LivenessTimeout = 100 * time.Millisecond
+			blocked := make(chan struct{})
+			ctrl.Do = reconcile.Func(func(r reconcile.Request) (reconcile.Result, error) {
+				reconciled <- r
+				<-blocked
+				return reconcile.Result{}, nil
+			})
+			other := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "baz"}}
+			ctrl.Queue.Add(request)
+			ctrl.Queue.Add(other)
+			Expect(ctrl.CheckLiveness(nil)).To(Succeed())
+
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(stop)).NotTo(HaveOccurred())
+			}()
+			Expect(<-reconciled).To(Equal(request))
+			Expect(ctrl.CheckLiveness(nil)).To(Succeed())
+			Eventually(func() error { return ctrl.CheckLiveness(nil) }, time.Second, 20*time.Millisecond).
+				Should(MatchError(ContainSubstring("controller liveness has not reconciled any of its 1 queued requests")))
+
+			By("succeeding once the worker reconciles a request")
+			close(blocked)
+			Expect(<-reconciled).To(Equal(other))
+			Expect(ctrl.CheckLiveness(nil)).To(Succeed())
+			close(done)
+		})
+	})
+
+	Describe("sampleSuccess", func() {
+		It("should sample every successful reconcile by default", func() {
+			for i := 0; i < 3; i++ {
+				Expect(ctrl.sampleSuccess()).To(BeTrue())
+			}
+		})
+
+		It("should sample one in every LogSuccessEvery successful reconciles", func() {
+			ctrl.LogSuccessEvery = 3
+			var sampled []bool
+			for i := 0; i < 7; i++ {
+				sampled = append(sampled, ctrl.sampleSuccess())
+			}
+			Expect(sampled).To(Equal([]bool{true, false, false, true, false, false, true}))
+		})
+	})
+
+	Describe("goroutineStack", func() {
+		It("should return the stack of the goroutine with the given ID", func() {
+			id := goroutineID()
+			Expect(id).NotTo(BeEmpty())
+			stack := string(goroutineStack(id))
+			Expect(stack).To(HavePrefix("goroutine " + id + " ["))
+			Expect(stack).To(ContainSubstring("controller_test.go"))
+			Expect(stack).NotTo(ContainSubstring("\n\ngoroutine "))
+		})
+	})
+
+	Describe("Concurrency", func() {
+		var blocked chan struct{}
+
+		BeforeEach(func() {
+			ctrl.Name = "concurrency"
+			ctrl.MaxConcurrentReconciles = 2
+			// The queue doesn't dedupe, so both workers get the request
+			ctrl.Queue = &controllertest.Queue{Interface: newFifoQueue()}
+			blocked = make(chan struct{})
+			ctrl.Do = reconcile.Func(func(r reconcile.Request) (reconcile.Result, error) {
+				reconciled <- r
+				<-blocked
+				return reconcile.Result{}, nil
+			})
+		})
+
+		gauge := func(g *prometheus.GaugeVec) float64 {
+			var m dto.Metric
+			Expect(g.WithLabelValues(ctrl.Name).Write(&m)).To(Succeed())
+			return m.GetGauge().GetValue()
+		}
+
+		It("should never reconcile a request in two workers at once", func(done Done) {
+			ctrl.Queue.Add(request)
+			ctrl.Queue.Add(request)
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(stop)).NotTo(HaveOccurred())
+			}()
+
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(ctrl.Queue.Len).Should(BeZero())
+			Consistently(reconciled).ShouldNot(Receive())
+
+			By("reconciling the request again once the first reconcile returns")
+			close(blocked)
+			Expect(<-reconciled).To(Equal(request))
+			close(done)
+		})
+
+		It("should record the saturation of the workers", func(done Done) {
+			ctrl.Queue.Add(request)
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(stop)).NotTo(HaveOccurred())
+			}()
+
+			<-reconciled
+			Expect(gauge(ctrlmetrics.ActiveWorkers)).To(Equal(1.0))
+			Expect(gauge(ctrlmetrics.MaxWorkers)).To(Equal(2.0))
+
+			close(blocked)
+			Eventually(func() float64 { return gauge(ctrlmetrics.ActiveWorkers) }).Should(BeZero())
+			close(done)
+		})
+	})
+
 	Describe("Processing queue items from a Controller", func() {
 		It("should call Reconciler if an item is enqueued", func(done Done) {
 			go func() {
@@ -328,6 +799,69 @@ var _ = Describe("controller", func() {
 			close(done)
 		}, 1.0)
 
+		It("should requeue a Request if the Reconciler panics and RecoverPanic is set", func(done Done) {
+			ctrl.Name = "recover-panic"
+			ctrl.RecoverPanic = true
+			ctrl.JitterPeriod = time.Millisecond
+			ctrlmetrics.ReconcilePanics.Reset()
+			panicked := false
+			ctrl.Do = reconcile.Func(func(r reconcile.Request) (reconcile.Result, error) {
+				reconciled <- r
+				if !panicked {
+					panicked = true
+					panic("expected panic: reconcile")
+				}
+				return reconcile.Result{}, nil
+			})
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(stop)).NotTo(HaveOccurred())
+			}()
+			ctrl.Queue.Add(request)
+
+			By("Invoking Reconciler which will panic")
+			Expect(<-reconciled).To(Equal(request))
+
+			By("Invoking Reconciler a second time without panicking")
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(ctrl.Queue.Len).Should(Equal(0))
+
+			var panics dto.Metric
+			Expect(ctrlmetrics.ReconcilePanics.WithLabelValues(ctrl.Name).Write(&panics)).To(Succeed())
+			Expect(panics.GetCounter().GetValue()).To(Equal(1.0))
+
+			close(done)
+		}, 1.0)
+
+		It("should log and count a Reconcile exceeding the StuckReconcileThreshold", func(done Done) {
+			ctrl.Name = "stuck-reconcile"
+			ctrl.StuckReconcileThreshold = 50 * time.Millisecond
+			ctrlmetrics.ReconcileStuck.Reset()
+			blocked := make(chan struct{})
+			ctrl.Do = reconcile.Func(func(r reconcile.Request) (reconcile.Result, error) {
+				reconciled <- r
+				<-blocked
+				return reconcile.Result{}, nil
+			})
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(stop)).NotTo(HaveOccurred())
+			}()
+			ctrl.Queue.Add(request)
+			Expect(<-reconciled).To(Equal(request))
+
+			stuck := func() float64 {
+				var m dto.Metric
+				Expect(ctrlmetrics.ReconcileStuck.WithLabelValues(ctrl.Name).Write(&m)).To(Succeed())
+				return m.GetCounter().GetValue()
+			}
+			Eventually(stuck).Should(Equal(1.0))
+			close(blocked)
+			Consistently(stuck, 100*time.Millisecond).Should(Equal(1.0))
+
+			close(done)
+		}, 1.0)
+
 		It("should requeue a Request if the Result sets Requeue:true and continue processing items", func() {
 			fakeReconcile.Result.Requeue = true
 			go func() {
@@ -391,6 +925,21 @@ var _ = Describe("controller", func() {
 			Eventually(func() int { return ctrl.Queue.NumRequeues(request) }).Should(Equal(0))
 		})
 
+		It("should add up to the RequeueAfterJitter to the RequeueAfter of the Result", func() {
+			result := reconcile.Result{RequeueAfter: 100 * time.Millisecond}
+			Expect(ctrl.requeueAfter(result)).To(Equal(result.RequeueAfter))
+
+			ctrl.RequeueAfterJitter = 0.5
+			delays := map[time.Duration]bool{}
+			for i := 0; i < 100; i++ {
+				d := ctrl.requeueAfter(result)
+				Expect(d).To(BeNumerically(">=", result.RequeueAfter))
+				Expect(d).To(BeNumerically("<=", 150*time.Millisecond))
+				delays[d] = true
+			}
+			Expect(len(delays)).To(BeNumerically(">", 1))
+		})
+
 		It("should forget the Request if Reconciler is successful", func() {
 			// TODO(community): write this test
 		})
@@ -415,6 +964,36 @@ var _ = Describe("controller", func() {
 				reconcileTotal.Reset()
 			})
 
+			It("should be initialized for every result when the controller starts", func(done Done) {
+				ctrl.Name = "init-metrics"
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(stop)).NotTo(HaveOccurred())
+				}()
+
+				Eventually(func() []string {
+					ch := make(chan prometheus.Metric, 10)
+					ctrlmetrics.ReconcileTotal.Collect(ch)
+					close(ch)
+					var results []string
+					for m := range ch {
+						var metric dto.Metric
+						Expect(m.Write(&metric)).To(Succeed())
+						labels := map[string]string{}
+						for _, l := range metric.GetLabel() {
+							labels[l.GetName()] = l.GetValue()
+						}
+						if labels["controller"] == ctrl.Name {
+							Expect(metric.GetCounter().GetValue()).To(BeZero())
+							results = append(results, labels["result"])
+						}
+					}
+					return results
+				}).Should(ConsistOf("success", "error", "requeue", "requeue_after"))
+
+				close(done)
+			})
+
 			It("should get updated on successful reconciliation", func(done Done) {
 				Expect(func() error {
 					ctrlmetrics.ReconcileTotal.WithLabelValues(ctrl.Name, "success").Write(&reconcileTotal)
@@ -531,6 +1110,61 @@ var _ = Describe("controller", func() {
 			}, 2.0)
 		})
 
+		Context("prometheus metric reconcile_namespace_total", func() {
+			var reconcileTotal dto.Metric
+
+			BeforeEach(func() {
+				ctrlmetrics.ReconcileNamespaceTotal.Reset()
+				reconcileTotal.Reset()
+			})
+
+			AfterEach(func() {
+				metrics.Configure(metrics.WithoutNamespaceTag())
+			})
+
+			It("should record the request namespace if it is allowed", func(done Done) {
+				metrics.Configure(metrics.WithNamespaceTag("foo"))
+
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(stop)).NotTo(HaveOccurred())
+				}()
+				ctrl.Queue.Add(request)
+
+				Expect(<-reconciled).To(Equal(request))
+				Eventually(func() error {
+					ctrlmetrics.ReconcileNamespaceTotal.WithLabelValues(ctrl.Name, "foo", "success").Write(&reconcileTotal)
+					if actual := reconcileTotal.GetCounter().GetValue(); actual != 1.0 {
+						return fmt.Errorf("metric reconcile namespace total expected: %v and got: %v", 1.0, actual)
+					}
+					return nil
+				}, 2.0).Should(Succeed())
+
+				close(done)
+			}, 2.0)
+
+			It("should record other for namespaces that are not allowed", func(done Done) {
+				metrics.Configure(metrics.WithNamespaceTag("baz"))
+
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(stop)).NotTo(HaveOccurred())
+				}()
+				ctrl.Queue.Add(request)
+
+				Expect(<-reconciled).To(Equal(request))
+				Eventually(func() error {
+					ctrlmetrics.ReconcileNamespaceTotal.WithLabelValues(ctrl.Name, metrics.OtherNamespace, "success").Write(&reconcileTotal)
+					if actual := reconcileTotal.GetCounter().GetValue(); actual != 1.0 {
+						return fmt.Errorf("metric reconcile namespace total expected: %v and got: %v", 1.0, actual)
+					}
+					return nil
+				}, 2.0).Should(Succeed())
+
+				close(done)
+			}, 2.0)
+		})
+
 		Context("should update prometheus metrics", func() {
 			It("should requeue a Request if there is an error and continue processing items", func(done Done) {
 				var queueLength, reconcileErrs dto.Metric
@@ -655,3 +1289,45 @@ func (q *DelegatingQueue) Add(item interface{}) {
 	q.countAdd++
 	q.RateLimitingInterface.Add(item)
 }
+
+// fifoQueue is a workqueue.Interface which, unlike the workqueue package, doesn't dedupe its items.
+type fifoQueue struct {
+	items    chan interface{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func newFifoQueue() *fifoQueue {
+	return &fifoQueue{items: make(chan interface{}, 10), stopped: make(chan struct{})}
+}
+
+func (q *fifoQueue) Add(item interface{}) {
+	select {
+	case <-q.stopped:
+	case q.items <- item:
+	}
+}
+
+func (q *fifoQueue) Len() int { return len(q.items) }
+
+func (q *fifoQueue) Get() (interface{}, bool) {
+	select {
+	case <-q.stopped:
+		return nil, true
+	case item := <-q.items:
+		return item, false
+	}
+}
+
+func (q *fifoQueue) Done(item interface{}) {}
+
+func (q *fifoQueue) ShutDown() { q.stopOnce.Do(func() { close(q.stopped) }) }
+
+func (q *fifoQueue) ShuttingDown() bool {
+	select {
+	case <-q.stopped:
+		return true
+	default:
+		return false
+	}
+}
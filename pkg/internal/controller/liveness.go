@@ -0,0 +1,53 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// CheckLiveness is a healthz.Checker failing once the Controller has had queued requests without
+// reconciling any request for LivenessTimeout, which indicates that its workers are deadlocked.  The
+// time without progress is measured between calls, so it must be called periodically, e.g. by the
+// liveness probe of the operator.  It always succeeds if LivenessTimeout is not set.
+func (c *Controller) CheckLiveness(_ *http.Request) error {
+	if c.LivenessTimeout <= 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	started := c.Started
+	c.mu.Unlock()
+
+	c.livenessMu.Lock()
+	defer c.livenessMu.Unlock()
+	processed := atomic.LoadUint64(&c.processed)
+	now := c.clock().Now()
+	if !started || c.Queue.Len() == 0 || processed != c.livenessProcessed || c.livenessSince.IsZero() {
+		c.livenessProcessed = processed
+		c.livenessSince = now
+		return nil
+	}
+	if stalled := now.Sub(c.livenessSince); stalled > c.LivenessTimeout {
+		return fmt.Errorf("controller %s has not reconciled any of its %d queued requests for %s",
+			c.Name, c.Queue.Len(), stalled.Round(time.Second))
+	}
+	return nil
+}
@@ -21,6 +21,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
+// The values of the result label of ReconcileTotal and ReconcileNamespaceTotal.
+const (
+	ResultSuccess      = "success"
+	ResultError        = "error"
+	ResultRequeue      = "requeue"
+	ResultRequeueAfter = "requeue_after"
+)
+
+// Results are all the values of the result label of ReconcileTotal.
+var Results = []string{ResultSuccess, ResultError, ResultRequeue, ResultRequeueAfter}
+
 var (
 	// QueueLength is a prometheus metric which counts the current reconcile
 	// queue length per controller
@@ -51,17 +62,102 @@ var (
 		Name: "controller_runtime_reconcile_time_seconds",
 		Help: "Length of time per reconciliation per controller",
 	}, []string{"controller"})
+
+	// BatchedReconcileTotal and BatchedReconcileTime record ReconcileTotal and
+	// ReconcileTime in batches once batching is enabled with metrics.WithBatching
+	BatchedReconcileTotal = metrics.NewBatchedCounter(ReconcileTotal)
+	BatchedReconcileTime  = metrics.NewBatchedHistogram(ReconcileTime)
+
+	// ReconcileNamespaceTotal is a prometheus counter metrics which holds the total
+	// number of reconciliations per controller and request namespace.  It is only
+	// recorded when namespace tagging is enabled with metrics.WithNamespaceTag.
+	ReconcileNamespaceTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_reconcile_namespace_total",
+		Help: "Total number of reconciliations per controller and namespace",
+	}, []string{"controller", "namespace", "result"})
+
+	// ReconcileNamespaceTime is a prometheus metric which keeps track of the duration
+	// of reconciliations per request namespace.  It is only recorded when namespace
+	// tagging is enabled with metrics.WithNamespaceTag.
+	ReconcileNamespaceTime = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "controller_runtime_reconcile_namespace_time_seconds",
+		Help: "Length of time per reconciliation per controller and namespace",
+	}, []string{"controller", "namespace"})
+
+	// ReconcileThrottled is a prometheus counter metrics which holds the total
+	// number of requests which were delayed because their namespace exceeded
+	// the per-namespace rate limit of the controller.  The namespace is only
+	// recorded when namespace tagging is enabled with metrics.WithNamespaceTag,
+	// and is empty otherwise.
+	ReconcileThrottled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_reconcile_throttled_total",
+		Help: "Total number of requests delayed by the per-namespace rate limit per controller and namespace",
+	}, []string{"controller", "namespace"})
+
+	// DrainDuration is a prometheus metric which holds the time it took the
+	// controller to finish its queued and in flight reconciles after it
+	// started draining on shutdown
+	DrainDuration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "controller_runtime_reconcile_drain_seconds",
+		Help: "Time taken to drain the reconcile queue on shutdown per controller",
+	}, []string{"controller"})
+
+	// ActiveWorkers is a prometheus metric which holds the number of workers
+	// currently reconciling a request per controller.  Divided by MaxWorkers,
+	// it is the saturation of the workers of the controller
+	ActiveWorkers = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "controller_runtime_reconcile_active_workers",
+		Help: "Number of workers currently reconciling a request per controller",
+	}, []string{"controller"})
+
+	// MaxWorkers is a prometheus metric which holds the maximum number of
+	// concurrent reconciles per controller
+	MaxWorkers = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "controller_runtime_reconcile_max_workers",
+		Help: "Maximum number of concurrent reconciles per controller",
+	}, []string{"controller"})
+
+	// ReconcilePanics is a prometheus counter metrics which holds the total
+	// number of panics recovered from the Reconciler per controller
+	ReconcilePanics = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_reconcile_panics_total",
+		Help: "Total number of reconciliation panics per controller",
+	}, []string{"controller"})
+
+	// ReconcileStuck is a prometheus counter metrics which holds the total
+	// number of reconciles which had not returned after the stuck reconcile
+	// threshold per controller
+	ReconcileStuck = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_reconcile_stuck_total",
+		Help: "Total number of reconciliations exceeding the stuck reconcile threshold per controller",
+	}, []string{"controller"})
+
+	// ReconcileAbandoned is a prometheus counter metrics which holds the total
+	// number of queued reconcile requests abandoned on shutdown per controller
+	ReconcileAbandoned = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_reconcile_abandoned_total",
+		Help: "Total number of queued reconcile requests abandoned on shutdown per controller",
+	}, []string{"controller"})
 )
 
 func init() {
-	metrics.Registry.MustRegister(
-		QueueLength,
-		ReconcileTotal,
-		ReconcileErrors,
-		ReconcileTime,
+	metrics.AddDefaultViews(
+		metrics.View{Collector: QueueLength},
+		metrics.View{Collector: ReconcileTotal},
+		metrics.View{Collector: ReconcileErrors},
+		metrics.View{Collector: ReconcileTime},
+		metrics.View{Collector: ReconcileNamespaceTotal, HighCardinality: true},
+		metrics.View{Collector: ReconcileNamespaceTime, HighCardinality: true},
+		metrics.View{Collector: ReconcileThrottled, HighCardinality: true},
+		metrics.View{Collector: DrainDuration},
+		metrics.View{Collector: ReconcileAbandoned},
+		metrics.View{Collector: ReconcilePanics},
+		metrics.View{Collector: ReconcileStuck},
+		metrics.View{Collector: ActiveWorkers},
+		metrics.View{Collector: MaxWorkers},
 		// expose process metrics like CPU, Memory, file descriptor usage etc.
-		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		metrics.View{Collector: prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{})},
 		// expose Go runtime metrics like GC stats, memory stats etc.
-		prometheus.NewGoCollector(),
+		metrics.View{Collector: prometheus.NewGoCollector()},
 	)
 }
@@ -0,0 +1,122 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Provenance is the watch which last enqueued a request, recorded if TrackProvenance is set.
+type Provenance struct {
+	// Source is the Source of the watch, as returned by its String method if it has one, or its type.
+	Source string `json:"source"`
+
+	// Handler is the type of the EventHandler of the watch.
+	Handler string `json:"handler"`
+
+	// Time is when the request was last enqueued.
+	Time time.Time `json:"time"`
+
+	// Count is the number of times the request was enqueued by any watch.
+	Count int `json:"count"`
+}
+
+// Provenance returns the Provenance of req.  It returns false if req was never enqueued by a watch
+// or TrackProvenance is not set.
+func (c *Controller) Provenance(req reconcile.Request) (Provenance, bool) {
+	c.provenanceMu.Lock()
+	defer c.provenanceMu.Unlock()
+	p, ok := c.provenance[req]
+	return p, ok
+}
+
+// ReportProvenance returns the Name of the Controller and the Provenance of the request for key.
+func (c *Controller) ReportProvenance(key types.NamespacedName) (string, interface{}, bool) {
+	p, ok := c.Provenance(reconcile.Request{NamespacedName: key})
+	return c.Name, p, ok
+}
+
+// recordProvenance records that item was enqueued by the watch of src and evthdler.
+func (c *Controller) recordProvenance(item interface{}, src source.Source, evthdler handler.EventHandler) {
+	req, ok := item.(reconcile.Request)
+	if !ok {
+		return
+	}
+	c.provenanceMu.Lock()
+	defer c.provenanceMu.Unlock()
+	if c.provenance == nil {
+		c.provenance = map[reconcile.Request]Provenance{}
+	}
+	p := c.provenance[req]
+	p.Source = sourceName(src)
+	p.Handler = fmt.Sprintf("%T", evthdler)
+	p.Time = c.clock().Now()
+	p.Count++
+	c.provenance[req] = p
+}
+
+// sourceName returns the name of src reported in its Provenance.
+func sourceName(src source.Source) string {
+	if s, ok := src.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", src)
+}
+
+var _ priorityqueue.Interface = &provenanceQueue{}
+
+// provenanceQueue wraps the Queue of a Controller for a single watch, so that the Provenance of the
+// requests enqueued for the events of its Source is recorded.
+type provenanceQueue struct {
+	workqueue.RateLimitingInterface
+
+	controller *Controller
+	source     source.Source
+	handler    handler.EventHandler
+}
+
+// Add implements workqueue.Interface
+func (q *provenanceQueue) Add(item interface{}) {
+	q.controller.recordProvenance(item, q.source, q.handler)
+	q.RateLimitingInterface.Add(item)
+}
+
+// AddWithPriority implements priorityqueue.Interface
+func (q *provenanceQueue) AddWithPriority(item interface{}, priority int) {
+	q.controller.recordProvenance(item, q.source, q.handler)
+	priorityqueue.AddWithPriority(q.RateLimitingInterface, item, priority)
+}
+
+// AddAfter implements workqueue.DelayingInterface
+func (q *provenanceQueue) AddAfter(item interface{}, duration time.Duration) {
+	q.controller.recordProvenance(item, q.source, q.handler)
+	q.RateLimitingInterface.AddAfter(item, duration)
+}
+
+// AddRateLimited implements workqueue.RateLimitingInterface
+func (q *provenanceQueue) AddRateLimited(item interface{}) {
+	q.controller.recordProvenance(item, q.source, q.handler)
+	q.RateLimitingInterface.AddRateLimited(item)
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ priorityqueue.Interface = &NamespaceThrottledQueue{}
+
+// NamespaceThrottledQueue wraps a RateLimitingInterface and limits the rate at which reconcile.Requests
+// are added for each namespace using a token bucket per namespace.  Requests added over the limit are
+// not dropped, but are delayed until a token is available for their namespace, so that a single namespace
+// cannot consume all of the reconcile capacity of a Controller.
+type NamespaceThrottledQueue struct {
+	workqueue.RateLimitingInterface
+
+	// Name is the name of the Controller, used to label the throttle metrics.
+	Name string
+
+	// QPS is the number of Requests per second that may be added for a single namespace.
+	QPS float32
+
+	// Burst is the number of Requests that may be added for a single namespace at once.
+	Burst int
+
+	// Clock measures the refill of the buckets.  It should be the Clock of the ClockedQueue the
+	// delayed Requests are added to, if any, so that they are added once their bucket has refilled.
+	// Defaults to the wall clock.
+	Clock clock.Clock
+
+	mu       sync.Mutex
+	limiters map[string]*namespaceLimiter
+
+	// lastEvicted is the last time the idle limiters were evicted from limiters.
+	lastEvicted time.Time
+}
+
+// namespaceLimiter is the token bucket of a namespace.
+type namespaceLimiter struct {
+	*rate.Limiter
+
+	// full is the time the bucket is full again, after which the limiter is idle and
+	// behaves like a new one.
+	full time.Time
+}
+
+// Add implements workqueue.Interface.  Requests exceeding the limit of their namespace are added after
+// the delay required to stay within the limit.
+func (q *NamespaceThrottledQueue) Add(item interface{}) {
+	q.AddWithPriority(item, 0)
+}
+
+// AddWithPriority implements priorityqueue.Interface.  Requests exceeding the limit of their namespace
+// are added after the delay required to stay within the limit, without their priority.
+func (q *NamespaceThrottledQueue) AddWithPriority(item interface{}, priority int) {
+	req, ok := item.(reconcile.Request)
+	if !ok {
+		priorityqueue.AddWithPriority(q.RateLimitingInterface, item, priority)
+		return
+	}
+
+	if delay := q.reserve(req.Namespace); delay > 0 {
+		// The namespace is only recorded if namespace tagging is enabled, and is limited like
+		// the namespaces of the other reconcile metrics
+		ns, _ := metrics.NamespaceTag(req.Namespace)
+		ctrlmetrics.ReconcileThrottled.WithLabelValues(q.Name, ns).Inc()
+		q.RateLimitingInterface.AddAfter(item, delay)
+		return
+	}
+	priorityqueue.AddWithPriority(q.RateLimitingInterface, item, priority)
+}
+
+// reserve takes a token from the bucket for namespace and returns how long to wait before it may be used.
+func (q *NamespaceThrottledQueue) reserve(namespace string) time.Duration {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	if q.limiters == nil {
+		q.limiters = map[string]*namespaceLimiter{}
+	}
+	q.evictIdle(now)
+	l, found := q.limiters[namespace]
+	if !found {
+		l = &namespaceLimiter{Limiter: rate.NewLimiter(rate.Limit(q.QPS), q.Burst)}
+		q.limiters[namespace] = l
+	}
+	delay := l.ReserveN(now, 1).DelayFrom(now)
+	l.full = now.Add(delay + q.refill())
+	return delay
+}
+
+// evictIdle removes the limiters of the namespaces whose bucket is full, so that limiters don't
+// accumulate for namespaces which are no longer reconciled.  The limiters are checked at most once
+// per refill of a bucket.
+func (q *NamespaceThrottledQueue) evictIdle(now time.Time) {
+	// Buckets never refill without a rate
+	if q.QPS <= 0 || now.Sub(q.lastEvicted) < q.refill() {
+		return
+	}
+	q.lastEvicted = now
+	for namespace, l := range q.limiters {
+		if !now.Before(l.full) {
+			delete(q.limiters, namespace)
+		}
+	}
+}
+
+// refill returns how long an empty bucket takes to be full.
+func (q *NamespaceThrottledQueue) refill() time.Duration {
+	if q.QPS <= 0 {
+		return 0
+	}
+	return time.Duration(float64(q.Burst) / float64(q.QPS) * float64(time.Second))
+}
+
+// now returns the time of the Clock, which defaults to the wall clock.
+func (q *NamespaceThrottledQueue) now() time.Time {
+	if q.Clock == nil {
+		return time.Now()
+	}
+	return q.Clock.Now()
+}
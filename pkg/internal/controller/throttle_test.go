@@ -0,0 +1,139 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("NamespaceThrottledQueue", func() {
+	var q *NamespaceThrottledQueue
+
+	BeforeEach(func() {
+		ctrlmetrics.ReconcileThrottled.Reset()
+		q = &NamespaceThrottledQueue{
+			RateLimitingInterface: workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+			Name:                  "throttled",
+			QPS:                   1,
+			Burst:                 1,
+		}
+	})
+
+	AfterEach(func() {
+		q.ShutDown()
+	})
+
+	requestFor := func(namespace, name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+	}
+
+	It("should add requests within the limit immediately", func() {
+		q.Add(requestFor("foo", "a"))
+		q.Add(requestFor("bar", "a"))
+		Expect(q.Len()).To(Equal(2))
+	})
+
+	It("should delay requests over the limit of their namespace", func() {
+		q.Add(requestFor("foo", "a"))
+		q.Add(requestFor("foo", "b"))
+		Expect(q.Len()).To(Equal(1))
+
+		var throttled dto.Metric
+		Expect(ctrlmetrics.ReconcileThrottled.WithLabelValues("throttled", "").Write(&throttled)).To(Succeed())
+		Expect(throttled.GetCounter().GetValue()).To(Equal(1.0))
+
+		By("adding the delayed request once a token is available")
+		Eventually(q.Len, 3*time.Second).Should(Equal(2))
+	})
+
+	It("should record the namespace of the requests delayed when namespace tagging is enabled", func() {
+		metrics.Configure(metrics.WithNamespaceTag("foo"))
+		defer metrics.Configure(metrics.WithoutNamespaceTag())
+
+		q.Add(requestFor("foo", "a"))
+		q.Add(requestFor("foo", "b"))
+		q.Add(requestFor("bar", "a"))
+		q.Add(requestFor("bar", "b"))
+
+		var throttled dto.Metric
+		Expect(ctrlmetrics.ReconcileThrottled.WithLabelValues("throttled", "foo").Write(&throttled)).To(Succeed())
+		Expect(throttled.GetCounter().GetValue()).To(Equal(1.0))
+		Expect(ctrlmetrics.ReconcileThrottled.WithLabelValues("throttled", metrics.OtherNamespace).Write(&throttled)).To(Succeed())
+		Expect(throttled.GetCounter().GetValue()).To(Equal(1.0))
+	})
+
+	It("should evict the limiters of idle namespaces", func() {
+		q.QPS = 100
+		q.Add(requestFor("foo", "a"))
+		q.Add(requestFor("bar", "a"))
+		Expect(q.limiters).To(HaveLen(2))
+
+		By("keeping the limiters until their bucket is full again")
+		time.Sleep(20 * time.Millisecond)
+		q.Add(requestFor("foo", "b"))
+		Expect(q.limiters).To(HaveLen(1))
+		Expect(q.limiters).To(HaveKey("foo"))
+	})
+
+	It("should refill the buckets with the Clock", func() {
+		fakeClock := clock.NewFakeClock(time.Now())
+		q.Clock = fakeClock
+		q.RateLimitingInterface = &ClockedQueue{RateLimitingInterface: q.RateLimitingInterface, Clock: fakeClock}
+		q.Add(requestFor("foo", "a"))
+		q.Add(requestFor("foo", "b"))
+		Expect(q.Len()).To(Equal(1))
+
+		By("not adding the delayed request until the Clock is stepped")
+		Consistently(q.Len).Should(Equal(1))
+
+		By("adding the delayed request once the Clock is stepped to the refill")
+		fakeClock.Step(time.Second)
+		Eventually(q.Len).Should(Equal(2))
+
+		By("adding requests once the bucket has refilled on the Clock")
+		fakeClock.Step(time.Second)
+		q.Add(requestFor("foo", "c"))
+		Expect(q.Len()).To(Equal(3))
+	})
+
+	It("should not throttle items which are not Requests", func() {
+		q.Add("a")
+		q.Add("b")
+		Expect(q.Len()).To(Equal(2))
+	})
+
+	It("should keep the priority of requests within the limit", func() {
+		q.RateLimitingInterface = priorityqueue.New(workqueue.DefaultControllerRateLimiter())
+		q.Add(requestFor("foo", "a"))
+		q.AddWithPriority(requestFor("bar", "a"), 1)
+
+		item, _ := q.Get()
+		Expect(item).To(Equal(requestFor("bar", "a")))
+		q.Done(item)
+	})
+})
@@ -0,0 +1,77 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync/atomic"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+)
+
+var _ priorityqueue.Interface = &watchQueue{}
+
+// watchQueue wraps the Queue of a Controller for a single watch, so that the requests enqueued for the
+// events of its Source are dropped once the Source is unwatched.
+type watchQueue struct {
+	workqueue.RateLimitingInterface
+
+	// stopped is non-zero once the watch is stopped, and must be accessed atomically.
+	stopped int32
+}
+
+// stop drops the requests added to the queue from now on.
+func (q *watchQueue) stop() {
+	atomic.StoreInt32(&q.stopped, 1)
+}
+
+func (q *watchQueue) isStopped() bool {
+	return atomic.LoadInt32(&q.stopped) != 0
+}
+
+// Add implements workqueue.Interface
+func (q *watchQueue) Add(item interface{}) {
+	if q.isStopped() {
+		return
+	}
+	q.RateLimitingInterface.Add(item)
+}
+
+// AddWithPriority implements priorityqueue.Interface
+func (q *watchQueue) AddWithPriority(item interface{}, priority int) {
+	if q.isStopped() {
+		return
+	}
+	priorityqueue.AddWithPriority(q.RateLimitingInterface, item, priority)
+}
+
+// AddAfter implements workqueue.DelayingInterface
+func (q *watchQueue) AddAfter(item interface{}, duration time.Duration) {
+	if q.isStopped() {
+		return
+	}
+	q.RateLimitingInterface.AddAfter(item, duration)
+}
+
+// AddRateLimited implements workqueue.RateLimitingInterface
+func (q *watchQueue) AddRateLimited(item interface{}) {
+	if q.isStopped() {
+		return
+	}
+	q.RateLimitingInterface.AddRateLimited(item)
+}
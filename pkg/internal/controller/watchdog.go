@@ -0,0 +1,100 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// maxStackSize is the maximum size of the goroutine dump searched for the stack of a stuck worker.
+const maxStackSize = 64 << 20
+
+// watchReconcile starts a watchdog which, if the Reconcile of req by the calling goroutine has not returned
+// after StuckReconcileThreshold, logs the stack of the goroutine and counts the Reconcile as stuck.  The
+// returned func stops the watchdog, and must be called once the Reconcile returns.
+func (c *Controller) watchReconcile(req reconcile.Request) func() {
+	if c.StuckReconcileThreshold <= 0 {
+		return func() {}
+	}
+	id := goroutineID()
+	timer := c.clock().NewTimer(c.StuckReconcileThreshold)
+	returned := make(chan struct{})
+	go func() {
+		select {
+		case <-timer.C():
+			select {
+			case <-returned:
+				// The Reconcile returned while the timer fired
+				return
+			default:
+			}
+			ctrlmetrics.ReconcileStuck.WithLabelValues(c.Name).Inc()
+			log.Error(fmt.Errorf("reconcile has not returned after %s", c.StuckReconcileThreshold), "Observed a stuck Reconciler",
+				"controller", c.Name, "request", req, "stack", string(goroutineStack(id)))
+		case <-returned:
+		}
+	}()
+	return func() {
+		timer.Stop()
+		close(returned)
+	}
+}
+
+// goroutineID returns the ID of the calling goroutine, parsed from the header of its stack.
+func goroutineID() string {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	// The header of the stack is "goroutine <id> [<state>]:"
+	fields := bytes.Fields(buf)
+	if len(fields) < 2 {
+		return ""
+	}
+	if _, err := strconv.ParseUint(string(fields[1]), 10, 64); err != nil {
+		return ""
+	}
+	return string(fields[1])
+}
+
+// goroutineStack returns the stack of the goroutine with the given ID, or the stacks of all goroutines if
+// it can't be found.
+func goroutineStack(id string) []byte {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxStackSize {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	if id == "" {
+		return buf
+	}
+	header := []byte("goroutine " + id + " [")
+	for _, stack := range bytes.Split(buf, []byte("\n\n")) {
+		if bytes.HasPrefix(stack, header) {
+			return stack
+		}
+	}
+	return buf
+}
@@ -22,6 +22,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	ref "k8s.io/client-go/tools/reference"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -46,14 +47,16 @@ var _ = Describe("recorder", func() {
 	})
 
 	Describe("recorder", func() {
-		It("should publish events", func(done Done) {
+		// publishesEvents verifies that the events recorded by the EventRecorder returned by
+		// getRecorder for the Manager are published for a Deployment with the given name.
+		publishesEvents := func(name string, getRecorder func(manager.Manager) record.EventRecorder) {
 			By("Creating the Manager")
 			cm, err := manager.New(cfg, manager.Options{})
 			Expect(err).NotTo(HaveOccurred())
 
 			By("Creating the Controller")
-			recorder := cm.GetRecorder("test-recorder")
-			instance, err := controller.New("foo-controller", cm, controller.Options{
+			recorder := getRecorder(cm)
+			instance, err := controller.New(name+"-controller", cm, controller.Options{
 				Reconciler: reconcile.Func(
 					func(request reconcile.Request) (reconcile.Result, error) {
 						dp, err := clientset.AppsV1().Deployments(request.Namespace).Get(request.Name, metav1.GetOptions{})
@@ -75,7 +78,7 @@ var _ = Describe("recorder", func() {
 			}()
 
 			deployment := &appsv1.Deployment{
-				ObjectMeta: metav1.ObjectMeta{Name: "deployment-name"},
+				ObjectMeta: metav1.ObjectMeta{Name: name},
 				Spec: appsv1.DeploymentSpec{
 					Selector: &metav1.LabelSelector{
 						MatchLabels: map[string]string{"foo": "bar"},
@@ -99,7 +102,9 @@ var _ = Describe("recorder", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			By("Validate event is published as expected")
-			evtWatcher, err := clientset.CoreV1().Events("default").Watch(metav1.ListOptions{})
+			evtWatcher, err := clientset.CoreV1().Events("default").Watch(metav1.ListOptions{
+				FieldSelector: "involvedObject.name=" + name,
+			})
 			Expect(err).NotTo(HaveOccurred())
 
 			resultEvent := <-evtWatcher.ResultChan()
@@ -115,7 +120,19 @@ var _ = Describe("recorder", func() {
 			Expect(evt.Type).To(Equal(corev1.EventTypeNormal))
 			Expect(evt.Reason).To(Equal("test-reason"))
 			Expect(evt.Message).To(Equal("test-msg"))
+		}
+
+		It("should publish events", func(done Done) {
+			publishesEvents("deployment-name", func(cm manager.Manager) record.EventRecorder {
+				return cm.GetRecorder("test-recorder")
+			})
+			close(done)
+		})
 
+		It("should publish events with GetEventRecorderFor", func(done Done) {
+			publishesEvents("event-recorder-for", func(cm manager.Manager) record.EventRecorder {
+				return cm.GetEventRecorderFor("test-recorder")
+			})
 			close(done)
 		})
 	})
@@ -21,6 +21,9 @@ import (
 	"io/ioutil"
 	"os"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -43,10 +46,15 @@ type Options struct {
 	// LeaderElectionID determines the name of the configmap that leader election
 	// will use for holding the leader lock.
 	LeaderElectionID string
+
+	// LeaderElectionResourceLock determines the type of the object holding the leader
+	// lock, one of resourcelock.ConfigMapsResourceLock, resourcelock.EndpointsResourceLock
+	// or LeasesResourceLock.  Defaults to resourcelock.ConfigMapsResourceLock.
+	LeaderElectionResourceLock string
 }
 
-// NewResourceLock creates a new config map resource lock for use in a leader
-// election loop
+// NewResourceLock creates a new resource lock of the LeaderElectionResourceLock type for
+// use in a leader election loop
 func NewResourceLock(config *rest.Config, recorderProvider recorder.Provider, options Options) (resourcelock.Interface, error) {
 	if !options.LeaderElection {
 		return nil, nil
@@ -66,6 +74,11 @@ func NewResourceLock(config *rest.Config, recorderProvider recorder.Provider, op
 		}
 	}
 
+	// Default the lock type
+	if options.LeaderElectionResourceLock == "" {
+		options.LeaderElectionResourceLock = resourcelock.ConfigMapsResourceLock
+	}
+
 	// Leader id, needs to be unique
 	id, err := os.Hostname()
 	if err != nil {
@@ -79,15 +92,84 @@ func NewResourceLock(config *rest.Config, recorderProvider recorder.Provider, op
 		return nil, err
 	}
 
-	// TODO(JoelSpeed): switch to leaderelection object in 1.12
-	return resourcelock.New(resourcelock.ConfigMapsResourceLock,
+	lockConfig := resourcelock.ResourceLockConfig{
+		Identity:      id,
+		EventRecorder: recorderProvider.GetEventRecorderFor(id),
+	}
+	if options.LeaderElectionResourceLock == LeasesResourceLock {
+		return &releasableLock{Interface: &LeaseLock{
+			LeaseMeta: metav1.ObjectMeta{
+				Namespace: options.LeaderElectionNamespace,
+				Name:      options.LeaderElectionID,
+			},
+			Client:     client.CoordinationV1beta1(),
+			LockConfig: lockConfig,
+		}}, nil
+	}
+	lock, err := resourcelock.New(options.LeaderElectionResourceLock,
 		options.LeaderElectionNamespace,
 		options.LeaderElectionID,
 		client.CoreV1(),
-		resourcelock.ResourceLockConfig{
-			Identity:      id,
-			EventRecorder: recorderProvider.GetEventRecorderFor(id),
-		})
+		lockConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &releasableLock{Interface: lock}, nil
+}
+
+// Release releases the leader lock if it is held by the identity of lock, so that another
+// candidate whose lock was created by NewResourceLock can acquire it without waiting for
+// the lease to expire.  It should only be called once the leader election loop has stopped
+// renewing the lock.
+func Release(lock resourcelock.Interface) error {
+	record, err := lock.Get()
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if record.HolderIdentity != lock.Identity() {
+		return nil
+	}
+	now := metav1.Now()
+	return lock.Update(resourcelock.LeaderElectionRecord{
+		LeaseDurationSeconds: 1,
+		AcquireTime:          now,
+		RenewTime:            now,
+		LeaderTransitions:    record.LeaderTransitions,
+	})
+}
+
+// releasableLock is a resource lock whose record, once released by its holder, can be acquired
+// immediately.  The leader election loop only acquires a lock held by another identity once it
+// has observed the record unchanged for the lease duration, so a released record is reported as
+// not found, and the lock is then acquired by updating the record instead of creating it.
+type releasableLock struct {
+	resourcelock.Interface
+	released bool
+}
+
+// Get returns the election record, or a NotFound error if the record is not held by any identity.
+func (l *releasableLock) Get() (*resourcelock.LeaderElectionRecord, error) {
+	record, err := l.Interface.Get()
+	if err != nil {
+		l.released = false
+		return nil, err
+	}
+	l.released = record.HolderIdentity == ""
+	if l.released {
+		return nil, apierrors.NewNotFound(schema.GroupResource{}, l.Describe())
+	}
+	return record, nil
+}
+
+// Create creates the election record, or updates it if it was released.
+func (l *releasableLock) Create(ler resourcelock.LeaderElectionRecord) error {
+	if l.released {
+		return l.Interface.Update(ler)
+	}
+	return l.Interface.Create(ler)
 }
 
 func getInClusterNamespace() (string, error) {
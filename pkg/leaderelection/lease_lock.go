@@ -0,0 +1,131 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leaderelection
+
+import (
+	"errors"
+	"fmt"
+
+	coordinationv1beta1 "k8s.io/api/coordination/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coordinationv1beta1client "k8s.io/client-go/kubernetes/typed/coordination/v1beta1"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// LeasesResourceLock is the lock type of a LeaseLock.
+const LeasesResourceLock = "leases"
+
+var _ resourcelock.Interface = &LeaseLock{}
+
+// LeaseLock is a resource lock holding the leader election record in the spec of a
+// coordination.k8s.io Lease.  Leases are lighter to update and watch than the ConfigMaps and
+// Endpoints locks, whose record is stored in an annotation.
+type LeaseLock struct {
+	// LeaseMeta should contain a Name and a Namespace of a Lease object that the
+	// LeaderElector will attempt to lead.
+	LeaseMeta  metav1.ObjectMeta
+	Client     coordinationv1beta1client.LeasesGetter
+	LockConfig resourcelock.ResourceLockConfig
+	lease      *coordinationv1beta1.Lease
+}
+
+// Get returns the election record from the spec of the Lease
+func (ll *LeaseLock) Get() (*resourcelock.LeaderElectionRecord, error) {
+	var err error
+	ll.lease, err = ll.Client.Leases(ll.LeaseMeta.Namespace).Get(ll.LeaseMeta.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return leaseSpecToRecord(&ll.lease.Spec), nil
+}
+
+// Create attempts to create a Lease holding the LeaderElectionRecord
+func (ll *LeaseLock) Create(ler resourcelock.LeaderElectionRecord) error {
+	var err error
+	ll.lease, err = ll.Client.Leases(ll.LeaseMeta.Namespace).Create(&coordinationv1beta1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ll.LeaseMeta.Name,
+			Namespace: ll.LeaseMeta.Namespace,
+		},
+		Spec: recordToLeaseSpec(&ler),
+	})
+	return err
+}
+
+// Update will update the spec of an existing Lease
+func (ll *LeaseLock) Update(ler resourcelock.LeaderElectionRecord) error {
+	if ll.lease == nil {
+		return errors.New("lease not initialized, call get or create first")
+	}
+	ll.lease.Spec = recordToLeaseSpec(&ler)
+	var err error
+	ll.lease, err = ll.Client.Leases(ll.LeaseMeta.Namespace).Update(ll.lease)
+	return err
+}
+
+// RecordEvent in leader election while adding meta-data
+func (ll *LeaseLock) RecordEvent(s string) {
+	if ll.LockConfig.EventRecorder == nil {
+		return
+	}
+	ll.LockConfig.EventRecorder.Eventf(&coordinationv1beta1.Lease{ObjectMeta: ll.lease.ObjectMeta}, corev1.EventTypeNormal,
+		"LeaderElection", "%v %v", ll.LockConfig.Identity, s)
+}
+
+// Describe is used to convert details on current resource lock
+// into a string
+func (ll *LeaseLock) Describe() string {
+	return fmt.Sprintf("%v/%v", ll.LeaseMeta.Namespace, ll.LeaseMeta.Name)
+}
+
+// Identity returns the Identity of the lock
+func (ll *LeaseLock) Identity() string {
+	return ll.LockConfig.Identity
+}
+
+func leaseSpecToRecord(spec *coordinationv1beta1.LeaseSpec) *resourcelock.LeaderElectionRecord {
+	var r resourcelock.LeaderElectionRecord
+	if spec.HolderIdentity != nil {
+		r.HolderIdentity = *spec.HolderIdentity
+	}
+	if spec.LeaseDurationSeconds != nil {
+		r.LeaseDurationSeconds = int(*spec.LeaseDurationSeconds)
+	}
+	if spec.LeaseTransitions != nil {
+		r.LeaderTransitions = int(*spec.LeaseTransitions)
+	}
+	if spec.AcquireTime != nil {
+		r.AcquireTime = metav1.NewTime(spec.AcquireTime.Time)
+	}
+	if spec.RenewTime != nil {
+		r.RenewTime = metav1.NewTime(spec.RenewTime.Time)
+	}
+	return &r
+}
+
+func recordToLeaseSpec(ler *resourcelock.LeaderElectionRecord) coordinationv1beta1.LeaseSpec {
+	leaseDurationSeconds := int32(ler.LeaseDurationSeconds)
+	leaseTransitions := int32(ler.LeaderTransitions)
+	return coordinationv1beta1.LeaseSpec{
+		HolderIdentity:       &ler.HolderIdentity,
+		LeaseDurationSeconds: &leaseDurationSeconds,
+		AcquireTime:          &metav1.MicroTime{Time: ler.AcquireTime.Time},
+		RenewTime:            &metav1.MicroTime{Time: ler.RenewTime.Time},
+		LeaseTransitions:     &leaseTransitions,
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leaderelection
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	coordinationv1beta1 "k8s.io/api/coordination/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	coordinationv1beta1client "k8s.io/client-go/kubernetes/typed/coordination/v1beta1"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+var _ = Describe("LeaseLock", func() {
+	var leases *fakeLeases
+	var lock *LeaseLock
+	var record resourcelock.LeaderElectionRecord
+
+	BeforeEach(func() {
+		leases = &fakeLeases{}
+		lock = &LeaseLock{
+			LeaseMeta:  metav1.ObjectMeta{Namespace: "default", Name: "leader"},
+			Client:     leases,
+			LockConfig: resourcelock.ResourceLockConfig{Identity: "foo"},
+		}
+		now := metav1.NewTime(time.Now().Truncate(time.Second))
+		record = resourcelock.LeaderElectionRecord{
+			HolderIdentity:       "foo",
+			LeaseDurationSeconds: 15,
+			AcquireTime:          now,
+			RenewTime:            now,
+			LeaderTransitions:    2,
+		}
+	})
+
+	It("should store the record in the spec of the Lease", func() {
+		Expect(lock.Create(record)).To(Succeed())
+		Expect(leases.lease.Name).To(Equal("leader"))
+		Expect(*leases.lease.Spec.HolderIdentity).To(Equal("foo"))
+		Expect(*leases.lease.Spec.LeaseDurationSeconds).To(BeEquivalentTo(15))
+		Expect(*leases.lease.Spec.LeaseTransitions).To(BeEquivalentTo(2))
+
+		got, err := lock.Get()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*got).To(Equal(record))
+	})
+
+	It("should update the spec of the Lease", func() {
+		Expect(lock.Create(record)).To(Succeed())
+		record.HolderIdentity = "bar"
+		Expect(lock.Update(record)).To(Succeed())
+		Expect(*leases.lease.Spec.HolderIdentity).To(Equal("bar"))
+	})
+
+	It("should return an error if the Lease was not read before updating it", func() {
+		Expect(lock.Update(record)).To(HaveOccurred())
+	})
+
+	It("should describe the Lease", func() {
+		Expect(lock.Describe()).To(Equal("default/leader"))
+		Expect(lock.Identity()).To(Equal("foo"))
+	})
+
+	Describe("Release", func() {
+		var releasable *releasableLock
+
+		BeforeEach(func() {
+			releasable = &releasableLock{Interface: lock}
+			Expect(releasable.Create(record)).To(Succeed())
+		})
+
+		It("should release the lock held by its identity", func() {
+			Expect(Release(releasable)).To(Succeed())
+			Expect(*leases.lease.Spec.HolderIdentity).To(BeEmpty())
+			Expect(*leases.lease.Spec.LeaseTransitions).To(BeEquivalentTo(2))
+
+			By("reporting the released lock as not found")
+			_, err := releasable.Get()
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			Expect(Release(releasable)).To(Succeed())
+
+			By("acquiring the released lock by updating it")
+			record.HolderIdentity = "bar"
+			Expect(releasable.Create(record)).To(Succeed())
+			Expect(*leases.lease.Spec.HolderIdentity).To(Equal("bar"))
+		})
+
+		It("should not release the lock held by another identity", func() {
+			other := &releasableLock{Interface: &LeaseLock{
+				LeaseMeta:  lock.LeaseMeta,
+				Client:     leases,
+				LockConfig: resourcelock.ResourceLockConfig{Identity: "bar"},
+			}}
+			Expect(Release(other)).To(Succeed())
+			Expect(*leases.lease.Spec.HolderIdentity).To(Equal("foo"))
+		})
+	})
+})
+
+// fakeLeases stores a single Lease.
+type fakeLeases struct {
+	coordinationv1beta1client.LeaseInterface
+	lease *coordinationv1beta1.Lease
+}
+
+func (f *fakeLeases) Leases(string) coordinationv1beta1client.LeaseInterface {
+	return f
+}
+
+func (f *fakeLeases) Get(name string, _ metav1.GetOptions) (*coordinationv1beta1.Lease, error) {
+	if f.lease == nil {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Group: "coordination.k8s.io", Resource: "leases"}, name)
+	}
+	return f.lease.DeepCopy(), nil
+}
+
+func (f *fakeLeases) Create(lease *coordinationv1beta1.Lease) (*coordinationv1beta1.Lease, error) {
+	f.lease = lease.DeepCopy()
+	return lease, nil
+}
+
+func (f *fakeLeases) Update(lease *coordinationv1beta1.Lease) (*coordinationv1beta1.Lease, error) {
+	f.lease = lease.DeepCopy()
+	return lease, nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+)
+
+// engagedCluster is a cluster engaged with the Manager by its ClusterProvider.
+type engagedCluster struct {
+	cluster cluster.Cluster
+	stop    <-chan struct{}
+}
+
+var _ cluster.Aware = &controllerManager{}
+
+// Engage implements cluster.Aware.  It engages each Runnable implementing cluster.Aware with the
+// cluster until stop is closed.  Runnables added in the meantime are engaged with it when added.  If a
+// Runnable fails to engage, the Runnables already engaged are disengaged.
+func (cm *controllerManager) Engage(name string, cl cluster.Cluster, stop <-chan struct{}) error {
+	// The Runnables are engaged until stop is closed, or until the engagement is rolled back
+	engaged := make(chan struct{})
+	var once sync.Once
+	disengage := func() {
+		once.Do(func() { close(engaged) })
+	}
+
+	// The cluster is recorded before the Runnables are engaged, without holding mu, so that the
+	// Runnables added meanwhile are engaged with it by Add
+	cm.mu.Lock()
+	var aware []cluster.Aware
+	for _, r := range cm.runnables {
+		if a, ok := r.(cluster.Aware); ok {
+			aware = append(aware, a)
+		}
+	}
+	cm.clusters[name] = engagedCluster{cluster: cl, stop: engaged}
+	cm.mu.Unlock()
+
+	go func() {
+		select {
+		case <-stop:
+		case <-engaged:
+		}
+		disengage()
+		cm.forgetCluster(name, engaged)
+	}()
+
+	for i, a := range aware {
+		if err := a.Engage(name, cl, engaged); err != nil {
+			disengage()
+			cm.forgetCluster(name, engaged)
+			return fmt.Errorf("unable to engage cluster %s, disengaged the %d Runnables engaged: %v", name, i, err)
+		}
+	}
+	return nil
+}
+
+// forgetCluster forgets the cluster name engaged until engaged is closed.
+func (cm *controllerManager) forgetCluster(name string, engaged <-chan struct{}) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	// The cluster may have been engaged again under the same name
+	if cm.clusters[name].stop == engaged {
+		delete(cm.clusters, name)
+	}
+}
+
+// engageRunnable engages r with the clusters engaged with the Manager, if r implements cluster.Aware.
+// It must be called with mu held.
+func (cm *controllerManager) engageRunnable(r Runnable) error {
+	aware, ok := r.(cluster.Aware)
+	if !ok {
+		return nil
+	}
+	for name, c := range cm.clusters {
+		if err := aware.Engage(name, c.cluster, c.stop); err != nil {
+			return fmt.Errorf("unable to engage cluster %s: %v", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"k8s.io/client-go/rest"
+)
+
+// redacted replaces the values of secret settings on the /debug/config endpoint.
+const redacted = "[redacted]"
+
+// ConfigReporter is implemented by Runnables which report their resolved settings on the
+// /debug/config endpoint of the debug server, such as Controllers.
+type ConfigReporter interface {
+	// ReportConfig returns the name of the Runnable and its settings, which must be encodable
+	// as JSON and must not contain secrets.
+	ReportConfig() (name string, config interface{})
+}
+
+// RuntimeConfig is the configuration a Manager is running with.  It is served as JSON by the
+// /debug/config endpoint of the debug server.
+type RuntimeConfig struct {
+	// Manager are the Options of the Manager, after defaulting.
+	Manager ManagerConfig `json:"manager"`
+
+	// FeatureGates are the FeatureGates of the Options of the Manager.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+
+	// Controllers are the settings reported by the Runnables implementing ConfigReporter,
+	// by name.
+	Controllers map[string]interface{} `json:"controllers,omitempty"`
+}
+
+// ManagerConfig are the resolved Options of a Manager.  Settings which configure behavior
+// through functions, such as NewClient, are not reported.
+type ManagerConfig struct {
+	REST                          RESTConfig `json:"rest"`
+	Namespace                     string     `json:"namespace,omitempty"`
+	DeferUnknownKinds             bool       `json:"deferUnknownKinds"`
+	SyncPeriod                    string     `json:"syncPeriod,omitempty"`
+	LeaderElection                bool       `json:"leaderElection"`
+	LeaderElectionNamespace       string     `json:"leaderElectionNamespace,omitempty"`
+	LeaderElectionID              string     `json:"leaderElectionID,omitempty"`
+	LeaderElectionResourceLock    string     `json:"leaderElectionResourceLock,omitempty"`
+	LeaseDuration                 string     `json:"leaseDuration"`
+	RenewDeadline                 string     `json:"renewDeadline"`
+	RetryPeriod                   string     `json:"retryPeriod"`
+	LeaderElectionReleaseOnCancel bool       `json:"leaderElectionReleaseOnCancel"`
+	MetricsBindAddress            string     `json:"metricsBindAddress,omitempty"`
+	HealthProbeBindAddress        string     `json:"healthProbeBindAddress,omitempty"`
+	DebugBindAddress              string     `json:"debugBindAddress,omitempty"`
+	LogLevelConfigMap             string     `json:"logLevelConfigMap,omitempty"`
+	DrainTimeout                  string     `json:"drainTimeout"`
+	StripManagedFields            bool       `json:"stripManagedFields"`
+	UncachedObjects               []string   `json:"uncachedObjects,omitempty"`
+	CacheUnstructured             bool       `json:"cacheUnstructured"`
+}
+
+// RESTConfig is the rest.Config used by a Manager to talk to the API server.  Credentials,
+// such as the bearer token and client key, are redacted.
+type RESTConfig struct {
+	Host        string  `json:"host"`
+	APIPath     string  `json:"apiPath,omitempty"`
+	UserAgent   string  `json:"userAgent,omitempty"`
+	QPS         float32 `json:"qps"`
+	Burst       int     `json:"burst"`
+	Timeout     string  `json:"timeout"`
+	Impersonate string  `json:"impersonate,omitempty"`
+	Username    string  `json:"username,omitempty"`
+	Password    string  `json:"password,omitempty"`
+	BearerToken string  `json:"bearerToken,omitempty"`
+	Insecure    bool    `json:"insecure"`
+	CAFile      string  `json:"caFile,omitempty"`
+	CertFile    string  `json:"certFile,omitempty"`
+	KeyFile     string  `json:"keyFile,omitempty"`
+	CertData    string  `json:"certData,omitempty"`
+	KeyData     string  `json:"keyData,omitempty"`
+}
+
+// newManagerConfig returns the ManagerConfig reported for a Manager created from config and the
+// defaulted options.
+func newManagerConfig(config *rest.Config, options Options) ManagerConfig {
+	c := ManagerConfig{
+		REST:                          newRESTConfig(config),
+		Namespace:                     options.Namespace,
+		DeferUnknownKinds:             options.DeferUnknownKinds,
+		LeaderElection:                options.LeaderElection,
+		LeaderElectionNamespace:       options.LeaderElectionNamespace,
+		LeaderElectionID:              options.LeaderElectionID,
+		LeaderElectionResourceLock:    options.LeaderElectionResourceLock,
+		LeaseDuration:                 options.LeaseDuration.String(),
+		RenewDeadline:                 options.RenewDeadline.String(),
+		RetryPeriod:                   options.RetryPeriod.String(),
+		LeaderElectionReleaseOnCancel: options.LeaderElectionReleaseOnCancel,
+		MetricsBindAddress:            options.MetricsBindAddress,
+		HealthProbeBindAddress:        options.HealthProbeBindAddress,
+		DebugBindAddress:              options.DebugBindAddress,
+		DrainTimeout:                  options.DrainTimeout.String(),
+		StripManagedFields:            options.StripManagedFields,
+		CacheUnstructured:             options.CacheUnstructured,
+	}
+	if options.SyncPeriod != nil {
+		c.SyncPeriod = options.SyncPeriod.String()
+	}
+	if options.LogLevels != nil && options.LogLevelConfigMap != nil {
+		c.LogLevelConfigMap = options.LogLevelConfigMap.String()
+	}
+	for _, obj := range options.UncachedObjects {
+		c.UncachedObjects = append(c.UncachedObjects, fmt.Sprintf("%T", obj))
+	}
+	return c
+}
+
+// newRESTConfig returns the RESTConfig reported for config.
+func newRESTConfig(config *rest.Config) RESTConfig {
+	return RESTConfig{
+		Host:        config.Host,
+		APIPath:     config.APIPath,
+		UserAgent:   config.UserAgent,
+		QPS:         config.QPS,
+		Burst:       config.Burst,
+		Timeout:     config.Timeout.String(),
+		Impersonate: config.Impersonate.UserName,
+		Username:    config.Username,
+		Password:    redact(config.Password),
+		BearerToken: redact(config.BearerToken),
+		Insecure:    config.Insecure,
+		CAFile:      config.CAFile,
+		CertFile:    config.CertFile,
+		KeyFile:     config.KeyFile,
+		CertData:    redact(string(config.CertData)),
+		KeyData:     redact(string(config.KeyData)),
+	}
+}
+
+// redact returns redacted if secret is set, so that the presence of a secret is reported
+// but not its value.
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redacted
+}
+
+// runtimeConfig returns the RuntimeConfig the Manager is running with.
+func (cm *controllerManager) runtimeConfig() RuntimeConfig {
+	config := RuntimeConfig{
+		Manager:      cm.managerConfig,
+		FeatureGates: cm.featureGates,
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	for _, r := range cm.runnables {
+		reporter, ok := r.(ConfigReporter)
+		if !ok {
+			continue
+		}
+		if config.Controllers == nil {
+			config.Controllers = map[string]interface{}{}
+		}
+		name, c := reporter.ReportConfig()
+		config.Controllers[name] = c
+	}
+	return config
+}
+
+// serveConfig writes the RuntimeConfig of the Manager.
+func (cm *controllerManager) serveConfig(resp http.ResponseWriter, _ *http.Request) {
+	resp.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(resp).Encode(cm.runtimeConfig()); err != nil {
+		log.Error(err, "unable to write runtime config")
+	}
+}
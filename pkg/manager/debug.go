@@ -0,0 +1,156 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/version"
+)
+
+// defaultDebugListener creates the default debug listener bound to the given address
+func defaultDebugListener(addr string) (net.Listener, error) {
+	if addr == "" || addr == "0" {
+		return nil, nil
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("error listening on %s: %v", addr, err)
+	}
+	return ln, nil
+}
+
+func (cm *controllerManager) serveDebug(stop <-chan struct{}) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/cache", cm.serveCacheDump)
+	mux.HandleFunc("/debug/config", cm.serveConfig)
+	mux.HandleFunc("/debug/provenance", cm.serveProvenance)
+	mux.HandleFunc("/debug/loglevel", cm.serveLogLevel)
+	mux.HandleFunc("/version", serveVersion)
+	server := http.Server{
+		Handler: mux,
+	}
+	// Run the server
+	go func() {
+		if err := server.Serve(cm.debugListener); err != nil && err != http.ErrServerClosed {
+			cm.errChan <- err
+		}
+	}()
+
+	// Shutdown the server when stop is closed
+	<-stop
+	if err := server.Shutdown(context.Background()); err != nil {
+		cm.errChan <- err
+	}
+}
+
+// serveCacheDump writes a cache.Snapshot of the informer for the group, version and kind query
+// parameters.  The objects in the informer store are included if the objects query parameter is true.
+func (cm *controllerManager) serveCacheDump(resp http.ResponseWriter, req *http.Request) {
+	dumper, ok := cm.cache.(cache.Dumper)
+	if !ok {
+		http.Error(resp, fmt.Sprintf("cache %T does not support dumps", cm.cache), http.StatusNotImplemented)
+		return
+	}
+
+	query := req.URL.Query()
+	gvk := schema.GroupVersionKind{
+		Group:   query.Get("group"),
+		Version: query.Get("version"),
+		Kind:    query.Get("kind"),
+	}
+	if gvk.Version == "" || gvk.Kind == "" {
+		http.Error(resp, "the version and kind query parameters are required", http.StatusBadRequest)
+		return
+	}
+	var includeObjects bool
+	if raw := query.Get("objects"); raw != "" {
+		var err error
+		if includeObjects, err = strconv.ParseBool(raw); err != nil {
+			http.Error(resp, fmt.Sprintf("invalid objects query parameter: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	snapshot, err := dumper.Dump(gvk, includeObjects)
+	if err != nil {
+		http.Error(resp, err.Error(), http.StatusNotFound)
+		return
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(resp).Encode(snapshot); err != nil {
+		log.Error(err, "unable to write cache dump", "gvk", gvk)
+	}
+}
+
+// ProvenanceReporter is implemented by Runnables which track what enqueued their requests, such as the
+// Controllers with TrackProvenance set.  Their provenance is served on the /debug/provenance endpoint.
+type ProvenanceReporter interface {
+	// ReportProvenance returns the name of the Runnable and the provenance of the request for key.  It
+	// returns false if the request was never enqueued or its provenance is not tracked.
+	ReportProvenance(key types.NamespacedName) (string, interface{}, bool)
+}
+
+// serveProvenance writes the provenance of the request for the namespace and name query parameters,
+// by name of the ProvenanceReporter which tracked it.  Only the provenance reported by the Runnable
+// named by the controller query parameter is written if it is set.
+func (cm *controllerManager) serveProvenance(resp http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	key := types.NamespacedName{Namespace: query.Get("namespace"), Name: query.Get("name")}
+	if key.Name == "" {
+		http.Error(resp, "the name query parameter is required", http.StatusBadRequest)
+		return
+	}
+	controller := query.Get("controller")
+
+	provenance := map[string]interface{}{}
+	cm.mu.Lock()
+	for _, r := range cm.runnables {
+		reporter, ok := r.(ProvenanceReporter)
+		if !ok {
+			continue
+		}
+		name, p, ok := reporter.ReportProvenance(key)
+		if !ok || (controller != "" && name != controller) {
+			continue
+		}
+		provenance[name] = p
+	}
+	cm.mu.Unlock()
+
+	resp.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(resp).Encode(provenance); err != nil {
+		log.Error(err, "unable to write provenance", "request", key)
+	}
+}
+
+// serveVersion writes the version.Info of the binary.
+func serveVersion(resp http.ResponseWriter, _ *http.Request) {
+	resp.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(resp).Encode(version.Get()); err != nil {
+		log.Error(err, "unable to write version")
+	}
+}
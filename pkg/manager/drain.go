@@ -0,0 +1,148 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// drainCheckName is the name of the readiness check reporting whether the Manager is draining
+const drainCheckName = "drain"
+
+// drainPollInterval is how often the Manager checks the drain progress of its Runnables while stopping
+const drainPollInterval = 100 * time.Millisecond
+
+// Drainer is implemented by Runnables that can finish their pending work before they are stopped, such
+// as Controllers.  When the Manager starts draining, Drain is called on each of its Drainers.
+type Drainer interface {
+	// Drain stops accepting new work while the work already pending keeps being processed.
+	Drain()
+
+	// DrainProgress returns the amount of work that is pending and the amount being processed.
+	DrainProgress() (pending, inFlight int)
+}
+
+// DrainStatus reports the progress of draining the Runnables of a Manager.  It is served as JSON by the
+// /drain endpoint of the health probes server.
+type DrainStatus struct {
+	// Draining is true once draining has begun.
+	Draining bool `json:"draining"`
+
+	// Drained is true once draining has begun and no Drainer has pending or in flight work.
+	Drained bool `json:"drained"`
+
+	// Pending is the total amount of pending work of all Drainers.
+	Pending int `json:"pending"`
+
+	// InFlight is the total amount of work being processed by all Drainers.
+	InFlight int `json:"inFlight"`
+
+	// ElapsedSeconds is the time since draining began.
+	ElapsedSeconds float64 `json:"elapsedSeconds"`
+}
+
+// drain begins draining every Runnable implementing Drainer.  Calling drain more than once has no effect.
+func (cm *controllerManager) drain() {
+	cm.drainMu.Lock()
+	if !cm.drainStart.IsZero() {
+		cm.drainMu.Unlock()
+		return
+	}
+	cm.drainStart = time.Now()
+	cm.drainMu.Unlock()
+
+	log.Info("Draining runnables")
+	for _, d := range cm.snapshotDrainers() {
+		d.Drain()
+	}
+}
+
+// drainStatus returns the current drain progress of the Manager.
+func (cm *controllerManager) drainStatus() DrainStatus {
+	cm.drainMu.Lock()
+	start := cm.drainStart
+	cm.drainMu.Unlock()
+
+	status := DrainStatus{Draining: !start.IsZero()}
+	if !status.Draining {
+		return status
+	}
+	for _, d := range cm.snapshotDrainers() {
+		pending, inFlight := d.DrainProgress()
+		status.Pending += pending
+		status.InFlight += inFlight
+	}
+	status.Drained = status.Pending == 0 && status.InFlight == 0
+	status.ElapsedSeconds = time.Since(start).Seconds()
+	return status
+}
+
+// waitForDrain drains the Runnables and waits until they are drained or timeout has elapsed.
+func (cm *controllerManager) waitForDrain(timeout time.Duration) {
+	cm.drain()
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for {
+		status := cm.drainStatus()
+		if status.Drained {
+			log.Info("Drained runnables", "elapsedSeconds", status.ElapsedSeconds)
+			return
+		}
+		select {
+		case <-deadline:
+			log.Info("Timed out draining runnables", "pending", status.Pending, "inFlight", status.InFlight)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// snapshotDrainers returns the Runnables of the Manager implementing Drainer.
+func (cm *controllerManager) snapshotDrainers() []Drainer {
+	cm.drainMu.Lock()
+	defer cm.drainMu.Unlock()
+	return append([]Drainer(nil), cm.drainers...)
+}
+
+// checkDrain is a readiness check failing once the Manager has begun draining.
+func (cm *controllerManager) checkDrain(_ *http.Request) error {
+	if cm.drainStatus().Draining {
+		return fmt.Errorf("manager is draining")
+	}
+	return nil
+}
+
+// serveDrain begins draining the Manager and reports the drain progress as a DrainStatus.  It responds
+// with http.StatusOK once drained, and http.StatusAccepted while draining is in progress, so that it can
+// be used as a preStop hook, and polled to measure how long draining takes.
+func (cm *controllerManager) serveDrain(resp http.ResponseWriter, _ *http.Request) {
+	cm.drain()
+	status := cm.drainStatus()
+	resp.Header().Set("Content-Type", "application/json")
+	if status.Drained {
+		resp.WriteHeader(http.StatusOK)
+	} else {
+		resp.WriteHeader(http.StatusAccepted)
+	}
+	if err := json.NewEncoder(resp).Encode(status); err != nil {
+		log.Error(err, "unable to write drain status")
+	}
+}
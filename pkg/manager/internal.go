@@ -22,6 +22,7 @@ import (
 	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -33,6 +34,9 @@ import (
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	ctrlleaderelection "sigs.k8s.io/controller-runtime/pkg/leaderelection"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/recorder"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
@@ -42,6 +46,24 @@ import (
 
 var log = logf.KBLog.WithName("manager")
 
+// cacheSyncCheckName is the name of the readiness check reporting whether the caches have synced
+const cacheSyncCheckName = "cache-sync"
+
+// Default timings of leader election, taken from:
+// https://github.com/kubernetes/apiserver/blob/master/pkg/apis/config/v1alpha1/defaults.go
+const (
+	defaultLeaseDuration = 15 * time.Second
+	defaultRenewDeadline = 10 * time.Second
+	defaultRetryPeriod   = 2 * time.Second
+)
+
+// states of the cache reported by the cache-sync readiness check
+const (
+	cacheNotStarted int32 = iota
+	cacheSyncing
+	cacheSynced
+)
+
 type controllerManager struct {
 	// config is the rest.config used to talk to the apiserver.  Required.
 	config *rest.Config
@@ -72,14 +94,92 @@ type controllerManager struct {
 	// resourceLock forms the basis for leader election
 	resourceLock resourcelock.Interface
 
+	// leaseDuration, renewDeadline and retryPeriod are the timings of leader election.
+	leaseDuration time.Duration
+	renewDeadline time.Duration
+	retryPeriod   time.Duration
+
+	// releaseOnCancel releases the resourceLock when the Manager is stopped.
+	releaseOnCancel bool
+
+	// leaderElectionStopped is closed once the leader election loop has returned.
+	leaderElectionStopped chan struct{}
+
+	// elected is closed once the Manager is elected leader, or started if leader election is disabled.
+	elected chan struct{}
+
+	// onStartedLeading and onStoppedLeading are called once the Manager starts and stops leading.
+	onStartedLeading func()
+	onStoppedLeading func()
+
 	// mapper is used to map resources to kind, and map kind and version.
 	mapper meta.RESTMapper
 
 	// metricsListener is used to serve prometheus metrics
 	metricsListener net.Listener
 
+	// healthProbeListener is used to serve liveness and readiness probes
+	healthProbeListener net.Listener
+
+	// debugListener is used to serve the debug endpoints
+	debugListener net.Listener
+
+	// logLevels are adjusted by the /debug/loglevel endpoint, and by logLevelWatcher if it is set.
+	logLevels       *logf.Levels
+	logLevelWatcher *logLevelWatcher
+
+	// healthzChecks and readyzChecks are the checks served under /healthz and /readyz.
+	healthzChecks map[string]healthz.Checker
+	readyzChecks  map[string]healthz.Checker
+
+	// healthzStarted is set once the health probes are being served, after which no
+	// more checks can be added.
+	healthzStarted bool
+
+	// healthzMu guards healthzChecks, readyzChecks and healthzStarted.  It is separate from mu,
+	// which is held while the caches sync, so that the probes are served meanwhile.
+	healthzMu sync.Mutex
+
+	// cacheState is one of cacheNotStarted, cacheSyncing or cacheSynced, and must be
+	// accessed atomically.
+	cacheState int32
+
+	// drainers are the runnables implementing Drainer.  They are tracked separately from
+	// runnables, guarded by drainMu, so that draining never waits for the Manager to start.
+	drainers []Drainer
+
+	// drainTimeout is the time to wait for the drainers to drain after the stop channel is
+	// closed.  Draining on stop is disabled if it is 0.
+	drainTimeout time.Duration
+
+	// drainStart is the time draining began, or the zero time if the Manager is not draining.
+	drainStart time.Time
+	drainMu    sync.Mutex
+
+	// managerConfig and featureGates are reported on the /debug/config endpoint.
+	managerConfig ManagerConfig
+	featureGates  map[string]bool
+
+	// clusterProvider discovers the clusters engaged with the Runnables implementing cluster.Aware.
+	clusterProvider cluster.Provider
+
+	// clusters are the clusters engaged by the clusterProvider, by name, guarded by mu.
+	clusters map[string]engagedCluster
+
 	mu      sync.Mutex
 	started bool
+
+	// startedCaches is set once the cache and the Runnables of the CachesPhase are started.  It is
+	// guarded by mu.
+	startedCaches bool
+
+	// startedNonLeader is set once the Runnables of the phases before the LeaderElectedPhase are started,
+	// while the Manager may not be leader yet.  It is guarded by mu.
+	startedNonLeader bool
+
+	// nonLeaderStarted is closed once startedNonLeader is set.
+	nonLeaderStarted chan struct{}
+
 	errChan chan error
 
 	// internalStop is the stop channel *actually* used by everything involved
@@ -105,13 +205,22 @@ func (cm *controllerManager) Add(r Runnable) error {
 		return err
 	}
 
+	// Engage the runnable with the clusters discovered so far
+	if err := cm.engageRunnable(r); err != nil {
+		return err
+	}
+
 	// Add the runnable to the list
 	cm.runnables = append(cm.runnables, r)
-	if cm.started {
+	if d, ok := r.(Drainer); ok {
+		cm.drainMu.Lock()
+		cm.drainers = append(cm.drainers, d)
+		cm.drainMu.Unlock()
+	}
+	if cm.started || (cm.startedNonLeader && startPhase(r) != LeaderElectedPhase) ||
+		(cm.startedCaches && startPhase(r) == CachesPhase) {
 		// If already started, start the controller
-		go func() {
-			cm.errChan <- r.Start(cm.internalStop)
-		}()
+		cm.startRunnable(r)
 	}
 
 	return nil
@@ -127,6 +236,9 @@ func (cm *controllerManager) SetFields(i interface{}) error {
 	if _, err := inject.SchemeInto(cm.scheme, i); err != nil {
 		return err
 	}
+	if _, err := inject.MapperInto(cm.mapper, i); err != nil {
+		return err
+	}
 	if _, err := inject.CacheInto(cm.cache, i); err != nil {
 		return err
 	}
@@ -166,16 +278,87 @@ func (cm *controllerManager) GetCache() cache.Cache {
 	return cm.cache
 }
 
-func (cm *controllerManager) GetRecorder(name string) record.EventRecorder {
+func (cm *controllerManager) GetEventRecorderFor(name string) record.EventRecorder {
 	return cm.recorderProvider.GetEventRecorderFor(name)
 }
 
+func (cm *controllerManager) GetRecorder(name string) record.EventRecorder {
+	return cm.GetEventRecorderFor(name)
+}
+
 func (cm *controllerManager) GetRESTMapper() meta.RESTMapper {
 	return cm.mapper
 }
 
+func (cm *controllerManager) Elected() <-chan struct{} {
+	return cm.elected
+}
+
+// AddHealthzCheck allows you to add Healthz checker
+func (cm *controllerManager) AddHealthzCheck(name string, check healthz.Checker) error {
+	return cm.addCheck(cm.healthzChecks, name, check)
+}
+
+// AddReadyzCheck allows you to add Readyz checker
+func (cm *controllerManager) AddReadyzCheck(name string, check healthz.Checker) error {
+	return cm.addCheck(cm.readyzChecks, name, check)
+}
+
+func (cm *controllerManager) addCheck(checks map[string]healthz.Checker, name string, check healthz.Checker) error {
+	cm.healthzMu.Lock()
+	defer cm.healthzMu.Unlock()
+
+	if cm.healthzStarted {
+		return fmt.Errorf("unable to add health check %q after the health probes are being served", name)
+	}
+	if _, found := checks[name]; found {
+		return fmt.Errorf("health check %q already exists", name)
+	}
+	checks[name] = check
+	return nil
+}
+
+// checkCacheSync is a readiness check which fails while the cache is started but has not synced.
+// It passes before the cache is started, so that replicas waiting for leader election are ready.
+func (cm *controllerManager) checkCacheSync(_ *http.Request) error {
+	if atomic.LoadInt32(&cm.cacheState) == cacheSyncing {
+		return fmt.Errorf("caches have not synced")
+	}
+	return nil
+}
+
+func (cm *controllerManager) serveHealthProbes(stop <-chan struct{}) {
+	cm.healthzMu.Lock()
+	cm.healthzStarted = true
+	mux := http.NewServeMux()
+	healthzHandler := http.StripPrefix("/healthz", &healthz.Handler{Checks: cm.healthzChecks})
+	readyzHandler := http.StripPrefix("/readyz", &healthz.Handler{Checks: cm.readyzChecks})
+	cm.healthzMu.Unlock()
+
+	mux.Handle("/healthz", healthzHandler)
+	mux.Handle("/healthz/", healthzHandler)
+	mux.Handle("/readyz", readyzHandler)
+	mux.Handle("/readyz/", readyzHandler)
+	mux.HandleFunc("/drain", cm.serveDrain)
+	server := http.Server{
+		Handler: mux,
+	}
+	// Run the server
+	go func() {
+		if err := server.Serve(cm.healthProbeListener); err != nil && err != http.ErrServerClosed {
+			cm.errChan <- err
+		}
+	}()
+
+	// Shutdown the server when stop is closed
+	<-stop
+	if err := server.Shutdown(context.Background()); err != nil {
+		cm.errChan <- err
+	}
+}
+
 func (cm *controllerManager) serveMetrics(stop <-chan struct{}) {
-	handler := promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{
+	handler := promhttp.HandlerFor(metrics.Gatherer, promhttp.HandlerOpts{
 		ErrorHandling: promhttp.HTTPErrorOnError,
 	})
 	// TODO(JoelSpeed): Use existing Kubernetes machinery for serving metrics
@@ -201,6 +384,11 @@ func (cm *controllerManager) serveMetrics(stop <-chan struct{}) {
 }
 
 func (cm *controllerManager) Start(stop <-chan struct{}) error {
+	// Wait for the leader lock to be released once everything is stopped
+	if cm.releaseOnCancel {
+		defer cm.waitForLeaderElection()
+	}
+
 	// join the passed-in stop channel as an upstream feeding into cm.internalStopper
 	defer close(cm.internalStopper)
 
@@ -211,17 +399,42 @@ func (cm *controllerManager) Start(stop <-chan struct{}) error {
 		go cm.serveMetrics(cm.internalStop)
 	}
 
+	// Health probes are also served whether the controller is leader or not.
+	if cm.healthProbeListener != nil {
+		go cm.serveHealthProbes(cm.internalStop)
+	}
+
+	// The debug endpoints are served whether the controller is leader or not.
+	if cm.debugListener != nil {
+		go cm.serveDebug(cm.internalStop)
+	}
+
+	// The log levels are also watched whether the controller is leader or not.
+	if cm.logLevelWatcher != nil {
+		go cm.logLevelWatcher.run(cm.internalStop)
+	}
+
+	// The Runnables which don't need leader election are started whether the controller is leader or not.
+	go cm.startNonLeaderElectionRunnables()
+
 	if cm.resourceLock != nil {
 		err := cm.startLeaderElection()
 		if err != nil {
 			return err
 		}
 	} else {
+		// Without leader election the Manager leads until it is stopped
+		cm.startLeading()
+		defer cm.stopLeading()
 		go cm.start()
 	}
 
 	select {
 	case <-stop:
+		// Let the Drainers finish their pending work before stopping everything
+		if cm.drainTimeout > 0 {
+			cm.waitForDrain(cm.drainTimeout)
+		}
 		// We are done
 		return nil
 	case err := <-cm.errChan:
@@ -231,53 +444,138 @@ func (cm *controllerManager) Start(stop <-chan struct{}) error {
 }
 
 func (cm *controllerManager) start() {
+	// Start the Runnables which need leader election after all the others
+	select {
+	case <-cm.nonLeaderStarted:
+	case <-cm.internalStop:
+		return
+	}
+
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 
+	for _, c := range cm.runnables {
+		if startPhase(c) == LeaderElectedPhase {
+			cm.startRunnable(c)
+		}
+	}
+
+	// Discover the clusters of the runnables once they are started
+	if cm.clusterProvider != nil {
+		go func() {
+			if err := cm.clusterProvider.Run(cm, cm.internalStop); err != nil {
+				cm.errChan <- err
+			}
+		}()
+	}
+
+	cm.started = true
+}
+
+// startNonLeaderElectionRunnables starts the cache and the runnables of the phases before the
+// LeaderElectedPhase, phase by phase.  mu is not held while the caches sync, so that runnables can
+// still be added meanwhile.
+func (cm *controllerManager) startNonLeaderElectionRunnables() {
+	cm.mu.Lock()
+
 	// Start the Cache. Allow the function to start the cache to be mocked out for testing
 	if cm.startCache == nil {
 		cm.startCache = cm.cache.Start
 	}
+	atomic.StoreInt32(&cm.cacheState, cacheSyncing)
 	go func() {
 		if err := cm.startCache(cm.internalStop); err != nil {
 			cm.errChan <- err
 		}
 	}()
 
+	// Start the other caches along with it
+	var syncers []CacheSyncer
+	for _, c := range cm.runnables {
+		if startPhase(c) == CachesPhase {
+			cm.startRunnable(c)
+			if s, ok := c.(CacheSyncer); ok {
+				syncers = append(syncers, s)
+			}
+		}
+	}
+	cm.startedCaches = true
+	cm.mu.Unlock()
+
 	// Wait for the caches to sync.
 	// TODO(community): Check the return value and write a test
-	cm.cache.WaitForCacheSync(cm.internalStop)
+	if cm.cache.WaitForCacheSync(cm.internalStop) {
+		atomic.StoreInt32(&cm.cacheState, cacheSynced)
+	}
+	for _, s := range syncers {
+		s.WaitForCacheSync(cm.internalStop)
+	}
 
-	// Start the runnables after the cache has synced
-	for _, c := range cm.runnables {
-		// Controllers block, but we want to return an error if any have an error starting.
-		// Write any Start errors to a channel so we can return them
-		ctrl := c
-		go func() {
-			cm.errChan <- ctrl.Start(cm.internalStop)
-		}()
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	// Start the runnables after the caches have synced, including those added meanwhile
+	for _, phase := range []StartPhase{WebhooksPhase, RunnablesPhase} {
+		for _, c := range cm.runnables {
+			if startPhase(c) == phase {
+				cm.startRunnable(c)
+			}
+		}
 	}
 
-	cm.started = true
+	cm.startedNonLeader = true
+	close(cm.nonLeaderStarted)
+}
+
+// startRunnable starts r.
+func (cm *controllerManager) startRunnable(r Runnable) {
+	// Controllers block, but we want to return an error if any have an error starting.
+	// Write any Start errors to a channel so we can return them
+	go func() {
+		cm.errChan <- r.Start(cm.internalStop)
+	}()
+}
+
+// startPhase returns the phase r is started in.
+func startPhase(r Runnable) StartPhase {
+	if pr, ok := r.(PhasedRunnable); ok {
+		return pr.StartPhase()
+	}
+	if ler, ok := r.(LeaderElectionRunnable); ok && !ler.NeedLeaderElection() {
+		return RunnablesPhase
+	}
+	return LeaderElectedPhase
 }
 
 func (cm *controllerManager) startLeaderElection() (err error) {
 	l, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
-		Lock: cm.resourceLock,
-		// Values taken from: https://github.com/kubernetes/apiserver/blob/master/pkg/apis/config/v1alpha1/defaults.go
-		// TODO(joelspeed): These timings should be configurable
-		LeaseDuration: 15 * time.Second,
-		RenewDeadline: 10 * time.Second,
-		RetryPeriod:   2 * time.Second,
+		Lock:          cm.resourceLock,
+		LeaseDuration: cm.leaseDuration,
+		RenewDeadline: cm.renewDeadline,
+		RetryPeriod:   cm.retryPeriod,
 		Callbacks: leaderelection.LeaderCallbacks{
 			OnStartedLeading: func(_ context.Context) {
+				cm.startLeading()
 				cm.start()
 			},
 			OnStoppedLeading: func() {
+				cm.stopLeading()
+				select {
+				case <-cm.internalStop:
+					// The Manager is stopping, so leader election was cancelled rather than lost
+					if cm.releaseOnCancel {
+						cm.releaseLeaderElection()
+					}
+					return
+				default:
+				}
 				// Most implementations of leader election log.Fatal() here.
 				// Since Start is wrapped in log.Fatal when called, we can just return
 				// an error here which will cause the program to exit.
-				cm.errChan <- fmt.Errorf("leader election lost")
+				select {
+				case cm.errChan <- fmt.Errorf("leader election lost"):
+				case <-cm.internalStop:
+				}
 			},
 		},
 	})
@@ -285,7 +583,54 @@ func (cm *controllerManager) startLeaderElection() (err error) {
 		return err
 	}
 
-	// Start the leader elector process
-	go l.Run(context.Background())
+	// Start the leader elector process, which is cancelled once the Manager is stopped
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-cm.internalStop
+		cancel()
+	}()
+	cm.leaderElectionStopped = make(chan struct{})
+	go func() {
+		defer close(cm.leaderElectionStopped)
+		l.Run(ctx)
+	}()
 	return nil
 }
+
+// startLeading closes the elected channel and calls the OnStartedLeading hook.
+func (cm *controllerManager) startLeading() {
+	close(cm.elected)
+	if cm.onStartedLeading != nil {
+		cm.onStartedLeading()
+	}
+}
+
+// stopLeading calls the OnStoppedLeading hook if the Manager was leading.
+func (cm *controllerManager) stopLeading() {
+	select {
+	case <-cm.elected:
+	default:
+		// The Manager never led
+		return
+	}
+	if cm.onStoppedLeading != nil {
+		cm.onStoppedLeading()
+	}
+}
+
+// releaseLeaderElection releases the leader lock, if it is held by the Manager.
+func (cm *controllerManager) releaseLeaderElection() {
+	if err := ctrlleaderelection.Release(cm.resourceLock); err != nil {
+		log.Error(err, "unable to release the leader lock", "lock", cm.resourceLock.Describe())
+		return
+	}
+	log.Info("Released the leader lock", "lock", cm.resourceLock.Describe())
+}
+
+// waitForLeaderElection waits for the leader election loop to return, and thereby for the leader
+// lock to be released.
+func (cm *controllerManager) waitForLeaderElection() {
+	if cm.leaderElectionStopped != nil {
+		<-cm.leaderElectionStopped
+	}
+}
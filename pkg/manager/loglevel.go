@@ -0,0 +1,204 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+// logLevelVerbosityKey is the key of the LogLevelConfigMap holding the verbosity of the messages of no
+// logf.Component.  Every other key is a Component key, holding the values and verbosities of its Components.
+const logLevelVerbosityKey = "verbosity"
+
+// LogLevelStatus are the logf.Levels of a Manager.  They are served as JSON by the /debug/loglevel
+// endpoint of the debug server.
+type LogLevelStatus struct {
+	// Verbosity is the verbosity of the messages of no logf.Component.
+	Verbosity int `json:"verbosity"`
+
+	// Components are the verbosities of the messages of the logf.Components overriding Verbosity.
+	Components []logf.ComponentLevel `json:"components"`
+}
+
+// serveLogLevel serves the LogLevelStatus on GET.  On PUT, it sets the verbosity of the v query
+// parameter for the Component named by the other query parameter, e.g. ?controller=foo&v=4, or for
+// the messages of no Component if there is none.  On DELETE, it resets the verbosity of the Component
+// named by the query parameter.
+func (cm *controllerManager) serveLogLevel(resp http.ResponseWriter, req *http.Request) {
+	if cm.logLevels == nil {
+		http.Error(resp, "the manager has no log levels", http.StatusNotImplemented)
+		return
+	}
+
+	query := req.URL.Query()
+	var component *logf.Component
+	for key := range query {
+		if key == "v" {
+			continue
+		}
+		if component != nil {
+			http.Error(resp, "at most one component query parameter is allowed", http.StatusBadRequest)
+			return
+		}
+		component = &logf.Component{Key: key, Value: query.Get(key)}
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		v, err := strconv.Atoi(query.Get("v"))
+		if err != nil || v < 0 {
+			http.Error(resp, fmt.Sprintf("invalid v query parameter %q", query.Get("v")), http.StatusBadRequest)
+			return
+		}
+		if component != nil {
+			cm.logLevels.SetComponent(*component, v)
+		} else {
+			cm.logLevels.SetVerbosity(v)
+		}
+		log.Info("Set the log verbosity", "component", component, "verbosity", v)
+	case http.MethodDelete:
+		if component == nil {
+			http.Error(resp, "a component query parameter is required", http.StatusBadRequest)
+			return
+		}
+		cm.logLevels.ResetComponent(*component)
+		log.Info("Reset the log verbosity", "component", component)
+	default:
+		http.Error(resp, fmt.Sprintf("method %s is not allowed", req.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := LogLevelStatus{
+		Verbosity:  cm.logLevels.Verbosity(),
+		Components: cm.logLevels.Components(),
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(resp).Encode(status); err != nil {
+		log.Error(err, "unable to write log levels")
+	}
+}
+
+// logLevelWatcher sets the logf.Levels of a Manager from the LogLevelConfigMap whenever it changes.
+type logLevelWatcher struct {
+	levels   *logf.Levels
+	key      types.NamespacedName
+	informer toolscache.SharedInformer
+
+	// verbosity is the verbosity of the messages of no Component set once the ConfigMap is deleted, or
+	// if it doesn't hold one.
+	verbosity int
+}
+
+// newLogLevelWatcher returns a logLevelWatcher watching the ConfigMap with the given key only.
+func newLogLevelWatcher(config *rest.Config, levels *logf.Levels, key types.NamespacedName) (*logLevelWatcher, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	lw := toolscache.NewListWatchFromClient(clientset.CoreV1().RESTClient(), "configmaps", key.Namespace,
+		fields.OneTermEqualSelector("metadata.name", key.Name))
+	w := &logLevelWatcher{
+		levels:    levels,
+		key:       key,
+		informer:  toolscache.NewSharedInformer(lw, &corev1.ConfigMap{}, 0),
+		verbosity: levels.Verbosity(),
+	}
+	w.informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { w.set(obj) },
+		UpdateFunc: func(_, obj interface{}) { w.set(obj) },
+		DeleteFunc: func(interface{}) { w.reset() },
+	})
+	return w, nil
+}
+
+// run watches the ConfigMap until stop is closed.
+func (w *logLevelWatcher) run(stop <-chan struct{}) {
+	w.informer.Run(stop)
+}
+
+// set sets the Levels from the ConfigMap obj.  The Components it doesn't hold are reset.
+func (w *logLevelWatcher) set(obj interface{}) {
+	configMap, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+	verbosity, components, err := parseLogLevels(configMap.Data)
+	if err != nil {
+		log.Error(err, "invalid log levels", "configmap", w.key)
+		return
+	}
+	if verbosity < 0 {
+		verbosity = w.verbosity
+	}
+	w.levels.SetVerbosity(verbosity)
+	w.levels.SetComponents(components)
+	log.Info("Set the log levels", "configmap", w.key, "verbosity", verbosity, "components", components)
+}
+
+// reset resets the Levels once the ConfigMap is deleted.
+func (w *logLevelWatcher) reset() {
+	w.levels.SetVerbosity(w.verbosity)
+	w.levels.SetComponents(nil)
+	log.Info("Reset the log levels", "configmap", w.key)
+}
+
+// parseLogLevels parses the data of a LogLevelConfigMap.  The verbosity key holds the verbosity of the
+// messages of no Component, and returned verbosity is -1 if there is none.  Each other key holds a
+// comma or whitespace separated list of value=verbosity for the Components of that key, e.g. the
+// controller key may hold "foo=4,bar=2".
+func parseLogLevels(data map[string]string) (int, []logf.ComponentLevel, error) {
+	verbosity := -1
+	var components []logf.ComponentLevel
+	for key, raw := range data {
+		if key == logLevelVerbosityKey {
+			v, err := strconv.Atoi(strings.TrimSpace(raw))
+			if err != nil || v < 0 {
+				return 0, nil, fmt.Errorf("invalid %s %q", logLevelVerbosityKey, raw)
+			}
+			verbosity = v
+			continue
+		}
+		for _, entry := range strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || r == ' ' || r == '\n' || r == '\t' }) {
+			i := strings.LastIndex(entry, "=")
+			if i <= 0 {
+				return 0, nil, fmt.Errorf("invalid %s %q, expected value=verbosity", key, entry)
+			}
+			v, err := strconv.Atoi(entry[i+1:])
+			if err != nil || v < 0 {
+				return 0, nil, fmt.Errorf("invalid verbosity of %s %q", key, entry)
+			}
+			components = append(components, logf.ComponentLevel{
+				Component: logf.Component{Key: key, Value: entry[:i]},
+				Verbosity: v,
+			})
+		}
+	}
+	return verbosity, components, nil
+}
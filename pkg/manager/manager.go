@@ -22,20 +22,26 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	apitypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	internalrecorder "sigs.k8s.io/controller-runtime/pkg/internal/recorder"
 	"sigs.k8s.io/controller-runtime/pkg/leaderelection"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/recorder"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
 )
@@ -46,6 +52,7 @@ type Manager interface {
 	// Add will set reqeusted dependencies on the component, and cause the component to be
 	// started when Start is called.  Add will inject any dependencies for which the argument
 	// implements the inject interface - e.g. inject.Client
+	// The component is started in its StartPhase, see PhasedRunnable.
 	Add(Runnable) error
 
 	// SetFields will set any dependencies on an object for which the object has implemented the inject
@@ -74,11 +81,30 @@ type Manager interface {
 	// GetCache returns a cache.Cache
 	GetCache() cache.Cache
 
+	// GetEventRecorderFor returns a new EventRecorder for the provided name.  Events are
+	// recorded with the Manager's Scheme and sent to the API server using the Manager's Config.
+	GetEventRecorderFor(name string) record.EventRecorder
+
 	// GetRecorder returns a new EventRecorder for the provided name
+	//
+	// Deprecated: use GetEventRecorderFor instead.
 	GetRecorder(name string) record.EventRecorder
 
 	// GetRESTMapper returns a RESTMapper
 	GetRESTMapper() meta.RESTMapper
+
+	// AddHealthzCheck allows you to add a Healthz checker, served under /healthz on the
+	// HealthProbeBindAddress.  Checks must be added before the Manager is started.
+	AddHealthzCheck(name string, check healthz.Checker) error
+
+	// AddReadyzCheck allows you to add a Readyz checker, served under /readyz on the
+	// HealthProbeBindAddress.  Checks must be added before the Manager is started.
+	AddReadyzCheck(name string, check healthz.Checker) error
+
+	// Elected returns a channel which is closed once the Manager is elected leader, or once it is
+	// started if leader election is disabled.  Work which must only be done by the leader, such as
+	// a background garbage collection loop, can wait on it.
+	Elected() <-chan struct{}
 }
 
 // Options are the arguments for creating a new Manager
@@ -108,16 +134,122 @@ type Options struct {
 	// will use for holding the leader lock.
 	LeaderElectionID string
 
+	// LeaderElectionResourceLock determines the type of the object holding the leader lock,
+	// "configmaps", "endpoints" or "leases".  Defaults to "configmaps".
+	LeaderElectionResourceLock string
+
+	// LeaseDuration is the duration that non-leader candidates will wait to force acquire
+	// leadership.  This is measured against time of last observed ack.  Defaults to 15 seconds.
+	LeaseDuration *time.Duration
+
+	// RenewDeadline is the duration that the acting leader will retry refreshing leadership
+	// before giving up.  Defaults to 10 seconds.
+	RenewDeadline *time.Duration
+
+	// RetryPeriod is the duration the leader election clients should wait between tries of
+	// actions.  Defaults to 2 seconds.
+	RetryPeriod *time.Duration
+
+	// LeaderElectionReleaseOnCancel, if true, releases the leader lock when the Manager is
+	// stopped, so that another replica can take over without waiting for the lease to expire.
+	// The Runnables must be done with their work once the stop channel is closed, since the new
+	// leader may start before they return.
+	LeaderElectionReleaseOnCancel bool
+
+	// OnStartedLeading, if set, is called once the Manager is elected leader, or once it is started
+	// if leader election is disabled, before the Runnables are started.  It must not block.
+	OnStartedLeading func()
+
+	// OnStoppedLeading, if set, is called once the Manager stops leading after OnStartedLeading was
+	// called, whether leadership was lost or the Manager was stopped.  It must not block.
+	OnStoppedLeading func()
+
 	// Namespace if specified restricts the manager's cache to watch objects in the desired namespace
 	// Defaults to all namespaces
 	// Note: If a namespace is specified then controllers can still Watch for a cluster-scoped resource e.g Node
 	// For namespaced resources the cache will only hold objects from the desired namespace.
 	Namespace string
 
+	// DeferUnknownKinds, if true, lets controllers watch kinds that are not served by the API server
+	// yet, such as kinds whose CustomResourceDefinitions are installed by a Runnable of the Manager at
+	// startup.  The informers of these kinds are started once the kinds are discovered, e.g. as soon as
+	// a crd.Installer added to the Manager has Established their CustomResourceDefinitions, and do not
+	// block the Manager from starting its Runnables.  See cache.Options.DeferUnknownKinds.
+	DeferUnknownKinds bool
+
 	// MetricsBindAddress is the TCP address that the controller should bind to
 	// for serving prometheus metrics
 	MetricsBindAddress string
 
+	// Metrics are the collectors of the metrics recorded by the operator, such as the number
+	// of databases it provisioned.  The Manager registers them with metrics.Registry so that
+	// they are exported alongside the metrics of the controller-runtime.
+	Metrics []prometheus.Collector
+
+	// HealthProbeBindAddress is the TCP address that the controller should bind to
+	// for serving health probes under /healthz and /readyz.  Defaults to "0", which
+	// disables serving health probes.
+	HealthProbeBindAddress string
+
+	// DebugBindAddress is the TCP address that the controller should bind to for serving
+	// debug endpoints, such as /debug/cache which dumps the keys and objects in the store of
+	// an informer of the cache, /debug/config which reports the RuntimeConfig of the Manager, and
+	// /debug/loglevel which adjusts the LogLevels, and /version which reports the version of the
+	// binary.  Defaults to "0", which disables serving debug endpoints.  The dumps may contain sensitive objects, so this address should not be
+	// exposed outside of the Pod.
+	DebugBindAddress string
+
+	// LogLevels, if set, are the Levels of the logger passed to log.SetLogger, which must be wrapped
+	// with log.WithLevels(logger, LogLevels).  They can then be adjusted at runtime per component, e.g.
+	// the "controller" key and the name of a controller or the "path" key and the path of a webhook,
+	// through the /debug/loglevel endpoint of the debug server and the LogLevelConfigMap.
+	LogLevels *logf.Levels
+
+	// LogLevelConfigMap, if set along with LogLevels, is the namespace and name of a ConfigMap whose data
+	// replace the LogLevels whenever it changes.  Its "verbosity" key holds the verbosity of the messages
+	// of no component.  Every other key is a component key holding comma separated value=verbosity pairs,
+	// e.g. "controller: foo=4,bar=2".  The ConfigMap is watched whether the Manager is leader or not.
+	LogLevelConfigMap *apitypes.NamespacedName
+
+	// DrainTimeout is the maximum time the Manager waits, once the stop channel is closed, for the
+	// Runnables implementing Drainer (such as Controllers) to finish their queued and in flight work
+	// before stopping them.  Defaults to 0, which stops the Runnables without draining them.
+	// Draining can also be started before the stop channel is closed through the /drain endpoint
+	// of the health probes server, e.g. from a preStop hook.
+	DrainTimeout time.Duration
+
+	// StripManagedFields, if true, removes metadata.managedFields from all objects read
+	// through the Manager's client, whether they are served from the cache or the API server.
+	StripManagedFields bool
+
+	// UncachedObjects are the types, such as Secrets, that the default client always reads
+	// from the API server instead of the cache, so that they are never cached by the Manager.
+	UncachedObjects []runtime.Object
+
+	// CacheUnstructured, if true, causes the default client to read unstructured objects from
+	// the cache, which starts dynamic informers for them on demand.  By default unstructured
+	// objects are read from the API server.
+	CacheUnstructured bool
+
+	// ClientRateLimiter limits the requests made to the API server by the cache, client, event
+	// recorders and leader election of the Manager, and by clients created from GetConfig.  It
+	// defaults to the RateLimiter of the rest.Config, or else to a limiter allowing the QPS and
+	// Burst of the rest.Config, so that the Manager's components share a single rate limit
+	// instead of one per client and kind.  The time requests wait for it is recorded in the
+	// controller_runtime_client_rate_limiter_wait_seconds metric.
+	ClientRateLimiter flowcontrol.RateLimiter
+
+	// ClusterProvider, if set, discovers the clusters the Manager's Runnables act on besides the
+	// cluster of the Manager, e.g. a cluster.KubeconfigSecretProvider.  It is run once the Manager
+	// is elected leader, and each Runnable implementing cluster.Aware is engaged with every cluster
+	// it discovers, including Runnables added after the cluster was engaged.
+	ClusterProvider cluster.Provider
+
+	// FeatureGates are the feature gates enabled or disabled in the operator.  They are not
+	// interpreted by the Manager, but are reported on the /debug/config endpoint of the debug
+	// server so that the running configuration can be verified.
+	FeatureGates map[string]bool
+
 	// Functions to all for a user to customize the values that will be injected.
 
 	// NewCache is the function that will create the cache to be used
@@ -130,17 +262,19 @@ type Options struct {
 	NewClient NewClientFunc
 
 	// Dependency injection for testing
-	newRecorderProvider func(config *rest.Config, scheme *runtime.Scheme, logger logr.Logger) (recorder.Provider, error)
-	newResourceLock     func(config *rest.Config, recorderProvider recorder.Provider, options leaderelection.Options) (resourcelock.Interface, error)
-	newAdmissionDecoder func(scheme *runtime.Scheme) (types.Decoder, error)
-	newMetricsListener  func(addr string) (net.Listener, error)
+	newRecorderProvider    func(config *rest.Config, scheme *runtime.Scheme, logger logr.Logger) (recorder.Provider, error)
+	newResourceLock        func(config *rest.Config, recorderProvider recorder.Provider, options leaderelection.Options) (resourcelock.Interface, error)
+	newAdmissionDecoder    func(scheme *runtime.Scheme) (types.Decoder, error)
+	newMetricsListener     func(addr string) (net.Listener, error)
+	newHealthProbeListener func(addr string) (net.Listener, error)
+	newDebugListener       func(addr string) (net.Listener, error)
 }
 
 // NewCacheFunc allows a user to define how to create a cache
-type NewCacheFunc func(config *rest.Config, opts cache.Options) (cache.Cache, error)
+type NewCacheFunc = cluster.NewCacheFunc
 
 // NewClientFunc allows a user to define how to create a client
-type NewClientFunc func(cache cache.Cache, config *rest.Config, options client.Options) (client.Client, error)
+type NewClientFunc = cluster.NewClientFunc
 
 // Runnable allows a component to be started.
 type Runnable interface {
@@ -149,6 +283,52 @@ type Runnable interface {
 	Start(<-chan struct{}) error
 }
 
+// LeaderElectionRunnable is implemented by Runnables which may run whether the Manager is leader or not,
+// such as Controllers only observing objects.  Runnables which don't implement it need leader election.
+type LeaderElectionRunnable interface {
+	// NeedLeaderElection returns whether the Runnable must only be started once the Manager is elected
+	// leader, if the Manager uses leader election.
+	NeedLeaderElection() bool
+}
+
+// StartPhase is the phase of the start of the Manager in which a Runnable is started.  The Manager starts
+// the Runnables phase by phase, so that a Runnable can rely on the Runnables of the previous phases.
+type StartPhase int
+
+const (
+	// CachesPhase is the first phase, in which the Runnables are started along with the cache, before it
+	// has synced.  The Runnables implementing CacheSyncer, e.g. additional caches, are waited for to sync
+	// along with the cache before the next phases.
+	CachesPhase StartPhase = iota
+
+	// WebhooksPhase is the phase in which the webhook servers are started, once the caches have synced.
+	WebhooksPhase
+
+	// RunnablesPhase is the phase in which the Runnables which don't need leader election are started,
+	// once the webhook servers are started.
+	RunnablesPhase
+
+	// LeaderElectedPhase is the last phase, in which the Runnables which need leader election, e.g. the
+	// Controllers, are started once the Manager is elected leader.
+	LeaderElectedPhase
+)
+
+// PhasedRunnable is implemented by Runnables which declare the StartPhase they are started in.  The
+// Runnables which don't implement it are started in the RunnablesPhase if they don't need leader
+// election, and in the LeaderElectedPhase otherwise.  The Runnables of the phases before the
+// LeaderElectedPhase are started whether the Manager is leader or not.
+type PhasedRunnable interface {
+	// StartPhase returns the phase the Runnable is started in.
+	StartPhase() StartPhase
+}
+
+// CacheSyncer is implemented by the Runnables of the CachesPhase which the Manager must wait for to sync
+// before starting the next phases.  Those added once the Manager has started are not waited for.
+type CacheSyncer interface {
+	// WaitForCacheSync waits for the Runnable to sync.  Returns false if it could not sync.
+	WaitForCacheSync(stop <-chan struct{}) bool
+}
+
 // RunnableFunc implements Runnable
 type RunnableFunc func(<-chan struct{}) error
 
@@ -167,6 +347,17 @@ func New(config *rest.Config, options Options) (Manager, error) {
 	// Set default values for options fields
 	options = setOptionsDefaults(options)
 
+	// Share a single rate limiter between the clients of the Manager
+	config = rest.CopyConfig(config)
+	switch {
+	case options.ClientRateLimiter != nil:
+		config.RateLimiter = client.ObserveRateLimiter(options.ClientRateLimiter)
+	case config.RateLimiter != nil:
+		config.RateLimiter = client.ObserveRateLimiter(config.RateLimiter)
+	default:
+		config.RateLimiter = client.NewRateLimiter(config.QPS, config.Burst)
+	}
+
 	// Create the mapper provider
 	mapper, err := options.MapperProvider(config)
 	if err != nil {
@@ -175,12 +366,24 @@ func New(config *rest.Config, options Options) (Manager, error) {
 	}
 
 	// Create the cache for the cached read client and registering informers
-	cache, err := options.NewCache(config, cache.Options{Scheme: options.Scheme, Mapper: mapper, Resync: options.SyncPeriod, Namespace: options.Namespace})
+	cache, err := options.NewCache(config, cache.Options{
+		Scheme:            options.Scheme,
+		Mapper:            mapper,
+		Resync:            options.SyncPeriod,
+		Namespace:         options.Namespace,
+		DeferUnknownKinds: options.DeferUnknownKinds,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	writeObj, err := options.NewClient(cache, config, client.Options{Scheme: options.Scheme, Mapper: mapper})
+	writeObj, err := options.NewClient(cache, config, client.Options{
+		Scheme:             options.Scheme,
+		Mapper:             mapper,
+		StripManagedFields: options.StripManagedFields,
+		UncachedObjects:    options.UncachedObjects,
+		CacheUnstructured:  options.CacheUnstructured,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -194,9 +397,10 @@ func New(config *rest.Config, options Options) (Manager, error) {
 
 	// Create the resource lock to enable leader election)
 	resourceLock, err := options.newResourceLock(config, recorderProvider, leaderelection.Options{
-		LeaderElection:          options.LeaderElection,
-		LeaderElectionID:        options.LeaderElectionID,
-		LeaderElectionNamespace: options.LeaderElectionNamespace,
+		LeaderElection:             options.LeaderElection,
+		LeaderElectionID:           options.LeaderElectionID,
+		LeaderElectionNamespace:    options.LeaderElectionNamespace,
+		LeaderElectionResourceLock: options.LeaderElectionResourceLock,
 	})
 	if err != nil {
 		return nil, err
@@ -207,6 +411,11 @@ func New(config *rest.Config, options Options) (Manager, error) {
 		return nil, err
 	}
 
+	// Register the operator metrics with the registry served by the metrics listener.
+	if err := metrics.Register(options.Metrics...); err != nil {
+		return nil, err
+	}
+
 	// Create the mertics listener. This will throw an error if the metrics bind
 	// address is invalid or already in use.
 	metricsListener, err := options.newMetricsListener(options.MetricsBindAddress)
@@ -214,9 +423,30 @@ func New(config *rest.Config, options Options) (Manager, error) {
 		return nil, err
 	}
 
+	// Create the health probes listener. This will throw an error if the bind
+	// address is invalid or already in use.
+	healthProbeListener, err := options.newHealthProbeListener(options.HealthProbeBindAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the debug listener. This will throw an error if the bind
+	// address is invalid or already in use.
+	debugListener, err := options.newDebugListener(options.DebugBindAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	var logLevelWatcher *logLevelWatcher
+	if options.LogLevels != nil && options.LogLevelConfigMap != nil {
+		if logLevelWatcher, err = newLogLevelWatcher(config, options.LogLevels, *options.LogLevelConfigMap); err != nil {
+			return nil, err
+		}
+	}
+
 	stop := make(chan struct{})
 
-	return &controllerManager{
+	cm := &controllerManager{
 		config:           config,
 		scheme:           options.Scheme,
 		admissionDecoder: admissionDecoder,
@@ -230,25 +460,48 @@ func New(config *rest.Config, options Options) (Manager, error) {
 		metricsListener:  metricsListener,
 		internalStop:     stop,
 		internalStopper:  stop,
-	}, nil
+
+		healthProbeListener: healthProbeListener,
+		debugListener:       debugListener,
+		healthzChecks:       map[string]healthz.Checker{},
+		readyzChecks:        map[string]healthz.Checker{},
+		drainTimeout:        options.DrainTimeout,
+		managerConfig:       newManagerConfig(config, options),
+		featureGates:        options.FeatureGates,
+		logLevels:           options.LogLevels,
+		logLevelWatcher:     logLevelWatcher,
+		clusterProvider:     options.ClusterProvider,
+		leaseDuration:       *options.LeaseDuration,
+		renewDeadline:       *options.RenewDeadline,
+		retryPeriod:         *options.RetryPeriod,
+		releaseOnCancel:     options.LeaderElectionReleaseOnCancel,
+		elected:             make(chan struct{}),
+		nonLeaderStarted:    make(chan struct{}),
+		onStartedLeading:    options.OnStartedLeading,
+		onStoppedLeading:    options.OnStoppedLeading,
+		clusters:            map[string]engagedCluster{},
+	}
+	if options.ClusterProvider != nil {
+		if err := cm.SetFields(options.ClusterProvider); err != nil {
+			return nil, err
+		}
+	}
+	cm.readyzChecks[cacheSyncCheckName] = cm.checkCacheSync
+	cm.readyzChecks[drainCheckName] = cm.checkDrain
+	return cm, nil
 }
 
-// defaultNewClient creates the default caching client
-func defaultNewClient(cache cache.Cache, config *rest.Config, options client.Options) (client.Client, error) {
-	// Create the Client for Write operations.
-	c, err := client.New(config, options)
-	if err != nil {
-		return nil, err
+// defaultHealthProbeListener creates the default health probes listener bound to the given address
+func defaultHealthProbeListener(addr string) (net.Listener, error) {
+	if addr == "" || addr == "0" {
+		return nil, nil
 	}
 
-	return &client.DelegatingClient{
-		Reader: &client.DelegatingReader{
-			CacheReader:  cache,
-			ClientReader: c,
-		},
-		Writer:       c,
-		StatusClient: c,
-	}, nil
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("error listening on %s: %v", addr, err)
+	}
+	return ln, nil
 }
 
 // setOptionsDefaults set default values for Options fields
@@ -264,7 +517,7 @@ func setOptionsDefaults(options Options) Options {
 
 	// Allow newClient to be mocked
 	if options.NewClient == nil {
-		options.NewClient = defaultNewClient
+		options.NewClient = cluster.DefaultNewClient
 	}
 
 	// Allow newCache to be mocked
@@ -277,6 +530,21 @@ func setOptionsDefaults(options Options) Options {
 		options.newRecorderProvider = internalrecorder.NewProvider
 	}
 
+	if options.LeaseDuration == nil {
+		leaseDuration := defaultLeaseDuration
+		options.LeaseDuration = &leaseDuration
+	}
+
+	if options.RenewDeadline == nil {
+		renewDeadline := defaultRenewDeadline
+		options.RenewDeadline = &renewDeadline
+	}
+
+	if options.RetryPeriod == nil {
+		retryPeriod := defaultRetryPeriod
+		options.RetryPeriod = &retryPeriod
+	}
+
 	// Allow newResourceLock to be mocked
 	if options.newResourceLock == nil {
 		options.newResourceLock = leaderelection.NewResourceLock
@@ -290,5 +558,13 @@ func setOptionsDefaults(options Options) Options {
 		options.newMetricsListener = metrics.NewListener
 	}
 
+	if options.newHealthProbeListener == nil {
+		options.newHealthProbeListener = defaultHealthProbeListener
+	}
+
+	if options.newDebugListener == nil {
+		options.newDebugListener = defaultDebugListener
+	}
+
 	return options
 }
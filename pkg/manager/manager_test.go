@@ -17,28 +17,39 @@ limitations under the License.
 package manager
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/util/flowcontrol"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/leaderelection"
 	fakeleaderelection "sigs.k8s.io/controller-runtime/pkg/leaderelection/fake"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/recorder"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/version"
 )
 
 var _ = Describe("manger.Manager", func() {
@@ -138,6 +149,24 @@ var _ = Describe("manger.Manager", func() {
 				Expect(rl.Describe()).To(Equal("default/controller-leader-election-helper"))
 			})
 
+			It("should create a Lease lock if the resource lock is leases", func() {
+				var rl resourcelock.Interface
+				m, err := New(cfg, Options{
+					LeaderElection:             true,
+					LeaderElectionNamespace:    "default",
+					LeaderElectionID:           "controller-runtime",
+					LeaderElectionResourceLock: leaderelection.LeasesResourceLock,
+					newResourceLock: func(config *rest.Config, recorderProvider recorder.Provider, options leaderelection.Options) (resourcelock.Interface, error) {
+						var err error
+						rl, err = leaderelection.NewResourceLock(config, recorderProvider, options)
+						return rl, err
+					},
+				})
+				Expect(m).ToNot(BeNil())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rl.Describe()).To(Equal("default/controller-runtime"))
+			})
+
 			It("should return an error if namespace not set and not running in cluster", func() {
 				m, err := New(cfg, Options{LeaderElection: true, LeaderElectionID: "controller-runtime"})
 				Expect(m).To(BeNil())
@@ -212,6 +241,107 @@ var _ = Describe("manger.Manager", func() {
 				close(done)
 			})
 
+			It("should Start the Components which don't need leader election", func(done Done) {
+				m, err := New(cfg, options)
+				Expect(err).NotTo(HaveOccurred())
+				c1 := make(chan struct{})
+				Expect(m.Add(nonLeaderRunnable(func(s <-chan struct{}) error {
+					defer close(c1)
+					return nil
+				}))).To(Succeed())
+
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(stop)).NotTo(HaveOccurred())
+				}()
+				<-c1
+
+				c2 := make(chan struct{})
+				Expect(m.Add(nonLeaderRunnable(func(s <-chan struct{}) error {
+					defer close(c2)
+					return nil
+				}))).To(Succeed())
+				<-c2
+
+				close(done)
+			})
+
+			It("should Start the Components phase by phase", func(done Done) {
+				m, err := New(cfg, options)
+				Expect(err).NotTo(HaveOccurred())
+				started := make(chan StartPhase, 4)
+				for _, phase := range []StartPhase{LeaderElectedPhase, RunnablesPhase, WebhooksPhase} {
+					Expect(m.Add(&phasedRunnable{phase: phase, started: started})).To(Succeed())
+				}
+				synced := make(chan struct{})
+				Expect(m.Add(&phasedRunnable{phase: CachesPhase, started: started, synced: synced})).To(Succeed())
+
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(stop)).NotTo(HaveOccurred())
+				}()
+				Eventually(started).Should(Receive(Equal(CachesPhase)))
+				Consistently(started, 50*time.Millisecond).ShouldNot(Receive())
+
+				close(synced)
+				var phases []StartPhase
+				for range []StartPhase{LeaderElectedPhase, RunnablesPhase, WebhooksPhase} {
+					phases = append(phases, <-started)
+				}
+				Expect(phases).To(ConsistOf(LeaderElectedPhase, RunnablesPhase, WebhooksPhase))
+
+				close(done)
+			})
+
+			It("should add Components while the caches are syncing", func(done Done) {
+				m, err := New(cfg, options)
+				Expect(err).NotTo(HaveOccurred())
+				started := make(chan StartPhase, 2)
+				synced := make(chan struct{})
+				Expect(m.Add(&phasedRunnable{phase: CachesPhase, started: started, synced: synced})).To(Succeed())
+
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(stop)).NotTo(HaveOccurred())
+				}()
+				Eventually(started).Should(Receive(Equal(CachesPhase)))
+
+				added := make(chan error)
+				go func() {
+					added <- m.Add(&phasedRunnable{phase: RunnablesPhase, started: started})
+				}()
+				Eventually(added).Should(Receive(BeNil()))
+				Consistently(started, 50*time.Millisecond).ShouldNot(Receive())
+
+				close(synced)
+				Eventually(started).Should(Receive(Equal(RunnablesPhase)))
+
+				close(done)
+			})
+
+			It("should close Elected and call the leadership hooks", func(done Done) {
+				var started, stopped int32
+				opts := options
+				opts.OnStartedLeading = func() { atomic.AddInt32(&started, 1) }
+				opts.OnStoppedLeading = func() { atomic.AddInt32(&stopped, 1) }
+				m, err := New(cfg, opts)
+				Expect(err).NotTo(HaveOccurred())
+				Consistently(m.Elected(), 50*time.Millisecond).ShouldNot(BeClosed())
+
+				s := make(chan struct{})
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(s)).NotTo(HaveOccurred())
+					close(done)
+				}()
+				Eventually(m.Elected()).Should(BeClosed())
+				Expect(atomic.LoadInt32(&started)).To(BeEquivalentTo(1))
+				Expect(atomic.LoadInt32(&stopped)).To(BeEquivalentTo(0))
+
+				close(s)
+				Eventually(func() int32 { return atomic.LoadInt32(&stopped) }).Should(BeEquivalentTo(1))
+			})
+
 			It("should stop when stop is called", func(done Done) {
 				m, err := New(cfg, options)
 				Expect(err).NotTo(HaveOccurred())
@@ -283,6 +413,16 @@ var _ = Describe("manger.Manager", func() {
 			})
 		})
 
+		Context("with leaderelection enabled and released on cancel", func() {
+			startSuite(Options{
+				LeaderElection:                true,
+				LeaderElectionID:              "controller-runtime",
+				LeaderElectionNamespace:       "default",
+				LeaderElectionReleaseOnCancel: true,
+				newResourceLock:               fakeleaderelection.NewResourceLock,
+			})
+		})
+
 		Context("should start serving metrics", func() {
 			var listener net.Listener
 			var opts Options
@@ -407,6 +547,681 @@ var _ = Describe("manger.Manager", func() {
 				ok := metrics.Registry.Unregister(one)
 				Expect(ok).To(BeTrue())
 			})
+
+			It("should serve the operator metrics passed in the options", func(done Done) {
+				provisioned := prometheus.NewCounter(prometheus.CounterOpts{
+					Name: "test_databases_provisioned_total",
+					Help: "test business metric for testing",
+				})
+				provisioned.Add(3)
+				defer metrics.Unregister(provisioned)
+
+				opts.MetricsBindAddress = ":0"
+				opts.Metrics = []prometheus.Collector{provisioned}
+				m, err := New(cfg, opts)
+				Expect(err).NotTo(HaveOccurred())
+
+				s := make(chan struct{})
+				defer close(s)
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(s)).NotTo(HaveOccurred())
+					close(done)
+				}()
+
+				metricsEndpoint := fmt.Sprintf("http://%s/metrics", listener.Addr().String())
+				resp, err := http.Get(metricsEndpoint)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.StatusCode).To(Equal(200))
+
+				data, err := ioutil.ReadAll(resp.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(data)).To(ContainSubstring("test_databases_provisioned_total 3"))
+			})
+
+			Context("with a registered operator metric", func() {
+				var registered prometheus.Collector
+
+				BeforeEach(func() {
+					registered = prometheus.NewCounter(prometheus.CounterOpts{
+						Name: "test_conflicting_total",
+						Help: "test metric for testing",
+					})
+					Expect(metrics.Registry.Register(registered)).To(Succeed())
+				})
+
+				AfterEach(func() {
+					Expect(metrics.Registry.Unregister(registered)).To(BeTrue())
+				})
+
+				It("should return an error if the operator metrics can't be registered", func() {
+					opts.Metrics = []prometheus.Collector{prometheus.NewCounter(prometheus.CounterOpts{
+						Name: "test_conflicting_total",
+						Help: "conflicting metric for testing",
+					})}
+					_, err := New(cfg, opts)
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+		Context("should start serving health probes", func() {
+			var listener net.Listener
+			var opts Options
+
+			BeforeEach(func() {
+				listener = nil
+				opts = Options{
+					HealthProbeBindAddress: ":0",
+					newHealthProbeListener: func(addr string) (net.Listener, error) {
+						var err error
+						listener, err = defaultHealthProbeListener(addr)
+						return listener, err
+					},
+				}
+			})
+
+			AfterEach(func() {
+				if listener != nil {
+					listener.Close()
+				}
+			})
+
+			It("should serve the healthz checks", func(done Done) {
+				m, err := New(cfg, opts)
+				Expect(err).NotTo(HaveOccurred())
+
+				res := fmt.Errorf("not ok")
+				Expect(m.AddHealthzCheck("ping", healthz.Ping)).To(Succeed())
+				Expect(m.AddHealthzCheck("fail", func(_ *http.Request) error {
+					return res
+				})).To(Succeed())
+
+				s := make(chan struct{})
+				defer close(s)
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(s)).NotTo(HaveOccurred())
+					close(done)
+				}()
+
+				endpoint := fmt.Sprintf("http://%s/healthz", listener.Addr().String())
+				Eventually(func() error {
+					_, err := http.Get(endpoint)
+					return err
+				}).Should(Succeed())
+
+				resp, err := http.Get(endpoint)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.StatusCode).To(Equal(http.StatusInternalServerError))
+
+				resp, err = http.Get(endpoint + "/ping")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			})
+
+			It("should serve the readyz checks including the cache sync check", func(done Done) {
+				m, err := New(cfg, opts)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(m.AddReadyzCheck("ping", healthz.Ping)).To(Succeed())
+
+				s := make(chan struct{})
+				defer close(s)
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(s)).NotTo(HaveOccurred())
+					close(done)
+				}()
+
+				endpoint := fmt.Sprintf("http://%s/readyz", listener.Addr().String())
+				Eventually(func() int {
+					resp, err := http.Get(endpoint + "?verbose")
+					if err != nil {
+						return 0
+					}
+					return resp.StatusCode
+				}).Should(Equal(http.StatusOK))
+
+				resp, err := http.Get(endpoint + "/" + cacheSyncCheckName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			})
+
+			It("should not wait for the caches to sync to add checks", func(done Done) {
+				m, err := New(cfg, opts)
+				Expect(err).NotTo(HaveOccurred())
+				// The additional cache never syncs
+				started := make(chan StartPhase, 1)
+				Expect(m.Add(&phasedRunnable{phase: CachesPhase, started: started, synced: make(chan struct{})})).To(Succeed())
+
+				s := make(chan struct{})
+				defer close(s)
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(s)).NotTo(HaveOccurred())
+					close(done)
+				}()
+				Eventually(started).Should(Receive())
+
+				added := make(chan error)
+				go func() {
+					added <- m.AddReadyzCheck("late", healthz.Ping)
+				}()
+				Eventually(added).Should(Receive())
+
+				endpoint := fmt.Sprintf("http://%s/readyz", listener.Addr().String())
+				Eventually(func() error {
+					_, err := http.Get(endpoint)
+					return err
+				}).Should(Succeed())
+			})
+
+			It("should not allow adding the same check twice", func() {
+				m, err := New(cfg, opts)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(m.AddHealthzCheck("ping", healthz.Ping)).To(Succeed())
+				Expect(m.AddHealthzCheck("ping", healthz.Ping)).NotTo(Succeed())
+			})
+
+			It("should not allow adding checks once the probes are served", func() {
+				m, err := New(cfg, opts)
+				Expect(err).NotTo(HaveOccurred())
+
+				s := make(chan struct{})
+				defer close(s)
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(s)).NotTo(HaveOccurred())
+				}()
+
+				i := 0
+				Eventually(func() error {
+					i++
+					return m.AddReadyzCheck(fmt.Sprintf("late-%d", i), healthz.Ping)
+				}).Should(MatchError(ContainSubstring("after the health probes are being served")))
+			})
+
+			It("should begin draining and report the drain progress on the drain endpoint", func(done Done) {
+				m, err := New(cfg, opts)
+				Expect(err).NotTo(HaveOccurred())
+				d := &drainable{pending: 2}
+				Expect(m.Add(d)).To(Succeed())
+
+				s := make(chan struct{})
+				defer close(s)
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(s)).NotTo(HaveOccurred())
+					close(done)
+				}()
+
+				base := fmt.Sprintf("http://%s", listener.Addr().String())
+				Eventually(func() int {
+					resp, err := http.Get(base + "/readyz")
+					if err != nil {
+						return 0
+					}
+					return resp.StatusCode
+				}).Should(Equal(http.StatusOK))
+
+				By("beginning the drain")
+				resp, err := http.Get(base + "/drain")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+				status := DrainStatus{}
+				Expect(json.NewDecoder(resp.Body).Decode(&status)).To(Succeed())
+				Expect(status.Draining).To(BeTrue())
+				Expect(status.Drained).To(BeFalse())
+				Expect(status.Pending).To(Equal(2))
+				Expect(d.drainCalls()).To(Equal(1))
+
+				By("not being ready while draining")
+				resp, err = http.Get(base + "/readyz/" + drainCheckName)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.StatusCode).To(Equal(http.StatusInternalServerError))
+
+				By("reporting when drained")
+				d.setPending(0)
+				resp, err = http.Get(base + "/drain")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				Expect(json.NewDecoder(resp.Body).Decode(&status)).To(Succeed())
+				Expect(status.Drained).To(BeTrue())
+				Expect(d.drainCalls()).To(Equal(1))
+			})
+		})
+		Context("should start serving debug endpoints", func() {
+			var listener net.Listener
+			var opts Options
+
+			BeforeEach(func() {
+				listener = nil
+				opts = Options{
+					DebugBindAddress: ":0",
+					newDebugListener: func(addr string) (net.Listener, error) {
+						var err error
+						listener, err = defaultDebugListener(addr)
+						return listener, err
+					},
+				}
+			})
+
+			AfterEach(func() {
+				if listener != nil {
+					listener.Close()
+				}
+			})
+
+			It("should not serve debug endpoints by default", func() {
+				opts.DebugBindAddress = ""
+				_, err := New(cfg, opts)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(listener).To(BeNil())
+			})
+
+			It("should serve dumps of the cache", func(done Done) {
+				m, err := New(cfg, opts)
+				Expect(err).NotTo(HaveOccurred())
+
+				s := make(chan struct{})
+				defer close(s)
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(s)).NotTo(HaveOccurred())
+					close(done)
+				}()
+
+				By("starting an informer for Pods")
+				_, err = m.GetCache().GetInformer(&corev1.Pod{})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("dumping the Pod informer")
+				endpoint := fmt.Sprintf("http://%s/debug/cache", listener.Addr().String())
+				resp, err := http.Get(endpoint + "?version=v1&kind=Pod")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				snapshot := cache.Snapshot{}
+				Expect(json.NewDecoder(resp.Body).Decode(&snapshot)).To(Succeed())
+				Expect(snapshot.Kind).To(Equal("Pod"))
+				Expect(snapshot.Synced).To(BeTrue())
+
+				By("rejecting dumps without a kind")
+				resp, err = http.Get(endpoint + "?version=v1")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+				By("reporting kinds without an informer as not found")
+				resp, err = http.Get(endpoint + "?group=apps&version=v1&kind=Deployment")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			})
+
+			It("should serve the version of the binary", func(done Done) {
+				m, err := New(cfg, opts)
+				Expect(err).NotTo(HaveOccurred())
+
+				s := make(chan struct{})
+				defer close(s)
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(s)).NotTo(HaveOccurred())
+					close(done)
+				}()
+
+				endpoint := fmt.Sprintf("http://%s/version", listener.Addr().String())
+				var resp *http.Response
+				Eventually(func() error {
+					var err error
+					resp, err = http.Get(endpoint)
+					return err
+				}).Should(Succeed())
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				info := version.Info{}
+				Expect(json.NewDecoder(resp.Body).Decode(&info)).To(Succeed())
+				Expect(info).To(Equal(version.Get()))
+			})
+
+			It("should serve the runtime config", func(done Done) {
+				opts.FeatureGates = map[string]bool{"Foo": true}
+				opts.DrainTimeout = 10 * time.Second
+				m, err := New(cfg, opts)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(m.Add(&reporter{name: "foo", config: map[string]int{"workers": 2}})).To(Succeed())
+
+				s := make(chan struct{})
+				defer close(s)
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(s)).NotTo(HaveOccurred())
+					close(done)
+				}()
+
+				endpoint := fmt.Sprintf("http://%s/debug/config", listener.Addr().String())
+				var resp *http.Response
+				Eventually(func() error {
+					var err error
+					resp, err = http.Get(endpoint)
+					return err
+				}).Should(Succeed())
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				config := struct {
+					RuntimeConfig
+					Controllers map[string]map[string]int `json:"controllers"`
+				}{}
+				Expect(json.NewDecoder(resp.Body).Decode(&config)).To(Succeed())
+				Expect(config.Manager.REST.Host).To(Equal(cfg.Host))
+				Expect(config.Manager.DebugBindAddress).To(Equal(":0"))
+				Expect(config.Manager.DrainTimeout).To(Equal("10s"))
+				Expect(config.FeatureGates).To(Equal(map[string]bool{"Foo": true}))
+				Expect(config.Controllers).To(Equal(map[string]map[string]int{"foo": {"workers": 2}}))
+			})
+
+			It("should serve the provenance of requests", func(done Done) {
+				m, err := New(cfg, opts)
+				Expect(err).NotTo(HaveOccurred())
+				key := types.NamespacedName{Namespace: "default", Name: "bar"}
+				Expect(m.Add(&reporter{name: "foo", provenance: map[types.NamespacedName]interface{}{key: "kind source"}})).To(Succeed())
+				Expect(m.Add(&reporter{name: "baz", provenance: map[types.NamespacedName]interface{}{key: "channel source"}})).To(Succeed())
+
+				s := make(chan struct{})
+				defer close(s)
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(s)).NotTo(HaveOccurred())
+					close(done)
+				}()
+
+				get := func(query string) *http.Response {
+					endpoint := fmt.Sprintf("http://%s/debug/provenance?%s", listener.Addr().String(), query)
+					var resp *http.Response
+					Eventually(func() error {
+						var err error
+						resp, err = http.Get(endpoint)
+						return err
+					}).Should(Succeed())
+					return resp
+				}
+
+				resp := get("namespace=default&name=bar")
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				provenance := map[string]string{}
+				Expect(json.NewDecoder(resp.Body).Decode(&provenance)).To(Succeed())
+				Expect(provenance).To(Equal(map[string]string{"foo": "kind source", "baz": "channel source"}))
+
+				resp = get("namespace=default&name=bar&controller=baz")
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				provenance = map[string]string{}
+				Expect(json.NewDecoder(resp.Body).Decode(&provenance)).To(Succeed())
+				Expect(provenance).To(Equal(map[string]string{"baz": "channel source"}))
+
+				resp = get("namespace=default&name=other")
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				provenance = map[string]string{}
+				Expect(json.NewDecoder(resp.Body).Decode(&provenance)).To(Succeed())
+				Expect(provenance).To(BeEmpty())
+
+				Expect(get("namespace=default").StatusCode).To(Equal(http.StatusBadRequest))
+			})
+
+			It("should adjust the log levels", func(done Done) {
+				levels := logf.NewLevels(0)
+				opts.LogLevels = levels
+				m, err := New(cfg, opts)
+				Expect(err).NotTo(HaveOccurred())
+
+				s := make(chan struct{})
+				defer close(s)
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(s)).NotTo(HaveOccurred())
+					close(done)
+				}()
+
+				do := func(method, query string) *http.Response {
+					endpoint := fmt.Sprintf("http://%s/debug/loglevel?%s", listener.Addr().String(), query)
+					req, err := http.NewRequest(method, endpoint, nil)
+					Expect(err).NotTo(HaveOccurred())
+					var resp *http.Response
+					Eventually(func() error {
+						var err error
+						resp, err = http.DefaultClient.Do(req)
+						return err
+					}).Should(Succeed())
+					return resp
+				}
+				foo := logf.Component{Key: "controller", Value: "foo"}
+
+				resp := do(http.MethodPut, "controller=foo&v=4")
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				Expect(do(http.MethodPut, "v=2").StatusCode).To(Equal(http.StatusOK))
+				Expect(levels.Verbosity()).To(Equal(2))
+				Expect(levels.Components()).To(Equal([]logf.ComponentLevel{{Component: foo, Verbosity: 4}}))
+
+				resp = do(http.MethodGet, "")
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				status := LogLevelStatus{}
+				Expect(json.NewDecoder(resp.Body).Decode(&status)).To(Succeed())
+				Expect(status).To(Equal(LogLevelStatus{
+					Verbosity:  2,
+					Components: []logf.ComponentLevel{{Component: foo, Verbosity: 4}},
+				}))
+
+				Expect(do(http.MethodDelete, "controller=foo").StatusCode).To(Equal(http.StatusOK))
+				Expect(levels.Components()).To(BeEmpty())
+
+				Expect(do(http.MethodPut, "controller=foo").StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(do(http.MethodPut, "controller=foo&path=/validate&v=1").StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(do(http.MethodDelete, "").StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(do(http.MethodPost, "v=1").StatusCode).To(Equal(http.StatusMethodNotAllowed))
+			})
+		})
+	})
+
+	Describe("LogLevelConfigMap", func() {
+		It("should parse the verbosity and the component levels", func() {
+			verbosity, components, err := parseLogLevels(map[string]string{
+				"verbosity":  "1",
+				"controller": "foo=4, bar=2",
+				"path":       "/validate-v1-pod=3",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verbosity).To(Equal(1))
+			Expect(components).To(ConsistOf(
+				logf.ComponentLevel{Component: logf.Component{Key: "controller", Value: "foo"}, Verbosity: 4},
+				logf.ComponentLevel{Component: logf.Component{Key: "controller", Value: "bar"}, Verbosity: 2},
+				logf.ComponentLevel{Component: logf.Component{Key: "path", Value: "/validate-v1-pod"}, Verbosity: 3},
+			))
+		})
+
+		It("should default the verbosity to -1", func() {
+			verbosity, _, err := parseLogLevels(map[string]string{"controller": "foo=4"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verbosity).To(Equal(-1))
+		})
+
+		It("should return an error for invalid levels", func() {
+			_, _, err := parseLogLevels(map[string]string{"verbosity": "high"})
+			Expect(err).To(HaveOccurred())
+			_, _, err = parseLogLevels(map[string]string{"controller": "foo"})
+			Expect(err).To(HaveOccurred())
+			_, _, err = parseLogLevels(map[string]string{"controller": "foo=-1"})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should set and reset the levels from the ConfigMap", func() {
+			levels := logf.NewLevels(1)
+			w := &logLevelWatcher{levels: levels, verbosity: 1}
+			w.set(&corev1.ConfigMap{Data: map[string]string{"verbosity": "3", "controller": "foo=4"}})
+			Expect(levels.Verbosity()).To(Equal(3))
+			Expect(levels.Components()).To(HaveLen(1))
+
+			w.set(&corev1.ConfigMap{Data: map[string]string{"controller": "bar=2"}})
+			Expect(levels.Verbosity()).To(Equal(1))
+			Expect(levels.Components()).To(Equal([]logf.ComponentLevel{
+				{Component: logf.Component{Key: "controller", Value: "bar"}, Verbosity: 2},
+			}))
+
+			w.reset()
+			Expect(levels.Verbosity()).To(Equal(1))
+			Expect(levels.Components()).To(BeEmpty())
+		})
+	})
+
+	Describe("RuntimeConfig", func() {
+		It("should redact the credentials of the rest.Config", func() {
+			config := newRESTConfig(&rest.Config{
+				Host:        "https://example.com",
+				Username:    "admin",
+				Password:    "secret",
+				BearerToken: "token",
+				TLSClientConfig: rest.TLSClientConfig{
+					CertFile: "/tls.crt",
+					CertData: []byte("cert"),
+					KeyData:  []byte("key"),
+				},
+			})
+			Expect(config.Host).To(Equal("https://example.com"))
+			Expect(config.Username).To(Equal("admin"))
+			Expect(config.CertFile).To(Equal("/tls.crt"))
+			Expect(config.Password).To(Equal(redacted))
+			Expect(config.BearerToken).To(Equal(redacted))
+			Expect(config.CertData).To(Equal(redacted))
+			Expect(config.KeyData).To(Equal(redacted))
+		})
+
+		It("should not report unset credentials", func() {
+			config := newRESTConfig(&rest.Config{Host: "https://example.com"})
+			Expect(config.Password).To(BeEmpty())
+			Expect(config.BearerToken).To(BeEmpty())
+			Expect(config.KeyData).To(BeEmpty())
+		})
+	})
+
+	Describe("DrainTimeout", func() {
+		It("should drain the Runnables before stopping them", func(done Done) {
+			m, err := New(cfg, Options{DrainTimeout: 10 * time.Second})
+			Expect(err).NotTo(HaveOccurred())
+			d := &drainable{pending: 1}
+			Expect(m.Add(d)).To(Succeed())
+
+			s := make(chan struct{})
+			stopped := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(s)).NotTo(HaveOccurred())
+				close(stopped)
+			}()
+			close(s)
+
+			Eventually(d.drainCalls).Should(Equal(1))
+			Consistently(stopped).ShouldNot(BeClosed())
+			d.setPending(0)
+			Eventually(stopped).Should(BeClosed())
+			close(done)
+		}, 5)
+
+		It("should stop the Runnables once the timeout has elapsed", func(done Done) {
+			m, err := New(cfg, Options{DrainTimeout: 200 * time.Millisecond})
+			Expect(err).NotTo(HaveOccurred())
+			d := &drainable{pending: 1}
+			Expect(m.Add(d)).To(Succeed())
+
+			s := make(chan struct{})
+			close(s)
+			Expect(m.Start(s)).NotTo(HaveOccurred())
+			Expect(d.drainCalls()).To(Equal(1))
+			close(done)
+		}, 5)
+	})
+
+	Describe("ClusterProvider", func() {
+		It("should engage the cluster.Aware Runnables with the discovered clusters", func(done Done) {
+			remote, err := cluster.New(&rest.Config{Host: "https://remote.example.com"}, cluster.Options{
+				MapperProvider: func(*rest.Config) (meta.RESTMapper, error) {
+					return meta.NewDefaultRESTMapper(nil), nil
+				},
+				NewCache: func(*rest.Config, cache.Options) (cache.Cache, error) {
+					return &informertest.FakeInformers{}, nil
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			provider := &fakeProvider{cluster: remote, disengage: make(chan struct{})}
+			m, err := New(cfg, Options{ClusterProvider: provider})
+			Expect(err).NotTo(HaveOccurred())
+			mgr, ok := m.(*controllerManager)
+			Expect(ok).To(BeTrue())
+
+			a1 := &awareRunnable{}
+			Expect(m.Add(a1)).To(Succeed())
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(stop)).NotTo(HaveOccurred())
+			}()
+
+			By("engaging the Runnables added before the cluster was discovered")
+			Eventually(a1.engagedClusters).Should(Equal([]string{"remote"}))
+
+			By("engaging the Runnables added after the cluster was discovered")
+			a2 := &awareRunnable{}
+			Expect(m.Add(a2)).To(Succeed())
+			Expect(a2.engagedClusters()).To(Equal([]string{"remote"}))
+
+			By("forgetting the cluster once it is disengaged")
+			close(provider.disengage)
+			Eventually(func() int {
+				mgr.mu.Lock()
+				defer mgr.mu.Unlock()
+				return len(mgr.clusters)
+			}).Should(Equal(0))
+			a3 := &awareRunnable{}
+			Expect(m.Add(a3)).To(Succeed())
+			Expect(a3.engagedClusters()).To(BeEmpty())
+
+			close(done)
+		}, 5)
+
+		It("should fail to add a Runnable which can't be engaged", func() {
+			m, err := New(cfg, Options{})
+			Expect(err).NotTo(HaveOccurred())
+			mgr, ok := m.(*controllerManager)
+			Expect(ok).To(BeTrue())
+			Expect(mgr.Engage("remote", nil, make(chan struct{}))).To(Succeed())
+
+			Expect(m.Add(&awareRunnable{err: fmt.Errorf("expected error")})).To(HaveOccurred())
+		})
+
+		It("should disengage the Runnables already engaged if one can't be engaged", func() {
+			m, err := New(cfg, Options{})
+			Expect(err).NotTo(HaveOccurred())
+			mgr, ok := m.(*controllerManager)
+			Expect(ok).To(BeTrue())
+			a1 := &awareRunnable{}
+			Expect(m.Add(a1)).To(Succeed())
+			Expect(m.Add(&awareRunnable{err: fmt.Errorf("expected error")})).To(Succeed())
+
+			stop := make(chan struct{})
+			defer close(stop)
+			Expect(mgr.Engage("remote", nil, stop)).To(MatchError(ContainSubstring("disengaged the 1 Runnables engaged")))
+			Expect(a1.engagedClusters()).To(Equal([]string{"remote"}))
+			Expect(a1.stops[0]).To(BeClosed())
+			mgr.mu.Lock()
+			defer mgr.mu.Unlock()
+			Expect(mgr.clusters).To(BeEmpty())
+		})
+
+		It("should not hold the Manager lock while engaging the Runnables", func(done Done) {
+			m, err := New(cfg, Options{})
+			Expect(err).NotTo(HaveOccurred())
+			mgr, ok := m.(*controllerManager)
+			Expect(ok).To(BeTrue())
+			added := &awareRunnable{}
+			Expect(m.Add(&awareRunnable{engage: func() {
+				defer GinkgoRecover()
+				Expect(m.Add(added)).To(Succeed())
+			}})).To(Succeed())
+
+			Expect(mgr.Engage("remote", nil, make(chan struct{}))).To(Succeed())
+			Expect(added.engagedClusters()).To(Equal([]string{"remote"}))
+			close(done)
 		})
 	})
 
@@ -569,6 +1384,20 @@ var _ = Describe("manger.Manager", func() {
 		})
 	})
 
+	It("should share a rate limiter between the clients created from its Config", func() {
+		m, err := New(cfg, Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m.GetConfig().RateLimiter).NotTo(BeNil())
+		Expect(m.GetConfig().RateLimiter.QPS()).To(Equal(rest.DefaultQPS))
+		Expect(cfg.RateLimiter).To(BeNil())
+	})
+
+	It("should use the ClientRateLimiter passed in the options", func() {
+		m, err := New(cfg, Options{ClientRateLimiter: flowcontrol.NewTokenBucketRateLimiter(42, 100)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m.GetConfig().RateLimiter.QPS()).To(BeEquivalentTo(42))
+	})
+
 	It("should provide a function to get the Config", func() {
 		m, err := New(cfg, Options{})
 		Expect(err).NotTo(HaveOccurred())
@@ -606,6 +1435,12 @@ var _ = Describe("manger.Manager", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(m.GetRecorder("test")).NotTo(BeNil())
 	})
+
+	It("should provide a function to get the EventRecorder for a name", func() {
+		m, err := New(cfg, Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m.GetEventRecorderFor("test")).NotTo(BeNil())
+	})
 })
 
 var _ reconcile.Reconciler = &failRec{}
@@ -686,3 +1521,159 @@ func (i *injectable) InjectStopChannel(stop <-chan struct{}) error {
 func (i *injectable) Start(<-chan struct{}) error {
 	return nil
 }
+
+var _ Drainer = &drainable{}
+
+// drainable is a Runnable implementing Drainer whose pending work is set by the test.
+type drainable struct {
+	mu      sync.Mutex
+	pending int
+	drained int
+}
+
+func (d *drainable) Start(stop <-chan struct{}) error {
+	<-stop
+	return nil
+}
+
+func (d *drainable) Drain() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.drained++
+}
+
+func (d *drainable) DrainProgress() (int, int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.pending, 0
+}
+
+func (d *drainable) setPending(pending int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.pending = pending
+}
+
+func (d *drainable) drainCalls() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.drained
+}
+
+var _ cluster.Provider = &fakeProvider{}
+
+// fakeProvider is a cluster.Provider discovering a single cluster, which is disengaged once
+// disengage is closed.
+type fakeProvider struct {
+	cluster   cluster.Cluster
+	disengage chan struct{}
+}
+
+func (p *fakeProvider) Run(aware cluster.Aware, stop <-chan struct{}) error {
+	if err := aware.Engage("remote", p.cluster, p.disengage); err != nil {
+		return err
+	}
+	<-stop
+	return nil
+}
+
+var _ cluster.Aware = &awareRunnable{}
+
+// awareRunnable is a Runnable implementing cluster.Aware which records the clusters it is engaged with.
+type awareRunnable struct {
+	mu      sync.Mutex
+	engaged []string
+	stops   []<-chan struct{}
+	err     error
+
+	// engage is called when engaged, if set.
+	engage func()
+}
+
+func (a *awareRunnable) Start(stop <-chan struct{}) error {
+	<-stop
+	return nil
+}
+
+func (a *awareRunnable) Engage(name string, _ cluster.Cluster, stop <-chan struct{}) error {
+	if a.engage != nil {
+		a.engage()
+	}
+	if a.err != nil {
+		return a.err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.engaged = append(a.engaged, name)
+	a.stops = append(a.stops, stop)
+	return nil
+}
+
+func (a *awareRunnable) engagedClusters() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string(nil), a.engaged...)
+}
+
+var _ ConfigReporter = &reporter{}
+
+type reporter struct {
+	name       string
+	config     interface{}
+	provenance map[types.NamespacedName]interface{}
+}
+
+func (r *reporter) Start(stop <-chan struct{}) error {
+	<-stop
+	return nil
+}
+
+func (r *reporter) ReportConfig() (string, interface{}) {
+	return r.name, r.config
+}
+
+func (r *reporter) ReportProvenance(key types.NamespacedName) (string, interface{}, bool) {
+	p, ok := r.provenance[key]
+	return r.name, p, ok
+}
+
+// nonLeaderRunnable is a Runnable which doesn't need leader election.
+type nonLeaderRunnable RunnableFunc
+
+func (r nonLeaderRunnable) Start(s <-chan struct{}) error {
+	return r(s)
+}
+
+func (nonLeaderRunnable) NeedLeaderElection() bool {
+	return false
+}
+
+// phasedRunnable is a Runnable started in phase, which sends its phase to started once started.  It
+// implements CacheSyncer if synced is set.
+type phasedRunnable struct {
+	phase   StartPhase
+	started chan<- StartPhase
+	synced  chan struct{}
+}
+
+func (r *phasedRunnable) Start(s <-chan struct{}) error {
+	r.started <- r.phase
+	<-s
+	return nil
+}
+
+func (r *phasedRunnable) StartPhase() StartPhase {
+	return r.phase
+}
+
+func (r *phasedRunnable) WaitForCacheSync(s <-chan struct{}) bool {
+	if r.synced == nil {
+		return true
+	}
+	select {
+	case <-r.synced:
+		return true
+	case <-s:
+		return false
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signals
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("manager signals", func() {
+	It("should cancel the context on the first signal", func() {
+		ctx := SetupSignalHandler()
+		Expect(ctx.Err()).NotTo(HaveOccurred())
+
+		proc, err := os.FindProcess(os.Getpid())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(proc.Signal(os.Interrupt)).To(Succeed())
+
+		Eventually(ctx.Done()).Should(BeClosed())
+		Expect(ctx.Err()).To(HaveOccurred())
+	})
+
+	It("should panic when set up twice", func() {
+		Expect(func() { SetupSignalHandler() }).To(Panic())
+	})
+})
@@ -0,0 +1,368 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// labelSeparator joins the label values of the pending recordings of a batch,
+// and can't be part of a valid label value.
+const labelSeparator = "\xff"
+
+// batchingEnabled is 1 if the recordings of the batches are buffered, and must
+// be accessed atomically, so that recordings don't contend on a lock.
+var batchingEnabled int32
+
+// batches are the batches flushed by Flush, and stop stops flushing them
+// periodically.
+var batches = struct {
+	sync.Mutex
+	list []batch
+	stop chan struct{}
+}{}
+
+// batch buffers the recordings of a metric.
+type batch interface {
+	flush()
+	reset()
+}
+
+// WithBatching buffers the recordings of the BatchedCounters and
+// BatchedHistograms of the controller-runtime, e.g. of the reconcile total and
+// time metrics, and records them every interval, instead of recording each of
+// them as it happens.  This cuts the contention on the locks of the metrics in
+// busy controllers, at the cost of exposing the recordings up to interval later,
+// except to Snapshot, which flushes them first.
+// An interval of 0 records the buffered recordings and stops buffering.
+func WithBatching(interval time.Duration) Option {
+	return func(*config) {
+		batches.Lock()
+		defer batches.Unlock()
+		if batches.stop != nil {
+			close(batches.stop)
+			batches.stop = nil
+		}
+		if interval <= 0 {
+			atomic.StoreInt32(&batchingEnabled, 0)
+			flush(batches.list)
+			return
+		}
+		atomic.StoreInt32(&batchingEnabled, 1)
+		batches.stop = make(chan struct{})
+		go flushEvery(interval, batches.stop)
+	}
+}
+
+func flushEvery(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			Flush()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Flush records the recordings buffered by the batches, e.g. before the
+// metrics are exported for the last time on shutdown.
+func Flush() {
+	batches.Lock()
+	list := batches.list
+	batches.Unlock()
+	flush(list)
+}
+
+func flush(list []batch) {
+	for _, b := range list {
+		b.flush()
+	}
+}
+
+// resetBatches discards the recordings buffered by the batches.
+func resetBatches() {
+	batches.Lock()
+	list := batches.list
+	batches.Unlock()
+	for _, b := range list {
+		b.reset()
+	}
+}
+
+func addBatch(b batch) {
+	batches.Lock()
+	defer batches.Unlock()
+	batches.list = append(batches.list, b)
+}
+
+// BatchedCounter records the increments of a CounterVec in batches once
+// batching is enabled with WithBatching, and as they happen otherwise.
+type BatchedCounter struct {
+	vec *prometheus.CounterVec
+	// pending maps the joined label values of the counters to their *pendingCount.
+	pending sync.Map
+}
+
+// NewBatchedCounter returns a BatchedCounter recording the increments of vec.
+func NewBatchedCounter(vec *prometheus.CounterVec) *BatchedCounter {
+	b := &BatchedCounter{vec: vec}
+	addBatch(b)
+	return b
+}
+
+// Inc increments the counter with labelValues by 1.
+func (b *BatchedCounter) Inc(labelValues ...string) {
+	b.Add(1, labelValues...)
+}
+
+// Add increments the counter with labelValues by v.
+func (b *BatchedCounter) Add(v float64, labelValues ...string) {
+	if atomic.LoadInt32(&batchingEnabled) == 0 {
+		b.vec.WithLabelValues(labelValues...).Add(v)
+		return
+	}
+	key := strings.Join(labelValues, labelSeparator)
+	for {
+		p, ok := b.pending.Load(key)
+		if !ok {
+			p, _ = b.pending.LoadOrStore(key, &pendingCount{labelValues: append([]string(nil), labelValues...)})
+		}
+		// The pending count may have been deleted by flush meanwhile
+		if p.(*pendingCount).add(v) {
+			return
+		}
+	}
+}
+
+// flush records the pending increments, and deletes the pending counts which had none, so that
+// the counts of label values which are no longer recorded don't accumulate.
+func (b *BatchedCounter) flush() {
+	b.pending.Range(func(key, p interface{}) bool {
+		pc := p.(*pendingCount)
+		if v := pc.take(); v != 0 {
+			b.vec.WithLabelValues(pc.labelValues...).Add(v)
+		} else if pc.delete() {
+			b.pending.Delete(key)
+		}
+		return true
+	})
+}
+
+func (b *BatchedCounter) reset() {
+	b.pending.Range(func(_, p interface{}) bool {
+		p.(*pendingCount).take()
+		return true
+	})
+}
+
+// deletedCount are the bits of a pendingCount deleted from its BatchedCounter, a NaN which
+// isn't the result of any addition.
+const deletedCount uint64 = 0x7ff8dead00000000
+
+// pendingCount is the pending increment of a counter.
+type pendingCount struct {
+	labelValues []string
+	// bits are the bits of the float64 increment, or deletedCount, and must be accessed atomically.
+	bits uint64
+}
+
+// add adds v to the pending increment, and returns false if the pending count was deleted.
+func (p *pendingCount) add(v float64) bool {
+	for {
+		old := atomic.LoadUint64(&p.bits)
+		if old == deletedCount {
+			return false
+		}
+		if atomic.CompareAndSwapUint64(&p.bits, old, math.Float64bits(math.Float64frombits(old)+v)) {
+			return true
+		}
+	}
+}
+
+// take returns the pending increment and resets it.
+func (p *pendingCount) take() float64 {
+	for {
+		old := atomic.LoadUint64(&p.bits)
+		if old == deletedCount {
+			return 0
+		}
+		if atomic.CompareAndSwapUint64(&p.bits, old, 0) {
+			return math.Float64frombits(old)
+		}
+	}
+}
+
+// delete marks the pending count deleted, unless an increment is pending.
+func (p *pendingCount) delete() bool {
+	return atomic.CompareAndSwapUint64(&p.bits, 0, deletedCount)
+}
+
+// BatchedHistogram records the observations of a HistogramVec in batches once
+// batching is enabled with WithBatching, and as they happen otherwise.  The
+// observations are buffered as the count and sum of the observations in each
+// bucket of the histogram, and recorded as that many observations of their mean,
+// which keeps the buckets, count and sum of the histogram.
+type BatchedHistogram struct {
+	vec *prometheus.HistogramVec
+	// pending maps the joined label values of the histograms to their *pendingObservations.
+	pending sync.Map
+
+	// upperBounds are the upper bounds of the buckets of the histograms, without +Inf.
+	upperBounds     []float64
+	upperBoundsOnce sync.Once
+}
+
+// NewBatchedHistogram returns a BatchedHistogram recording the observations of vec.
+func NewBatchedHistogram(vec *prometheus.HistogramVec) *BatchedHistogram {
+	b := &BatchedHistogram{vec: vec}
+	addBatch(b)
+	return b
+}
+
+// Observe adds v to the histogram with labelValues.
+func (b *BatchedHistogram) Observe(v float64, labelValues ...string) {
+	if atomic.LoadInt32(&batchingEnabled) == 0 {
+		b.vec.WithLabelValues(labelValues...).Observe(v)
+		return
+	}
+	key := strings.Join(labelValues, labelSeparator)
+	for {
+		p, ok := b.pending.Load(key)
+		if !ok {
+			p, _ = b.pending.LoadOrStore(key, b.newPendingObservations(labelValues))
+		}
+		// The pending observations may have been deleted by flush meanwhile
+		if p.(*pendingObservations).add(v) {
+			return
+		}
+	}
+}
+
+// newPendingObservations returns the pending observations of the histogram with labelValues.
+func (b *BatchedHistogram) newPendingObservations(labelValues []string) *pendingObservations {
+	b.upperBoundsOnce.Do(func() {
+		// The buckets of the histograms of the vec are only exposed by the histograms
+		var m dto.Metric
+		if metric, ok := b.vec.WithLabelValues(labelValues...).(prometheus.Metric); ok && metric.Write(&m) == nil {
+			for _, bucket := range m.GetHistogram().GetBucket() {
+				if !math.IsInf(bucket.GetUpperBound(), 1) {
+					b.upperBounds = append(b.upperBounds, bucket.GetUpperBound())
+				}
+			}
+		}
+	})
+	return &pendingObservations{
+		labelValues: append([]string(nil), labelValues...),
+		upperBounds: b.upperBounds,
+		counts:      make([]uint64, len(b.upperBounds)+1),
+		sums:        make([]float64, len(b.upperBounds)+1),
+	}
+}
+
+// flush records the pending observations, and deletes the pending observations which had none, so
+// that the histograms of label values which are no longer recorded don't accumulate.
+func (b *BatchedHistogram) flush() {
+	b.pending.Range(func(key, p interface{}) bool {
+		po := p.(*pendingObservations)
+		counts, sums, deleted := po.take(true)
+		if deleted {
+			b.pending.Delete(key)
+			return true
+		}
+		h := b.vec.WithLabelValues(po.labelValues...)
+		for i, count := range counts {
+			if count == 0 {
+				continue
+			}
+			v := po.mean(i, sums[i]/float64(count))
+			for j := uint64(0); j < count; j++ {
+				h.Observe(v)
+			}
+		}
+		return true
+	})
+}
+
+func (b *BatchedHistogram) reset() {
+	b.pending.Range(func(_, p interface{}) bool {
+		p.(*pendingObservations).take(false)
+		return true
+	})
+}
+
+// pendingObservations are the pending observations of a histogram, as the count and sum of the
+// observations in each of its buckets, the last one being +Inf.
+type pendingObservations struct {
+	labelValues []string
+	upperBounds []float64
+
+	mu      sync.Mutex
+	counts  []uint64
+	sums    []float64
+	total   uint64
+	deleted bool
+}
+
+// add adds v to the pending observations, and returns false if they were deleted.
+func (p *pendingObservations) add(v float64) bool {
+	i := sort.SearchFloat64s(p.upperBounds, v)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.deleted {
+		return false
+	}
+	p.counts[i]++
+	p.sums[i] += v
+	p.total++
+	return true
+}
+
+// take returns the counts and sums of the pending observations and resets them.  If there are none
+// and del is set, the pending observations are deleted instead.
+func (p *pendingObservations) take(del bool) (counts []uint64, sums []float64, deleted bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.total == 0 {
+		p.deleted = p.deleted || del
+		return nil, nil, p.deleted
+	}
+	counts, sums = p.counts, p.sums
+	p.counts, p.sums, p.total = make([]uint64, len(counts)), make([]float64, len(sums)), 0
+	return counts, sums, false
+}
+
+// mean returns the mean of the observations in bucket i, kept within the bucket despite rounding.
+func (p *pendingObservations) mean(i int, mean float64) float64 {
+	if i < len(p.upperBounds) && mean > p.upperBounds[i] {
+		return p.upperBounds[i]
+	}
+	if i > 0 && mean <= p.upperBounds[i-1] {
+		return math.Nextafter(p.upperBounds[i-1], math.Inf(1))
+	}
+	return mean
+}
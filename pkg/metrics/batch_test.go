@@ -0,0 +1,171 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics_test
+
+import (
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("WithBatching", func() {
+	var counterVec *prometheus.CounterVec
+	var histogramVec *prometheus.HistogramVec
+	var counter *metrics.BatchedCounter
+	var histogram *metrics.BatchedHistogram
+
+	BeforeEach(func() {
+		counterVec = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_total"}, []string{"foo"})
+		histogramVec = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_seconds"}, []string{"foo"})
+		counter = metrics.NewBatchedCounter(counterVec)
+		histogram = metrics.NewBatchedHistogram(histogramVec)
+	})
+
+	AfterEach(func() {
+		metrics.Configure(metrics.WithBatching(0))
+	})
+
+	count := func() float64 {
+		var m dto.Metric
+		Expect(counterVec.WithLabelValues("bar").Write(&m)).To(Succeed())
+		return m.GetCounter().GetValue()
+	}
+
+	observations := func() uint64 {
+		var m dto.Metric
+		Expect(histogramVec.WithLabelValues("bar").(prometheus.Metric).Write(&m)).To(Succeed())
+		return m.GetHistogram().GetSampleCount()
+	}
+
+	It("should record the recordings as they happen by default", func() {
+		counter.Inc("bar")
+		histogram.Observe(1, "bar")
+		Expect(count()).To(Equal(1.0))
+		Expect(observations()).To(Equal(uint64(1)))
+	})
+
+	It("should record the recordings once flushed", func() {
+		metrics.Configure(metrics.WithBatching(time.Hour))
+		counter.Inc("bar")
+		counter.Add(2, "bar")
+		histogram.Observe(1, "bar")
+		histogram.Observe(2, "bar")
+		Expect(count()).To(Equal(0.0))
+		Expect(observations()).To(Equal(uint64(0)))
+
+		metrics.Flush()
+		Expect(count()).To(Equal(3.0))
+		Expect(observations()).To(Equal(uint64(2)))
+
+		By("not recording the flushed recordings again")
+		metrics.Flush()
+		Expect(count()).To(Equal(3.0))
+		Expect(observations()).To(Equal(uint64(2)))
+	})
+
+	It("should keep the buckets and sum of the observations", func() {
+		metrics.Configure(metrics.WithBatching(time.Hour))
+		direct := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "direct_seconds"})
+		for _, v := range []float64{0.001, 0.02, 0.03, 0.1, 0.1, 0.1, 0.7, 3, 100} {
+			histogram.Observe(v, "bar")
+			direct.Observe(v)
+		}
+		metrics.Flush()
+
+		var batched, expected dto.Metric
+		Expect(histogramVec.WithLabelValues("bar").(prometheus.Metric).Write(&batched)).To(Succeed())
+		Expect(direct.Write(&expected)).To(Succeed())
+		Expect(batched.GetHistogram().GetSampleCount()).To(Equal(expected.GetHistogram().GetSampleCount()))
+		Expect(batched.GetHistogram().GetSampleSum()).To(BeNumerically("~", expected.GetHistogram().GetSampleSum(), 1e-9))
+		for i, bucket := range expected.GetHistogram().GetBucket() {
+			Expect(batched.GetHistogram().GetBucket()[i].GetCumulativeCount()).To(Equal(bucket.GetCumulativeCount()))
+		}
+	})
+
+	It("should keep recording the label values idle for a whole interval", func() {
+		metrics.Configure(metrics.WithBatching(time.Hour))
+		counter.Inc("bar")
+		histogram.Observe(1, "bar")
+		metrics.Flush()
+
+		By("flushing the label values without recordings")
+		metrics.Flush()
+
+		counter.Inc("bar")
+		histogram.Observe(1, "bar")
+		metrics.Flush()
+		Expect(count()).To(Equal(2.0))
+		Expect(observations()).To(Equal(uint64(2)))
+	})
+
+	It("should not lose the recordings made while flushing", func() {
+		metrics.Configure(metrics.WithBatching(time.Hour))
+		stop := make(chan struct{})
+		flushed := make(chan struct{})
+		go func() {
+			defer close(flushed)
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					metrics.Flush()
+				}
+			}
+		}()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 1000; j++ {
+					counter.Inc("bar")
+					histogram.Observe(1, "bar")
+				}
+			}()
+		}
+		wg.Wait()
+		close(stop)
+		<-flushed
+
+		metrics.Flush()
+		Expect(count()).To(Equal(4000.0))
+		Expect(observations()).To(Equal(uint64(4000)))
+	})
+
+	It("should record the recordings every interval", func() {
+		metrics.Configure(metrics.WithBatching(10 * time.Millisecond))
+		counter.Inc("bar")
+		Eventually(count).Should(Equal(1.0))
+	})
+
+	It("should record the buffered recordings once batching is disabled", func() {
+		metrics.Configure(metrics.WithBatching(time.Hour))
+		counter.Inc("bar")
+		metrics.Configure(metrics.WithBatching(0))
+		Expect(count()).To(Equal(1.0))
+
+		counter.Inc("bar")
+		Expect(count()).To(Equal(2.0))
+	})
+})
@@ -0,0 +1,36 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/version"
+)
+
+// BuildInfo is a prometheus gauge metric which is always 1, and labelled with the
+// version information of the binary, so that the versions running across a fleet
+// of operators can be queried.
+var BuildInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "controller_runtime_build_info",
+	Help: "Version information of the controller-runtime binary, with a constant value of 1",
+}, []string{"version", "git_commit", "go_version", "k8s_client_version"})
+
+func init() {
+	info := version.Get()
+	BuildInfo.WithLabelValues(info.Version, info.GitCommit, info.GoVersion, info.KubernetesClientVersion).Set(1)
+	AddDefaultViews(View{Collector: BuildInfo, Constant: true})
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/version"
+)
+
+var _ = Describe("BuildInfo", func() {
+	It("should be labelled with the version information of the binary", func() {
+		families, err := metrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+
+		labels := map[string]string{}
+		for _, family := range families {
+			if family.GetName() != "controller_runtime_build_info" {
+				continue
+			}
+			Expect(family.GetMetric()).To(HaveLen(1))
+			Expect(family.GetMetric()[0].GetGauge().GetValue()).To(BeEquivalentTo(1))
+			for _, label := range family.GetMetric()[0].GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+		}
+
+		info := version.Get()
+		Expect(labels).To(Equal(map[string]string{
+			"version":            info.Version,
+			"git_commit":         info.GitCommit,
+			"go_version":         info.GoVersion,
+			"k8s_client_version": info.KubernetesClientVersion,
+		}))
+	})
+})
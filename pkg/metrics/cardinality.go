@@ -0,0 +1,120 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var log = logf.KBLog.WithName("metrics")
+
+// OverflowLabelValue is the label value recorded instead of the values of a
+// label beyond its limit of distinct values set with WithLabelValueLimit.
+const OverflowLabelValue = "overflow"
+
+// The labels whose values are limited by WithLabelValueLimit, with the metrics
+// they are recorded on.  The values of the other labels of the
+// controller-runtime metrics are not limited: the controller, webhook, quota
+// and operation tracker names are bounded by the application, and the
+// results, reasons and sources have a fixed set of values.
+const (
+	// LabelNamespace is the label of the request namespaces of the
+	// controller_runtime_reconcile_namespace_total,
+	// controller_runtime_reconcile_namespace_time_seconds and
+	// controller_runtime_reconcile_throttled_total metrics.
+	LabelNamespace = "namespace"
+	// LabelKind is the label of the object kinds of the
+	// controller_runtime_cache_consistency_checks_total and
+	// controller_runtime_cache_consistency_divergences_total metrics.
+	LabelKind = "kind"
+	// LabelContentType is the label of the response content types of the
+	// controller_runtime_client_responses_total metric.
+	LabelContentType = "content_type"
+	// LabelHandler is the label of the handler names of the
+	// controller_runtime_webhook_handler_latency_seconds metric.
+	LabelHandler = "handler"
+)
+
+// labelValues tracks the distinct values recorded for the limited labels, and
+// the labels whose limiting has been logged.
+var labelValues = struct {
+	sync.Mutex
+	seen   map[string]map[string]struct{}
+	logged map[string]bool
+}{seen: map[string]map[string]struct{}{}, logged: map[string]bool{}}
+
+// WithLabelValueLimit limits the number of distinct values recorded for each
+// of labels, which default to all the labels listed above, to protect
+// exporters from an unbounded growth of the number of series.  Values beyond
+// the limit are recorded as OverflowLabelValue, and the limiting is logged
+// once per label.  A limit of 0 removes the limit of labels.
+func WithLabelValueLimit(limit int, labels ...string) Option {
+	if len(labels) == 0 {
+		labels = []string{LabelNamespace, LabelKind, LabelContentType, LabelHandler}
+	}
+	return func(c *config) {
+		if c.labelValueLimits == nil {
+			c.labelValueLimits = map[string]int{}
+		}
+		labelValues.Lock()
+		defer labelValues.Unlock()
+		for _, label := range labels {
+			if limit > 0 {
+				c.labelValueLimits[label] = limit
+			} else {
+				delete(c.labelValueLimits, label)
+			}
+			delete(labelValues.seen, label)
+			delete(labelValues.logged, label)
+		}
+	}
+}
+
+// LabelValue returns the value to record for label, which is value unless
+// label has already been recorded with as many distinct values as its limit
+// set with WithLabelValueLimit.  The values recorded before the limit was
+// reached are still recorded as-is.
+func LabelValue(label, value string) string {
+	mu.RLock()
+	limit, ok := cfg.labelValueLimits[label]
+	mu.RUnlock()
+	if !ok {
+		return value
+	}
+
+	labelValues.Lock()
+	defer labelValues.Unlock()
+	seen := labelValues.seen[label]
+	if seen == nil {
+		seen = map[string]struct{}{}
+		labelValues.seen[label] = seen
+	}
+	if _, ok := seen[value]; ok {
+		return value
+	}
+	if len(seen) < limit {
+		seen[value] = struct{}{}
+		return value
+	}
+	if !labelValues.logged[label] {
+		labelValues.logged[label] = true
+		log.Info("limiting the values of a metrics label", "label", label, "limit", limit)
+	}
+	return OverflowLabelValue
+}
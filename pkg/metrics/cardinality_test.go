@@ -0,0 +1,73 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("WithLabelValueLimit", func() {
+	AfterEach(func() {
+		metrics.Configure(metrics.WithLabelValueLimit(0), metrics.WithoutNamespaceTag())
+	})
+
+	It("should not limit the label values by default", func() {
+		for _, v := range []string{"foo", "bar", "baz"} {
+			Expect(metrics.LabelValue(metrics.LabelKind, v)).To(Equal(v))
+		}
+	})
+
+	It("should record the values beyond the limit as overflow", func() {
+		metrics.Configure(metrics.WithLabelValueLimit(2))
+		Expect(metrics.LabelValue(metrics.LabelKind, "foo")).To(Equal("foo"))
+		Expect(metrics.LabelValue(metrics.LabelKind, "bar")).To(Equal("bar"))
+		Expect(metrics.LabelValue(metrics.LabelKind, "baz")).To(Equal(metrics.OverflowLabelValue))
+
+		By("still recording the values seen before the limit was reached")
+		Expect(metrics.LabelValue(metrics.LabelKind, "foo")).To(Equal("foo"))
+
+		By("limiting each label separately")
+		Expect(metrics.LabelValue(metrics.LabelHandler, "baz")).To(Equal("baz"))
+	})
+
+	It("should only limit the given labels", func() {
+		metrics.Configure(metrics.WithLabelValueLimit(1, metrics.LabelHandler))
+		Expect(metrics.LabelValue(metrics.LabelHandler, "foo")).To(Equal("foo"))
+		Expect(metrics.LabelValue(metrics.LabelHandler, "bar")).To(Equal(metrics.OverflowLabelValue))
+		Expect(metrics.LabelValue(metrics.LabelKind, "foo")).To(Equal("foo"))
+		Expect(metrics.LabelValue(metrics.LabelKind, "bar")).To(Equal("bar"))
+	})
+
+	It("should forget the values seen once the limit is changed", func() {
+		metrics.Configure(metrics.WithLabelValueLimit(1))
+		Expect(metrics.LabelValue(metrics.LabelKind, "foo")).To(Equal("foo"))
+		metrics.Configure(metrics.WithLabelValueLimit(1))
+		Expect(metrics.LabelValue(metrics.LabelKind, "bar")).To(Equal("bar"))
+	})
+
+	It("should limit the tagged namespaces", func() {
+		metrics.Configure(metrics.WithNamespaceTag(), metrics.WithLabelValueLimit(1, metrics.LabelNamespace))
+		ns, ok := metrics.NamespaceTag("foo")
+		Expect(ok).To(BeTrue())
+		Expect(ns).To(Equal("foo"))
+		ns, ok = metrics.NamespaceTag("bar")
+		Expect(ok).To(BeTrue())
+		Expect(ns).To(Equal(metrics.OverflowLabelValue))
+	})
+})
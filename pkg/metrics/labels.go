@@ -0,0 +1,101 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"os"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Gatherer gathers the metrics of Registry with the deployment labels set
+// with WithDeploymentLabels.  The Manager serves its metrics endpoint from it.
+var Gatherer prometheus.Gatherer = deploymentLabelsGatherer{Gatherer: Registry}
+
+// WithDeploymentLabels adds labels, e.g. the pod, node or cluster of the
+// operator, to every metric of Registry when it is gathered by Gatherer or
+// registered by RegisterDefaultViews, so that every metric carries the
+// dimensions of the deployment without each recording setting them.  The
+// labels a metric already has are left as-is.
+func WithDeploymentLabels(labels map[string]string) Option {
+	copied := make(map[string]string, len(labels))
+	for k, v := range labels {
+		copied[k] = v
+	}
+	return func(c *config) {
+		c.deploymentLabels = copied
+	}
+}
+
+// DeploymentLabelsFromEnv returns the deployment labels whose values are read
+// from environment variables, e.g. set from the downward API, keyed by label.
+// For example {"pod": "POD_NAME", "node": "NODE_NAME"}.  The labels of unset
+// variables are omitted.
+func DeploymentLabelsFromEnv(vars map[string]string) map[string]string {
+	labels := map[string]string{}
+	for label, name := range vars {
+		if v := os.Getenv(name); v != "" {
+			labels[label] = v
+		}
+	}
+	return labels
+}
+
+// deploymentLabels returns the labels set with WithDeploymentLabels.
+func deploymentLabels() map[string]string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return cfg.deploymentLabels
+}
+
+// deploymentLabelsGatherer adds the deployment labels to the metrics of a Gatherer.
+type deploymentLabelsGatherer struct {
+	prometheus.Gatherer
+}
+
+// Gather implements prometheus.Gatherer
+func (g deploymentLabelsGatherer) Gather() ([]*dto.MetricFamily, error) {
+	families, err := g.Gatherer.Gather()
+	labels := deploymentLabels()
+	if len(labels) == 0 {
+		return families, err
+	}
+	for _, family := range families {
+		for _, m := range family.Metric {
+			m.Label = withLabels(m.Label, labels)
+		}
+	}
+	return families, err
+}
+
+// withLabels returns pairs with the labels it doesn't have yet, sorted by name.
+func withLabels(pairs []*dto.LabelPair, labels map[string]string) []*dto.LabelPair {
+	has := make(map[string]bool, len(pairs))
+	for _, p := range pairs {
+		has[p.GetName()] = true
+	}
+	for name, value := range labels {
+		if !has[name] {
+			name, value := name, value
+			pairs = append(pairs, &dto.LabelPair{Name: &name, Value: &value})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].GetName() < pairs[j].GetName() })
+	return pairs
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("WithDeploymentLabels", func() {
+	var counter *prometheus.CounterVec
+
+	BeforeEach(func() {
+		counter = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "test_deployment_labels_total",
+			Help: "Test metric",
+		}, []string{"node"})
+		counter.WithLabelValues("foo").Inc()
+		Expect(metrics.Register(counter)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		metrics.Unregister(counter)
+		metrics.Configure(metrics.WithDeploymentLabels(nil))
+	})
+
+	labels := func() map[string]string {
+		families, err := metrics.Gatherer.Gather()
+		Expect(err).NotTo(HaveOccurred())
+		for _, f := range families {
+			if f.GetName() == "test_deployment_labels_total" {
+				return labelMap(f.Metric[0].Label)
+			}
+		}
+		Fail("metric not gathered")
+		return nil
+	}
+
+	It("should not add labels by default", func() {
+		Expect(labels()).To(Equal(map[string]string{"node": "foo"}))
+	})
+
+	It("should add the deployment labels to the gathered metrics", func() {
+		metrics.Configure(metrics.WithDeploymentLabels(map[string]string{"pod": "bar", "cluster": "baz"}))
+		Expect(labels()).To(Equal(map[string]string{"node": "foo", "pod": "bar", "cluster": "baz"}))
+	})
+
+	It("should leave the labels the metrics already have", func() {
+		metrics.Configure(metrics.WithDeploymentLabels(map[string]string{"node": "bar"}))
+		Expect(labels()).To(Equal(map[string]string{"node": "foo"}))
+	})
+
+	It("should register the default views with the deployment labels", func() {
+		metrics.Configure(metrics.WithDeploymentLabels(map[string]string{"pod": "bar"}))
+		r := prometheus.NewRegistry()
+		Expect(metrics.RegisterDefaultViews(r)).To(Succeed())
+		Expect(metrics.RegisterDefaultViews(r)).To(Succeed())
+
+		families, err := r.Gather()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(families).NotTo(BeEmpty())
+		for _, f := range families {
+			for _, m := range f.Metric {
+				Expect(labelMap(m.Label)).To(HaveKeyWithValue("pod", "bar"))
+			}
+		}
+	})
+
+	It("should read the deployment labels from the environment", func() {
+		Expect(os.Setenv("TEST_POD_NAME", "bar")).To(Succeed())
+		defer os.Unsetenv("TEST_POD_NAME")
+		Expect(metrics.DeploymentLabelsFromEnv(map[string]string{
+			"pod":  "TEST_POD_NAME",
+			"node": "TEST_NODE_NAME",
+		})).To(Equal(map[string]string{"pod": "bar"}))
+	})
+})
+
+func labelMap(pairs []*dto.LabelPair) map[string]string {
+	labels := map[string]string{}
+	for _, p := range pairs {
+		labels[p.GetName()] = p.GetValue()
+	}
+	return labels
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package metricstest provides utilities to assert on the metrics recorded by the controller-runtime in tests.
+
+The data recorded by the default views is shared by every test of a binary, so tests should reset it before
+recording, and read it back once recorded:
+
+	BeforeEach(func() {
+		metrics.Reset()
+	})
+
+	It("should count the reconciliations", func() {
+		// reconcile a request
+		v, err := metricstest.Value("controller_runtime_reconcile_total",
+			map[string]string{"controller": "foo", "result": "success"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v).To(Equal(1.0))
+	})
+
+Metrics registered with an isolated prometheus.Registry created for a test are read back with GatherFrom.
+
+An Exporter captures the Samples of every metric as Rows, which are queried by label:
+
+	e := &metricstest.Exporter{}
+	Expect(e.Export()).To(Succeed())
+	rows := e.Rows("controller_runtime_reconcile_time_seconds").ByTag("controller", "foo")
+	Expect(rows.Count()).To(Equal(uint64(1)))
+*/
+package metricstest
@@ -0,0 +1,100 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricstest
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Rows are the Samples of a metric captured by an Exporter.
+type Rows []Sample
+
+// ByTag returns the Rows whose label name has value.
+func (r Rows) ByTag(name, value string) Rows {
+	var rows Rows
+	for _, s := range r {
+		if v, ok := s.Labels[name]; ok && v == value {
+			rows = append(rows, s)
+		}
+	}
+	return rows
+}
+
+// Sum returns the sum of the Values of the Rows.
+func (r Rows) Sum() float64 {
+	var sum float64
+	for _, s := range r {
+		sum += s.Value
+	}
+	return sum
+}
+
+// Count returns the sum of the Counts of the Rows, i.e. the number of observations of a histogram
+// or summary.
+func (r Rows) Count() uint64 {
+	var count uint64
+	for _, s := range r {
+		count += s.Count
+	}
+	return count
+}
+
+// Exporter captures in memory the Samples of every metric gathered from its Gatherer when Export is
+// called, so that tests can query them.
+type Exporter struct {
+	// Gatherer is gathered by Export.  Defaults to metrics.Registry, whose batched recordings are
+	// flushed before it is gathered.
+	Gatherer prometheus.Gatherer
+
+	mu   sync.Mutex
+	rows map[string]Rows
+}
+
+// Export replaces the captured Rows with the Samples of every metric gathered from the Gatherer.
+func (e *Exporter) Export() error {
+	g := e.Gatherer
+	if g == nil {
+		metrics.Flush()
+		g = metrics.Registry
+	}
+	families, err := g.Gather()
+	if err != nil {
+		return err
+	}
+
+	rows := make(map[string]Rows, len(families))
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			rows[f.GetName()] = append(rows[f.GetName()], newSample(m))
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rows = rows
+	return nil
+}
+
+// Rows returns the Rows of the metric named name captured by the last Export.
+func (e *Exporter) Rows(name string) Rows {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.rows[name]
+}
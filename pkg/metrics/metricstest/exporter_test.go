@@ -0,0 +1,80 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricstest_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/metricstest"
+)
+
+var _ = Describe("Exporter", func() {
+	var e *metricstest.Exporter
+
+	BeforeEach(func() {
+		metrics.Reset()
+		e = &metricstest.Exporter{}
+	})
+
+	It("should capture the Rows of the metrics of the Registry", func() {
+		testTotal.WithLabelValues("foo", "success").Add(2)
+		testTotal.WithLabelValues("foo", "error").Inc()
+		testTotal.WithLabelValues("bar", "success").Inc()
+		testSeconds.WithLabelValues("foo").Observe(1)
+		testSeconds.WithLabelValues("bar").Observe(2)
+		testSeconds.WithLabelValues("bar").Observe(3)
+		Expect(e.Export()).To(Succeed())
+
+		rows := e.Rows("metricstest_total")
+		Expect(rows).To(HaveLen(3))
+		Expect(rows.Sum()).To(Equal(4.0))
+		Expect(rows.ByTag("name", "foo").Sum()).To(Equal(3.0))
+		Expect(rows.ByTag("name", "foo").ByTag("result", "error").Sum()).To(Equal(1.0))
+		Expect(rows.ByTag("name", "baz")).To(BeEmpty())
+
+		rows = e.Rows("metricstest_seconds")
+		Expect(rows.Count()).To(Equal(uint64(3)))
+		Expect(rows.ByTag("name", "bar").Count()).To(Equal(uint64(2)))
+		Expect(rows.ByTag("name", "bar").Sum()).To(Equal(5.0))
+
+		Expect(e.Rows("metricstest_unknown")).To(BeEmpty())
+	})
+
+	It("should replace the captured Rows on every Export", func() {
+		testTotal.WithLabelValues("foo", "success").Inc()
+		Expect(e.Export()).To(Succeed())
+		testTotal.WithLabelValues("foo", "success").Inc()
+		Expect(e.Rows("metricstest_total").Sum()).To(Equal(1.0))
+
+		Expect(e.Export()).To(Succeed())
+		Expect(e.Rows("metricstest_total").Sum()).To(Equal(2.0))
+	})
+
+	It("should capture the Rows of its Gatherer", func() {
+		r := prometheus.NewRegistry()
+		g := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "metricstest_exporter_gauge", Help: "Test gauge"}, []string{"name"})
+		Expect(r.Register(g)).To(Succeed())
+		g.WithLabelValues("foo").Set(3)
+		e.Gatherer = r
+
+		Expect(e.Export()).To(Succeed())
+		Expect(e.Rows("metricstest_exporter_gauge").ByTag("name", "foo").Sum()).To(Equal(3.0))
+		Expect(e.Rows("metricstest_total")).To(BeEmpty())
+	})
+})
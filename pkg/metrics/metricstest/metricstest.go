@@ -0,0 +1,108 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricstest
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Sample is the data recorded by a metric for a set of label values.
+type Sample struct {
+	// Labels are the label values of the Sample keyed by label name.
+	Labels map[string]string
+
+	// Value is the value of a counter, gauge or untyped metric, or the sum of the observations of a
+	// histogram or summary.
+	Value float64
+
+	// Count is the number of observations of a histogram or summary.
+	Count uint64
+}
+
+// Gather returns the Samples of the metric named name gathered from metrics.Registry, once the recordings
+// buffered by the batches are flushed.  It returns no Samples if the metric recorded no data.
+func Gather(name string) ([]Sample, error) {
+	metrics.Flush()
+	return GatherFrom(metrics.Registry, name)
+}
+
+// GatherFrom returns the Samples of the metric named name gathered from g.
+func GatherFrom(g prometheus.Gatherer, name string) ([]Sample, error) {
+	families, err := g.Gather()
+	if err != nil {
+		return nil, err
+	}
+	var samples []Sample
+	for _, f := range families {
+		if f.GetName() != name {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			samples = append(samples, newSample(m))
+		}
+	}
+	return samples, nil
+}
+
+// Value returns the Value of the Sample of the metric named name gathered from metrics.Registry whose
+// label values include labels.  It returns 0 if there is no such Sample.
+func Value(name string, labels map[string]string) (float64, error) {
+	samples, err := Gather(name)
+	if err != nil {
+		return 0, err
+	}
+	for _, s := range samples {
+		if s.matches(labels) {
+			return s.Value, nil
+		}
+	}
+	return 0, nil
+}
+
+func newSample(m *dto.Metric) Sample {
+	s := Sample{Labels: map[string]string{}}
+	for _, l := range m.GetLabel() {
+		s.Labels[l.GetName()] = l.GetValue()
+	}
+	switch {
+	case m.Counter != nil:
+		s.Value = m.GetCounter().GetValue()
+	case m.Gauge != nil:
+		s.Value = m.GetGauge().GetValue()
+	case m.Untyped != nil:
+		s.Value = m.GetUntyped().GetValue()
+	case m.Histogram != nil:
+		s.Value = m.GetHistogram().GetSampleSum()
+		s.Count = m.GetHistogram().GetSampleCount()
+	case m.Summary != nil:
+		s.Value = m.GetSummary().GetSampleSum()
+		s.Count = m.GetSummary().GetSampleCount()
+	}
+	return s
+}
+
+// matches returns true if the label values of s include labels.
+func (s Sample) matches(labels map[string]string) bool {
+	for k, v := range labels {
+		if s.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
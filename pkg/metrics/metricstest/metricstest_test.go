@@ -0,0 +1,113 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricstest_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/metricstest"
+)
+
+var (
+	testTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "metricstest_total",
+		Help: "Total number of test recordings",
+	}, []string{"name", "result"})
+	testSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "metricstest_seconds",
+		Help: "Duration of the test recordings",
+	}, []string{"name"})
+	batchedTestTotal = metrics.NewBatchedCounter(testTotal)
+)
+
+func init() {
+	metrics.AddDefaultViews(metrics.View{Collector: testTotal}, metrics.View{Collector: testSeconds})
+}
+
+var _ = Describe("metricstest", func() {
+	BeforeEach(func() {
+		metrics.Reset()
+	})
+
+	It("should gather the Samples of a metric", func() {
+		testTotal.WithLabelValues("foo", "success").Add(2)
+		testTotal.WithLabelValues("foo", "error").Inc()
+		testSeconds.WithLabelValues("foo").Observe(1.5)
+		testSeconds.WithLabelValues("foo").Observe(0.5)
+
+		samples, err := metricstest.Gather("metricstest_total")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(samples).To(ConsistOf(
+			metricstest.Sample{Labels: map[string]string{"name": "foo", "result": "success"}, Value: 2},
+			metricstest.Sample{Labels: map[string]string{"name": "foo", "result": "error"}, Value: 1},
+		))
+
+		samples, err = metricstest.Gather("metricstest_seconds")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(samples).To(Equal([]metricstest.Sample{{Labels: map[string]string{"name": "foo"}, Value: 2, Count: 2}}))
+	})
+
+	It("should read back the Value of the Sample with labels", func() {
+		testTotal.WithLabelValues("foo", "success").Add(2)
+		testTotal.WithLabelValues("bar", "success").Inc()
+
+		Expect(metricstest.Value("metricstest_total", map[string]string{"name": "bar"})).To(Equal(1.0))
+		Expect(metricstest.Value("metricstest_total", map[string]string{"name": "foo", "result": "success"})).To(Equal(2.0))
+		Expect(metricstest.Value("metricstest_total", map[string]string{"name": "baz"})).To(BeZero())
+	})
+
+	It("should not observe the data recorded before the metrics were reset", func() {
+		testTotal.WithLabelValues("foo", "success").Inc()
+		metrics.Reset()
+
+		samples, err := metricstest.Gather("metricstest_total")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(samples).To(BeEmpty())
+	})
+
+	It("should flush the batched recordings", func() {
+		metrics.Configure(metrics.WithBatching(time.Hour))
+		defer metrics.Configure(metrics.WithBatching(0))
+
+		batchedTestTotal.Inc("foo", "success")
+		Expect(metricstest.Value("metricstest_total", map[string]string{"name": "foo"})).To(Equal(1.0))
+
+		By("discarding the batched recordings when the metrics are reset")
+		batchedTestTotal.Inc("foo", "success")
+		metrics.Reset()
+		Expect(metricstest.Value("metricstest_total", map[string]string{"name": "foo"})).To(BeZero())
+	})
+
+	It("should gather the Samples of an isolated registry", func() {
+		r := prometheus.NewRegistry()
+		g := prometheus.NewGauge(prometheus.GaugeOpts{Name: "metricstest_gauge", Help: "Test gauge"})
+		Expect(r.Register(g)).To(Succeed())
+		g.Set(3)
+
+		samples, err := metricstest.GatherFrom(r, "metricstest_gauge")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(samples).To(Equal([]metricstest.Sample{{Labels: map[string]string{}, Value: 3}}))
+
+		samples, err = metricstest.Gather("metricstest_gauge")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(samples).To(BeEmpty())
+	})
+})
@@ -0,0 +1,104 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "sync"
+
+// OtherNamespace is the namespace label value recorded for requests whose
+// namespace is not in the allowlist passed to WithNamespaceTag.
+const OtherNamespace = "other"
+
+// Option configures optional behavior of the metrics recorded by the
+// controller-runtime.
+type Option func(*config)
+
+// config holds the metrics settings applied by Configure.
+type config struct {
+	namespaceTag       bool
+	namespaceAllowlist map[string]struct{}
+	labelValueLimits   map[string]int
+	samplers           map[string]*sampler
+	deploymentLabels   map[string]string
+}
+
+var (
+	mu  sync.RWMutex
+	cfg config
+)
+
+// Configure applies opts to the metrics recorded by the controller-runtime.
+// It should be called before any controllers are started.
+func Configure(opts ...Option) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+}
+
+// WithNamespaceTag enables tagging reconcile metrics with the namespace of the
+// request being reconciled.  To bound the cardinality of the metrics, only the
+// namespaces in allowlist are recorded as-is and all other namespaces are
+// recorded as OtherNamespace.  If allowlist is empty every namespace is recorded.
+func WithNamespaceTag(allowlist ...string) Option {
+	return func(c *config) {
+		c.namespaceTag = true
+		c.namespaceAllowlist = nil
+		if len(allowlist) == 0 {
+			return
+		}
+		c.namespaceAllowlist = make(map[string]struct{}, len(allowlist))
+		for _, ns := range allowlist {
+			c.namespaceAllowlist[ns] = struct{}{}
+		}
+	}
+}
+
+// WithoutNamespaceTag disables tagging reconcile metrics with the request namespace.
+func WithoutNamespaceTag() Option {
+	return func(c *config) {
+		c.namespaceTag = false
+		c.namespaceAllowlist = nil
+	}
+}
+
+// NamespaceTag returns the namespace label value to record for a request in
+// namespace ns.  It returns false if namespace tagging is not enabled.
+// Cluster-scoped requests are recorded with an empty namespace.  The namespace
+// is limited by the limit of LabelNamespace set with WithLabelValueLimit.
+func NamespaceTag(ns string) (string, bool) {
+	tag, ok := namespaceTag(ns)
+	if !ok {
+		return "", false
+	}
+	return LabelValue(LabelNamespace, tag), true
+}
+
+func namespaceTag(ns string) (string, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if !cfg.namespaceTag {
+		return "", false
+	}
+	if ns == "" || cfg.namespaceAllowlist == nil {
+		return ns, true
+	}
+	if _, ok := cfg.namespaceAllowlist[ns]; ok {
+		return ns, true
+	}
+	return OtherNamespace, true
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("Options", func() {
+	AfterEach(func() {
+		metrics.Configure(metrics.WithoutNamespaceTag())
+	})
+
+	Describe("WithNamespaceTag", func() {
+		It("should not tag namespaces by default", func() {
+			_, ok := metrics.NamespaceTag("foo")
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should tag every namespace if no allowlist is given", func() {
+			metrics.Configure(metrics.WithNamespaceTag())
+			ns, ok := metrics.NamespaceTag("foo")
+			Expect(ok).To(BeTrue())
+			Expect(ns).To(Equal("foo"))
+		})
+
+		It("should tag namespaces outside the allowlist as other", func() {
+			metrics.Configure(metrics.WithNamespaceTag("foo"))
+			ns, ok := metrics.NamespaceTag("foo")
+			Expect(ok).To(BeTrue())
+			Expect(ns).To(Equal("foo"))
+
+			ns, ok = metrics.NamespaceTag("bar")
+			Expect(ok).To(BeTrue())
+			Expect(ns).To(Equal(metrics.OtherNamespace))
+		})
+
+		It("should tag cluster-scoped requests with an empty namespace", func() {
+			metrics.Configure(metrics.WithNamespaceTag("foo"))
+			ns, ok := metrics.NamespaceTag("")
+			Expect(ok).To(BeTrue())
+			Expect(ns).To(Equal(""))
+		})
+	})
+})
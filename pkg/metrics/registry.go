@@ -16,8 +16,46 @@ limitations under the License.
 
 package metrics
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
 
 // Registry is a prometheus registry for storing metrics within the
 // controller-runtime
 var Registry = prometheus.NewRegistry()
+
+// Register registers the collectors of the metrics recorded by an operator,
+// such as the number of databases it provisioned, with Registry so that the
+// Manager exports them alongside the metrics of the controller-runtime.
+// Collectors which are already registered are skipped, and the errors of the
+// collectors which could not be registered are aggregated.
+func Register(cs ...prometheus.Collector) error {
+	return register(Registry, cs)
+}
+
+// MustRegister is like Register but panics if a collector could not be registered.
+func MustRegister(cs ...prometheus.Collector) {
+	if err := Register(cs...); err != nil {
+		panic(err)
+	}
+}
+
+// Unregister removes c from Registry.  It returns false if c was not registered.
+func Unregister(c prometheus.Collector) bool {
+	return Registry.Unregister(c)
+}
+
+// register registers cs with r, skipping the collectors which are already registered.
+func register(r prometheus.Registerer, cs []prometheus.Collector) error {
+	var errs []error
+	for _, c := range cs {
+		if err := r.Register(c); err != nil {
+			if _, ok := err.(prometheus.AlreadyRegisteredError); ok {
+				continue
+			}
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
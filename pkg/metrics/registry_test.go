@@ -0,0 +1,67 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("Register", func() {
+	It("should register the operator metrics with the Registry", func() {
+		provisioned := prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "test_databases_provisioned_total",
+			Help: "test business metric for testing",
+		})
+		Expect(metrics.Register(provisioned)).To(Succeed())
+		defer metrics.Unregister(provisioned)
+
+		err := metrics.Registry.Register(provisioned)
+		Expect(err).To(BeAssignableToTypeOf(prometheus.AlreadyRegisteredError{}))
+	})
+
+	It("should skip the metrics which are already registered", func() {
+		provisioned := prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "test_databases_provisioned_total",
+			Help: "test business metric for testing",
+		})
+		Expect(metrics.Register(provisioned)).To(Succeed())
+		defer metrics.Unregister(provisioned)
+
+		Expect(metrics.Register(provisioned)).To(Succeed())
+	})
+
+	It("should return an error if a metric conflicts with a runtime metric", func() {
+		err := metrics.Register(prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "controller_runtime_reconcile_total",
+			Help: "conflicting metric for testing",
+		}))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should remove the unregistered metrics from the Registry", func() {
+		provisioned := prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "test_databases_provisioned_total",
+			Help: "test business metric for testing",
+		})
+		Expect(metrics.Unregister(provisioned)).To(BeFalse())
+		metrics.MustRegister(provisioned)
+		Expect(metrics.Unregister(provisioned)).To(BeTrue())
+	})
+})
@@ -0,0 +1,97 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The metrics recorded on hot paths, whose recordings can be sampled with WithSampling.
+const (
+	// ClientRateLimiterWait is recorded for each request of the clients.
+	ClientRateLimiterWait = "controller_runtime_client_rate_limiter_wait_seconds"
+	// ClientResponses is recorded for each response to the clients.
+	ClientResponses = "controller_runtime_client_responses_total"
+	// ReconcileTime is recorded for each reconcile of the controllers.
+	ReconcileTime = "controller_runtime_reconcile_time_seconds"
+)
+
+// SamplingRate is a prometheus metric which holds the sampling rate of the
+// sampled metrics, i.e. the N of the 1 in N recordings which are recorded.
+// Dashboards multiply the sampled counters and histogram counts by it.
+var SamplingRate = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "controller_runtime_metrics_sampling_rate",
+	Help: "Sampling rate of the sampled metrics, i.e. 1 in how many recordings are recorded",
+}, []string{"metric"})
+
+func init() {
+	AddDefaultViews(View{Collector: SamplingRate, Constant: true})
+}
+
+// sampler counts the recordings of each series of a sampled metric, so that
+// the series interleaving their recordings are all sampled at its rate.
+type sampler struct {
+	rate   uint64
+	counts sync.Map // label values joined by labelSeparator -> *uint64
+}
+
+// WithSampling records only 1 in every rate recordings of the metrics named
+// names, which default to all the metrics listed above, to cut the cost of the
+// recordings in operators with very high request or reconcile rates.  The rate
+// is recorded by the SamplingRate metric.  A rate of 1 or less records every
+// recording.
+func WithSampling(rate int, names ...string) Option {
+	if len(names) == 0 {
+		names = []string{ClientRateLimiterWait, ClientResponses, ReconcileTime}
+	}
+	return func(c *config) {
+		if c.samplers == nil {
+			c.samplers = map[string]*sampler{}
+		}
+		for _, name := range names {
+			if rate > 1 {
+				c.samplers[name] = &sampler{rate: uint64(rate)}
+				SamplingRate.WithLabelValues(name).Set(float64(rate))
+			} else {
+				delete(c.samplers, name)
+				SamplingRate.WithLabelValues(name).Set(1)
+			}
+		}
+	}
+}
+
+// Sample returns true if the current recording of the series of the metric
+// named name with labelValues must be recorded, i.e. if the metric is not
+// sampled, or if it is the first of the rate recordings of the series.
+func Sample(name string, labelValues ...string) bool {
+	mu.RLock()
+	s, ok := cfg.samplers[name]
+	mu.RUnlock()
+	if !ok {
+		return true
+	}
+	key := strings.Join(labelValues, labelSeparator)
+	count, ok := s.counts.Load(key)
+	if !ok {
+		count, _ = s.counts.LoadOrStore(key, new(uint64))
+	}
+	return atomic.AddUint64(count.(*uint64), 1)%s.rate == 1
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("WithSampling", func() {
+	AfterEach(func() {
+		metrics.Configure(metrics.WithSampling(1))
+	})
+
+	sampled := func(name string, n int) int {
+		count := 0
+		for i := 0; i < n; i++ {
+			if metrics.Sample(name) {
+				count++
+			}
+		}
+		return count
+	}
+
+	rate := func(name string) float64 {
+		var m dto.Metric
+		Expect(metrics.SamplingRate.WithLabelValues(name).Write(&m)).To(Succeed())
+		return m.GetGauge().GetValue()
+	}
+
+	It("should record every recording by default", func() {
+		Expect(sampled(metrics.ReconcileTime, 10)).To(Equal(10))
+	})
+
+	It("should record 1 in every rate recordings of the sampled metrics", func() {
+		metrics.Configure(metrics.WithSampling(5))
+		Expect(metrics.Sample(metrics.ClientResponses)).To(BeTrue())
+		Expect(sampled(metrics.ClientResponses, 99)).To(Equal(19))
+		Expect(rate(metrics.ClientResponses)).To(Equal(5.0))
+	})
+
+	It("should only sample the given metrics", func() {
+		metrics.Configure(metrics.WithSampling(10, metrics.ClientRateLimiterWait))
+		Expect(sampled(metrics.ClientRateLimiterWait, 100)).To(Equal(10))
+		Expect(sampled(metrics.ReconcileTime, 100)).To(Equal(100))
+	})
+
+	It("should sample each series separately", func() {
+		metrics.Configure(metrics.WithSampling(2))
+		counts := map[string]int{}
+		for i := 0; i < 10; i++ {
+			for _, name := range []string{"foo", "bar"} {
+				if metrics.Sample(metrics.ReconcileTime, name) {
+					counts[name]++
+				}
+			}
+		}
+		Expect(counts).To(Equal(map[string]int{"foo": 5, "bar": 5}))
+	})
+
+	It("should record every recording once the sampling is disabled", func() {
+		metrics.Configure(metrics.WithSampling(10))
+		metrics.Configure(metrics.WithSampling(1))
+		Expect(sampled(metrics.ReconcileTime, 10)).To(Equal(10))
+		Expect(rate(metrics.ReconcileTime)).To(Equal(1.0))
+	})
+})
@@ -0,0 +1,151 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Names of the metrics read by Snapshot.
+const (
+	queueLengthName     = "controller_runtime_reconcile_queue_length"
+	reconcileTotalName  = "controller_runtime_reconcile_total"
+	reconcileErrorsName = "controller_runtime_reconcile_errors_total"
+	reconcileTimeName   = "controller_runtime_reconcile_time_seconds"
+	webhookTotalName    = "controller_runtime_webhook_requests_total"
+	webhookLatencyName  = "controller_runtime_webhook_latency_seconds"
+)
+
+// RuntimeSnapshot is a point in time copy of the metrics recorded by the controller-runtime.
+type RuntimeSnapshot struct {
+	// Controllers holds the metrics of each Controller keyed by the Controller name.
+	Controllers map[string]*ControllerSnapshot
+
+	// Webhooks holds the metrics of each admission webhook keyed by the webhook name.
+	Webhooks map[string]*WebhookSnapshot
+}
+
+// ControllerSnapshot holds the metrics of a single Controller.
+type ControllerSnapshot struct {
+	// QueueLength is the current length of the reconcile queue.
+	QueueLength float64
+
+	// ReconcileTotal is the number of reconciliations keyed by result,
+	// i.e. success, error, requeue or requeue_after.
+	ReconcileTotal map[string]float64
+
+	// ReconcileErrors is the number of errors returned by the Reconciler.
+	ReconcileErrors float64
+
+	// ReconcileCount is the number of observed reconcile durations.
+	ReconcileCount uint64
+
+	// ReconcileSeconds is the sum of the observed reconcile durations in seconds.
+	ReconcileSeconds float64
+}
+
+// WebhookSnapshot holds the metrics of a single admission webhook.
+type WebhookSnapshot struct {
+	// RequestsSucceeded is the number of admission requests that were allowed.
+	RequestsSucceeded float64
+
+	// RequestsFailed is the number of admission requests that were denied or errored.
+	RequestsFailed float64
+
+	// LatencyCount is the number of observed admission request latencies.
+	LatencyCount uint64
+
+	// LatencySeconds is the sum of the observed admission request latencies in seconds.
+	LatencySeconds float64
+}
+
+// Snapshot reads the current values of the controller-runtime metrics from Registry,
+// so that tests and debug endpoints can inspect them without scraping the metrics endpoint.
+// The recordings buffered by WithBatching are flushed first, so that the snapshot includes
+// all the recordings made before it was taken.
+func Snapshot() (*RuntimeSnapshot, error) {
+	Flush()
+	families, err := Registry.Gather()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &RuntimeSnapshot{
+		Controllers: map[string]*ControllerSnapshot{},
+		Webhooks:    map[string]*WebhookSnapshot{},
+	}
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			switch family.GetName() {
+			case queueLengthName:
+				s.controller(m).QueueLength = m.GetGauge().GetValue()
+			case reconcileTotalName:
+				s.controller(m).ReconcileTotal[labelValue(m, "result")] = m.GetCounter().GetValue()
+			case reconcileErrorsName:
+				s.controller(m).ReconcileErrors = m.GetCounter().GetValue()
+			case reconcileTimeName:
+				c := s.controller(m)
+				c.ReconcileCount = m.GetHistogram().GetSampleCount()
+				c.ReconcileSeconds = m.GetHistogram().GetSampleSum()
+			case webhookTotalName:
+				w := s.webhook(m)
+				if labelValue(m, "succeeded") == "true" {
+					w.RequestsSucceeded = m.GetCounter().GetValue()
+				} else {
+					w.RequestsFailed = m.GetCounter().GetValue()
+				}
+			case webhookLatencyName:
+				w := s.webhook(m)
+				w.LatencyCount = m.GetHistogram().GetSampleCount()
+				w.LatencySeconds = m.GetHistogram().GetSampleSum()
+			}
+		}
+	}
+	return s, nil
+}
+
+// controller returns the ControllerSnapshot for the controller label of m, creating it if needed.
+func (s *RuntimeSnapshot) controller(m *dto.Metric) *ControllerSnapshot {
+	name := labelValue(m, "controller")
+	c, found := s.Controllers[name]
+	if !found {
+		c = &ControllerSnapshot{ReconcileTotal: map[string]float64{}}
+		s.Controllers[name] = c
+	}
+	return c
+}
+
+// webhook returns the WebhookSnapshot for the webhook label of m, creating it if needed.
+func (s *RuntimeSnapshot) webhook(m *dto.Metric) *WebhookSnapshot {
+	name := labelValue(m, "webhook")
+	w, found := s.Webhooks[name]
+	if !found {
+		w = &WebhookSnapshot{}
+		s.Webhooks[name] = w
+	}
+	return w
+}
+
+// labelValue returns the value of the label name of m, or the empty string if it is not set.
+func labelValue(m *dto.Metric, name string) string {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+	return ""
+}
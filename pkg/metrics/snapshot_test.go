@@ -0,0 +1,98 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("Snapshot", func() {
+	// the webhook metrics are internal to the webhook package, so register equivalents here
+	webhookTotal := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_webhook_requests_total",
+		Help: "Total number of admission requests",
+	}, []string{"webhook", "succeeded"})
+	webhookLatency := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "controller_runtime_webhook_latency_seconds",
+		Help: "Histogram of the latency of processing admission requests",
+	}, []string{"webhook"})
+	metrics.Registry.MustRegister(webhookTotal, webhookLatency)
+
+	BeforeEach(func() {
+		ctrlmetrics.QueueLength.Reset()
+		ctrlmetrics.ReconcileTotal.Reset()
+		ctrlmetrics.ReconcileErrors.Reset()
+		ctrlmetrics.ReconcileTime.Reset()
+		webhookTotal.Reset()
+		webhookLatency.Reset()
+	})
+
+	It("should read the controller metrics", func() {
+		ctrlmetrics.QueueLength.WithLabelValues("foo").Set(3)
+		ctrlmetrics.ReconcileTotal.WithLabelValues("foo", "success").Add(2)
+		ctrlmetrics.ReconcileTotal.WithLabelValues("foo", "error").Inc()
+		ctrlmetrics.ReconcileErrors.WithLabelValues("foo").Inc()
+		ctrlmetrics.ReconcileTime.WithLabelValues("foo").Observe(0.5)
+
+		s, err := metrics.Snapshot()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Controllers).To(HaveKey("foo"))
+		c := s.Controllers["foo"]
+		Expect(c.QueueLength).To(Equal(3.0))
+		Expect(c.ReconcileTotal).To(Equal(map[string]float64{"success": 2, "error": 1}))
+		Expect(c.ReconcileErrors).To(Equal(1.0))
+		Expect(c.ReconcileCount).To(Equal(uint64(1)))
+		Expect(c.ReconcileSeconds).To(Equal(0.5))
+	})
+
+	It("should read the reconciliations recorded in batches", func() {
+		metrics.Configure(metrics.WithBatching(time.Hour))
+		defer metrics.Configure(metrics.WithBatching(0))
+
+		ctrlmetrics.BatchedReconcileTotal.Inc("foo", "success")
+		ctrlmetrics.BatchedReconcileTime.Observe(0.5, "foo")
+
+		s, err := metrics.Snapshot()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Controllers).To(HaveKey("foo"))
+		c := s.Controllers["foo"]
+		Expect(c.ReconcileTotal).To(Equal(map[string]float64{"success": 1}))
+		Expect(c.ReconcileCount).To(Equal(uint64(1)))
+		Expect(c.ReconcileSeconds).To(Equal(0.5))
+	})
+
+	It("should read the webhook metrics", func() {
+		webhookTotal.WithLabelValues("bar", "true").Add(4)
+		webhookTotal.WithLabelValues("bar", "false").Inc()
+		webhookLatency.WithLabelValues("bar").Observe(0.25)
+
+		s, err := metrics.Snapshot()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Webhooks).To(HaveKey("bar"))
+		w := s.Webhooks["bar"]
+		Expect(w.RequestsSucceeded).To(Equal(4.0))
+		Expect(w.RequestsFailed).To(Equal(1.0))
+		Expect(w.LatencyCount).To(Equal(uint64(1)))
+		Expect(w.LatencySeconds).To(Equal(0.25))
+	})
+})
@@ -0,0 +1,119 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// View is a collector of the metrics recorded by the controller-runtime.
+type View struct {
+	prometheus.Collector
+
+	// HighCardinality is true if the metrics of the View have labels with a
+	// large number of values, such as request namespaces or URLs.
+	HighCardinality bool
+
+	// Constant is true if the metrics of the View hold settings, such as the
+	// version of the binary, rather than recordings, so that they are kept by Reset.
+	Constant bool
+}
+
+var (
+	viewsMu      sync.Mutex
+	defaultViews []View
+)
+
+// AddDefaultViews registers the collectors of views with Registry and adds
+// them to the views registered by RegisterDefaultViews.  It is called by the
+// packages of the controller-runtime recording metrics when they are initialized.
+func AddDefaultViews(views ...View) {
+	viewsMu.Lock()
+	defer viewsMu.Unlock()
+	for _, v := range views {
+		Registry.MustRegister(v.Collector)
+	}
+	defaultViews = append(defaultViews, views...)
+}
+
+// resetter is implemented by the collectors whose recorded data can be deleted,
+// such as the metric vectors.
+type resetter interface {
+	Reset()
+}
+
+// Reset deletes the data recorded by the default views whose collectors can be
+// reset, such as the metric vectors, except the Constant views, and discards the recordings buffered by the
+// batches.  It is meant for tests, so that their assertions on the metrics of
+// the controller-runtime don't depend on the tests run before them.
+func Reset() {
+	resetBatches()
+
+	viewsMu.Lock()
+	defer viewsMu.Unlock()
+	for _, v := range defaultViews {
+		if r, ok := v.Collector.(resetter); ok && !v.Constant {
+			r.Reset()
+		}
+	}
+}
+
+// ViewOption configures RegisterDefaultViews.
+type ViewOption func(*viewOptions)
+
+// viewOptions holds the settings applied by RegisterDefaultViews.
+type viewOptions struct {
+	excludeHighCardinality bool
+}
+
+// WithoutHighCardinalityViews excludes the views whose metrics have labels with
+// a large number of values from the views registered by RegisterDefaultViews.
+func WithoutHighCardinalityViews() ViewOption {
+	return func(o *viewOptions) {
+		o.excludeHighCardinality = true
+	}
+}
+
+// RegisterDefaultViews registers the default views of the controller and
+// webhook packages with r, e.g. prometheus.DefaultRegisterer, so that they are
+// exposed next to the metrics of the application.  Views already registered
+// with r are skipped, so it is safe to call RegisterDefaultViews more than once.
+// The views are registered with the labels set with WithDeploymentLabels.
+// The errors of all the views which could not be registered are aggregated.
+func RegisterDefaultViews(r prometheus.Registerer, opts ...ViewOption) error {
+	o := &viewOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if labels := deploymentLabels(); len(labels) > 0 {
+		r = prometheus.WrapRegistererWith(labels, r)
+	}
+
+	viewsMu.Lock()
+	defer viewsMu.Unlock()
+	var cs []prometheus.Collector
+	for _, v := range defaultViews {
+		if o.excludeHighCardinality && v.HighCardinality {
+			continue
+		}
+		cs = append(cs, v.Collector)
+	}
+	return register(r, cs)
+}
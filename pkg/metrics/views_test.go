@@ -0,0 +1,98 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("RegisterDefaultViews", func() {
+	var r *prometheus.Registry
+
+	BeforeEach(func() {
+		r = prometheus.NewRegistry()
+	})
+
+	It("should register the default views", func() {
+		Expect(metrics.RegisterDefaultViews(r)).To(Succeed())
+
+		err := r.Register(ctrlmetrics.ReconcileTotal)
+		Expect(err).To(BeAssignableToTypeOf(prometheus.AlreadyRegisteredError{}))
+		err = r.Register(ctrlmetrics.ReconcileNamespaceTotal)
+		Expect(err).To(BeAssignableToTypeOf(prometheus.AlreadyRegisteredError{}))
+	})
+
+	It("should be idempotent", func() {
+		Expect(metrics.RegisterDefaultViews(r)).To(Succeed())
+		Expect(metrics.RegisterDefaultViews(r)).To(Succeed())
+	})
+
+	It("should exclude the high cardinality views", func() {
+		Expect(metrics.RegisterDefaultViews(r, metrics.WithoutHighCardinalityViews())).To(Succeed())
+
+		err := r.Register(ctrlmetrics.ReconcileTotal)
+		Expect(err).To(BeAssignableToTypeOf(prometheus.AlreadyRegisteredError{}))
+		Expect(r.Register(ctrlmetrics.ReconcileNamespaceTotal)).To(Succeed())
+		Expect(r.Register(ctrlmetrics.ReconcileThrottled)).To(Succeed())
+	})
+
+	It("should aggregate the errors of the views which could not be registered", func() {
+		Expect(r.Register(prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "controller_runtime_reconcile_total",
+			Help: "Conflicting metric",
+		}))).To(Succeed())
+		Expect(r.Register(prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "controller_runtime_reconcile_errors_total",
+			Help: "Conflicting metric",
+		}))).To(Succeed())
+
+		err := metrics.RegisterDefaultViews(r)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("controller_runtime_reconcile_total"))
+		Expect(err.Error()).To(ContainSubstring("controller_runtime_reconcile_errors_total"))
+
+		err = r.Register(ctrlmetrics.QueueLength)
+		Expect(err).To(BeAssignableToTypeOf(prometheus.AlreadyRegisteredError{}))
+	})
+})
+
+var _ = Describe("Reset", func() {
+	It("should delete the data recorded by the default views", func() {
+		ctrlmetrics.ReconcileErrors.WithLabelValues("reset").Inc()
+		Expect(collectedCount(ctrlmetrics.ReconcileErrors)).NotTo(BeZero())
+
+		metrics.Reset()
+		Expect(collectedCount(ctrlmetrics.ReconcileErrors)).To(BeZero())
+	})
+
+	It("should keep the data of the Constant views", func() {
+		metrics.Reset()
+		Expect(collectedCount(metrics.BuildInfo)).To(Equal(1))
+	})
+})
+
+// collectedCount returns the number of metrics collected from c.
+func collectedCount(c prometheus.Collector) int {
+	ch := make(chan prometheus.Metric, 100)
+	c.Collect(ch)
+	close(ch)
+	return len(ch)
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package operation tracks long-running external operations, such as provisioning cloud resources, so that
+they run outside of the reconcile calls and don't block the workers of a Controller.
+
+A Tracker is added to the Manager, which runs its pool of workers, and its Source is watched by the
+Controller, which is sent an event for the Request of each operation once it completes:
+
+	tracker := &operation.Tracker{Name: "instances", Workers: 4}
+	if err := mgr.Add(tracker); err != nil {
+		// handle error
+	}
+	err := c.Watch(tracker.Source(), &handler.EnqueueRequestForObject{})
+	if err != nil {
+		// handle error
+	}
+
+The Reconciler submits the operation, and returns while it is in flight.  Submitting the operation again
+returns its Status, so that the Reconciler handles the result of the operation once it is re-enqueued,
+and then forgets it:
+
+	status, err := tracker.Submit(ctx, string(instance.UID), req, func(ctx context.Context) error {
+		return cloud.Provision(ctx, instance.Spec)
+	})
+	if err != nil || !status.Done() {
+		return reconcile.Result{}, err
+	}
+	if status.State == operation.Failed {
+		// report status.Error
+	}
+	err = tracker.Forget(ctx, status.ID)
+
+The operations are retried with the Backoff of the Tracker until they succeed or run out of attempts.  The
+Statuses of the operations are saved in the Store of the Tracker, if set, so that the results of the
+operations survive restarts, and the Requests of the operations interrupted by a restart are re-enqueued
+so that they can be submitted again.
+*/
+package operation
@@ -0,0 +1,69 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// The values of the result label of OperationsTotal.
+const (
+	ResultSucceeded = "succeeded"
+	ResultFailed    = "failed"
+)
+
+var (
+	// OperationsInFlight is a prometheus metric which holds the number of
+	// operations submitted and not completed yet per tracker
+	OperationsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "controller_runtime_operations_in_flight",
+		Help: "Number of operations submitted and not completed yet per tracker",
+	}, []string{"tracker"})
+
+	// OperationsTotal is a prometheus counter metrics which holds the total
+	// number of completed operations per tracker and result, i.e. succeeded
+	// or failed
+	OperationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_operations_total",
+		Help: "Total number of completed operations per tracker and result",
+	}, []string{"tracker", "result"})
+
+	// OperationRetries is a prometheus counter metrics which holds the total
+	// number of failed attempts of operations which were retried per tracker
+	OperationRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_operation_retries_total",
+		Help: "Total number of retried attempts of operations per tracker",
+	}, []string{"tracker"})
+
+	// OperationTime is a prometheus metric which keeps track of the duration
+	// of operations, from their submission to their completion, per tracker
+	OperationTime = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "controller_runtime_operation_time_seconds",
+		Help:    "Length of time from the submission to the completion of operations per tracker",
+		Buckets: prometheus.ExponentialBuckets(0.1, 2, 14),
+	}, []string{"tracker"})
+)
+
+func init() {
+	metrics.AddDefaultViews(
+		metrics.View{Collector: OperationsInFlight},
+		metrics.View{Collector: OperationsTotal},
+		metrics.View{Collector: OperationRetries},
+		metrics.View{Collector: OperationTime},
+	)
+}
@@ -0,0 +1,419 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/operation/internal/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.KBLog.WithName("operation")
+
+// DefaultBackoff is the Backoff of the Trackers which don't specify one: up to 5 attempts, starting 1
+// second apart.
+var DefaultBackoff = wait.Backoff{Duration: time.Second, Factor: 2, Jitter: 0.1, Steps: 5}
+
+// Func runs an operation.  The ctx is cancelled when the Tracker is stopped.
+type Func func(ctx context.Context) error
+
+// State is the state of an operation.
+type State string
+
+const (
+	// Pending operations are waiting for a worker.
+	Pending State = "Pending"
+
+	// Running operations are run by a worker, possibly after failed attempts.
+	Running State = "Running"
+
+	// Succeeded operations completed without error.
+	Succeeded State = "Succeeded"
+
+	// Failed operations ran out of attempts.  The Error of their Status is the error of the last attempt.
+	Failed State = "Failed"
+)
+
+// Status is the status of an operation.
+type Status struct {
+	// ID identifies the operation.
+	ID string `json:"id"`
+
+	// Request is re-enqueued once the operation completes.
+	Request reconcile.Request `json:"request"`
+
+	// State is the state of the operation.
+	State State `json:"state"`
+
+	// Attempts is the number of attempts of the operation so far.
+	Attempts int `json:"attempts,omitempty"`
+
+	// Error is the error of the last failed attempt of the operation.
+	Error string `json:"error,omitempty"`
+
+	// SubmitTime is the time the operation was submitted.
+	SubmitTime time.Time `json:"submitTime"`
+
+	// CompletionTime is the time the operation succeeded or failed.
+	CompletionTime time.Time `json:"completionTime,omitempty"`
+}
+
+// Done returns whether the operation succeeded or failed.
+func (s Status) Done() bool {
+	return s.State == Succeeded || s.State == Failed
+}
+
+// Store persists the Statuses of the operations of a Tracker, e.g. in ConfigMaps or in an external database.
+type Store interface {
+	// Save saves the Status of an operation, whenever it changes.
+	Save(ctx context.Context, status Status) error
+
+	// Delete deletes the Status of an operation once it is forgotten.
+	Delete(ctx context.Context, id string) error
+
+	// List returns the Statuses saved, when the Tracker is started.
+	List(ctx context.Context) ([]Status, error)
+}
+
+var _ manager.Runnable = &Tracker{}
+
+// Tracker runs long-running operations in a pool of workers, retries them, and re-enqueues their Requests
+// once they complete.  It must be added to the Manager to run the operations.
+type Tracker struct {
+	// Name is the name of the Tracker in the metrics and logs.
+	Name string
+
+	// Workers is the maximum number of operations running concurrently.  Defaults to 1.
+	Workers int
+
+	// Backoff is the backoff between the attempts of the operations, whose Steps is the maximum number of
+	// attempts.  Defaults to DefaultBackoff.
+	Backoff wait.Backoff
+
+	// Store, if set, persists the Statuses of the operations.
+	Store Store
+
+	once   sync.Once
+	mu     sync.Mutex
+	queue  workqueue.Interface
+	ops    map[string]*op
+	sinks  []sink
+	unsent []reconcile.Request
+}
+
+// op is an operation tracked.
+type op struct {
+	status Status
+	fn     Func
+}
+
+// sink is a Controller watching the Source of a Tracker.
+type sink struct {
+	handler    handler.EventHandler
+	queue      workqueue.RateLimitingInterface
+	predicates []predicate.Predicate
+}
+
+func (t *Tracker) init() {
+	t.once.Do(func() {
+		if t.Workers <= 0 {
+			t.Workers = 1
+		}
+		if t.Backoff == (wait.Backoff{}) {
+			t.Backoff = DefaultBackoff
+		}
+		t.queue = workqueue.New()
+		t.ops = map[string]*op{}
+	})
+}
+
+// Submit submits the operation id, run by fn, whose Request is re-enqueued once it completes.  If the
+// operation was already submitted and not forgotten, its Status is returned and fn is ignored.
+func (t *Tracker) Submit(ctx context.Context, id string, req reconcile.Request, fn Func) (Status, error) {
+	t.init()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if o, ok := t.ops[id]; ok {
+		return o.status, nil
+	}
+	o := &op{
+		status: Status{ID: id, Request: req, State: Pending, SubmitTime: time.Now()},
+		fn:     fn,
+	}
+	if t.Store != nil {
+		if err := t.Store.Save(ctx, o.status); err != nil {
+			return Status{}, fmt.Errorf("unable to save the status of operation %s: %v", id, err)
+		}
+	}
+	t.ops[id] = o
+	metrics.OperationsInFlight.WithLabelValues(t.Name).Inc()
+	t.queue.Add(id)
+	return o.status, nil
+}
+
+// Get returns the Status of the operation id, and whether it was submitted and not forgotten.
+func (t *Tracker) Get(id string) (Status, bool) {
+	t.init()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	o, ok := t.ops[id]
+	if !ok {
+		return Status{}, false
+	}
+	return o.status, true
+}
+
+// Forget forgets the completed operation id, so that it can be submitted again.
+func (t *Tracker) Forget(ctx context.Context, id string) error {
+	t.init()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	o, ok := t.ops[id]
+	if !ok {
+		return nil
+	}
+	if !o.status.Done() {
+		return fmt.Errorf("operation %s is still in flight", id)
+	}
+	if t.Store != nil {
+		if err := t.Store.Delete(ctx, id); err != nil {
+			return fmt.Errorf("unable to delete the status of operation %s: %v", id, err)
+		}
+	}
+	delete(t.ops, id)
+	return nil
+}
+
+// Source returns the Source of the events for the Requests of the completed operations, which are
+// GenericEvents whose Meta has the Namespace and Name of the Request.
+func (t *Tracker) Source() source.Source {
+	return source.Func(func(h handler.EventHandler, q workqueue.RateLimitingInterface, prct ...predicate.Predicate) error {
+		t.init()
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		s := sink{handler: h, queue: q, predicates: prct}
+		t.sinks = append(t.sinks, s)
+		for _, req := range t.unsent {
+			s.send(req)
+		}
+		t.unsent = nil
+		return nil
+	})
+}
+
+// Start implements manager.Runnable.  It runs the operations until stop is closed, and then cancels the
+// running operations.  The operations interrupted are left Pending in the Store.
+func (t *Tracker) Start(stop <-chan struct{}) error {
+	t.init()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := t.restore(ctx); err != nil {
+		return err
+	}
+
+	wg := &sync.WaitGroup{}
+	wg.Add(t.Workers)
+	for i := 0; i < t.Workers; i++ {
+		go func() {
+			defer wg.Done()
+			for t.processNext(ctx) {
+			}
+		}()
+	}
+
+	<-stop
+	cancel()
+	t.queue.ShutDown()
+	wg.Wait()
+	return nil
+}
+
+// restore restores the Statuses of the Store.  The Func of the operations interrupted by a restart is lost,
+// so they are deleted and their Requests re-enqueued to submit them again.
+func (t *Tracker) restore(ctx context.Context) error {
+	if t.Store == nil {
+		return nil
+	}
+	statuses, err := t.Store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list the statuses of the operations: %v", err)
+	}
+
+	for _, status := range statuses {
+		t.mu.Lock()
+		_, submitted := t.ops[status.ID]
+		if !submitted && status.Done() {
+			t.ops[status.ID] = &op{status: status}
+		}
+		t.mu.Unlock()
+		if submitted {
+			continue
+		}
+		if !status.Done() {
+			log.Info("Resubmitting interrupted operation", "tracker", t.Name, "id", status.ID)
+			if err := t.Store.Delete(ctx, status.ID); err != nil {
+				return fmt.Errorf("unable to delete the status of operation %s: %v", status.ID, err)
+			}
+		}
+		t.notify(status.Request)
+	}
+	return nil
+}
+
+func (t *Tracker) processNext(ctx context.Context) bool {
+	item, shutdown := t.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer t.queue.Done(item)
+	t.run(ctx, item.(string))
+	return true
+}
+
+// run runs the operation id, with the Backoff between its attempts.
+func (t *Tracker) run(ctx context.Context, id string) {
+	t.mu.Lock()
+	o := t.ops[id]
+	t.mu.Unlock()
+	if o == nil {
+		return
+	}
+
+	duration := t.Backoff.Duration
+	for {
+		var err error
+		t.update(o, func(s *Status) {
+			s.State = Running
+			s.Attempts++
+		})
+		if err = o.fn(ctx); err == nil {
+			t.update(o, func(s *Status) {
+				s.State = Succeeded
+				s.CompletionTime = time.Now()
+			})
+			break
+		}
+		t.update(o, func(s *Status) { s.Error = err.Error() })
+		if ctx.Err() != nil {
+			// The Tracker was stopped, leave the operation to resubmit it after a restart
+			t.update(o, func(s *Status) { s.State = Pending })
+			return
+		}
+
+		status := t.status(o)
+		if status.Attempts >= t.Backoff.Steps {
+			log.Error(err, "Operation failed", "tracker", t.Name, "id", id, "attempts", status.Attempts)
+			t.update(o, func(s *Status) {
+				s.State = Failed
+				s.CompletionTime = time.Now()
+			})
+			break
+		}
+		log.Info("Retrying failed operation", "tracker", t.Name, "id", id, "attempts", status.Attempts, "error", err.Error())
+		metrics.OperationRetries.WithLabelValues(t.Name).Inc()
+
+		delay := duration
+		if t.Backoff.Jitter > 0 {
+			delay = wait.Jitter(duration, t.Backoff.Jitter)
+		}
+		duration = time.Duration(float64(duration) * t.Backoff.Factor)
+		select {
+		case <-ctx.Done():
+			t.update(o, func(s *Status) { s.State = Pending })
+			return
+		case <-time.After(delay):
+		}
+	}
+
+	status := t.status(o)
+	result := metrics.ResultSucceeded
+	if status.State == Failed {
+		result = metrics.ResultFailed
+	}
+	metrics.OperationsInFlight.WithLabelValues(t.Name).Dec()
+	metrics.OperationsTotal.WithLabelValues(t.Name, result).Inc()
+	metrics.OperationTime.WithLabelValues(t.Name).Observe(status.CompletionTime.Sub(status.SubmitTime).Seconds())
+	t.notify(status.Request)
+}
+
+// update updates the Status of o, and saves it in the Store.
+func (t *Tracker) update(o *op, f func(*Status)) {
+	t.mu.Lock()
+	f(&o.status)
+	status := o.status
+	t.mu.Unlock()
+
+	if t.Store == nil {
+		return
+	}
+	// The operation may be interrupted, but its Status must still be saved
+	if err := t.Store.Save(context.Background(), status); err != nil {
+		log.Error(err, "Unable to save the status of operation", "tracker", t.Name, "id", status.ID)
+	}
+}
+
+func (t *Tracker) status(o *op) Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return o.status
+}
+
+// notify sends the event for req to the Controllers watching the Source, or keeps it until a Controller
+// watches it.
+func (t *Tracker) notify(req reconcile.Request) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.sinks) == 0 {
+		t.unsent = append(t.unsent, req)
+		return
+	}
+	for _, s := range t.sinks {
+		s.send(req)
+	}
+}
+
+func (s sink) send(req reconcile.Request) {
+	obj := &unstructured.Unstructured{}
+	obj.SetNamespace(req.Namespace)
+	obj.SetName(req.Name)
+	evt := event.GenericEvent{Meta: obj, Object: obj}
+	for _, p := range s.predicates {
+		if !p.Generic(evt) {
+			return
+		}
+	}
+	s.handler.Generic(evt, s.queue)
+}
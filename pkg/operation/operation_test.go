@@ -0,0 +1,256 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operation_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/operation"
+	"sigs.k8s.io/controller-runtime/pkg/operation/internal/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Tracker", func() {
+	var stop chan struct{}
+	var stopped chan struct{}
+	var queue workqueue.RateLimitingInterface
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "instance"}}
+
+	start := func(t *operation.Tracker) {
+		Expect(t.Source().Start(&handler.EnqueueRequestForObject{}, queue)).To(Succeed())
+		go func() {
+			defer GinkgoRecover()
+			defer close(stopped)
+			Expect(t.Start(stop)).To(Succeed())
+		}()
+	}
+
+	requeued := func() reconcile.Request {
+		item, _ := queue.Get()
+		queue.Done(item)
+		return item.(reconcile.Request)
+	}
+
+	gauge := func(name string) float64 {
+		m := &dto.Metric{}
+		Expect(metrics.OperationsInFlight.WithLabelValues(name).Write(m)).To(Succeed())
+		return m.GetGauge().GetValue()
+	}
+
+	BeforeEach(func() {
+		stop = make(chan struct{})
+		stopped = make(chan struct{})
+		queue = workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	})
+
+	AfterEach(func() {
+		close(stop)
+		Eventually(stopped).Should(BeClosed())
+		queue.ShutDown()
+	})
+
+	It("should run the operations and re-enqueue their Requests once they complete", func() {
+		t := &operation.Tracker{Name: "succeeding"}
+		runs := make(chan struct{}, 2)
+		release := make(chan struct{})
+		fn := func(context.Context) error {
+			runs <- struct{}{}
+			<-release
+			return nil
+		}
+		status, err := t.Submit(ctx, "op", req, fn)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.State).To(Equal(operation.Pending))
+		Expect(gauge("succeeding")).To(Equal(1.0))
+		start(t)
+
+		Eventually(runs).Should(Receive())
+		status, err = t.Submit(ctx, "op", req, fn)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.State).To(Equal(operation.Running))
+		Expect(t.Forget(ctx, "op")).To(MatchError(ContainSubstring("still in flight")))
+
+		close(release)
+		Expect(requeued()).To(Equal(req))
+		status, ok := t.Get("op")
+		Expect(ok).To(BeTrue())
+		Expect(status.State).To(Equal(operation.Succeeded))
+		Expect(status.Attempts).To(Equal(1))
+		Expect(status.Done()).To(BeTrue())
+		Expect(gauge("succeeding")).To(Equal(0.0))
+
+		By("returning the Status of the completed operation until it is forgotten")
+		status, err = t.Submit(ctx, "op", req, fn)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.State).To(Equal(operation.Succeeded))
+		Expect(runs).NotTo(Receive())
+
+		Expect(t.Forget(ctx, "op")).To(Succeed())
+		_, ok = t.Get("op")
+		Expect(ok).To(BeFalse())
+		_, err = t.Submit(ctx, "op", req, fn)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(runs).Should(Receive())
+	})
+
+	It("should retry the failed operations until they run out of attempts", func() {
+		t := &operation.Tracker{Name: "retrying", Backoff: wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 3}}
+		start(t)
+
+		attempts := 0
+		_, err := t.Submit(ctx, "flaky", req, func(context.Context) error {
+			if attempts++; attempts < 2 {
+				return fmt.Errorf("unavailable")
+			}
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeued()).To(Equal(req))
+		status, _ := t.Get("flaky")
+		Expect(status.State).To(Equal(operation.Succeeded))
+		Expect(status.Attempts).To(Equal(2))
+
+		_, err = t.Submit(ctx, "broken", req, func(context.Context) error {
+			return fmt.Errorf("quota exceeded")
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeued()).To(Equal(req))
+		status, _ = t.Get("broken")
+		Expect(status.State).To(Equal(operation.Failed))
+		Expect(status.Attempts).To(Equal(3))
+		Expect(status.Error).To(Equal("quota exceeded"))
+		Expect(status.CompletionTime).NotTo(BeZero())
+	})
+
+	It("should save the Statuses in the Store, and restore them on start", func() {
+		other := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "other"}}
+		store := &memoryStore{statuses: map[string]operation.Status{
+			"done":        {ID: "done", Request: req, State: operation.Succeeded},
+			"interrupted": {ID: "interrupted", Request: other, State: operation.Running},
+		}}
+		t := &operation.Tracker{Name: "persistent", Store: store}
+		start(t)
+
+		Expect([]reconcile.Request{requeued(), requeued()}).To(ConsistOf(req, other))
+		status, ok := t.Get("done")
+		Expect(ok).To(BeTrue())
+		Expect(status.State).To(Equal(operation.Succeeded))
+		_, ok = t.Get("interrupted")
+		Expect(ok).To(BeFalse())
+		Eventually(store.ids).Should(ConsistOf("done"))
+
+		_, err := t.Submit(ctx, "interrupted", other, func(context.Context) error { return nil })
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeued()).To(Equal(other))
+		Expect(store.get("interrupted").State).To(Equal(operation.Succeeded))
+
+		Expect(t.Forget(ctx, "done")).To(Succeed())
+		Expect(store.ids()).To(ConsistOf("interrupted"))
+	})
+
+	It("should cancel the running operations when stopped, and leave them Pending in the Store", func() {
+		store := &memoryStore{statuses: map[string]operation.Status{}}
+		t := &operation.Tracker{Name: "stopped", Store: store}
+		start(t)
+
+		running := make(chan struct{})
+		_, err := t.Submit(ctx, "slow", req, func(ctx context.Context) error {
+			close(running)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(running).Should(BeClosed())
+
+		close(stop)
+		Eventually(stopped).Should(BeClosed())
+		Expect(store.get("slow").State).To(Equal(operation.Pending))
+		stop = make(chan struct{})
+		stopped = make(chan struct{})
+		close(stopped)
+	})
+
+	It("should return an error if the Status can't be saved", func() {
+		t := &operation.Tracker{Name: "failing-store", Store: &memoryStore{err: fmt.Errorf("unavailable")}}
+		_, err := t.Submit(ctx, "op", req, func(context.Context) error { return nil })
+		Expect(err).To(MatchError(ContainSubstring("unable to save the status of operation op")))
+		_, ok := t.Get("op")
+		Expect(ok).To(BeFalse())
+		stopped = make(chan struct{})
+		close(stopped)
+	})
+})
+
+type memoryStore struct {
+	mu       sync.Mutex
+	statuses map[string]operation.Status
+	err      error
+}
+
+func (s *memoryStore) Save(_ context.Context, status operation.Status) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.statuses[status.ID] = status
+	return nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.statuses, id)
+	return nil
+}
+
+func (s *memoryStore) List(context.Context) ([]operation.Status, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var statuses []operation.Status
+	for _, status := range s.statuses {
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (s *memoryStore) get(id string) operation.Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.statuses[id]
+}
+
+func (s *memoryStore) ids() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []string
+	for id := range s.statuses {
+		ids = append(ids, id)
+	}
+	return ids
+}
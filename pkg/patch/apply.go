@@ -0,0 +1,206 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/mattbaird/jsonpatch"
+)
+
+// ApplyJSONPatch applies patches to the JSON document doc and returns the patched document.
+// It supports the add, remove and replace operations, which are the operations generated
+// by NewJSONPatch, and the test operation.  The move and copy operations are refused, since
+// jsonpatch.JsonPatchOperation has no from field to express them.
+func ApplyJSONPatch(doc []byte, patches []jsonpatch.JsonPatchOperation) ([]byte, error) {
+	var root interface{}
+	if err := json.Unmarshal(doc, &root); err != nil {
+		return nil, err
+	}
+	for _, p := range patches {
+		var err error
+		root, err = applyOperation(root, p)
+		if err != nil {
+			return nil, fmt.Errorf("unable to apply %s %s: %v", p.Operation, p.Path, err)
+		}
+	}
+	return json.Marshal(root)
+}
+
+func applyOperation(root interface{}, p jsonpatch.JsonPatchOperation) (interface{}, error) {
+	if p.Operation != "add" && p.Operation != "remove" && p.Operation != "replace" && p.Operation != "test" {
+		return nil, fmt.Errorf("unsupported operation %q", p.Operation)
+	}
+	tokens, err := parsePointer(p.Path)
+	if err != nil {
+		return nil, err
+	}
+	// Use the values as decoded from JSON, e.g. with float64 numbers, like the rest of the document
+	if p.Value, err = decoded(p.Value); err != nil {
+		return nil, err
+	}
+	if p.Operation == "test" {
+		return root, testValue(root, tokens, p.Value)
+	}
+	if len(tokens) == 0 {
+		if p.Operation == "remove" {
+			return nil, nil
+		}
+		return p.Value, nil
+	}
+	return applyAt(root, tokens, p)
+}
+
+// applyAt applies p at tokens relative to node and returns the new value of node.
+func applyAt(node interface{}, tokens []string, p jsonpatch.JsonPatchOperation) (interface{}, error) {
+	token, last := tokens[0], len(tokens) == 1
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, found := n[token]
+		if !last {
+			if !found {
+				return nil, fmt.Errorf("path element %q not found", token)
+			}
+			updated, err := applyAt(child, tokens[1:], p)
+			if err != nil {
+				return nil, err
+			}
+			n[token] = updated
+			return n, nil
+		}
+		switch p.Operation {
+		case "add":
+			n[token] = p.Value
+		case "replace":
+			if !found {
+				return nil, fmt.Errorf("path element %q not found", token)
+			}
+			n[token] = p.Value
+		case "remove":
+			if !found {
+				return nil, fmt.Errorf("path element %q not found", token)
+			}
+			delete(n, token)
+		}
+		return n, nil
+	case []interface{}:
+		if last && p.Operation == "add" && token == "-" {
+			return append(n, p.Value), nil
+		}
+		i, err := arrayIndex(token)
+		if err != nil || i > len(n) || (i == len(n) && !(last && p.Operation == "add")) {
+			return nil, fmt.Errorf("invalid array index %q", token)
+		}
+		if !last {
+			updated, err := applyAt(n[i], tokens[1:], p)
+			if err != nil {
+				return nil, err
+			}
+			n[i] = updated
+			return n, nil
+		}
+		switch p.Operation {
+		case "add":
+			n = append(n, nil)
+			copy(n[i+1:], n[i:])
+			n[i] = p.Value
+		case "replace":
+			n[i] = p.Value
+		case "remove":
+			n = append(n[:i], n[i+1:]...)
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("path element %q does not refer to an object or array", token)
+	}
+}
+
+// testValue returns an error unless the value at tokens relative to root is equal to value.
+func testValue(root interface{}, tokens []string, value interface{}) error {
+	actual, err := valueAt(root, tokens)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(actual, value) {
+		return fmt.Errorf("value is %v, not %v", actual, value)
+	}
+	return nil
+}
+
+// decoded returns value as decoded from its JSON encoding.
+func decoded(value interface{}) (interface{}, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// valueAt returns the value at tokens relative to node.
+func valueAt(node interface{}, tokens []string) (interface{}, error) {
+	for _, token := range tokens {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			child, found := n[token]
+			if !found {
+				return nil, fmt.Errorf("path element %q not found", token)
+			}
+			node = child
+		case []interface{}:
+			i, err := arrayIndex(token)
+			if err != nil || i >= len(n) {
+				return nil, fmt.Errorf("invalid array index %q", token)
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("path element %q does not refer to an object or array", token)
+		}
+	}
+	return node, nil
+}
+
+// arrayIndex returns the array index token refers to, which must be a number without
+// leading zeros or sign.
+func arrayIndex(token string) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || token[0] < '0' || token[0] > '9' {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	return strconv.Atoi(token)
+}
+
+// parsePointer splits the JSON pointer path into its unescaped reference tokens.
+func parsePointer(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", path)
+	}
+	tokens := strings.Split(path[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(t, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
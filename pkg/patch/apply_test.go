@@ -0,0 +1,189 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patch_test
+
+import (
+	"github.com/mattbaird/jsonpatch"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/patch"
+)
+
+var _ = Describe("ApplyJSONPatch", func() {
+	const doc = `{"a":{"b":"c","d/e":1,"f~g":2},"list":[1,2,3]}`
+
+	op := func(operation, path string, value interface{}) jsonpatch.JsonPatchOperation {
+		return jsonpatch.JsonPatchOperation{Operation: operation, Path: path, Value: value}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		patches  []jsonpatch.JsonPatchOperation
+		expected string
+	}{
+		{
+			name:     "should add a member",
+			patches:  []jsonpatch.JsonPatchOperation{op("add", "/a/h", "i")},
+			expected: `{"a":{"b":"c","d/e":1,"f~g":2,"h":"i"},"list":[1,2,3]}`,
+		},
+		{
+			name:     "should replace a member with add",
+			patches:  []jsonpatch.JsonPatchOperation{op("add", "/a/b", "x")},
+			expected: `{"a":{"b":"x","d/e":1,"f~g":2},"list":[1,2,3]}`,
+		},
+		{
+			name:     "should insert an array element",
+			patches:  []jsonpatch.JsonPatchOperation{op("add", "/list/1", 9)},
+			expected: `{"a":{"b":"c","d/e":1,"f~g":2},"list":[1,9,2,3]}`,
+		},
+		{
+			name:     "should add an array element after the last one",
+			patches:  []jsonpatch.JsonPatchOperation{op("add", "/list/3", 9)},
+			expected: `{"a":{"b":"c","d/e":1,"f~g":2},"list":[1,2,3,9]}`,
+		},
+		{
+			name:     "should append an array element with the - index",
+			patches:  []jsonpatch.JsonPatchOperation{op("add", "/list/-", 9)},
+			expected: `{"a":{"b":"c","d/e":1,"f~g":2},"list":[1,2,3,9]}`,
+		},
+		{
+			name:     "should remove a member",
+			patches:  []jsonpatch.JsonPatchOperation{op("remove", "/a/b", nil)},
+			expected: `{"a":{"d/e":1,"f~g":2},"list":[1,2,3]}`,
+		},
+		{
+			name:     "should remove an array element",
+			patches:  []jsonpatch.JsonPatchOperation{op("remove", "/list/0", nil)},
+			expected: `{"a":{"b":"c","d/e":1,"f~g":2},"list":[2,3]}`,
+		},
+		{
+			name:     "should replace a member",
+			patches:  []jsonpatch.JsonPatchOperation{op("replace", "/a", "x")},
+			expected: `{"a":"x","list":[1,2,3]}`,
+		},
+		{
+			name:     "should replace an array element",
+			patches:  []jsonpatch.JsonPatchOperation{op("replace", "/list/2", 9)},
+			expected: `{"a":{"b":"c","d/e":1,"f~g":2},"list":[1,2,9]}`,
+		},
+		{
+			name:     "should replace the whole document",
+			patches:  []jsonpatch.JsonPatchOperation{op("replace", "", map[string]interface{}{"x": 1})},
+			expected: `{"x":1}`,
+		},
+		{
+			name:     "should unescape ~1 to / in the path",
+			patches:  []jsonpatch.JsonPatchOperation{op("replace", "/a/d~1e", 9)},
+			expected: `{"a":{"b":"c","d/e":9,"f~g":2},"list":[1,2,3]}`,
+		},
+		{
+			name:     "should unescape ~0 to ~ in the path",
+			patches:  []jsonpatch.JsonPatchOperation{op("remove", "/a/f~0g", nil)},
+			expected: `{"a":{"b":"c","d/e":1},"list":[1,2,3]}`,
+		},
+		{
+			name:     "should pass the test operation when the values are equal",
+			patches:  []jsonpatch.JsonPatchOperation{op("test", "/a/d~1e", 1), op("test", "/list", []int{1, 2, 3}), op("test", "/a/b", "c")},
+			expected: doc,
+		},
+		{
+			name:     "should apply the operations in order",
+			patches:  []jsonpatch.JsonPatchOperation{op("add", "/list/-", 4), op("test", "/list/3", 4), op("remove", "/list/0", nil)},
+			expected: `{"a":{"b":"c","d/e":1,"f~g":2},"list":[2,3,4]}`,
+		},
+	} {
+		tc := tc
+		It(tc.name, func() {
+			patched, err := patch.ApplyJSONPatch([]byte(doc), tc.patches)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(patched).To(MatchJSON(tc.expected))
+		})
+	}
+
+	for _, tc := range []struct {
+		name    string
+		patch   jsonpatch.JsonPatchOperation
+		message string
+	}{
+		{
+			name:    "should fail the test operation when the values differ",
+			patch:   op("test", "/a/b", "x"),
+			message: "value is c, not x",
+		},
+		{
+			name:    "should refuse to add an array element beyond the last one",
+			patch:   op("add", "/list/4", 9),
+			message: `invalid array index "4"`,
+		},
+		{
+			name:    "should refuse to replace an array element out of range",
+			patch:   op("replace", "/list/3", 9),
+			message: `invalid array index "3"`,
+		},
+		{
+			name:    "should refuse to remove an array element out of range",
+			patch:   op("remove", "/list/-1", nil),
+			message: `invalid array index "-1"`,
+		},
+		{
+			name:    "should refuse the - index for other operations than add",
+			patch:   op("remove", "/list/-", nil),
+			message: `invalid array index "-"`,
+		},
+		{
+			name:    "should refuse array indexes with leading zeros",
+			patch:   op("replace", "/list/01", 9),
+			message: `invalid array index "01"`,
+		},
+		{
+			name:    "should refuse to replace a missing member",
+			patch:   op("replace", "/a/x", 9),
+			message: `path element "x" not found`,
+		},
+		{
+			name:    "should refuse to add below a missing member",
+			patch:   op("add", "/x/y", 9),
+			message: `path element "x" not found`,
+		},
+		{
+			name:    "should refuse paths through values which are not objects or arrays",
+			patch:   op("add", "/a/b/c", 9),
+			message: `path element "c" does not refer to an object or array`,
+		},
+		{
+			name:    "should refuse paths which are not JSON pointers",
+			patch:   op("add", "a", 9),
+			message: `invalid JSON pointer "a"`,
+		},
+		{
+			name:    "should refuse the move operation",
+			patch:   op("move", "/a/b", nil),
+			message: `unsupported operation "move"`,
+		},
+		{
+			name:    "should refuse the copy operation",
+			patch:   op("copy", "/a/b", nil),
+			message: `unsupported operation "copy"`,
+		},
+	} {
+		tc := tc
+		It(tc.name, func() {
+			_, err := patch.ApplyJSONPatch([]byte(doc), []jsonpatch.JsonPatchOperation{tc.patch})
+			Expect(err).To(MatchError(ContainSubstring(tc.message)))
+		})
+	}
+})
@@ -31,3 +31,9 @@ func ExampleFuncs() {
 		},
 	}
 }
+
+// This example creates a new Predicate to drop the Update Events which only changed the status of an
+// object, unless its labels changed.
+func ExampleOr() {
+	p = predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{})
+}
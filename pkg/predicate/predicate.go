@@ -17,6 +17,11 @@ limitations under the License.
 package predicate
 
 import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
@@ -40,6 +45,12 @@ type Predicate interface {
 
 var _ Predicate = Funcs{}
 var _ Predicate = ResourceVersionChangedPredicate{}
+var _ Predicate = GenerationChangedPredicate{}
+var _ Predicate = LabelChangedPredicate{}
+var _ Predicate = AnnotationChangedPredicate{}
+var _ Predicate = and{}
+var _ Predicate = or{}
+var _ Predicate = not{}
 
 // Funcs is a function that implements Predicate.
 type Funcs struct {
@@ -95,6 +106,59 @@ type ResourceVersionChangedPredicate struct {
 
 // Update implements default UpdateEvent filter for validating resource version change
 func (ResourceVersionChangedPredicate) Update(e event.UpdateEvent) bool {
+	if !hasMetadata(e) {
+		return false
+	}
+	if e.MetaNew.GetResourceVersion() == e.MetaOld.GetResourceVersion() {
+		return false
+	}
+	return true
+}
+
+// GenerationChangedPredicate implements a default update predicate function on generation change, so that
+// the updates of an object which only changed its status or metadata are skipped.  Objects whose
+// status is not a subresource get a new generation for every update.
+type GenerationChangedPredicate struct {
+	Funcs
+}
+
+// Update implements default UpdateEvent filter for validating generation change
+func (GenerationChangedPredicate) Update(e event.UpdateEvent) bool {
+	if !hasMetadata(e) {
+		return false
+	}
+	return e.MetaNew.GetGeneration() != e.MetaOld.GetGeneration()
+}
+
+// LabelChangedPredicate implements a default update predicate function on labels change
+type LabelChangedPredicate struct {
+	Funcs
+}
+
+// Update implements default UpdateEvent filter for validating labels change
+func (LabelChangedPredicate) Update(e event.UpdateEvent) bool {
+	if !hasMetadata(e) {
+		return false
+	}
+	return !labels.Equals(e.MetaNew.GetLabels(), e.MetaOld.GetLabels())
+}
+
+// AnnotationChangedPredicate implements a default update predicate function on annotations change
+type AnnotationChangedPredicate struct {
+	Funcs
+}
+
+// Update implements default UpdateEvent filter for validating annotations change
+func (AnnotationChangedPredicate) Update(e event.UpdateEvent) bool {
+	if !hasMetadata(e) {
+		return false
+	}
+	return !labels.Equals(e.MetaNew.GetAnnotations(), e.MetaOld.GetAnnotations())
+}
+
+// hasMetadata returns true if e has both its old and new objects and metadata, and logs the
+// missing one otherwise.
+func hasMetadata(e event.UpdateEvent) bool {
 	if e.MetaOld == nil {
 		log.Error(nil, "UpdateEvent has no old metadata", "event", e)
 		return false
@@ -111,8 +175,176 @@ func (ResourceVersionChangedPredicate) Update(e event.UpdateEvent) bool {
 		log.Error(nil, "UpdateEvent has no new metadata", "event", e)
 		return false
 	}
-	if e.MetaNew.GetResourceVersion() == e.MetaOld.GetResourceVersion() {
-		return false
+	return true
+}
+
+// NewPredicateFuncs returns a Predicate which processes the events of the objects for which filter
+// returns true.  Update events are processed if filter returns true for the new object.
+func NewPredicateFuncs(filter func(meta metav1.Object) bool) Predicate {
+	return Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return e.Meta != nil && filter(e.Meta)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return e.Meta != nil && filter(e.Meta)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.MetaNew != nil && filter(e.MetaNew)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return e.Meta != nil && filter(e.Meta)
+		},
+	}
+}
+
+// LabelSelectorPredicate returns a Predicate which processes the events of the objects whose labels
+// match selector.  It returns an error if selector is invalid.
+func LabelSelectorPredicate(selector metav1.LabelSelector) (Predicate, error) {
+	s, err := metav1.LabelSelectorAsSelector(&selector)
+	if err != nil {
+		return nil, err
+	}
+	return NewPredicateFuncs(func(meta metav1.Object) bool {
+		return s.Matches(labels.Set(meta.GetLabels()))
+	}), nil
+}
+
+// FieldSelectorPredicate returns a Predicate which processes the events of the objects whose fields
+// match selector, such as "metadata.namespace!=kube-system".  Only the metadata.name and
+// metadata.namespace fields are supported, as they are the only fields common to every object.  It
+// returns an error if selector is invalid or selects other fields.
+func FieldSelectorPredicate(selector string) (Predicate, error) {
+	s, err := fields.ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	s, err = s.Transform(func(field, value string) (string, string, error) {
+		if field != "metadata.name" && field != "metadata.namespace" {
+			return "", "", fmt.Errorf("field selector %q selects unsupported field %q", selector, field)
+		}
+		return field, value, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewPredicateFuncs(func(meta metav1.Object) bool {
+		return s.Matches(fields.Set{"metadata.name": meta.GetName(), "metadata.namespace": meta.GetNamespace()})
+	}), nil
+}
+
+// And returns a Predicate which processes an event only if all of predicates process it.  It
+// processes every event if predicates is empty.
+func And(predicates ...Predicate) Predicate {
+	return and{predicates}
+}
+
+type and struct {
+	predicates []Predicate
+}
+
+func (a and) Create(e event.CreateEvent) bool {
+	for _, p := range a.predicates {
+		if !p.Create(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a and) Delete(e event.DeleteEvent) bool {
+	for _, p := range a.predicates {
+		if !p.Delete(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a and) Update(e event.UpdateEvent) bool {
+	for _, p := range a.predicates {
+		if !p.Update(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a and) Generic(e event.GenericEvent) bool {
+	for _, p := range a.predicates {
+		if !p.Generic(e) {
+			return false
+		}
 	}
 	return true
 }
+
+// Or returns a Predicate which processes an event if any of predicates processes it.  It processes
+// no event if predicates is empty.
+func Or(predicates ...Predicate) Predicate {
+	return or{predicates}
+}
+
+type or struct {
+	predicates []Predicate
+}
+
+func (o or) Create(e event.CreateEvent) bool {
+	for _, p := range o.predicates {
+		if p.Create(e) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o or) Delete(e event.DeleteEvent) bool {
+	for _, p := range o.predicates {
+		if p.Delete(e) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o or) Update(e event.UpdateEvent) bool {
+	for _, p := range o.predicates {
+		if p.Update(e) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o or) Generic(e event.GenericEvent) bool {
+	for _, p := range o.predicates {
+		if p.Generic(e) {
+			return true
+		}
+	}
+	return false
+}
+
+// Not returns a Predicate which processes the events predicate doesn't process.
+func Not(predicate Predicate) Predicate {
+	return not{predicate}
+}
+
+type not struct {
+	predicate Predicate
+}
+
+func (n not) Create(e event.CreateEvent) bool {
+	return !n.predicate.Create(e)
+}
+
+func (n not) Delete(e event.DeleteEvent) bool {
+	return !n.predicate.Delete(e)
+}
+
+func (n not) Update(e event.UpdateEvent) bool {
+	return !n.predicate.Update(e)
+}
+
+func (n not) Generic(e event.GenericEvent) bool {
+	return !n.predicate.Generic(e)
+}
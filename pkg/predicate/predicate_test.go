@@ -308,4 +308,169 @@ var _ = Describe("Predicate", func() {
 		})
 
 	})
+
+	Describe("When checking a GenerationChangedPredicate", func() {
+		instance := predicate.GenerationChangedPredicate{}
+
+		It("should return true only if the Generation has changed", func() {
+			old := pod.DeepCopy()
+			old.Generation = 1
+			new := pod.DeepCopy()
+			new.Generation = 1
+			new.ResourceVersion = "v2"
+			Expect(instance.Create(event.CreateEvent{})).Should(BeTrue())
+			Expect(instance.Delete(event.DeleteEvent{})).Should(BeTrue())
+			Expect(instance.Generic(event.GenericEvent{})).Should(BeTrue())
+			Expect(instance.Update(updateEvent(old, new))).Should(BeFalse())
+
+			new.Generation = 2
+			Expect(instance.Update(updateEvent(old, new))).Should(BeTrue())
+			Expect(instance.Update(event.UpdateEvent{MetaOld: old.GetObjectMeta(), ObjectOld: old})).Should(BeFalse())
+		})
+	})
+
+	Describe("When checking a LabelChangedPredicate", func() {
+		instance := predicate.LabelChangedPredicate{}
+
+		It("should return true only if the labels have changed", func() {
+			old := pod.DeepCopy()
+			new := pod.DeepCopy()
+			new.Labels = map[string]string{}
+			new.Annotations = map[string]string{"foo": "bar"}
+			Expect(instance.Create(event.CreateEvent{})).Should(BeTrue())
+			Expect(instance.Update(updateEvent(old, new))).Should(BeFalse())
+
+			new.Labels["foo"] = "bar"
+			Expect(instance.Update(updateEvent(old, new))).Should(BeTrue())
+			old.Labels = map[string]string{"foo": "baz"}
+			Expect(instance.Update(updateEvent(old, new))).Should(BeTrue())
+			Expect(instance.Update(event.UpdateEvent{MetaNew: new.GetObjectMeta(), ObjectNew: new})).Should(BeFalse())
+		})
+	})
+
+	Describe("When checking an AnnotationChangedPredicate", func() {
+		instance := predicate.AnnotationChangedPredicate{}
+
+		It("should return true only if the annotations have changed", func() {
+			old := pod.DeepCopy()
+			new := pod.DeepCopy()
+			new.Annotations = map[string]string{}
+			new.Labels = map[string]string{"foo": "bar"}
+			Expect(instance.Create(event.CreateEvent{})).Should(BeTrue())
+			Expect(instance.Update(updateEvent(old, new))).Should(BeFalse())
+
+			new.Annotations["foo"] = "bar"
+			Expect(instance.Update(updateEvent(old, new))).Should(BeTrue())
+			Expect(instance.Update(event.UpdateEvent{MetaNew: new.GetObjectMeta(), ObjectNew: new})).Should(BeFalse())
+		})
+	})
+
+	Describe("When checking a LabelSelectorPredicate", func() {
+		It("should process the events of the objects matching the selector", func() {
+			instance, err := predicate.LabelSelectorPredicate(metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "foo"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "tier", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"cache"}},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			matching := pod.DeepCopy()
+			matching.Labels = map[string]string{"app": "foo", "tier": "web"}
+			other := pod.DeepCopy()
+			other.Labels = map[string]string{"app": "foo", "tier": "cache"}
+			Expect(instance.Create(event.CreateEvent{Meta: matching.GetObjectMeta(), Object: matching})).To(BeTrue())
+			Expect(instance.Delete(event.DeleteEvent{Meta: matching.GetObjectMeta(), Object: matching})).To(BeTrue())
+			Expect(instance.Generic(event.GenericEvent{Meta: matching.GetObjectMeta(), Object: matching})).To(BeTrue())
+			Expect(instance.Update(updateEvent(other, matching))).To(BeTrue())
+			Expect(instance.Create(event.CreateEvent{Meta: other.GetObjectMeta(), Object: other})).To(BeFalse())
+			Expect(instance.Update(updateEvent(matching, other))).To(BeFalse())
+			Expect(instance.Create(event.CreateEvent{Meta: pod.GetObjectMeta(), Object: pod})).To(BeFalse())
+			Expect(instance.Create(event.CreateEvent{})).To(BeFalse())
+		})
+
+		It("should return an error if the selector is invalid", func() {
+			_, err := predicate.LabelSelectorPredicate(metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tier", Operator: "Foo"}},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("When checking a FieldSelectorPredicate", func() {
+		It("should process the events of the objects matching the selector", func() {
+			instance, err := predicate.FieldSelectorPredicate("metadata.namespace!=kube-system,metadata.name=baz")
+			Expect(err).NotTo(HaveOccurred())
+
+			other := pod.DeepCopy()
+			other.Namespace = "kube-system"
+			Expect(instance.Create(event.CreateEvent{Meta: pod.GetObjectMeta(), Object: pod})).To(BeTrue())
+			Expect(instance.Update(updateEvent(other, pod))).To(BeTrue())
+			Expect(instance.Create(event.CreateEvent{Meta: other.GetObjectMeta(), Object: other})).To(BeFalse())
+			Expect(instance.Delete(event.DeleteEvent{Meta: other.GetObjectMeta(), Object: other})).To(BeFalse())
+		})
+
+		It("should return an error if the selector is invalid or selects other fields", func() {
+			_, err := predicate.FieldSelectorPredicate("metadata.name")
+			Expect(err).To(HaveOccurred())
+			_, err = predicate.FieldSelectorPredicate("status.phase=Running")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("When composing Predicates", func() {
+		passFuncs := predicate.Funcs{}
+		failFuncs := predicate.Funcs{
+			CreateFunc:  func(event.CreateEvent) bool { return false },
+			DeleteFunc:  func(event.DeleteEvent) bool { return false },
+			UpdateFunc:  func(event.UpdateEvent) bool { return false },
+			GenericFunc: func(event.GenericEvent) bool { return false },
+		}
+		expectAll := func(p predicate.Predicate, expected bool) {
+			Expect(p.Create(event.CreateEvent{})).To(Equal(expected))
+			Expect(p.Delete(event.DeleteEvent{})).To(Equal(expected))
+			Expect(p.Update(event.UpdateEvent{})).To(Equal(expected))
+			Expect(p.Generic(event.GenericEvent{})).To(Equal(expected))
+		}
+
+		It("should process an event with And only if all the Predicates process it", func() {
+			expectAll(predicate.And(), true)
+			expectAll(predicate.And(passFuncs, passFuncs), true)
+			expectAll(predicate.And(passFuncs, failFuncs), false)
+			expectAll(predicate.And(failFuncs, passFuncs), false)
+		})
+
+		It("should process an event with Or if any of the Predicates processes it", func() {
+			expectAll(predicate.Or(), false)
+			expectAll(predicate.Or(passFuncs, failFuncs), true)
+			expectAll(predicate.Or(failFuncs, passFuncs), true)
+			expectAll(predicate.Or(failFuncs, failFuncs), false)
+		})
+
+		It("should process an event with Not only if the Predicate doesn't process it", func() {
+			expectAll(predicate.Not(passFuncs), false)
+			expectAll(predicate.Not(failFuncs), true)
+			expectAll(predicate.Not(predicate.And(passFuncs, failFuncs)), true)
+		})
+
+		It("should skip the status only updates of an object unless its labels changed", func() {
+			instance := predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{})
+			old := pod.DeepCopy()
+			new := pod.DeepCopy()
+			new.Status.Phase = corev1.PodRunning
+			Expect(instance.Update(updateEvent(old, new))).Should(BeFalse())
+
+			new.Labels = map[string]string{"foo": "bar"}
+			Expect(instance.Update(updateEvent(old, new))).Should(BeTrue())
+		})
+	})
 })
+
+func updateEvent(old, new *corev1.Pod) event.UpdateEvent {
+	return event.UpdateEvent{
+		MetaOld:   old.GetObjectMeta(),
+		ObjectOld: old,
+		MetaNew:   new.GetObjectMeta(),
+		ObjectNew: new,
+	}
+}
@@ -0,0 +1,117 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicate
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// TypedPredicate filters events for objects of type T before enqueuing the keys.
+type TypedPredicate[T any] interface {
+	// Create returns true if the Create event should be processed
+	Create(event.TypedCreateEvent[T]) bool
+
+	// Delete returns true if the Delete event should be processed
+	Delete(event.TypedDeleteEvent[T]) bool
+
+	// Update returns true if the Update event should be processed
+	Update(event.TypedUpdateEvent[T]) bool
+
+	// Generic returns true if the Generic event should be processed
+	Generic(event.TypedGenericEvent[T]) bool
+}
+
+// TypedFuncs is a function that implements TypedPredicate.
+type TypedFuncs[T any] struct {
+	// Create returns true if the Create event should be processed
+	CreateFunc func(event.TypedCreateEvent[T]) bool
+
+	// Delete returns true if the Delete event should be processed
+	DeleteFunc func(event.TypedDeleteEvent[T]) bool
+
+	// Update returns true if the Update event should be processed
+	UpdateFunc func(event.TypedUpdateEvent[T]) bool
+
+	// Generic returns true if the Generic event should be processed
+	GenericFunc func(event.TypedGenericEvent[T]) bool
+}
+
+// Create implements TypedPredicate
+func (p TypedFuncs[T]) Create(e event.TypedCreateEvent[T]) bool {
+	if p.CreateFunc != nil {
+		return p.CreateFunc(e)
+	}
+	return true
+}
+
+// Delete implements TypedPredicate
+func (p TypedFuncs[T]) Delete(e event.TypedDeleteEvent[T]) bool {
+	if p.DeleteFunc != nil {
+		return p.DeleteFunc(e)
+	}
+	return true
+}
+
+// Update implements TypedPredicate
+func (p TypedFuncs[T]) Update(e event.TypedUpdateEvent[T]) bool {
+	if p.UpdateFunc != nil {
+		return p.UpdateFunc(e)
+	}
+	return true
+}
+
+// Generic implements TypedPredicate
+func (p TypedFuncs[T]) Generic(e event.TypedGenericEvent[T]) bool {
+	if p.GenericFunc != nil {
+		return p.GenericFunc(e)
+	}
+	return true
+}
+
+// FromTyped returns a Predicate calling p for events whose objects are of type T, so that
+// typed predicates can be passed to Controller.Watch.  Events for objects of any other type
+// are filtered out.
+func FromTyped[T any](p TypedPredicate[T]) Predicate {
+	return typed[T]{p: p}
+}
+
+type typed[T any] struct {
+	p TypedPredicate[T]
+}
+
+func (t typed[T]) Create(e event.CreateEvent) bool {
+	te, ok := event.TypedCreateEventFrom[T](e)
+	return ok && t.p.Create(te)
+}
+
+func (t typed[T]) Delete(e event.DeleteEvent) bool {
+	te, ok := event.TypedDeleteEventFrom[T](e)
+	return ok && t.p.Delete(te)
+}
+
+func (t typed[T]) Update(e event.UpdateEvent) bool {
+	te, ok := event.TypedUpdateEventFrom[T](e)
+	return ok && t.p.Update(te)
+}
+
+func (t typed[T]) Generic(e event.GenericEvent) bool {
+	te, ok := event.TypedGenericEventFrom[T](e)
+	return ok && t.p.Generic(te)
+}
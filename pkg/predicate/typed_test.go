@@ -0,0 +1,72 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicate_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+var _ = Describe("TypedPredicate", func() {
+	var pod *corev1.Pod
+	var p predicate.Predicate
+
+	BeforeEach(func() {
+		pod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "biz", Name: "baz"}}
+		p = predicate.FromTyped[*corev1.Pod](predicate.TypedFuncs[*corev1.Pod]{
+			CreateFunc: func(e event.TypedCreateEvent[*corev1.Pod]) bool {
+				return e.Object.Name == "baz"
+			},
+			DeleteFunc: func(e event.TypedDeleteEvent[*corev1.Pod]) bool {
+				return e.DeleteStateUnknown
+			},
+			UpdateFunc: func(e event.TypedUpdateEvent[*corev1.Pod]) bool {
+				return e.ObjectOld.Name != e.ObjectNew.Name
+			},
+		})
+	})
+
+	It("should call the typed funcs with the typed objects", func() {
+		Expect(p.Create(event.CreateEvent{Meta: pod, Object: pod})).To(BeTrue())
+		Expect(p.Delete(event.DeleteEvent{Meta: pod, Object: pod, DeleteStateUnknown: true})).To(BeTrue())
+		Expect(p.Delete(event.DeleteEvent{Meta: pod, Object: pod})).To(BeFalse())
+
+		renamed := pod.DeepCopy()
+		renamed.Name = "qux"
+		Expect(p.Update(event.UpdateEvent{MetaOld: pod, ObjectOld: pod, MetaNew: renamed, ObjectNew: renamed})).To(BeTrue())
+		Expect(p.Update(event.UpdateEvent{MetaOld: pod, ObjectOld: pod, MetaNew: pod, ObjectNew: pod})).To(BeFalse())
+	})
+
+	It("should default to processing events with unset funcs", func() {
+		Expect(p.Generic(event.GenericEvent{Meta: pod, Object: pod})).To(BeTrue())
+	})
+
+	It("should filter out events for objects of other types", func() {
+		deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "biz", Name: "baz"}}
+		Expect(p.Create(event.CreateEvent{Meta: deploy, Object: deploy})).To(BeFalse())
+		Expect(p.Generic(event.GenericEvent{Meta: deploy, Object: deploy})).To(BeFalse())
+		Expect(p.Update(event.UpdateEvent{MetaOld: pod, ObjectOld: pod, MetaNew: deploy, ObjectNew: deploy})).To(BeFalse())
+	})
+})
@@ -0,0 +1,26 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package ratelimiter contains presets of the rate limiters delaying the reconcile.Requests requeued
+by Controllers, which can be set as the RateLimiter of the controller.Options.
+
+	c, err := controller.New("pod-controller", mgr, controller.Options{
+		Reconciler:  r,
+		RateLimiter: ratelimiter.WithMaxDelay(time.Minute),
+	})
+*/
+package ratelimiter
@@ -0,0 +1,112 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimiter
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	// DefaultBaseDelay is the delay of the first requeue of a request by the Default rate limiter.
+	DefaultBaseDelay = 5 * time.Millisecond
+
+	// DefaultMaxDelay is the maximum delay of the requeues of a request by the Default rate limiter.
+	DefaultMaxDelay = 1000 * time.Second
+
+	// DefaultQPS is the number of requeues per second allowed by the Default rate limiter.
+	DefaultQPS = 10
+
+	// DefaultBurst is the number of requeues allowed at once by the Default rate limiter.
+	DefaultBurst = 100
+)
+
+// Default returns the default rate limiter of Controllers, which delays the requeues of each request
+// exponentially from DefaultBaseDelay up to DefaultMaxDelay, and the requeues of all requests to
+// DefaultQPS with bursts of DefaultBurst.
+func Default() workqueue.RateLimiter {
+	return WithMaxDelay(DefaultMaxDelay)
+}
+
+// WithMaxDelay returns the Default rate limiter, except that the delay of the requeues of each request
+// is capped at maxDelay, so that requests failing for a long time are still retried regularly.
+func WithMaxDelay(maxDelay time.Duration) workqueue.RateLimiter {
+	return MaxOf(
+		ExponentialBackoff(DefaultBaseDelay, maxDelay),
+		Bucket(DefaultQPS, DefaultBurst),
+	)
+}
+
+// DefaultWithClock returns the Default rate limiter, except that the rate of the requeues of all requests
+// is measured by c, e.g. a fake clock which tests step instead of sleeping.
+func DefaultWithClock(c clock.Clock) workqueue.RateLimiter {
+	return MaxOf(
+		ExponentialBackoff(DefaultBaseDelay, DefaultMaxDelay),
+		BucketWithClock(DefaultQPS, DefaultBurst, c),
+	)
+}
+
+// ExponentialBackoff returns a rate limiter delaying the requeues of each request exponentially, doubling
+// the delay from baseDelay on each requeue up to maxDelay until the request is forgotten.
+func ExponentialBackoff(baseDelay, maxDelay time.Duration) workqueue.RateLimiter {
+	return workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay)
+}
+
+// Bucket returns a rate limiter delaying the requeues of all requests to qps per second with bursts of
+// burst requeues.
+func Bucket(qps float64, burst int) workqueue.RateLimiter {
+	return &workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(qps), burst)}
+}
+
+// BucketWithClock returns the Bucket rate limiter, except that its rate is measured by c.
+func BucketWithClock(qps float64, burst int, c clock.Clock) workqueue.RateLimiter {
+	return &clockedBucket{limiter: rate.NewLimiter(rate.Limit(qps), burst), clock: c}
+}
+
+// clockedBucket is a token bucket rate limiter whose rate is measured by a clock.
+type clockedBucket struct {
+	limiter *rate.Limiter
+	clock   clock.Clock
+}
+
+// When implements workqueue.RateLimiter
+func (b *clockedBucket) When(interface{}) time.Duration {
+	now := b.clock.Now()
+	return b.limiter.ReserveN(now, 1).DelayFrom(now)
+}
+
+// NumRequeues implements workqueue.RateLimiter
+func (b *clockedBucket) NumRequeues(interface{}) int {
+	return 0
+}
+
+// Forget implements workqueue.RateLimiter
+func (b *clockedBucket) Forget(interface{}) {}
+
+// FastSlow returns a rate limiter delaying the first fastAttempts requeues of each request by fastDelay,
+// and the following requeues by slowDelay, until the request is forgotten.
+func FastSlow(fastDelay, slowDelay time.Duration, fastAttempts int) workqueue.RateLimiter {
+	return workqueue.NewItemFastSlowRateLimiter(fastDelay, slowDelay, fastAttempts)
+}
+
+// MaxOf returns a rate limiter delaying each requeue by the longest delay of limiters.
+func MaxOf(limiters ...workqueue.RateLimiter) workqueue.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(limiters...)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimiter_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+)
+
+var _ = Describe("ratelimiter", func() {
+	It("should back off exponentially up to the default max delay by default", func() {
+		l := ratelimiter.Default()
+		Expect(l.When("a")).To(Equal(ratelimiter.DefaultBaseDelay))
+		Expect(l.When("a")).To(Equal(2 * ratelimiter.DefaultBaseDelay))
+		Expect(l.NumRequeues("a")).To(Equal(2))
+		for i := 0; i < 30; i++ {
+			l.When("b")
+		}
+		Expect(l.When("b")).To(Equal(ratelimiter.DefaultMaxDelay))
+
+		l.Forget("a")
+		Expect(l.NumRequeues("a")).To(Equal(0))
+		Expect(l.When("a")).To(Equal(ratelimiter.DefaultBaseDelay))
+	})
+
+	It("should cap the backoff at the max delay", func() {
+		l := ratelimiter.WithMaxDelay(time.Minute)
+		for i := 0; i < 30; i++ {
+			l.When("a")
+		}
+		Expect(l.When("a")).To(Equal(time.Minute))
+	})
+
+	It("should delay the requeues over the bucket limit", func() {
+		l := ratelimiter.Bucket(1, 2)
+		Expect(l.When("a")).To(BeZero())
+		Expect(l.When("b")).To(BeZero())
+		Expect(l.When("c")).To(BeNumerically(">", 0))
+	})
+
+	It("should measure the rate of the bucket with the clock", func() {
+		c := clock.NewFakeClock(time.Now())
+		l := ratelimiter.BucketWithClock(1, 1, c)
+		Expect(l.When("a")).To(BeZero())
+		Expect(l.When("b")).To(Equal(time.Second))
+		c.Step(2 * time.Second)
+		Expect(l.When("c")).To(BeZero())
+		Expect(l.When("d")).To(Equal(time.Second))
+
+		l = ratelimiter.DefaultWithClock(c)
+		Expect(l.When("a")).To(Equal(ratelimiter.DefaultBaseDelay))
+		Expect(l.When("a")).To(Equal(2 * ratelimiter.DefaultBaseDelay))
+	})
+
+	It("should delay the requeues by the fast delay and then the slow delay", func() {
+		l := ratelimiter.FastSlow(time.Millisecond, time.Second, 2)
+		Expect(l.When("a")).To(Equal(time.Millisecond))
+		Expect(l.When("a")).To(Equal(time.Millisecond))
+		Expect(l.When("a")).To(Equal(time.Second))
+	})
+
+	It("should delay by the longest delay of the limiters", func() {
+		l := ratelimiter.MaxOf(
+			ratelimiter.ExponentialBackoff(time.Millisecond, time.Hour),
+			ratelimiter.FastSlow(time.Second, time.Minute, 1),
+		)
+		Expect(l.When("a")).To(Equal(time.Second))
+		Expect(l.When("a")).To(Equal(time.Minute))
+	})
+})
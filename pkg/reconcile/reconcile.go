@@ -28,6 +28,7 @@ type Result struct {
 	Requeue bool
 
 	// RequeueAfter if greater than 0, tells the Controller to requeue the reconcile key after the Duration.
+	// Controllers with a RequeueAfterJitter add up to that fraction of the Duration at random.
 	RequeueAfter time.Duration
 }
 
@@ -0,0 +1,28 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package reference contains helpers for resolving references to Kubernetes objects,
+such as corev1.ObjectReference and corev1.TypedLocalObjectReference, into the
+typed objects they refer to.
+
+	obj, err := reference.Resolve(ctx, mgr.GetClient(), mgr.GetScheme(), &ref)
+	if err != nil {
+		// handle error
+	}
+	deploy, ok := obj.(*appsv1.Deployment)
+*/
+package reference
@@ -0,0 +1,130 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reference
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Resolve reads the object referred to by ref using c, into a new object of the kind
+// registered in scheme for the APIVersion and Kind of ref.  If ref has a UID, Resolve
+// returns an error if it does not match the UID of the object that was read, since
+// the reference is stale.
+func Resolve(ctx context.Context, c client.Reader, scheme *runtime.Scheme, ref *corev1.ObjectReference) (runtime.Object, error) {
+	if ref.Kind == "" {
+		return nil, fmt.Errorf("reference to %q is missing a kind", ref.Name)
+	}
+	if ref.APIVersion == "" {
+		return nil, fmt.Errorf("reference to %s %q is missing an apiVersion", ref.Kind, ref.Name)
+	}
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("reference to %s %q has an invalid apiVersion: %v", ref.Kind, ref.Name, err)
+	}
+
+	obj, err := newObject(scheme, gv.WithKind(ref.Kind))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, obj); err != nil {
+		return nil, err
+	}
+
+	if ref.UID != "" {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+		if accessor.GetUID() != ref.UID {
+			return nil, fmt.Errorf("reference to %s %s/%s is stale: expected uid %s but found %s",
+				ref.Kind, ref.Namespace, ref.Name, ref.UID, accessor.GetUID())
+		}
+	}
+	return obj, nil
+}
+
+// ResolveLocal reads the object in namespace referred to by ref using c.  Since ref only
+// has an APIGroup, the object is read using the most stable version of its kind
+// registered in scheme.  A nil APIGroup refers to the core group.
+func ResolveLocal(ctx context.Context, c client.Reader, scheme *runtime.Scheme, namespace string, ref *corev1.TypedLocalObjectReference) (runtime.Object, error) {
+	if ref.Kind == "" {
+		return nil, fmt.Errorf("reference to %q is missing a kind", ref.Name)
+	}
+	group := ""
+	if ref.APIGroup != nil {
+		group = *ref.APIGroup
+	}
+
+	gvk, err := kindForGroup(scheme, schema.GroupKind{Group: group, Kind: ref.Kind})
+	if err != nil {
+		return nil, err
+	}
+	obj, err := newObject(scheme, gvk)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// newObject returns a new object of kind gvk from scheme, with an error describing
+// which part of gvk is not registered if it cannot be created.
+func newObject(scheme *runtime.Scheme, gvk schema.GroupVersionKind) (runtime.Object, error) {
+	if !scheme.IsGroupRegistered(gvk.Group) {
+		return nil, fmt.Errorf("group %q is not registered in the scheme", gvk.Group)
+	}
+	if !scheme.IsVersionRegistered(gvk.GroupVersion()) {
+		return nil, fmt.Errorf("version %q is not registered in the scheme for group %q", gvk.Version, gvk.Group)
+	}
+	obj, err := scheme.New(gvk)
+	if err != nil {
+		return nil, fmt.Errorf("kind %q is not registered in the scheme for %s: %v", gvk.Kind, gvk.GroupVersion(), err)
+	}
+	return obj, nil
+}
+
+// kindForGroup returns the most stable version of gk registered in scheme, e.g. v1 is
+// preferred over v1beta1.
+func kindForGroup(scheme *runtime.Scheme, gk schema.GroupKind) (schema.GroupVersionKind, error) {
+	if !scheme.IsGroupRegistered(gk.Group) {
+		return schema.GroupVersionKind{}, fmt.Errorf("group %q is not registered in the scheme", gk.Group)
+	}
+	var found *schema.GroupVersionKind
+	for _, gv := range scheme.PrioritizedVersionsForGroup(gk.Group) {
+		gvk := gv.WithKind(gk.Kind)
+		if !scheme.Recognizes(gvk) {
+			continue
+		}
+		if found == nil || version.CompareKubeAwareVersionStrings(gvk.Version, found.Version) > 0 {
+			found = &gvk
+		}
+	}
+	if found == nil {
+		return schema.GroupVersionKind{}, fmt.Errorf("kind %q is not registered in the scheme for group %q", gk.Kind, gk.Group)
+	}
+	return *found, nil
+}
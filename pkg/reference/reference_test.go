@@ -0,0 +1,116 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reference_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reference"
+)
+
+var _ = Describe("Reference", func() {
+	var c client.Client
+
+	BeforeEach(func() {
+		c = fake.NewFakeClient(
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "deploy", UID: "deploy-uid"}},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cm"}},
+		)
+	})
+
+	Describe("Resolve", func() {
+		It("should read the referenced object into its typed kind", func() {
+			obj, err := reference.Resolve(context.TODO(), c, scheme.Scheme, &corev1.ObjectReference{
+				APIVersion: "apps/v1", Kind: "Deployment", Namespace: "default", Name: "deploy", UID: "deploy-uid",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(obj).To(BeAssignableToTypeOf(&appsv1.Deployment{}))
+			Expect(obj.(*appsv1.Deployment).Name).To(Equal("deploy"))
+		})
+
+		It("should return an error if the reference is stale", func() {
+			_, err := reference.Resolve(context.TODO(), c, scheme.Scheme, &corev1.ObjectReference{
+				APIVersion: "apps/v1", Kind: "Deployment", Namespace: "default", Name: "deploy", UID: "other-uid",
+			})
+			Expect(err).To(MatchError(ContainSubstring("is stale")))
+		})
+
+		It("should return an error if the kind is missing", func() {
+			_, err := reference.Resolve(context.TODO(), c, scheme.Scheme, &corev1.ObjectReference{
+				APIVersion: "apps/v1", Namespace: "default", Name: "deploy",
+			})
+			Expect(err).To(MatchError(ContainSubstring("missing a kind")))
+		})
+
+		It("should return an error if the group is not registered", func() {
+			_, err := reference.Resolve(context.TODO(), c, scheme.Scheme, &corev1.ObjectReference{
+				APIVersion: "example.com/v1", Kind: "Deployment", Namespace: "default", Name: "deploy",
+			})
+			Expect(err).To(MatchError(ContainSubstring(`group "example.com" is not registered`)))
+		})
+
+		It("should return an error if the kind is not registered for the group", func() {
+			_, err := reference.Resolve(context.TODO(), c, scheme.Scheme, &corev1.ObjectReference{
+				APIVersion: "apps/v1", Kind: "ConfigMap", Namespace: "default", Name: "cm",
+			})
+			Expect(err).To(MatchError(ContainSubstring(`kind "ConfigMap" is not registered`)))
+		})
+
+		It("should return the error from the client if the object does not exist", func() {
+			_, err := reference.Resolve(context.TODO(), c, scheme.Scheme, &corev1.ObjectReference{
+				APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "missing",
+			})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Describe("ResolveLocal", func() {
+		It("should read a referenced object in the core group", func() {
+			obj, err := reference.ResolveLocal(context.TODO(), c, scheme.Scheme, "default", &corev1.TypedLocalObjectReference{
+				Kind: "ConfigMap", Name: "cm",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(obj.(*corev1.ConfigMap).Name).To(Equal("cm"))
+		})
+
+		It("should read a referenced object in a named group", func() {
+			group := "apps"
+			obj, err := reference.ResolveLocal(context.TODO(), c, scheme.Scheme, "default", &corev1.TypedLocalObjectReference{
+				APIGroup: &group, Kind: "Deployment", Name: "deploy",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(obj).To(BeAssignableToTypeOf(&appsv1.Deployment{}))
+		})
+
+		It("should return an error if the kind is not registered for the group", func() {
+			group := "apps"
+			_, err := reference.ResolveLocal(context.TODO(), c, scheme.Scheme, "default", &corev1.TypedLocalObjectReference{
+				APIGroup: &group, Kind: "ConfigMap", Name: "cm",
+			})
+			Expect(err).To(MatchError(ContainSubstring(`kind "ConfigMap" is not registered in the scheme for group "apps"`)))
+		})
+	})
+})
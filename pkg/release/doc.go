@@ -0,0 +1,41 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package release applies sets of objects as numbered revisions of a release, like Helm releases.
+
+Each applied revision is recorded in a Secret or ConfigMap holding a snapshot of its objects and a
+hash of them, which gives an audit trail of what was applied when, and allows rolling back to an
+earlier revision:
+
+	releases := &release.Manager{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Namespace: "my-operator"}
+	rev, err := releases.Apply(ctx, "frontend", deployment, service)
+	...
+	history, err := releases.History(ctx, "frontend")
+	...
+	rev, err = releases.Rollback(ctx, "frontend", history[0].Number)
+
+Applying the same objects as the latest revision re-applies them without recording a new revision.
+
+With Prune, the revisions serve as the inventory of the objects applied: the objects of the latest
+revision which are not applied anymore, e.g. because they were removed from the desired state or renamed,
+are deleted.  MaxPrune guards against deleting many objects at once.
+
+With the RollbackOnFailure FailurePolicy, the objects of a revision already applied are restored to their
+previous state, and the ones created deleted, if another object of the revision fails to apply, so that
+a revision is applied either entirely or, on a best-effort basis, not at all.
+*/
+package release
@@ -0,0 +1,521 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var log = logf.KBLog.WithName("release")
+
+const (
+	// NameLabel is the label of the objects storing the revisions of a release holding its name.
+	NameLabel = "release.controller-runtime.sigs.k8s.io/name"
+
+	// RevisionLabel is the label of the objects storing the revisions of a release holding the
+	// number of the revision.
+	RevisionLabel = "release.controller-runtime.sigs.k8s.io/revision"
+
+	// HashAnnotation is the annotation of the objects storing the revisions of a release holding
+	// the hash of the objects of the revision.
+	HashAnnotation = "release.controller-runtime.sigs.k8s.io/hash"
+
+	// AppliedAnnotation is the annotation of the objects storing the revisions of a release holding
+	// the time the revision was applied, in RFC 3339 format.
+	AppliedAnnotation = "release.controller-runtime.sigs.k8s.io/applied"
+
+	// manifestKey is the data key of the objects storing the revisions of a release holding the
+	// JSON snapshot of the objects of the revision.
+	manifestKey = "manifest"
+
+	// defaultFieldOwner is the default field manager of the objects applied by a Manager.
+	defaultFieldOwner = "controller-runtime-release"
+)
+
+// Driver is the kind of the objects storing the revisions of a release.
+type Driver string
+
+const (
+	// SecretDriver stores the revisions of a release in Secrets, as their objects may be sensitive.
+	SecretDriver Driver = "Secret"
+
+	// ConfigMapDriver stores the revisions of a release in ConfigMaps.
+	ConfigMapDriver Driver = "ConfigMap"
+)
+
+// FailurePolicy is what Apply does with the objects of a revision already applied once another object of
+// the revision fails to apply, e.g. because it is rejected by validation or an admission webhook.
+type FailurePolicy string
+
+const (
+	// AbortOnFailure leaves the objects already applied as they are.
+	AbortOnFailure FailurePolicy = "Abort"
+
+	// RollbackOnFailure restores the objects already applied to their state before Apply, deleting the
+	// ones it created, so that a revision is applied either entirely or, on a best-effort basis, not at
+	// all.
+	RollbackOnFailure FailurePolicy = "Rollback"
+)
+
+// Revision is an applied revision of a release.
+type Revision struct {
+	// Release is the name of the release.
+	Release string `json:"release"`
+
+	// Number is the number of the revision, starting at 1 and incremented by every applied revision.
+	Number int `json:"number"`
+
+	// Hash is the hash of the objects of the revision.
+	Hash string `json:"hash"`
+
+	// Applied is the time the revision was applied.
+	Applied metav1.Time `json:"applied"`
+
+	// Objects is a snapshot of the objects of the revision, as they were applied.
+	Objects []*unstructured.Unstructured `json:"objects"`
+}
+
+// Manager applies sets of objects as the revisions of releases, recording every revision in a Secret
+// or ConfigMap named <release>.v<number>.
+type Manager struct {
+	// Client applies the objects and stores the revisions.
+	Client client.Client
+
+	// Scheme resolves the GroupVersionKind of the applied objects, and converts the objects of a
+	// revision rolled back to into typed objects for the kinds it knows.
+	Scheme *runtime.Scheme
+
+	// Namespace is the namespace of the objects storing the revisions.
+	Namespace string
+
+	// Driver is the kind of the objects storing the revisions.  Defaults to SecretDriver.
+	Driver Driver
+
+	// FieldOwner is the field manager the objects are applied with using server-side apply.
+	// Defaults to "controller-runtime-release".
+	FieldOwner string
+
+	// MaxHistory is the maximum number of revisions kept per release, the oldest revisions being
+	// deleted first.  Defaults to 0, which keeps every revision.
+	MaxHistory int
+
+	// ConflictStrategy is how the conflicts of the objects applied with the fields owned by other field
+	// managers are handled.  The unresolved conflicts are returned by Apply as a client.ConflictError,
+	// which can be got with client.AsConflictError.  Defaults to forcing the ownership of the
+	// conflicting fields.
+	ConflictStrategy *client.ConflictStrategy
+
+	// FailurePolicy is what Apply does with the objects already applied once an object fails to apply.
+	// Defaults to AbortOnFailure.
+	FailurePolicy FailurePolicy
+
+	// Prune deletes the objects of the latest revision which are not in the objects applied, e.g. because
+	// they were removed from the desired state or renamed, once the objects are applied.  The revisions
+	// serve as the inventory of the objects applied, so that only these are ever pruned, and nothing is
+	// pruned if the release has no recorded revision.  The objects are identified by their group, kind,
+	// namespace and name, so that changing the version they are applied with doesn't prune them.
+	Prune bool
+
+	// MaxPrune, if set, is the maximum number of objects pruned by an Apply, as a safeguard against a
+	// mass deletion, e.g. when a bug empties the desired state.  Apply fails without pruning any object
+	// if more would be pruned.  Defaults to 0, which doesn't limit pruning.
+	MaxPrune int
+}
+
+// Apply applies objs with server-side apply as the next revision of release, and records it.  If objs
+// are the same as the objects of the latest revision, they are applied again to correct any drift,
+// and the latest revision is returned without recording a new one.  No revision is recorded if any
+// object fails to apply, in which case the objects already applied are handled according to the
+// FailurePolicy.
+func (m *Manager) Apply(ctx context.Context, release string, objs ...runtime.Object) (*Revision, error) {
+	snapshot, err := m.snapshot(objs)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(manifest)
+	hash := hex.EncodeToString(sum[:])
+
+	history, err := m.History(ctx, release)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.apply(ctx, release, objs, snapshot); err != nil {
+		return nil, err
+	}
+
+	if len(history) > 0 && history[len(history)-1].Hash == hash {
+		return &history[len(history)-1], nil
+	}
+
+	// Prune before recording the revision, so that pruning is retried by the next Apply if it fails
+	if m.Prune && len(history) > 0 {
+		if err := m.prune(ctx, release, history[len(history)-1].Objects, snapshot); err != nil {
+			return nil, err
+		}
+	}
+
+	rev := &Revision{
+		Release: release,
+		Number:  1,
+		Hash:    hash,
+		Applied: metav1.NewTime(time.Now().Truncate(time.Second)),
+		Objects: snapshot,
+	}
+	if len(history) > 0 {
+		rev.Number = history[len(history)-1].Number + 1
+	}
+	if err := m.Client.Create(ctx, m.newStorage(rev, manifest)); err != nil {
+		return nil, fmt.Errorf("unable to record revision %d of release %s: %v", rev.Number, release, err)
+	}
+	log.Info("Applied release", "release", release, "revision", rev.Number, "objects", len(objs))
+
+	if history = append(history, *rev); m.MaxHistory > 0 && len(history) > m.MaxHistory {
+		for _, old := range history[:len(history)-m.MaxHistory] {
+			if err := m.Client.Delete(ctx, m.emptyStorage(release, old.Number)); err != nil {
+				return rev, fmt.Errorf("unable to delete revision %d of release %s: %v", old.Number, release, err)
+			}
+		}
+	}
+	return rev, nil
+}
+
+// apply applies objs, whose snapshot is given, according to the FailurePolicy.
+func (m *Manager) apply(ctx context.Context, release string, objs []runtime.Object, snapshot []*unstructured.Unstructured) error {
+	fieldOwner := m.FieldOwner
+	if fieldOwner == "" {
+		fieldOwner = defaultFieldOwner
+	}
+	strategy := client.ConflictStrategy{Force: true}
+	if m.ConflictStrategy != nil {
+		strategy = *m.ConflictStrategy
+	}
+	policy := m.FailurePolicy
+	if policy == "" {
+		policy = AbortOnFailure
+	}
+	if policy != AbortOnFailure && policy != RollbackOnFailure {
+		return fmt.Errorf("unknown release FailurePolicy %q", policy)
+	}
+
+	// previous are the objects applied so far as they were before, nil for the objects created
+	var applied, previous []runtime.Object
+	for i, obj := range objs {
+		var prev runtime.Object
+		var err error
+		if policy == RollbackOnFailure {
+			prev, err = m.current(ctx, snapshot[i])
+		}
+		if err == nil {
+			// Server-side apply requires the apiVersion and kind of the object
+			obj.GetObjectKind().SetGroupVersionKind(snapshot[i].GroupVersionKind())
+			err = client.ApplyWithStrategy(ctx, m.Client, obj, strategy, client.FieldOwner(fieldOwner))
+		}
+		if err != nil {
+			err = &applyError{err: err, msg: fmt.Sprintf("unable to apply %s %s/%s of release %s", snapshot[i].GetKind(),
+				snapshot[i].GetNamespace(), snapshot[i].GetName(), release)}
+			if policy == RollbackOnFailure && len(applied) > 0 {
+				if rerr := m.restore(ctx, applied, previous); rerr != nil {
+					return fmt.Errorf("%v, and unable to roll back the objects already applied: %v", err, rerr)
+				}
+				log.Info("Rolled back the objects applied", "release", release, "objects", len(applied))
+			}
+			return err
+		}
+		applied, previous = append(applied, obj), append(previous, prev)
+	}
+	return nil
+}
+
+// prune deletes the objects of inventory which are not in applied.
+func (m *Manager) prune(ctx context.Context, release string, inventory, applied []*unstructured.Unstructured) error {
+	keep := map[objectKey]bool{}
+	for _, u := range applied {
+		keep[keyOf(u)] = true
+	}
+	var pruned []*unstructured.Unstructured
+	for _, u := range inventory {
+		if !keep[keyOf(u)] {
+			pruned = append(pruned, u)
+		}
+	}
+	if m.MaxPrune > 0 && len(pruned) > m.MaxPrune {
+		return fmt.Errorf("refusing to prune %d objects of release %s, more than MaxPrune %d", len(pruned), release, m.MaxPrune)
+	}
+
+	for _, u := range pruned {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(u.GroupVersionKind())
+		obj.SetNamespace(u.GetNamespace())
+		obj.SetName(u.GetName())
+		if err := m.Client.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("unable to prune %s %s/%s of release %s: %v", u.GetKind(), u.GetNamespace(), u.GetName(), release, err)
+		}
+		log.Info("Pruned object of release", "release", release, "kind", u.GetKind(), "namespace", u.GetNamespace(), "name", u.GetName())
+	}
+	return nil
+}
+
+// objectKey identifies an object of a release across the versions of its kind.
+type objectKey struct {
+	group, kind, namespace, name string
+}
+
+func keyOf(u *unstructured.Unstructured) objectKey {
+	gvk := u.GroupVersionKind()
+	return objectKey{group: gvk.Group, kind: gvk.Kind, namespace: u.GetNamespace(), name: u.GetName()}
+}
+
+// Rollback applies the objects of the given revision of release again, as a new revision.  Objects
+// added to the release after that revision are not deleted, unless Prune is set.
+func (m *Manager) Rollback(ctx context.Context, release string, number int) (*Revision, error) {
+	history, err := m.History(ctx, release)
+	if err != nil {
+		return nil, err
+	}
+	for _, rev := range history {
+		if rev.Number != number {
+			continue
+		}
+		objs := make([]runtime.Object, 0, len(rev.Objects))
+		for _, u := range rev.Objects {
+			obj, err := m.typed(u)
+			if err != nil {
+				return nil, err
+			}
+			objs = append(objs, obj)
+		}
+		log.Info("Rolling back release", "release", release, "revision", number)
+		return m.Apply(ctx, release, objs...)
+	}
+	return nil, fmt.Errorf("revision %d of release %s not found", number, release)
+}
+
+// History returns the recorded revisions of release, sorted by Number.
+func (m *Manager) History(ctx context.Context, release string) ([]Revision, error) {
+	opts := []client.ListOptionFunc{client.InNamespace(m.Namespace), client.MatchingLabels(map[string]string{NameLabel: release})}
+	var history []Revision
+	switch m.driver() {
+	case SecretDriver:
+		list := &corev1.SecretList{}
+		if err := m.Client.List(ctx, list, opts...); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			rev, err := decodeRevision(&list.Items[i].ObjectMeta, list.Items[i].Data[manifestKey])
+			if err != nil {
+				return nil, err
+			}
+			history = append(history, *rev)
+		}
+	case ConfigMapDriver:
+		list := &corev1.ConfigMapList{}
+		if err := m.Client.List(ctx, list, opts...); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			rev, err := decodeRevision(&list.Items[i].ObjectMeta, []byte(list.Items[i].Data[manifestKey]))
+			if err != nil {
+				return nil, err
+			}
+			history = append(history, *rev)
+		}
+	default:
+		return nil, fmt.Errorf("unknown release Driver %q", m.Driver)
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].Number < history[j].Number })
+	return history, nil
+}
+
+// applyError is the error of an object of a release failing to apply.  It wraps the error of the apply so
+// that client.AsConflictError finds the conflicts in it.
+type applyError struct {
+	msg string
+	err error
+}
+
+// Error implements error
+func (e *applyError) Error() string {
+	return e.msg + ": " + e.err.Error()
+}
+
+// Unwrap returns the error of the apply.
+func (e *applyError) Unwrap() error {
+	return e.err
+}
+
+// current returns the current state of the object identified by u, or nil if it doesn't exist.
+func (m *Manager) current(ctx context.Context, u *unstructured.Unstructured) (runtime.Object, error) {
+	obj, err := m.typed(&unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": u.GetAPIVersion(),
+		"kind":       u.GetKind(),
+	}})
+	if err != nil {
+		return nil, err
+	}
+	if err := m.Client.Get(ctx, client.ObjectKey{Namespace: u.GetNamespace(), Name: u.GetName()}, obj); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return obj, nil
+}
+
+// restore restores the applied objects to their previous state, in reverse order, deleting the ones
+// without a previous state.
+func (m *Manager) restore(ctx context.Context, applied, previous []runtime.Object) error {
+	var errs []error
+	for i := len(applied) - 1; i >= 0; i-- {
+		if previous[i] == nil {
+			if err := m.Client.Delete(ctx, applied[i]); err != nil && !errors.IsNotFound(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		// Update the object from its current resourceVersion
+		accessor, err := meta.Accessor(previous[i])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		appliedAccessor, err := meta.Accessor(applied[i])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		accessor.SetResourceVersion(appliedAccessor.GetResourceVersion())
+		if err := m.Client.Update(ctx, previous[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (m *Manager) driver() Driver {
+	if m.Driver == "" {
+		return SecretDriver
+	}
+	return m.Driver
+}
+
+// snapshot returns objs as unstructured objects with their GroupVersionKind set.
+func (m *Manager) snapshot(objs []runtime.Object) ([]*unstructured.Unstructured, error) {
+	snapshot := make([]*unstructured.Unstructured, 0, len(objs))
+	for _, obj := range objs {
+		gvk, err := apiutil.GVKForObject(obj, m.Scheme)
+		if err != nil {
+			return nil, err
+		}
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		u := &unstructured.Unstructured{Object: content}
+		u.SetGroupVersionKind(gvk)
+		snapshot = append(snapshot, u)
+	}
+	return snapshot, nil
+}
+
+// typed converts u into a typed object if its kind is known to the Scheme.
+func (m *Manager) typed(u *unstructured.Unstructured) (runtime.Object, error) {
+	if m.Scheme == nil || !m.Scheme.Recognizes(u.GroupVersionKind()) {
+		return u, nil
+	}
+	obj, err := m.Scheme.New(u.GroupVersionKind())
+	if err != nil {
+		return nil, err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+		return nil, err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(u.GroupVersionKind())
+	return obj, nil
+}
+
+// newStorage returns the object storing rev, whose objects are serialized in manifest.
+func (m *Manager) newStorage(rev *Revision, manifest []byte) runtime.Object {
+	meta := metav1.ObjectMeta{
+		Namespace: m.Namespace,
+		Name:      storageName(rev.Release, rev.Number),
+		Labels: map[string]string{
+			NameLabel:     rev.Release,
+			RevisionLabel: strconv.Itoa(rev.Number),
+		},
+		Annotations: map[string]string{
+			HashAnnotation:    rev.Hash,
+			AppliedAnnotation: rev.Applied.UTC().Format(time.RFC3339),
+		},
+	}
+	if m.driver() == ConfigMapDriver {
+		return &corev1.ConfigMap{ObjectMeta: meta, Data: map[string]string{manifestKey: string(manifest)}}
+	}
+	return &corev1.Secret{ObjectMeta: meta, Data: map[string][]byte{manifestKey: manifest}}
+}
+
+// emptyStorage returns an object identifying the storage of the given revision of release.
+func (m *Manager) emptyStorage(release string, number int) runtime.Object {
+	meta := metav1.ObjectMeta{Namespace: m.Namespace, Name: storageName(release, number)}
+	if m.driver() == ConfigMapDriver {
+		return &corev1.ConfigMap{ObjectMeta: meta}
+	}
+	return &corev1.Secret{ObjectMeta: meta}
+}
+
+func storageName(release string, number int) string {
+	return fmt.Sprintf("%s.v%d", release, number)
+}
+
+// decodeRevision decodes the Revision stored in an object with the given metadata and manifest.
+func decodeRevision(meta *metav1.ObjectMeta, manifest []byte) (*Revision, error) {
+	number, err := strconv.Atoi(meta.Labels[RevisionLabel])
+	if err != nil {
+		return nil, fmt.Errorf("invalid revision of %s/%s: %v", meta.Namespace, meta.Name, err)
+	}
+	rev := &Revision{Release: meta.Labels[NameLabel], Number: number, Hash: meta.Annotations[HashAnnotation]}
+	if applied, err := time.Parse(time.RFC3339, meta.Annotations[AppliedAnnotation]); err == nil {
+		rev.Applied = metav1.NewTime(applied)
+	}
+	if err := json.Unmarshal(manifest, &rev.Objects); err != nil {
+		return nil, fmt.Errorf("invalid manifest of %s/%s: %v", meta.Namespace, meta.Name, err)
+	}
+	return rev, nil
+}
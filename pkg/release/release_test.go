@@ -0,0 +1,299 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/release"
+)
+
+var _ = Describe("Manager", func() {
+	var (
+		ctx      context.Context
+		c        client.Client
+		releases *release.Manager
+	)
+
+	configMap := func(value string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "config"},
+			Data:       map[string]string{"key": value},
+		}
+	}
+	get := func() *corev1.ConfigMap {
+		cm := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "config"}, cm)).To(Succeed())
+		return cm
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		c = fake.NewFakeClient()
+		releases = &release.Manager{Client: c, Scheme: scheme.Scheme, Namespace: "operator"}
+	})
+
+	It("should apply the objects and record every revision", func() {
+		rev, err := releases.Apply(ctx, "app", configMap("v1"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rev.Number).To(Equal(1))
+		Expect(rev.Hash).NotTo(BeEmpty())
+		Expect(get().Data).To(Equal(map[string]string{"key": "v1"}))
+
+		rev, err = releases.Apply(ctx, "app", configMap("v2"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rev.Number).To(Equal(2))
+		Expect(get().Data).To(Equal(map[string]string{"key": "v2"}))
+
+		history, err := releases.History(ctx, "app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(history).To(HaveLen(2))
+		Expect(history[0].Number).To(Equal(1))
+		Expect(history[0].Objects).To(HaveLen(1))
+		Expect(history[0].Objects[0].GetKind()).To(Equal("ConfigMap"))
+		Expect(history[1].Hash).To(Equal(rev.Hash))
+
+		By("storing the revisions in Secrets")
+		secret := &corev1.Secret{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "operator", Name: "app.v2"}, secret)).To(Succeed())
+		Expect(secret.Labels).To(HaveKeyWithValue(release.RevisionLabel, "2"))
+		Expect(secret.Annotations).To(HaveKeyWithValue(release.HashAnnotation, rev.Hash))
+	})
+
+	It("should not record a revision if the objects are unchanged", func() {
+		first, err := releases.Apply(ctx, "app", configMap("v1"))
+		Expect(err).NotTo(HaveOccurred())
+
+		By("correcting the drift of the objects")
+		drifted := get()
+		drifted.Data["key"] = "drifted"
+		Expect(c.Update(ctx, drifted)).To(Succeed())
+
+		rev, err := releases.Apply(ctx, "app", configMap("v1"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rev.Number).To(Equal(first.Number))
+		Expect(get().Data).To(Equal(map[string]string{"key": "v1"}))
+
+		history, err := releases.History(ctx, "app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(history).To(HaveLen(1))
+	})
+
+	It("should roll back to a previous revision as a new revision", func() {
+		_, err := releases.Apply(ctx, "app", configMap("v1"))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = releases.Apply(ctx, "app", configMap("v2"))
+		Expect(err).NotTo(HaveOccurred())
+
+		rev, err := releases.Rollback(ctx, "app", 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rev.Number).To(Equal(3))
+		Expect(get().Data).To(Equal(map[string]string{"key": "v1"}))
+
+		history, err := releases.History(ctx, "app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(history[2].Hash).To(Equal(history[0].Hash))
+
+		_, err = releases.Rollback(ctx, "app", 7)
+		Expect(err).To(MatchError(ContainSubstring("revision 7 of release app not found")))
+	})
+
+	It("should only keep MaxHistory revisions", func() {
+		releases.MaxHistory = 2
+		for _, value := range []string{"v1", "v2", "v3"} {
+			_, err := releases.Apply(ctx, "app", configMap(value))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		history, err := releases.History(ctx, "app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(history).To(HaveLen(2))
+		Expect(history[0].Number).To(Equal(2))
+		Expect(history[1].Number).To(Equal(3))
+	})
+
+	It("should store the revisions in ConfigMaps with the ConfigMapDriver", func() {
+		releases.Driver = release.ConfigMapDriver
+		_, err := releases.Apply(ctx, "app", configMap("v1"))
+		Expect(err).NotTo(HaveOccurred())
+		storage := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "operator", Name: "app.v1"}, storage)).To(Succeed())
+		Expect(storage.Labels).To(HaveKeyWithValue(release.NameLabel, "app"))
+
+		history, err := releases.History(ctx, "app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(history).To(HaveLen(1))
+	})
+
+	Context("when an object fails to apply", func() {
+		var secret *corev1.Secret
+		var created *corev1.ConfigMap
+
+		BeforeEach(func() {
+			secret = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "rejected"}}
+			created = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "created"}}
+			_, err := releases.Apply(ctx, "app", configMap("v1"))
+			Expect(err).NotTo(HaveOccurred())
+			releases.Client = &rejectingClient{Client: c, name: "rejected"}
+		})
+
+		It("should leave the objects already applied with the AbortOnFailure policy", func() {
+			_, err := releases.Apply(ctx, "app", configMap("v2"), created, secret)
+			Expect(err).To(MatchError(ContainSubstring("unable to apply Secret default/rejected of release app")))
+			Expect(get().Data).To(Equal(map[string]string{"key": "v2"}))
+			Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "created"}, &corev1.ConfigMap{})).To(Succeed())
+
+			history, err := releases.History(ctx, "app")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(HaveLen(1))
+		})
+
+		It("should roll back the objects already applied with the RollbackOnFailure policy", func() {
+			releases.FailurePolicy = release.RollbackOnFailure
+			_, err := releases.Apply(ctx, "app", configMap("v2"), created, secret)
+			Expect(err).To(MatchError(ContainSubstring("unable to apply Secret default/rejected of release app")))
+			Expect(get().Data).To(Equal(map[string]string{"key": "v1"}))
+			err = c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "created"}, &corev1.ConfigMap{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			history, err := releases.History(ctx, "app")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(HaveLen(1))
+		})
+
+		It("should return the unresolved conflicts as a ConflictError", func() {
+			releases.ConflictStrategy = &client.ConflictStrategy{}
+			releases.Client = &rejectingClient{Client: c, name: "rejected", err: &errors.StatusError{ErrStatus: metav1.Status{
+				Status:  metav1.StatusFailure,
+				Code:    409,
+				Reason:  metav1.StatusReasonConflict,
+				Message: `Apply failed with 1 conflict: conflict with "kubectl": .data.key`,
+				Details: &metav1.StatusDetails{Causes: []metav1.StatusCause{
+					{Type: "FieldManagerConflict", Message: `conflict with "kubectl"`, Field: ".data.key"},
+				}},
+			}}}
+			_, err := releases.Apply(ctx, "app", secret)
+			Expect(err).To(MatchError(ContainSubstring("unable to apply Secret default/rejected of release app")))
+			conflictErr, ok := client.AsConflictError(err)
+			Expect(ok).To(BeTrue())
+			Expect(conflictErr.Managers()).To(Equal([]string{"kubectl"}))
+		})
+	})
+
+	Context("with Prune", func() {
+		secret := func(name string) *corev1.Secret {
+			return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}
+		}
+		exists := func(name string) bool {
+			err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, &corev1.Secret{})
+			if errors.IsNotFound(err) {
+				return false
+			}
+			Expect(err).NotTo(HaveOccurred())
+			return true
+		}
+
+		BeforeEach(func() {
+			releases.Prune = true
+		})
+
+		It("should delete the objects removed from the release", func() {
+			_, err := releases.Apply(ctx, "app", configMap("v1"), secret("old"), secret("kept"))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = releases.Apply(ctx, "app", configMap("v2"), secret("new"), secret("kept"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists("old")).To(BeFalse())
+			Expect(exists("kept")).To(BeTrue())
+			Expect(exists("new")).To(BeTrue())
+			Expect(get().Data).To(Equal(map[string]string{"key": "v2"}))
+		})
+
+		It("should not prune anything without a recorded revision", func() {
+			Expect(c.Create(ctx, secret("unrecorded"))).To(Succeed())
+			_, err := releases.Apply(ctx, "app", configMap("v1"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists("unrecorded")).To(BeTrue())
+		})
+
+		It("should refuse to prune more than MaxPrune objects", func() {
+			releases.MaxPrune = 1
+			_, err := releases.Apply(ctx, "app", configMap("v1"), secret("a"), secret("b"))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = releases.Apply(ctx, "app", configMap("v2"))
+			Expect(err).To(MatchError(ContainSubstring("refusing to prune 2 objects of release app")))
+			Expect(exists("a")).To(BeTrue())
+			Expect(exists("b")).To(BeTrue())
+
+			history, err := releases.History(ctx, "app")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(HaveLen(1))
+		})
+
+		It("should prune the objects added after the revision rolled back to", func() {
+			_, err := releases.Apply(ctx, "app", configMap("v1"))
+			Expect(err).NotTo(HaveOccurred())
+			_, err = releases.Apply(ctx, "app", configMap("v2"), secret("added"))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = releases.Rollback(ctx, "app", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists("added")).To(BeFalse())
+		})
+	})
+
+	It("should keep the releases apart", func() {
+		_, err := releases.Apply(ctx, "app", configMap("v1"))
+		Expect(err).NotTo(HaveOccurred())
+		history, err := releases.History(ctx, "other")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(history).To(BeEmpty())
+	})
+})
+
+// rejectingClient fails to patch the objects with the given name, like an admission webhook rejecting
+// them.
+type rejectingClient struct {
+	client.Client
+	name string
+	err  error
+}
+
+func (c *rejectingClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOptionFunc) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	if accessor.GetName() == c.name {
+		if c.err != nil {
+			return c.err
+		}
+		return fmt.Errorf("admission webhook denied the request")
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
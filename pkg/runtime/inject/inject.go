@@ -17,6 +17,7 @@ limitations under the License.
 package inject
 
 import (
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -98,6 +99,21 @@ func SchemeInto(scheme *runtime.Scheme, i interface{}) (bool, error) {
 	return false, nil
 }
 
+// Mapper is used by the ControllerManager to inject the RESTMapper into Sources, EventHandlers, Predicates, and
+// Reconciles
+type Mapper interface {
+	InjectMapper(meta.RESTMapper) error
+}
+
+// MapperInto will set the rest mapper on i and return the result if it implements Mapper.
+// Returns false if i does not implement Mapper.
+func MapperInto(mapper meta.RESTMapper, i interface{}) (bool, error) {
+	if m, ok := i.(Mapper); ok {
+		return true, m.InjectMapper(mapper)
+	}
+	return false, nil
+}
+
 // Stoppable is used by the ControllerManager to inject stop channel into Sources,
 // EventHandlers, Predicates, and Reconciles.
 type Stoppable interface {
@@ -22,6 +22,7 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -150,6 +151,27 @@ var _ = Describe("runtime inject", func() {
 		Expect(res).To(Equal(true))
 	})
 
+	It("should set rest mapper", func() {
+
+		mapper := meta.NewDefaultRESTMapper(nil)
+
+		By("Validating injecting rest mapper")
+		res, err := MapperInto(mapper, instance)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(true))
+		Expect(mapper).To(Equal(instance.GetMapper()))
+
+		By("Returning false if the type does not implement inject.Mapper")
+		res, err = MapperInto(mapper, uninjectable)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(false))
+
+		By("Returning an error if rest mapper injection fails")
+		res, err = MapperInto(nil, instance)
+		Expect(err).To(Equal(errInjectFail))
+		Expect(res).To(Equal(true))
+	})
+
 	It("should set dependencies", func() {
 
 		f := func(interface{}) error { return nil }
@@ -181,6 +203,7 @@ type testSource struct {
 	client client.Client
 	f      Func
 	stop   <-chan struct{}
+	mapper meta.RESTMapper
 }
 
 func (s *testSource) InjectCache(c cache.Cache) error {
@@ -223,6 +246,14 @@ func (s *testSource) InjectStopChannel(stop <-chan struct{}) error {
 	return fmt.Errorf("injection fails")
 }
 
+func (s *testSource) InjectMapper(mapper meta.RESTMapper) error {
+	if mapper != nil {
+		s.mapper = mapper
+		return nil
+	}
+	return fmt.Errorf("injection fails")
+}
+
 func (s *testSource) InjectFunc(f Func) error {
 	if f != nil {
 		s.f = f
@@ -255,6 +286,10 @@ func (s *testSource) GetStop() <-chan struct{} {
 	return s.stop
 }
 
+func (s *testSource) GetMapper() meta.RESTMapper {
+	return s.mapper
+}
+
 type failSource struct {
 	scheme *runtime.Scheme
 	cache  cache.Cache
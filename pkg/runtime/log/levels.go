@@ -0,0 +1,215 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+// Component identifies the log messages of a component, such as a controller or a webhook, by a
+// key and value they are logged with, e.g. the "controller" key and the name of a controller.
+type Component struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// ComponentLevel is the verbosity of the log messages of a Component.
+type ComponentLevel struct {
+	Component `json:",inline"`
+	Verbosity int `json:"verbosity"`
+}
+
+// Levels are the verbosity of the log messages written by the Loggers returned by WithLevels,
+// which can be changed at runtime, both for all messages and for the messages of a Component.
+// Levels are safe for concurrent use.
+type Levels struct {
+	mu         sync.RWMutex
+	verbosity  int
+	components map[Component]int
+}
+
+// NewLevels returns Levels enabling the messages of the given verbosity and lower.
+func NewLevels(verbosity int) *Levels {
+	return &Levels{verbosity: verbosity, components: map[Component]int{}}
+}
+
+// Verbosity returns the verbosity of the messages of no Component.
+func (l *Levels) Verbosity() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.verbosity
+}
+
+// SetVerbosity sets the verbosity of the messages of no Component.
+func (l *Levels) SetVerbosity(verbosity int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.verbosity = verbosity
+}
+
+// SetComponent sets the verbosity of the messages of c, overriding the Verbosity.
+func (l *Levels) SetComponent(c Component, verbosity int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.components[c] = verbosity
+}
+
+// ResetComponent resets the verbosity of the messages of c to the Verbosity.
+func (l *Levels) ResetComponent(c Component) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.components, c)
+}
+
+// SetComponents replaces the verbosity of the messages of every Component by the given levels.
+func (l *Levels) SetComponents(levels []ComponentLevel) {
+	components := make(map[Component]int, len(levels))
+	for _, cl := range levels {
+		components[cl.Component] = cl.Verbosity
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.components = components
+}
+
+// Components returns the verbosity of the messages of every Component whose verbosity was set,
+// sorted by Component.
+func (l *Levels) Components() []ComponentLevel {
+	l.mu.RLock()
+	levels := make([]ComponentLevel, 0, len(l.components))
+	for c, v := range l.components {
+		levels = append(levels, ComponentLevel{Component: c, Verbosity: v})
+	}
+	l.mu.RUnlock()
+	sort.Slice(levels, func(i, j int) bool {
+		if levels[i].Key != levels[j].Key {
+			return levels[i].Key < levels[j].Key
+		}
+		return levels[i].Value < levels[j].Value
+	})
+	return levels
+}
+
+// enabled returns whether a message of the given level logged with the given key/value pairs is
+// enabled.  It is enabled by the highest verbosity of the Components it is logged with, or by the
+// Verbosity if it is logged with none.
+func (l *Levels) enabled(level int, keysAndValues ...[]interface{}) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	verbosity, found := 0, false
+	if len(l.components) > 0 {
+		for _, kvs := range keysAndValues {
+			for i := 0; i+1 < len(kvs); i += 2 {
+				key, ok := kvs[i].(string)
+				if !ok {
+					continue
+				}
+				value, ok := kvs[i+1].(string)
+				if !ok {
+					continue
+				}
+				if v, ok := l.components[Component{Key: key, Value: value}]; ok && (!found || v > verbosity) {
+					verbosity, found = v, true
+				}
+			}
+		}
+	}
+	if !found {
+		verbosity = l.verbosity
+	}
+	return level <= verbosity
+}
+
+// WithLevels returns a Logger writing to logger the messages enabled by levels, so that their
+// verbosity can be changed at runtime, e.g. through the /debug/loglevel endpoint of the Manager.
+// Enabled messages of a verbosity above 0 are written as info messages with a "v" key holding their
+// verbosity, so logger doesn't need to enable them.  Errors are always written.
+func WithLevels(logger logr.Logger, levels *Levels) logr.Logger {
+	return &levelLogger{logger: logger, levels: levels}
+}
+
+// levelLogger is a logr.Logger filtering its messages by Levels.
+type levelLogger struct {
+	logger logr.Logger
+	levels *Levels
+
+	// values are the key/value pairs added by WithValues, which identify the Components of the messages
+	values []interface{}
+}
+
+var _ logr.Logger = &levelLogger{}
+
+// Info implements logr.InfoLogger
+func (l *levelLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.V(0).Info(msg, keysAndValues...)
+}
+
+// Enabled implements logr.InfoLogger
+func (l *levelLogger) Enabled() bool {
+	return l.V(0).Enabled()
+}
+
+// Error implements logr.Logger
+func (l *levelLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.logger.Error(err, msg, keysAndValues...)
+}
+
+// V implements logr.Logger
+func (l *levelLogger) V(level int) logr.InfoLogger {
+	return &levelInfoLogger{levelLogger: l, level: level}
+}
+
+// WithValues implements logr.Logger
+func (l *levelLogger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	values := append([]interface{}(nil), l.values...)
+	return &levelLogger{
+		logger: l.logger.WithValues(keysAndValues...),
+		levels: l.levels,
+		values: append(values, keysAndValues...),
+	}
+}
+
+// WithName implements logr.Logger
+func (l *levelLogger) WithName(name string) logr.Logger {
+	return &levelLogger{logger: l.logger.WithName(name), levels: l.levels, values: l.values}
+}
+
+// levelInfoLogger is a logr.InfoLogger of a given level filtering its messages by Levels.
+type levelInfoLogger struct {
+	*levelLogger
+	level int
+}
+
+// Info implements logr.InfoLogger
+func (l *levelInfoLogger) Info(msg string, keysAndValues ...interface{}) {
+	if !l.levels.enabled(l.level, l.values, keysAndValues) {
+		return
+	}
+	if l.level > 0 {
+		keysAndValues = append([]interface{}{"v", l.level}, keysAndValues...)
+	}
+	l.logger.Info(msg, keysAndValues...)
+}
+
+// Enabled implements logr.InfoLogger.  It only reports whether the messages of the Components the
+// Logger was created with are enabled, as the Components a message is logged with are unknown.
+func (l *levelInfoLogger) Enabled() bool {
+	return l.levels.enabled(l.level, l.values)
+}
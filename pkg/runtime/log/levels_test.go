@@ -0,0 +1,89 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WithLevels", func() {
+	var root *fakeLoggerRoot
+	var levels *Levels
+
+	BeforeEach(func() {
+		root = &fakeLoggerRoot{}
+		levels = NewLevels(0)
+	})
+
+	It("should only write the messages of the Verbosity and lower", func() {
+		logger := WithLevels(&fakeLogger{root: root}, levels)
+		logger.Info("info")
+		logger.V(1).Info("debug")
+		Expect(logger.V(1).Enabled()).To(BeFalse())
+
+		levels.SetVerbosity(1)
+		logger.V(1).Info("debug")
+		Expect(logger.V(1).Enabled()).To(BeTrue())
+		Expect(root.messages).To(Equal([]logInfo{
+			{msg: "info"},
+			{tags: []interface{}{"v", 1}, msg: "debug"},
+		}))
+	})
+
+	It("should always write errors", func() {
+		levels.SetVerbosity(-1)
+		WithLevels(&fakeLogger{root: root}, levels).Error(errors.New("expected"), "error")
+		Expect(root.messages).To(HaveLen(1))
+	})
+
+	It("should write the messages of the verbosity of their Component", func() {
+		logger := WithLevels(&fakeLogger{root: root}, levels).WithName("controller")
+		foo := logger.WithValues("controller", "foo")
+		levels.SetComponent(Component{Key: "controller", Value: "foo"}, 2)
+
+		foo.V(2).Info("debug foo")
+		Expect(foo.V(2).Enabled()).To(BeTrue())
+		logger.V(2).Info("debug bar", "controller", "bar")
+		logger.V(2).Info("debug foo", "controller", "foo")
+		Expect(root.messages).To(Equal([]logInfo{
+			{name: []string{"controller"}, tags: []interface{}{"controller", "foo", "v", 2}, msg: "debug foo"},
+			{name: []string{"controller"}, tags: []interface{}{"v", 2, "controller", "foo"}, msg: "debug foo"},
+		}))
+
+		By("writing them with the Verbosity once the Component is reset")
+		levels.ResetComponent(Component{Key: "controller", Value: "foo"})
+		foo.V(2).Info("debug foo")
+		Expect(root.messages).To(HaveLen(2))
+	})
+
+	It("should use the highest verbosity of the Components of a message", func() {
+		levels.SetComponents([]ComponentLevel{
+			{Component: Component{Key: "controller", Value: "foo"}, Verbosity: 1},
+			{Component: Component{Key: "path", Value: "/validate"}, Verbosity: 3},
+		})
+		logger := WithLevels(&fakeLogger{root: root}, levels)
+		logger.V(3).Info("debug", "controller", "foo", "path", "/validate")
+		Expect(root.messages).To(HaveLen(1))
+		Expect(levels.Components()).To(Equal([]ComponentLevel{
+			{Component: Component{Key: "controller", Value: "foo"}, Verbosity: 1},
+			{Component: Component{Key: "path", Value: "/validate"}, Verbosity: 3},
+		}))
+	})
+})
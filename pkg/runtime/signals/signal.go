@@ -17,27 +17,15 @@ limitations under the License.
 package signals
 
 import (
-	"os"
-	"os/signal"
+	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 )
 
-var onlyOneSignalHandler = make(chan struct{})
-
 // SetupSignalHandler registered for SIGTERM and SIGINT. A stop channel is returned
 // which is closed on one of these signals. If a second signal is caught, the program
 // is terminated with exit code 1.
+//
+// Deprecated: use sigs.k8s.io/controller-runtime/pkg/manager/signals.SetupSignalHandler,
+// which returns a context.Context instead of a stop channel.
 func SetupSignalHandler() (stopCh <-chan struct{}) {
-	close(onlyOneSignalHandler) // panics when called twice
-
-	stop := make(chan struct{})
-	c := make(chan os.Signal, 2)
-	signal.Notify(c, shutdownSignals...)
-	go func() {
-		<-c
-		close(stop)
-		<-c
-		os.Exit(1) // second signal. Exit directly.
-	}()
-
-	return stop
+	return signals.SetupSignalHandler().Done()
 }
@@ -40,6 +40,10 @@ type EventHandler struct {
 	EventHandler handler.EventHandler
 	Queue        workqueue.RateLimitingInterface
 	Predicates   []predicate.Predicate
+
+	// SkipUnchangedUpdates drops UpdateEvents whose object has the same resourceVersion before
+	// and after the update before they reach the Predicates.
+	SkipUnchangedUpdates bool
 }
 
 // OnAdd creates and CreateEvent and calls Create on EventHandler
@@ -114,6 +118,12 @@ func (e EventHandler) OnUpdate(oldObj, newObj interface{}) {
 		return
 	}
 
+	// Periodic resyncs send updates of objects which did not change
+	if e.SkipUnchangedUpdates && u.MetaOld.GetResourceVersion() != "" &&
+		u.MetaOld.GetResourceVersion() == u.MetaNew.GetResourceVersion() {
+		return
+	}
+
 	for _, p := range e.Predicates {
 		if !p.Update(u) {
 			return
@@ -228,6 +228,37 @@ var _ = Describe("Internal", func() {
 			close(done)
 		})
 
+		It("should skip UpdateEvents with an unchanged resourceVersion if asked to", func() {
+			instance = internal.EventHandler{
+				Queue:        controllertest.Queue{},
+				EventHandler: setfuncs,
+				Predicates: []predicate.Predicate{predicate.Funcs{UpdateFunc: func(event.UpdateEvent) bool {
+					defer GinkgoRecover()
+					Fail("Did not expect the Predicates to be called.")
+					return true
+				}}},
+				SkipUnchangedUpdates: true,
+			}
+			pod.ResourceVersion = "1"
+			newPod.ResourceVersion = "1"
+
+			set = false
+			instance.OnUpdate(pod, newPod)
+			Expect(set).To(BeFalse())
+
+			By("not skipping UpdateEvents with a changed resourceVersion")
+			instance.Predicates = nil
+			newPod.ResourceVersion = "2"
+			instance.OnUpdate(pod, newPod)
+			Expect(set).To(BeTrue())
+
+			By("not skipping unchanged UpdateEvents by default")
+			set = false
+			instance.SkipUnchangedUpdates = false
+			instance.OnUpdate(pod, pod.DeepCopy())
+			Expect(set).To(BeTrue())
+		})
+
 		It("should not call Update EventHandler if the object is not a runtime.Object", func(done Done) {
 			instance.OnUpdate(&metav1.ObjectMeta{}, &corev1.Pod{})
 			instance.OnUpdate(&corev1.Pod{}, &metav1.ObjectMeta{})
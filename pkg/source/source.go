@@ -46,6 +46,8 @@ const (
 //
 // * Use Kind for events originating in the cluster (e.g. Pod Create, Pod Update, Deployment Update).
 //
+// * Use NewKindWithCache for events originating in another cluster, e.g. watched with the Cache of a cluster.Cluster.
+//
 // * Use Channel for events originating outside the cluster (eh.g. GitHub Webhook callback, Polling external urls).
 //
 // Users may build their own Source implementations.  If their implementations implement any of the inject package
@@ -56,16 +58,36 @@ type Source interface {
 	Start(handler.EventHandler, workqueue.RateLimitingInterface, ...predicate.Predicate) error
 }
 
+// SyncingSource is a Source whose events come from a cache, which the Controller waits to be synced before
+// it starts reconciling.
+type SyncingSource interface {
+	Source
+
+	// WaitForSync waits until the cache of the Source is synced.  It returns false if stop is closed first.
+	WaitForSync(stop <-chan struct{}) bool
+}
+
+// NewKindWithCache returns a Kind watching the objects of type obj in c, instead of the cache injected by
+// the Controller, e.g. the cache of a cluster.Cluster other than the cluster of the Manager.
+func NewKindWithCache(obj runtime.Object, c cache.Cache) *Kind {
+	return &Kind{Type: obj, cache: c}
+}
+
 // Kind is used to provide a source of events originating inside the cluster from Watches (e.g. Pod Create)
 type Kind struct {
 	// Type is the type of object to watch.  e.g. &v1.Pod{}
 	Type runtime.Object
 
+	// SkipUnchangedUpdates, if true, drops Update events in which the resourceVersion of the
+	// object did not change, such as the events sent for every object on periodic resyncs of
+	// the cache, before they reach the predicates.
+	SkipUnchangedUpdates bool
+
 	// cache used to watch APIs
 	cache cache.Cache
 }
 
-var _ Source = &Kind{}
+var _ SyncingSource = &Kind{}
 
 // Start is internal and should be called only by the Controller to register an EventHandler with the Informer
 // to enqueue reconcile.Requests.
@@ -91,10 +113,23 @@ func (ks *Kind) Start(handler handler.EventHandler, queue workqueue.RateLimiting
 		}
 		return err
 	}
-	i.AddEventHandler(internal.EventHandler{Queue: queue, EventHandler: handler, Predicates: prct})
+	i.AddEventHandler(internal.EventHandler{
+		Queue:                queue,
+		EventHandler:         handler,
+		Predicates:           prct,
+		SkipUnchangedUpdates: ks.SkipUnchangedUpdates,
+	})
 	return nil
 }
 
+// WaitForSync implements SyncingSource
+func (ks *Kind) WaitForSync(stop <-chan struct{}) bool {
+	if ks.cache == nil {
+		return false
+	}
+	return ks.cache.WaitForCacheSync(stop)
+}
+
 func (ks *Kind) String() string {
 	if ks.Type != nil && ks.Type.GetObjectKind() != nil {
 		return fmt.Sprintf("kind source: %v", ks.Type.GetObjectKind().GroupVersionKind().String())
@@ -118,6 +153,8 @@ var _ Source = &Channel{}
 // Channel is used to provide a source of events originating outside the cluster
 // (e.g. GitHub Webhook callback).  Channel requires the user to wire the external
 // source (eh.g. http handler) to write GenericEvents to the underlying channel.
+// Each EventHandler watching a Channel buffers up to DestBufferSize GenericEvents,
+// and OverflowPolicy sets what happens once its buffer is full.
 type Channel struct {
 	// once ensures the event distribution goroutine will be performed only once
 	once sync.Once
@@ -135,10 +172,31 @@ type Channel struct {
 	// Default to 1024 if not specified.
 	DestBufferSize int
 
+	// OverflowPolicy is what is done with a GenericEvent once the buffer of a
+	// dest channel is full.  Defaults to ChannelBlock.
+	OverflowPolicy ChannelOverflowPolicy
+
 	// destLock is to ensure the destination channels are safely added/removed
 	destLock sync.Mutex
 }
 
+// ChannelOverflowPolicy is what a Channel does with a GenericEvent once the
+// buffer of an EventHandler watching it is full, i.e. once the EventHandler
+// can't keep up with the GenericEvents sent to the Channel.
+type ChannelOverflowPolicy string
+
+const (
+	// ChannelBlock blocks the distribution of the GenericEvents until the
+	// EventHandler catches up.  The goroutines sending to Source block once
+	// its own buffer is full, which applies backpressure to them.
+	ChannelBlock ChannelOverflowPolicy = "Block"
+
+	// ChannelDrop drops the GenericEvents the EventHandler has no room for, so
+	// that sending to Source never blocks because of a slow EventHandler.  It
+	// suits level-based signals, e.g. periodic resyncs, which are sent again.
+	ChannelDrop ChannelOverflowPolicy = "Drop"
+)
+
 func (cs *Channel) String() string {
 	return fmt.Sprintf("channel source: %p", cs)
 }
@@ -175,6 +233,14 @@ func (cs *Channel) Start(
 		cs.DestBufferSize = defaultBufferSize
 	}
 
+	switch cs.OverflowPolicy {
+	case "":
+		cs.OverflowPolicy = ChannelBlock
+	case ChannelBlock, ChannelDrop:
+	default:
+		return fmt.Errorf("unknown Channel.OverflowPolicy %q", cs.OverflowPolicy)
+	}
+
 	cs.once.Do(func() {
 		// Distribute GenericEvents to all EventHandler / Queue pairs Watching this source
 		go cs.syncLoop()
@@ -219,6 +285,15 @@ func (cs *Channel) distribute(evt event.GenericEvent) {
 	defer cs.destLock.Unlock()
 
 	for _, dst := range cs.dest {
+		if cs.OverflowPolicy == ChannelDrop {
+			select {
+			case dst <- evt:
+			default:
+				log.V(1).Info("Dropping GenericEvent, the EventHandler buffer is full", "source", cs.String())
+			}
+			continue
+		}
+
 		// We cannot make it under goroutine here, or we'll meet the
 		// race condition of writing message to closed channels.
 		// To avoid blocking, the dest channels are expected to be of
@@ -379,6 +379,49 @@ var _ = Describe("Source", func() {
 
 				close(done)
 			})
+			It("should drop the events the handler has no room for with ChannelDrop", func(done Done) {
+				ch := make(chan event.GenericEvent)
+				unblock := make(chan struct{})
+				handled := make(chan string, 10)
+				evt := func(name string) event.GenericEvent {
+					p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+					return event.GenericEvent{Object: p, Meta: p}
+				}
+
+				q := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
+				instance := &source.Channel{Source: ch, DestBufferSize: 1, OverflowPolicy: source.ChannelDrop}
+				inject.StopChannelInto(stop, instance)
+				err := instance.Start(handler.Funcs{
+					GenericFunc: func(evt event.GenericEvent, q2 workqueue.RateLimitingInterface) {
+						handled <- evt.Meta.GetName()
+						<-unblock
+					},
+				}, q)
+				Expect(err).NotTo(HaveOccurred())
+
+				// The 1st event blocks the handler and the 2nd fills its buffer, so that
+				// the 3rd and 4th are dropped without blocking the sender.  The 4th has
+				// been distributed once the 5th is received from the source channel.
+				ch <- evt("1")
+				Expect(<-handled).To(Equal("1"))
+				ch <- evt("2")
+				ch <- evt("3")
+				ch <- evt("4")
+				ch <- evt("5")
+				close(unblock)
+
+				Expect(<-handled).To(Equal("2"))
+				Consistently(handled).ShouldNot(Receive(Or(Equal("3"), Equal("4"))))
+				close(done)
+			})
+			It("should get error if the overflow policy is unknown", func(done Done) {
+				q := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
+				instance := &source.Channel{Source: ch, OverflowPolicy: "Foo"}
+				inject.StopChannelInto(stop, instance)
+				err := instance.Start(handler.Funcs{}, q)
+				Expect(err).To(Equal(fmt.Errorf("unknown Channel.OverflowPolicy \"Foo\"")))
+				close(done)
+			})
 			It("should get error if no source specified", func(done Done) {
 				q := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
 				instance := &source.Channel{ /*no source specified*/ }
@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package version reports the version of the controller-runtime a binary was built with.
+//
+// When the binary is built as a Go module the versions are read from its build information.
+// Otherwise they can be set with the linker, e.g.
+//
+//	go build -ldflags "-X sigs.k8s.io/controller-runtime/pkg/version.version=v0.1.10 -X sigs.k8s.io/controller-runtime/pkg/version.gitCommit=$(git rev-parse HEAD)"
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+const modulePath = "sigs.k8s.io/controller-runtime"
+
+var (
+	// version is the version of the controller-runtime.
+	version = "unknown"
+
+	// gitCommit is the commit of the binary.
+	gitCommit = "unknown"
+
+	// kubernetesClientVersion is the version of client-go vendored by the controller-runtime.
+	kubernetesClientVersion = "kubernetes-1.12.6"
+)
+
+// Info is the version information of a binary.
+type Info struct {
+	// Version is the version of the controller-runtime.
+	Version string `json:"version"`
+
+	// GitCommit is the commit the binary was built from.
+	GitCommit string `json:"gitCommit"`
+
+	// GoVersion is the version of Go the binary was built with.
+	GoVersion string `json:"goVersion"`
+
+	// KubernetesClientVersion is the version of client-go the binary was built with.
+	KubernetesClientVersion string `json:"kubernetesClientVersion"`
+
+	// Platform is the OS and architecture the binary was built for.
+	Platform string `json:"platform"`
+}
+
+// Get returns the version information of the binary.
+func Get() Info {
+	info := Info{
+		Version:                 version,
+		GitCommit:               gitCommit,
+		GoVersion:               runtime.Version(),
+		KubernetesClientVersion: kubernetesClientVersion,
+		Platform:                fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+	}
+
+	// Versions set with the linker take precedence over the build information
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	if version == "unknown" && buildInfo.Main.Path == modulePath && buildInfo.Main.Version != "(devel)" {
+		info.Version = buildInfo.Main.Version
+	}
+	for _, dep := range buildInfo.Deps {
+		switch {
+		case dep.Path == modulePath && version == "unknown":
+			info.Version = dep.Version
+		case dep.Path == "k8s.io/client-go":
+			info.KubernetesClientVersion = dep.Version
+		}
+	}
+	for _, setting := range buildInfo.Settings {
+		if setting.Key == "vcs.revision" && gitCommit == "unknown" {
+			info.GitCommit = setting.Value
+		}
+	}
+	return info
+}
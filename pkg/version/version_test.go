@@ -0,0 +1,40 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version_test
+
+import (
+	"runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/version"
+)
+
+var _ = Describe("Get", func() {
+	It("should report the Go version and platform of the binary", func() {
+		info := version.Get()
+		Expect(info.GoVersion).To(Equal(runtime.Version()))
+		Expect(info.Platform).To(Equal(runtime.GOOS + "/" + runtime.GOARCH))
+	})
+
+	It("should report the versions of the controller-runtime and client-go", func() {
+		info := version.Get()
+		Expect(info.Version).NotTo(BeEmpty())
+		Expect(info.GitCommit).NotTo(BeEmpty())
+		Expect(info.KubernetesClientVersion).NotTo(BeEmpty())
+	})
+})
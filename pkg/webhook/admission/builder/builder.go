@@ -25,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/types"
 )
@@ -63,6 +64,13 @@ type WebhookBuilder struct {
 	// namespaceSelector maps to the NamespaceSelector in the admissionregistrationv1beta1.Webhook
 	namespaceSelector *metav1.LabelSelector
 
+	// sideEffects maps to the SideEffects in the admissionregistrationv1beta1.Webhook
+	sideEffects *admissionregistrationv1beta1.SideEffectClass
+
+	// validatesDelete is set for webhooks built ForValidator, which also handle
+	// deletes if no operations are set.
+	validatesDelete bool
+
 	// manager is the manager for the webhook.
 	// It is used for provisioning various dependencies for the webhook. e.g. RESTMapper.
 	manager manager.Manager
@@ -144,6 +152,14 @@ func (b *WebhookBuilder) NamespaceSelector(namespaceSelector *metav1.LabelSelect
 	return b
 }
 
+// SideEffects sets the SideEffects of the webhook, which declares whether calling it has
+// side effects.  The API server only sends dry run requests to webhooks with the None or
+// NoneOnDryRun side effect classes.
+func (b *WebhookBuilder) SideEffects(sideEffects admissionregistrationv1beta1.SideEffectClass) *WebhookBuilder {
+	b.sideEffects = &sideEffects
+	return b
+}
+
 // WithManager set the manager for the webhook for provisioning various dependencies. e.g. client etc.
 func (b *WebhookBuilder) WithManager(mgr manager.Manager) *WebhookBuilder {
 	b.manager = mgr
@@ -156,6 +172,37 @@ func (b *WebhookBuilder) Handlers(handlers ...admission.Handler) *WebhookBuilder
 	return b
 }
 
+// ForDefaulter builds a mutating webhook for the type of defaulter, which sets
+// defaults on objects using its Default method.
+// It sets the type, ForType and Handlers of the webhook.
+func (b *WebhookBuilder) ForDefaulter(defaulter admission.Defaulter) *WebhookBuilder {
+	b.Mutating()
+	b.apiType = defaulter
+	b.handlers = []admission.Handler{admission.DefaultingWebhookFor(defaulter)}
+	return b
+}
+
+// ForValidator builds a validating webhook for the type of validator, which validates
+// objects using its ValidateCreate, ValidateUpdate and ValidateDelete methods.
+// It sets the type, ForType and Handlers of the webhook. If Operations are not set,
+// the webhook handles creates, updates and deletes.
+func (b *WebhookBuilder) ForValidator(validator admission.Validator) *WebhookBuilder {
+	b.Validating()
+	b.apiType = validator
+	b.handlers = []admission.Handler{admission.ValidatingWebhookFor(validator)}
+	b.validatesDelete = true
+	return b
+}
+
+// Complete builds the webhook and registers it with srv.
+func (b *WebhookBuilder) Complete(srv *webhook.Server) error {
+	w, err := b.Build()
+	if err != nil {
+		return err
+	}
+	return srv.Register(w)
+}
+
 func (b *WebhookBuilder) validate() error {
 	if b.t == nil {
 		return errors.New("webhook type cannot be nil")
@@ -182,6 +229,7 @@ func (b *WebhookBuilder) Build() (*admission.Webhook, error) {
 		Path:              b.path,
 		FailurePolicy:     b.failurePolicy,
 		NamespaceSelector: b.namespaceSelector,
+		SideEffects:       b.sideEffects,
 		Handlers:          b.handlers,
 	}
 
@@ -206,6 +254,9 @@ func (b *WebhookBuilder) Build() (*admission.Webhook, error) {
 				admissionregistrationv1beta1.Create,
 				admissionregistrationv1beta1.Update,
 			}
+			if b.validatesDelete {
+				b.operations = append(b.operations, admissionregistrationv1beta1.Delete)
+			}
 		}
 		w.Rules = []admissionregistrationv1beta1.RuleWithOperations{
 			{
@@ -63,6 +63,26 @@ The following snippet shows how to register CRD types with manager's scheme.
 		// handle error
 	}
 
+Types implementing admission.Defaulter or admission.Validator can be used to build a
+webhook which decodes requests into the type and calls its methods, and to register
+the webhook with a webhook server.
+
+	err := NewWebhookBuilder().
+		ForDefaulter(&Kraken{}).
+		WithManager(mgr).
+		Complete(srv)
+	if err != nil {
+		// handle error
+	}
+
+	err = NewWebhookBuilder().
+		ForValidator(&Kraken{}).
+		WithManager(mgr).
+		Complete(srv)
+	if err != nil {
+		// handle error
+	}
+
 There are more options for configuring a webhook. e.g. Name, Path, FailurePolicy, NamespaceSelector.
 Here is another example:
 
@@ -0,0 +1,176 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mattbaird/jsonpatch"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/patch"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	atypes "sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/internal/metrics"
+)
+
+// Named returns a Handler that invokes h, and is labeled with name in the per-handler
+// metrics of a Chain.  Handlers that are not named are labeled with their type.
+func Named(name string, h Handler) Handler {
+	return &namedHandler{name: name, Handler: h}
+}
+
+type namedHandler struct {
+	name string
+	Handler
+}
+
+// HasSideEffects implements SideEffector
+func (h *namedHandler) HasSideEffects() bool {
+	return hasSideEffects(h.Handler)
+}
+
+var _ inject.Client = &namedHandler{}
+
+// InjectClient injects the client into the wrapped handler.
+func (h *namedHandler) InjectClient(c client.Client) error {
+	_, err := inject.ClientInto(c, h.Handler)
+	return err
+}
+
+var _ inject.Decoder = &namedHandler{}
+
+// InjectDecoder injects the decoder into the wrapped handler.
+func (h *namedHandler) InjectDecoder(d atypes.Decoder) error {
+	_, err := inject.DecoderInto(d, h.Handler)
+	return err
+}
+
+// Chain returns a Handler that invokes handlers in order as a single handler, so that
+// they can be composed on the same path.  Unlike the handlers of a Webhook, which all see
+// the original object, each handler in a chain sees the object as patched by the handlers
+// before it, and the response contains the combined patch.  The chain stops at the first
+// handler that denies the request.
+//
+// The latency of each handler is recorded in the
+// controller_runtime_webhook_handler_latency_seconds metric, labeled with name.
+func Chain(name string, handlers ...Handler) Handler {
+	return &chain{name: name, handlers: handlers}
+}
+
+type chain struct {
+	name     string
+	handlers []Handler
+}
+
+// HasSideEffects implements SideEffector, returning true if any handler of the chain has side effects.
+func (c *chain) HasSideEffects() bool {
+	for _, h := range c.handlers {
+		if hasSideEffects(h) {
+			return true
+		}
+	}
+	return false
+}
+
+var _ inject.Client = &chain{}
+
+// InjectClient injects the client into the handlers of the chain.
+func (c *chain) InjectClient(cl client.Client) error {
+	for _, h := range c.handlers {
+		if _, err := inject.ClientInto(cl, h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ inject.Decoder = &chain{}
+
+// InjectDecoder injects the decoder into the handlers of the chain.
+func (c *chain) InjectDecoder(d atypes.Decoder) error {
+	for _, h := range c.handlers {
+		if _, err := inject.DecoderInto(d, h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handle implements Handler
+func (c *chain) Handle(ctx context.Context, req atypes.Request) atypes.Response {
+	if req.AdmissionRequest == nil {
+		return ErrorResponse(http.StatusBadRequest, errors.New("got an empty AdmissionRequest"))
+	}
+	original := req.AdmissionRequest.Object.Raw
+	current := original
+
+	for _, h := range c.handlers {
+		ar := *req.AdmissionRequest
+		ar.Object.Raw = current
+
+		startTS := time.Now()
+		resp := h.Handle(ctx, atypes.Request{AdmissionRequest: &ar})
+		metrics.HandlerLatency.WithLabelValues(c.name, ctrlmetrics.LabelValue(ctrlmetrics.LabelHandler, handlerName(h))).Observe(time.Now().Sub(startTS).Seconds())
+
+		if resp.Response == nil || !resp.Response.Allowed {
+			return resp
+		}
+		if resp.Response.PatchType != nil && *resp.Response.PatchType != admissionv1beta1.PatchTypeJSONPatch {
+			return ErrorResponse(http.StatusInternalServerError,
+				fmt.Errorf("unexpected patch type returned by the handler: %v, only allow: %v",
+					resp.Response.PatchType, admissionv1beta1.PatchTypeJSONPatch))
+		}
+		if len(resp.Patches) == 0 {
+			continue
+		}
+		patched, err := patch.ApplyJSONPatch(current, resp.Patches)
+		if err != nil {
+			return ErrorResponse(http.StatusInternalServerError,
+				fmt.Errorf("unable to apply the patch returned by handler %s: %v", handlerName(h), err))
+		}
+		current = patched
+	}
+
+	if len(original) == 0 || string(current) == string(original) {
+		return ValidationResponse(true, "")
+	}
+	patches, err := jsonpatch.CreatePatch(original, current)
+	if err != nil {
+		return ErrorResponse(http.StatusInternalServerError, fmt.Errorf("error when creating the patch: %v", err))
+	}
+	return atypes.Response{
+		Patches: patches,
+		Response: &admissionv1beta1.AdmissionResponse{
+			Allowed:   true,
+			PatchType: func() *admissionv1beta1.PatchType { pt := admissionv1beta1.PatchTypeJSONPatch; return &pt }(),
+		},
+	}
+}
+
+func handlerName(h Handler) string {
+	if named, ok := h.(*namedHandler); ok {
+		return named.name
+	}
+	return fmt.Sprintf("%T", h)
+}
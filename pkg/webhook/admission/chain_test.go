@@ -0,0 +1,114 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/patch"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	atypes "sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/internal/metrics"
+)
+
+var _ = Describe("Chain", func() {
+	jsonDecoder := DecodeFunc(func(req atypes.Request, obj runtime.Object) error {
+		return json.Unmarshal(req.AdmissionRequest.Object.Raw, obj)
+	})
+
+	var original []byte
+	var req atypes.Request
+
+	BeforeEach(func() {
+		var err error
+		original, err = json.Marshal(&fakeKraken{ObjectMeta: metav1.ObjectMeta{Name: "kraken"}})
+		Expect(err).NotTo(HaveOccurred())
+		req = atypes.Request{AdmissionRequest: &admissionv1beta1.AdmissionRequest{
+			Operation: admissionv1beta1.Create,
+			Object:    runtime.RawExtension{Raw: original},
+		}}
+	})
+
+	// doubler doubles the tentacles of the kraken it sees.
+	doubler := HandlerFunc(func(_ context.Context, req atypes.Request) atypes.Response {
+		k := &fakeKraken{}
+		Expect(json.Unmarshal(req.AdmissionRequest.Object.Raw, k)).To(Succeed())
+		doubled := k.DeepCopyObject().(*fakeKraken)
+		doubled.Tentacles *= 2
+		return PatchResponse(k, doubled)
+	})
+
+	patched := func(resp atypes.Response) *fakeKraken {
+		raw, err := patch.ApplyJSONPatch(original, resp.Patches)
+		Expect(err).NotTo(HaveOccurred())
+		k := &fakeKraken{}
+		Expect(json.Unmarshal(raw, k)).To(Succeed())
+		return k
+	}
+
+	It("should pass the object patched by each handler to the next one", func() {
+		h := Chain("kraken", DefaultingWebhookFor(&fakeKraken{}), ValidatingWebhookFor(&fakeKraken{}), doubler)
+		_, err := inject.DecoderInto(jsonDecoder, h)
+		Expect(err).NotTo(HaveOccurred())
+
+		resp := h.Handle(context.TODO(), req)
+		Expect(resp.Response.Allowed).To(BeTrue())
+		Expect(*resp.Response.PatchType).To(Equal(admissionv1beta1.PatchTypeJSONPatch))
+		Expect(patched(resp).Tentacles).To(Equal(16))
+	})
+
+	It("should stop at the first handler that denies the request", func() {
+		called := false
+		h := Chain("kraken", ValidatingWebhookFor(&fakeKraken{}), HandlerFunc(func(context.Context, atypes.Request) atypes.Response {
+			called = true
+			return ValidationResponse(true, "")
+		}))
+		_, err := inject.DecoderInto(jsonDecoder, h)
+		Expect(err).NotTo(HaveOccurred())
+
+		resp := h.Handle(context.TODO(), req)
+		Expect(resp.Response.Allowed).To(BeFalse())
+		Expect(called).To(BeFalse())
+	})
+
+	It("should allow the request without a patch if no handler changed the object", func() {
+		h := Chain("kraken", HandlerFunc(func(context.Context, atypes.Request) atypes.Response {
+			return ValidationResponse(true, "")
+		}))
+		resp := h.Handle(context.TODO(), req)
+		Expect(resp.Response.Allowed).To(BeTrue())
+		Expect(resp.Patches).To(BeEmpty())
+	})
+
+	It("should record the latency of each handler", func() {
+		h := Chain("latency-chain", Named("doubler", doubler))
+		h.Handle(context.TODO(), req)
+
+		var latency dto.Metric
+		Expect(metrics.HandlerLatency.WithLabelValues("latency-chain", "doubler").(prometheus.Histogram).Write(&latency)).To(Succeed())
+		Expect(latency.GetHistogram().GetSampleCount()).To(BeEquivalentTo(1))
+	})
+})
@@ -17,6 +17,8 @@ limitations under the License.
 package admission
 
 import (
+	"errors"
+
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
@@ -46,3 +48,14 @@ func (d decoder) Decode(req types.Request, into runtime.Object) error {
 	deserializer := d.codecs.UniversalDeserializer()
 	return runtime.DecodeInto(deserializer, req.AdmissionRequest.Object.Raw, into)
 }
+
+// DecodeOldObject decodes the OldObject in the AdmissionRequest, which is set for updates,
+// into the passed-in runtime.Object using d.
+func DecodeOldObject(d types.Decoder, req types.Request, into runtime.Object) error {
+	if req.AdmissionRequest == nil {
+		return errors.New("got an empty AdmissionRequest")
+	}
+	oldReq := *req.AdmissionRequest
+	oldReq.Object = req.AdmissionRequest.OldObject
+	return d.Decode(types.Request{AdmissionRequest: &oldReq}, into)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+)
+
+// Defaulter defines functions for setting defaults on resources
+type Defaulter interface {
+	runtime.Object
+	Default()
+}
+
+// DefaultingWebhookFor creates a new Handler for defaulting the type of defaulter.
+// Each request is decoded into a new copy of defaulter, and the response patches
+// the object with the changes made by its Default method.
+func DefaultingWebhookFor(defaulter Defaulter) Handler {
+	return &mutatingHandler{defaulter: defaulter}
+}
+
+type mutatingHandler struct {
+	defaulter Defaulter
+	decoder   types.Decoder
+}
+
+var _ inject.Decoder = &mutatingHandler{}
+
+// InjectDecoder injects the decoder into a mutatingHandler.
+func (h *mutatingHandler) InjectDecoder(d types.Decoder) error {
+	h.decoder = d
+	return nil
+}
+
+// Handle handles admission requests.
+func (h *mutatingHandler) Handle(_ context.Context, req types.Request) types.Response {
+	if h.decoder == nil {
+		return ErrorResponse(http.StatusInternalServerError, errors.New("decoder has not been injected"))
+	}
+
+	// Get the object in the request
+	obj := h.defaulter.DeepCopyObject().(Defaulter)
+	if err := h.decoder.Decode(req, obj); err != nil {
+		return ErrorResponse(http.StatusBadRequest, err)
+	}
+
+	// Default the object
+	original := obj.DeepCopyObject()
+	obj.Default()
+	return PatchResponse(original, obj)
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	atypes "sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+)
+
+var _ = Describe("Defaulter and Validator handlers", func() {
+	jsonDecoder := DecodeFunc(func(req atypes.Request, obj runtime.Object) error {
+		return json.Unmarshal(req.AdmissionRequest.Object.Raw, obj)
+	})
+
+	requestFor := func(op admissionv1beta1.Operation, obj, old *fakeKraken) atypes.Request {
+		req := atypes.Request{AdmissionRequest: &admissionv1beta1.AdmissionRequest{Operation: op}}
+		if obj != nil {
+			raw, err := json.Marshal(obj)
+			Expect(err).NotTo(HaveOccurred())
+			req.AdmissionRequest.Object.Raw = raw
+		}
+		if old != nil {
+			raw, err := json.Marshal(old)
+			Expect(err).NotTo(HaveOccurred())
+			req.AdmissionRequest.OldObject.Raw = raw
+		}
+		return req
+	}
+
+	Describe("DefaultingWebhookFor", func() {
+		It("should return an error if the decoder has not been injected", func() {
+			resp := DefaultingWebhookFor(&fakeKraken{}).Handle(context.TODO(), requestFor(admissionv1beta1.Create, &fakeKraken{}, nil))
+			Expect(resp.Response.Allowed).To(BeFalse())
+		})
+
+		It("should patch the object with its defaults", func() {
+			h := DefaultingWebhookFor(&fakeKraken{})
+			Expect(h.(*mutatingHandler).InjectDecoder(jsonDecoder)).To(Succeed())
+
+			resp := h.Handle(context.TODO(), requestFor(admissionv1beta1.Create, &fakeKraken{}, nil))
+			Expect(resp.Response.Allowed).To(BeTrue())
+			Expect(resp.Patches).To(HaveLen(1))
+			Expect(resp.Patches[0].Path).To(Equal("/tentacles"))
+			Expect(resp.Patches[0].Value).To(BeEquivalentTo(8))
+		})
+	})
+
+	Describe("ValidatingWebhookFor", func() {
+		var h Handler
+
+		BeforeEach(func() {
+			h = ValidatingWebhookFor(&fakeKraken{})
+			Expect(h.(*validatingHandler).InjectDecoder(jsonDecoder)).To(Succeed())
+		})
+
+		It("should validate creates", func() {
+			resp := h.Handle(context.TODO(), requestFor(admissionv1beta1.Create, &fakeKraken{Tentacles: 8}, nil))
+			Expect(resp.Response.Allowed).To(BeTrue())
+
+			resp = h.Handle(context.TODO(), requestFor(admissionv1beta1.Create, &fakeKraken{Tentacles: 9}, nil))
+			Expect(resp.Response.Allowed).To(BeFalse())
+			Expect(resp.Response.Result.Reason).To(BeEquivalentTo("krakens have 8 tentacles"))
+		})
+
+		It("should validate updates against the old object", func() {
+			resp := h.Handle(context.TODO(), requestFor(admissionv1beta1.Update, &fakeKraken{Tentacles: 8}, &fakeKraken{Tentacles: 8}))
+			Expect(resp.Response.Allowed).To(BeTrue())
+
+			resp = h.Handle(context.TODO(), requestFor(admissionv1beta1.Update, &fakeKraken{Tentacles: 7}, &fakeKraken{Tentacles: 8}))
+			Expect(resp.Response.Allowed).To(BeFalse())
+		})
+
+		It("should validate deletes", func() {
+			resp := h.Handle(context.TODO(), requestFor(admissionv1beta1.Delete, nil, &fakeKraken{Tentacles: 8, Protected: true}))
+			Expect(resp.Response.Allowed).To(BeFalse())
+
+			resp = h.Handle(context.TODO(), requestFor(admissionv1beta1.Delete, nil, nil))
+			Expect(resp.Response.Allowed).To(BeTrue())
+		})
+
+		It("should return an error for an empty AdmissionRequest", func() {
+			resp := h.Handle(context.TODO(), atypes.Request{})
+			Expect(resp.Response.Allowed).To(BeFalse())
+			Expect(resp.Response.Result.Code).To(BeEquivalentTo(http.StatusBadRequest))
+		})
+	})
+})
+
+var _ Defaulter = &fakeKraken{}
+var _ Validator = &fakeKraken{}
+
+type fakeKraken struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Tentacles int  `json:"tentacles,omitempty"`
+	Protected bool `json:"protected,omitempty"`
+}
+
+func (k *fakeKraken) DeepCopyObject() runtime.Object {
+	out := *k
+	k.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	return &out
+}
+
+func (k *fakeKraken) Default() {
+	if k.Tentacles == 0 {
+		k.Tentacles = 8
+	}
+}
+
+func (k *fakeKraken) ValidateCreate() error {
+	if k.Tentacles != 8 {
+		return errors.New("krakens have 8 tentacles")
+	}
+	return nil
+}
+
+func (k *fakeKraken) ValidateUpdate(old runtime.Object) error {
+	if k.Tentacles < old.(*fakeKraken).Tentacles {
+		return errors.New("krakens do not lose tentacles")
+	}
+	return nil
+}
+
+func (k *fakeKraken) ValidateDelete() error {
+	if k.Protected {
+		return errors.New("kraken is protected")
+	}
+	return nil
+}
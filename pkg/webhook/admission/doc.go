@@ -91,6 +91,11 @@ The following snippet is an example implementation of validating handler.
 		h.decoder = d
 		return nil
 	}
+
+Handlers can be composed into a chain, in which each handler sees the object as patched
+by the handlers before it.
+
+	handler := admission.Chain("pods", &Mutator{}, admission.Named("validator", &Validator{}))
 */
 package admission
 
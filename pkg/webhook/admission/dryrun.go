@@ -0,0 +1,98 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	atypes "sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+)
+
+type dryRunKey struct{}
+
+// IsDryRun returns true if ctx is the context of a dry run request, whose modifications
+// will not be persisted.  Handlers must not have side effects, e.g. changes to other
+// objects, for dry run requests.
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// IsDryRunRequest returns true if req is a dry run request.
+func IsDryRunRequest(req atypes.Request) bool {
+	return req.AdmissionRequest != nil && req.AdmissionRequest.DryRun != nil && *req.AdmissionRequest.DryRun
+}
+
+// withDryRun returns a copy of ctx that records whether req is a dry run request.
+func withDryRun(ctx context.Context, req atypes.Request) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, IsDryRunRequest(req))
+}
+
+// SideEffector is implemented by handlers that have side effects, e.g. that create or
+// update other objects.  A Webhook that declares it has no side effects can't have such
+// handlers.
+type SideEffector interface {
+	HasSideEffects() bool
+}
+
+// hasSideEffects returns true if h declares it has side effects.
+func hasSideEffects(h Handler) bool {
+	s, ok := h.(SideEffector)
+	return ok && s.HasSideEffects()
+}
+
+// SkipOnDryRun returns a Handler that allows dry run requests without invoking h, for
+// handlers that only exist for their side effects.  A Webhook with such handlers can
+// declare the NoneOnDryRun side effect class.
+func SkipOnDryRun(h Handler) Handler {
+	return &skipOnDryRun{Handler: h}
+}
+
+type skipOnDryRun struct {
+	Handler
+}
+
+// Handle implements Handler
+func (h *skipOnDryRun) Handle(ctx context.Context, req atypes.Request) atypes.Response {
+	if IsDryRun(ctx) || IsDryRunRequest(req) {
+		return ValidationResponse(true, "")
+	}
+	return h.Handler.Handle(ctx, req)
+}
+
+// HasSideEffects implements SideEffector
+func (h *skipOnDryRun) HasSideEffects() bool {
+	return hasSideEffects(h.Handler)
+}
+
+var _ inject.Client = &skipOnDryRun{}
+
+// InjectClient injects the client into the wrapped handler.
+func (h *skipOnDryRun) InjectClient(c client.Client) error {
+	_, err := inject.ClientInto(c, h.Handler)
+	return err
+}
+
+var _ inject.Decoder = &skipOnDryRun{}
+
+// InjectDecoder injects the decoder into the wrapped handler.
+func (h *skipOnDryRun) InjectDecoder(d atypes.Decoder) error {
+	_, err := inject.DecoderInto(d, h.Handler)
+	return err
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	atypes "sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/types"
+)
+
+// sideEffectHandler records whether it was called, standing in for a handler that
+// changes other objects.
+type sideEffectHandler struct {
+	called bool
+}
+
+func (h *sideEffectHandler) Handle(context.Context, atypes.Request) atypes.Response {
+	h.called = true
+	return ValidationResponse(true, "")
+}
+
+func (h *sideEffectHandler) HasSideEffects() bool {
+	return true
+}
+
+var _ = Describe("dry run", func() {
+	requestWithDryRun := func(dryRun bool) atypes.Request {
+		return atypes.Request{AdmissionRequest: &admissionv1beta1.AdmissionRequest{
+			Operation: admissionv1beta1.Create,
+			DryRun:    &dryRun,
+		}}
+	}
+
+	It("should expose the dry run flag of the request in the context", func() {
+		var dryRun bool
+		wh := &Webhook{
+			Type: types.WebhookTypeValidating,
+			Handlers: []Handler{HandlerFunc(func(ctx context.Context, _ atypes.Request) atypes.Response {
+				dryRun = IsDryRun(ctx)
+				return ValidationResponse(true, "")
+			})},
+		}
+
+		wh.Handle(context.TODO(), requestWithDryRun(true))
+		Expect(dryRun).To(BeTrue())
+		wh.Handle(context.TODO(), requestWithDryRun(false))
+		Expect(dryRun).To(BeFalse())
+	})
+
+	It("should not invoke a SkipOnDryRun handler for dry run requests", func() {
+		h := &sideEffectHandler{}
+		skip := SkipOnDryRun(h)
+
+		resp := skip.Handle(context.TODO(), requestWithDryRun(true))
+		Expect(resp.Response.Allowed).To(BeTrue())
+		Expect(h.called).To(BeFalse())
+
+		skip.Handle(context.TODO(), requestWithDryRun(false))
+		Expect(h.called).To(BeTrue())
+	})
+
+	Describe("Validate", func() {
+		webhookWith := func(sideEffects admissionregistrationv1beta1.SideEffectClass, h Handler) *Webhook {
+			return &Webhook{
+				Name:        "side-effects.example.com",
+				Path:        "/side-effects",
+				Type:        types.WebhookTypeValidating,
+				Rules:       []admissionregistrationv1beta1.RuleWithOperations{{}},
+				SideEffects: &sideEffects,
+				Handlers:    []Handler{h},
+			}
+		}
+
+		It("should reject handlers with side effects when the webhook declares None", func() {
+			Expect(webhookWith(admissionregistrationv1beta1.SideEffectClassNone, &sideEffectHandler{}).Validate()).
+				To(MatchError(ContainSubstring("has side effects")))
+			Expect(webhookWith(admissionregistrationv1beta1.SideEffectClassNone, Chain("chain", &sideEffectHandler{})).Validate()).
+				To(MatchError(ContainSubstring("has side effects")))
+		})
+
+		It("should allow handlers with side effects when the webhook declares NoneOnDryRun", func() {
+			Expect(webhookWith(admissionregistrationv1beta1.SideEffectClassNoneOnDryRun, SkipOnDryRun(&sideEffectHandler{})).Validate()).
+				To(Succeed())
+		})
+	})
+})
@@ -17,7 +17,6 @@ limitations under the License.
 package admission
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -52,20 +51,27 @@ func (wh *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	startTS := time.Now()
 	defer metrics.RequestLatency.WithLabelValues(wh.Name).Observe(time.Now().Sub(startTS).Seconds())
 
+	// Correlate the logs of the request, and return its ID to the caller.  The ID itself is not
+	// recorded in the metrics, whose cardinality would be unbounded, only where it comes from.
+	id, source := requestIDFor(r)
+	w.Header().Set(RequestIDHeader, id)
+	metrics.RequestIDs.WithLabelValues(wh.Name, source).Inc()
+	reqLog := log.WithValues("webhook", wh.Name, "path", wh.Path, "request id", id)
+
 	var body []byte
 	var err error
 
 	var reviewResponse types.Response
 	if r.Body != nil {
 		if body, err = ioutil.ReadAll(r.Body); err != nil {
-			log.Error(err, "unable to read the body from the incoming request")
+			reqLog.Error(err, "unable to read the body from the incoming request")
 			reviewResponse = ErrorResponse(http.StatusBadRequest, err)
 			wh.writeResponse(w, reviewResponse)
 			return
 		}
 	} else {
 		err = errors.New("request body is empty")
-		log.Error(err, "bad request")
+		reqLog.Error(err, "bad request")
 		reviewResponse = ErrorResponse(http.StatusBadRequest, err)
 		wh.writeResponse(w, reviewResponse)
 		return
@@ -75,7 +81,7 @@ func (wh *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	contentType := r.Header.Get("Content-Type")
 	if contentType != "application/json" {
 		err = fmt.Errorf("contentType=%s, expect application/json", contentType)
-		log.Error(err, "unable to process a request with an unknown content type", "content type", contentType)
+		reqLog.Error(err, "unable to process a request with an unknown content type", "content type", contentType)
 		reviewResponse = ErrorResponse(http.StatusBadRequest, err)
 		wh.writeResponse(w, reviewResponse)
 		return
@@ -83,14 +89,14 @@ func (wh *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	ar := v1beta1.AdmissionReview{}
 	if _, _, err := admissionv1beta1schemecodecs.UniversalDeserializer().Decode(body, nil, &ar); err != nil {
-		log.Error(err, "unable to decode the request")
+		reqLog.Error(err, "unable to decode the request")
 		reviewResponse = ErrorResponse(http.StatusBadRequest, err)
 		wh.writeResponse(w, reviewResponse)
 		return
 	}
 
 	// TODO: add panic-recovery for Handle
-	reviewResponse = wh.Handle(context.Background(), types.Request{AdmissionRequest: ar.Request})
+	reviewResponse = wh.Handle(withRequestID(r.Context(), id), types.Request{AdmissionRequest: ar.Request})
 	wh.writeResponse(w, reviewResponse)
 }
 
@@ -22,6 +22,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -142,6 +143,52 @@ var _ = Describe("admission webhook http handler", func() {
 			Expect(h.invoked).To(BeTrue())
 		})
 	})
+
+	Describe("request id", func() {
+		var id string
+		var wh *Webhook
+		BeforeEach(func() {
+			id = ""
+			wh = &Webhook{
+				Type: types.WebhookTypeValidating,
+				Handlers: []Handler{&fakeHandler{fn: func(ctx context.Context, _ atypes.Request) atypes.Response {
+					id = RequestID(ctx)
+					return ValidationResponse(true, "")
+				}}},
+			}
+		})
+
+		request := func(header http.Header) *http.Request {
+			header.Set("Content-Type", "application/json")
+			return &http.Request{Header: header, Body: nopCloser{Reader: bytes.NewBufferString(`{"request":{}}`)}}
+		}
+
+		It("should propagate the request id from the request header", func() {
+			wh.ServeHTTP(w, request(http.Header{RequestIDHeader: []string{"foo"}}))
+			Expect(id).To(Equal("foo"))
+			Expect(w.Header().Get(RequestIDHeader)).To(Equal("foo"))
+		})
+
+		It("should generate a request id if the request has none", func() {
+			wh.ServeHTTP(w, request(http.Header{}))
+			Expect(id).NotTo(BeEmpty())
+			Expect(w.Header().Get(RequestIDHeader)).To(Equal(id))
+		})
+
+		It("should generate a request id if the request header is invalid", func() {
+			wh.ServeHTTP(w, request(http.Header{RequestIDHeader: []string{"foo bar"}}))
+			Expect(id).NotTo(Equal("foo bar"))
+			Expect(w.Header().Get(RequestIDHeader)).To(Equal(id))
+
+			wh.ServeHTTP(w, request(http.Header{RequestIDHeader: []string{strings.Repeat("a", maxRequestIDLength+1)}}))
+			Expect(id).To(HaveLen(36))
+		})
+
+		It("should return the request id of requests failing to decode", func() {
+			wh.ServeHTTP(w, &http.Request{Header: http.Header{RequestIDHeader: []string{"foo"}}})
+			Expect(w.Header().Get(RequestIDHeader)).To(Equal("foo"))
+		})
+	})
 })
 
 type nopCloser struct {
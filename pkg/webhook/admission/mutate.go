@@ -0,0 +1,117 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The Ensure functions apply common mutations idempotently, so that a mutating webhook
+// produces an empty patch when it is invoked again on an object it already mutated, e.g.
+// when the API server reinvokes webhooks after a later webhook changed the object.
+// Applying a mutation unconditionally instead would inject a second sidecar, or a
+// duplicate environment variable, on each invocation.  Each function returns whether it
+// changed the object.
+
+// EnsureLabel sets the label key of obj to value.
+func EnsureLabel(obj metav1.Object, key, value string) bool {
+	labels := obj.GetLabels()
+	if v, ok := labels[key]; ok && v == value {
+		return false
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[key] = value
+	obj.SetLabels(labels)
+	return true
+}
+
+// EnsureAnnotation sets the annotation key of obj to value.
+func EnsureAnnotation(obj metav1.Object, key, value string) bool {
+	annotations := obj.GetAnnotations()
+	if v, ok := annotations[key]; ok && v == value {
+		return false
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[key] = value
+	obj.SetAnnotations(annotations)
+	return true
+}
+
+// EnsureContainer appends container to the containers of spec, unless spec already has a
+// container with its name.  An existing container is left as is, so that the mutation
+// does not override changes made to it by other webhooks.
+func EnsureContainer(spec *corev1.PodSpec, container corev1.Container) bool {
+	for _, c := range spec.Containers {
+		if c.Name == container.Name {
+			return false
+		}
+	}
+	spec.Containers = append(spec.Containers, container)
+	return true
+}
+
+// EnsureInitContainer is like EnsureContainer, but for the init containers of spec.
+func EnsureInitContainer(spec *corev1.PodSpec, container corev1.Container) bool {
+	for _, c := range spec.InitContainers {
+		if c.Name == container.Name {
+			return false
+		}
+	}
+	spec.InitContainers = append(spec.InitContainers, container)
+	return true
+}
+
+// EnsureVolume appends volume to the volumes of spec, unless spec already has a volume
+// with its name.
+func EnsureVolume(spec *corev1.PodSpec, volume corev1.Volume) bool {
+	for _, v := range spec.Volumes {
+		if v.Name == volume.Name {
+			return false
+		}
+	}
+	spec.Volumes = append(spec.Volumes, volume)
+	return true
+}
+
+// EnsureEnvVar appends env to the environment of container, unless container already
+// has a variable with its name.
+func EnsureEnvVar(container *corev1.Container, env corev1.EnvVar) bool {
+	for _, e := range container.Env {
+		if e.Name == env.Name {
+			return false
+		}
+	}
+	container.Env = append(container.Env, env)
+	return true
+}
+
+// EnsureVolumeMount appends mount to the volume mounts of container, unless container
+// already mounts a volume at its path.
+func EnsureVolumeMount(container *corev1.Container, mount corev1.VolumeMount) bool {
+	for _, m := range container.VolumeMounts {
+		if m.MountPath == mount.MountPath {
+			return false
+		}
+	}
+	container.VolumeMounts = append(container.VolumeMounts, mount)
+	return true
+}
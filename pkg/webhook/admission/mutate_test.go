@@ -0,0 +1,121 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/patch"
+	atypes "sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+)
+
+var _ = Describe("Ensure mutations", func() {
+	sidecar := corev1.Container{Name: "proxy", Image: "proxy:v1"}
+
+	// injector injects a sidecar into the pods it sees, as a mutating webhook would.
+	injector := HandlerFunc(func(_ context.Context, req atypes.Request) atypes.Response {
+		pod := &corev1.Pod{}
+		Expect(json.Unmarshal(req.AdmissionRequest.Object.Raw, pod)).To(Succeed())
+		original := pod.DeepCopy()
+
+		EnsureLabel(pod, "proxy-injected", "true")
+		EnsureAnnotation(pod, "proxy-version", "v1")
+		EnsureVolume(&pod.Spec, corev1.Volume{Name: "proxy-config"})
+		EnsureInitContainer(&pod.Spec, corev1.Container{Name: "proxy-init", Image: "proxy-init:v1"})
+		EnsureContainer(&pod.Spec, sidecar)
+		for i := range pod.Spec.Containers {
+			EnsureEnvVar(&pod.Spec.Containers[i], corev1.EnvVar{Name: "PROXY_PORT", Value: "15001"})
+			EnsureVolumeMount(&pod.Spec.Containers[i], corev1.VolumeMount{Name: "proxy-config", MountPath: "/etc/proxy"})
+		}
+		return PatchResponse(original, pod)
+	})
+
+	// invoke invokes injector on raw, and returns the response and the patched object.
+	invoke := func(raw []byte) (atypes.Response, []byte) {
+		resp := injector.Handle(context.TODO(), atypes.Request{AdmissionRequest: &admissionv1beta1.AdmissionRequest{
+			Operation: admissionv1beta1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		}})
+		Expect(resp.Response.Allowed).To(BeTrue())
+		patched, err := patch.ApplyJSONPatch(raw, resp.Patches)
+		Expect(err).NotTo(HaveOccurred())
+		return resp, patched
+	}
+
+	var raw []byte
+	BeforeEach(func() {
+		var err error
+		raw, err = json.Marshal(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app:v1"}}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should produce an empty patch when reinvoked on the mutated object", func() {
+		resp, patched := invoke(raw)
+		Expect(resp.Patches).NotTo(BeEmpty())
+
+		resp, reinvoked := invoke(patched)
+		Expect(resp.Patches).To(BeEmpty())
+		Expect(reinvoked).To(MatchJSON(patched))
+
+		pod := &corev1.Pod{}
+		Expect(json.Unmarshal(reinvoked, pod)).To(Succeed())
+		Expect(pod.Spec.Containers).To(HaveLen(2))
+		Expect(pod.Spec.InitContainers).To(HaveLen(1))
+		Expect(pod.Spec.Volumes).To(HaveLen(1))
+		for _, c := range pod.Spec.Containers {
+			Expect(c.Env).To(HaveLen(1))
+			Expect(c.VolumeMounts).To(HaveLen(1))
+		}
+	})
+
+	It("should not override changes made by later webhooks when reinvoked", func() {
+		_, patched := invoke(raw)
+
+		By("changing the sidecar as a later webhook would")
+		pod := &corev1.Pod{}
+		Expect(json.Unmarshal(patched, pod)).To(Succeed())
+		pod.Spec.Containers[1].Image = "proxy:v2"
+		changed, err := json.Marshal(pod)
+		Expect(err).NotTo(HaveOccurred())
+
+		resp, _ := invoke(changed)
+		Expect(resp.Patches).To(BeEmpty())
+	})
+
+	It("should report whether the object changed", func() {
+		pod := &corev1.Pod{}
+		Expect(EnsureLabel(pod, "app", "foo")).To(BeTrue())
+		Expect(EnsureLabel(pod, "app", "foo")).To(BeFalse())
+		Expect(EnsureLabel(pod, "app", "bar")).To(BeTrue())
+		Expect(pod.Labels).To(Equal(map[string]string{"app": "bar"}))
+
+		Expect(EnsureContainer(&pod.Spec, sidecar)).To(BeTrue())
+		Expect(EnsureContainer(&pod.Spec, corev1.Container{Name: "proxy", Image: "proxy:v2"})).To(BeFalse())
+		Expect(pod.Spec.Containers).To(Equal([]corev1.Container{sidecar}))
+	})
+})
@@ -0,0 +1,109 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	atypes "sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/internal/metrics"
+)
+
+// Quota limits the number of objects which can be created, e.g. the number of
+// Deployments with a given label in each namespace.
+type Quota struct {
+	// Name names the quota in denials and in the controller_runtime_webhook_quota_requests_total
+	// metric.
+	Name string
+
+	// List is a list of the type of the objects counted against the quota, e.g. &appsv1.DeploymentList{}.
+	List runtime.Object
+
+	// Limit is the number of objects which can exist.  Creations are denied once Limit
+	// objects are counted.
+	Limit int
+
+	// Selector returns the list options selecting the objects counted against the quota
+	// of a creation, e.g. the objects with the label of the created object.  Use field
+	// selectors on fields indexed in the cache, rather than label selectors, to count
+	// without listing every object of the type.  It defaults to the objects in the
+	// namespace of the request.
+	Selector func(req atypes.Request) ([]client.ListOptionFunc, error)
+}
+
+// QuotaWebhookFor creates a new Handler denying creations once quota.Limit objects
+// matching quota.Selector exist.  The objects are counted with the Reader returned by
+// ReaderFromContext, so the Webhook serving the handler must be injected with the cache.
+//
+// The count is not transactional: the cache may not have observed objects created
+// recently, and concurrent requests are counted independently, so the limit may be
+// briefly exceeded.  Quotas which must never be exceeded need to be enforced by a
+// ResourceQuota, or by a controller cleaning up the excess objects.
+func QuotaWebhookFor(quota Quota) Handler {
+	return &quotaHandler{quota: quota}
+}
+
+type quotaHandler struct {
+	quota Quota
+}
+
+// Handle handles admission requests.
+func (h *quotaHandler) Handle(ctx context.Context, req atypes.Request) atypes.Response {
+	if req.AdmissionRequest.Operation != admissionv1beta1.Create {
+		return ValidationResponse(true, "")
+	}
+
+	resp := h.handle(ctx, req)
+	metrics.QuotaRequests.WithLabelValues(h.quota.Name, strconv.FormatBool(resp.Response.Allowed)).Inc()
+	return resp
+}
+
+func (h *quotaHandler) handle(ctx context.Context, req atypes.Request) atypes.Response {
+	r, ok := ReaderFromContext(ctx)
+	if !ok {
+		return ErrorResponse(http.StatusInternalServerError, errors.New("cache has not been injected"))
+	}
+
+	opts := []client.ListOptionFunc{client.InNamespace(req.AdmissionRequest.Namespace)}
+	if h.quota.Selector != nil {
+		var err error
+		if opts, err = h.quota.Selector(req); err != nil {
+			return ErrorResponse(http.StatusBadRequest, err)
+		}
+	}
+
+	list := h.quota.List.DeepCopyObject()
+	if err := r.List(ctx, list, opts...); err != nil {
+		return ErrorResponse(http.StatusInternalServerError, err)
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return ErrorResponse(http.StatusInternalServerError, err)
+	}
+	if count := len(items); count >= h.quota.Limit {
+		return ValidationResponse(false, fmt.Sprintf("quota %s exceeded: %d of %d objects exist", h.quota.Name, count, h.quota.Limit))
+	}
+	return ValidationResponse(true, "")
+}
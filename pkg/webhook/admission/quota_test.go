@@ -0,0 +1,130 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	atypes "sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/internal/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/types"
+)
+
+var _ = Describe("QuotaWebhookFor", func() {
+	var stop chan struct{}
+	var c *readingCache
+
+	requestFor := func(op admissionv1beta1.Operation) atypes.Request {
+		return atypes.Request{AdmissionRequest: &admissionv1beta1.AdmissionRequest{
+			Operation: op,
+			Namespace: "tenant",
+		}}
+	}
+
+	webhookFor := func(quota Quota) *Webhook {
+		wh := &Webhook{Type: types.WebhookTypeValidating, Handlers: []Handler{QuotaWebhookFor(quota)}}
+		Expect(wh.InjectStopChannel(stop)).To(Succeed())
+		Expect(wh.InjectCache(c)).To(Succeed())
+		return wh
+	}
+
+	// handleSynced handles req once the reader of wh has observed that the cache synced.
+	handleSynced := func(wh *Webhook, req atypes.Request) atypes.Response {
+		Eventually(func() error {
+			_, err := wh.getReader().reader()
+			return err
+		}).Should(Succeed())
+		return wh.Handle(context.Background(), req)
+	}
+
+	BeforeEach(func() {
+		synced := true
+		c = &readingCache{Client: fake.NewFakeClient(
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant", Name: "a"}},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant", Name: "b"}},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "c"}},
+		)}
+		c.Synced = &synced
+		stop = make(chan struct{})
+	})
+
+	AfterEach(func() {
+		close(stop)
+	})
+
+	It("should allow creations below the limit", func() {
+		wh := webhookFor(Quota{Name: "below", List: &corev1.ConfigMapList{}, Limit: 3})
+		Expect(handleSynced(wh, requestFor(admissionv1beta1.Create)).Response.Allowed).To(BeTrue())
+	})
+
+	It("should deny creations once the limit is reached", func() {
+		wh := webhookFor(Quota{Name: "reached", List: &corev1.ConfigMapList{}, Limit: 2})
+		resp := handleSynced(wh, requestFor(admissionv1beta1.Create))
+		Expect(resp.Response.Allowed).To(BeFalse())
+		Expect(string(resp.Response.Result.Reason)).To(Equal("quota reached exceeded: 2 of 2 objects exist"))
+	})
+
+	It("should allow other operations", func() {
+		wh := webhookFor(Quota{Name: "update", List: &corev1.ConfigMapList{}, Limit: 0})
+		Expect(handleSynced(wh, requestFor(admissionv1beta1.Update)).Response.Allowed).To(BeTrue())
+	})
+
+	It("should count the objects matching the selector", func() {
+		wh := webhookFor(Quota{Name: "selector", List: &corev1.ConfigMapList{}, Limit: 2,
+			Selector: func(atypes.Request) ([]client.ListOptionFunc, error) {
+				return []client.ListOptionFunc{client.InNamespace("other")}, nil
+			}})
+		Expect(handleSynced(wh, requestFor(admissionv1beta1.Create)).Response.Allowed).To(BeTrue())
+	})
+
+	It("should reject requests the selector fails for", func() {
+		wh := webhookFor(Quota{Name: "invalid", List: &corev1.ConfigMapList{}, Limit: 2,
+			Selector: func(atypes.Request) ([]client.ListOptionFunc, error) {
+				return nil, errors.New("unable to select")
+			}})
+		resp := handleSynced(wh, requestFor(admissionv1beta1.Create))
+		Expect(resp.Response.Allowed).To(BeFalse())
+		Expect(resp.Response.Result.Code).To(BeEquivalentTo(400))
+	})
+
+	It("should fail if the cache has not been injected", func() {
+		h := QuotaWebhookFor(Quota{Name: "uninjected", List: &corev1.ConfigMapList{}, Limit: 2})
+		resp := h.Handle(context.Background(), requestFor(admissionv1beta1.Create))
+		Expect(resp.Response.Allowed).To(BeFalse())
+		Expect(resp.Response.Result.Message).To(Equal("cache has not been injected"))
+	})
+
+	It("should count the checked creations", func() {
+		wh := webhookFor(Quota{Name: "counted", List: &corev1.ConfigMapList{}, Limit: 2})
+		handleSynced(wh, requestFor(admissionv1beta1.Create))
+
+		var denied dto.Metric
+		Expect(metrics.QuotaRequests.WithLabelValues("counted", "false").(prometheus.Counter).Write(&denied)).To(Succeed())
+		Expect(denied.GetCounter().GetValue()).To(BeEquivalentTo(1))
+	})
+})
@@ -0,0 +1,102 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type readerKey struct{}
+
+// ReaderFromContext returns the client.Reader of the Webhook handling the request of ctx.
+// Handlers can use it for validation rules needing cluster context, e.g. counting the
+// objects of a quota, instead of creating their own clients for each request.  The Reader
+// reads from the Manager's cache once the cache has synced, and from the API server until
+// then, so that it never returns results from a cache which is still being filled.  It
+// returns false if the Webhook was not injected with a cache.
+func ReaderFromContext(ctx context.Context) (client.Reader, bool) {
+	r, ok := ctx.Value(readerKey{}).(client.Reader)
+	return r, ok
+}
+
+// withReader returns a copy of ctx holding r.
+func withReader(ctx context.Context, r client.Reader) context.Context {
+	return context.WithValue(ctx, readerKey{}, r)
+}
+
+var _ client.Reader = &syncedReader{}
+
+// syncedReader reads from a cache once it has synced, and from the API server until then.
+type syncedReader struct {
+	cache  cache.Cache
+	config *rest.Config
+	scheme *runtime.Scheme
+
+	// synced is set to 1 once the cache has synced, and must be accessed atomically.
+	synced int32
+
+	liveOnce sync.Once
+	live     client.Reader
+	liveErr  error
+}
+
+// waitForCacheSync marks the reader synced once the cache has synced, or returns once stop is closed.
+func (r *syncedReader) waitForCacheSync(stop <-chan struct{}) {
+	if r.cache.WaitForCacheSync(stop) {
+		atomic.StoreInt32(&r.synced, 1)
+	}
+}
+
+// reader returns the cache if it has synced, and a client reading from the API server otherwise.
+func (r *syncedReader) reader() (client.Reader, error) {
+	if atomic.LoadInt32(&r.synced) == 1 {
+		return r.cache, nil
+	}
+	if r.config == nil {
+		return nil, fmt.Errorf("the cache has not synced and no config was injected to read from the API server")
+	}
+	r.liveOnce.Do(func() {
+		r.live, r.liveErr = client.New(r.config, client.Options{Scheme: r.scheme})
+	})
+	return r.live, r.liveErr
+}
+
+// Get implements client.Reader
+func (r *syncedReader) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	reader, err := r.reader()
+	if err != nil {
+		return err
+	}
+	return reader.Get(ctx, key, obj)
+}
+
+// List implements client.Reader
+func (r *syncedReader) List(ctx context.Context, list runtime.Object, opts ...client.ListOptionFunc) error {
+	reader, err := r.reader()
+	if err != nil {
+		return err
+	}
+	return reader.List(ctx, list, opts...)
+}
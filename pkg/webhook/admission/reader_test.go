@@ -0,0 +1,126 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	atypes "sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/types"
+)
+
+// readingCache is a cache reading from a fake client.
+type readingCache struct {
+	informertest.FakeInformers
+	client.Client
+}
+
+func (c *readingCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	return c.Client.Get(ctx, key, obj)
+}
+
+func (c *readingCache) List(ctx context.Context, list runtime.Object, opts ...client.ListOptionFunc) error {
+	return c.Client.List(ctx, list, opts...)
+}
+
+// cacheHandler records the cache injected into it.
+type cacheHandler struct {
+	HandlerFunc
+	cache cache.Cache
+}
+
+func (h *cacheHandler) InjectCache(c cache.Cache) error {
+	h.cache = c
+	return nil
+}
+
+var _ = Describe("ReaderFromContext", func() {
+	var c *readingCache
+	var stop chan struct{}
+	var err error
+	var found bool
+	var wh *Webhook
+
+	readPod := HandlerFunc(func(ctx context.Context, _ atypes.Request) atypes.Response {
+		var r client.Reader
+		r, found = ReaderFromContext(ctx)
+		if found {
+			err = r.Get(ctx, client.ObjectKey{Namespace: "default", Name: "quota-pod"}, &corev1.Pod{})
+		}
+		return ValidationResponse(true, "")
+	})
+	request := atypes.Request{AdmissionRequest: &admissionv1beta1.AdmissionRequest{Operation: admissionv1beta1.Create}}
+
+	BeforeEach(func() {
+		c = &readingCache{Client: fake.NewFakeClient(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "quota-pod"},
+		})}
+		stop = make(chan struct{})
+		err, found = nil, false
+		wh = &Webhook{Type: types.WebhookTypeValidating, Handlers: []Handler{readPod}}
+		Expect(wh.InjectStopChannel(stop)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		close(stop)
+	})
+
+	It("should not provide a reader if no cache was injected", func() {
+		wh.Handle(context.Background(), request)
+		Expect(found).To(BeFalse())
+	})
+
+	It("should read from the cache once it has synced", func() {
+		synced := true
+		c.Synced = &synced
+		Expect(wh.InjectCache(c)).To(Succeed())
+
+		Eventually(func() error {
+			wh.Handle(context.Background(), request)
+			return err
+		}).Should(Succeed())
+		Expect(found).To(BeTrue())
+	})
+
+	It("should fail reads if the cache has not synced and no config was injected", func() {
+		synced := false
+		c.Synced = &synced
+		Expect(wh.InjectCache(c)).To(Succeed())
+
+		wh.Handle(context.Background(), request)
+		Expect(found).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("the cache has not synced")))
+	})
+
+	It("should inject the cache into the handlers", func() {
+		h := &cacheHandler{HandlerFunc: readPod}
+		wh.Handlers = []Handler{h}
+		Expect(wh.InjectCache(c)).To(Succeed())
+		Expect(h.cache).To(Equal(c))
+	})
+})
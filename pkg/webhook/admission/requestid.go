@@ -0,0 +1,75 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+// RequestIDHeader is the header of the correlation ID of an admission request.  The ID of the
+// request is propagated from this header if it is set by the caller, e.g. a proxy in front of the
+// webhook, and generated otherwise.  It is returned in the same header of the response.
+const RequestIDHeader = "X-Request-Id"
+
+// maxRequestIDLength bounds the length of the propagated IDs, since they are logged.
+const maxRequestIDLength = 128
+
+const (
+	// requestIDPropagated is the source of IDs propagated from the RequestIDHeader.
+	requestIDPropagated = "propagated"
+	// requestIDGenerated is the source of IDs generated by the webhook.
+	requestIDGenerated = "generated"
+)
+
+type requestIDKey struct{}
+
+// RequestID returns the correlation ID of the admission request of ctx, or an empty string if
+// ctx is not the context of an admission request.  Handlers can log it to correlate their logs
+// with the logs of the webhook and of the caller.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// withRequestID returns a copy of ctx holding id.
+func withRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// requestIDFor returns the correlation ID of r and whether it was propagated or generated.
+// IDs that are too long or not printable ASCII are replaced by generated ones.
+func requestIDFor(r *http.Request) (string, string) {
+	if id := r.Header.Get(RequestIDHeader); isValidRequestID(id) {
+		return id, requestIDPropagated
+	}
+	return string(uuid.NewUUID()), requestIDGenerated
+}
+
+func isValidRequestID(id string) bool {
+	if len(id) == 0 || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
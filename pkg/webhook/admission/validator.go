@@ -0,0 +1,103 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+)
+
+// Validator defines functions for validating an operation
+type Validator interface {
+	runtime.Object
+	ValidateCreate() error
+	ValidateUpdate(old runtime.Object) error
+	ValidateDelete() error
+}
+
+// ValidatingWebhookFor creates a new Handler for validating the type of validator.
+// Each request is decoded into new copies of validator, and is denied with the
+// error returned by the Validate method for its operation.
+func ValidatingWebhookFor(validator Validator) Handler {
+	return &validatingHandler{validator: validator}
+}
+
+type validatingHandler struct {
+	validator Validator
+	decoder   types.Decoder
+}
+
+var _ inject.Decoder = &validatingHandler{}
+
+// InjectDecoder injects the decoder into a validatingHandler.
+func (h *validatingHandler) InjectDecoder(d types.Decoder) error {
+	h.decoder = d
+	return nil
+}
+
+// Handle handles admission requests.
+func (h *validatingHandler) Handle(_ context.Context, req types.Request) types.Response {
+	if h.decoder == nil {
+		return ErrorResponse(http.StatusInternalServerError, errors.New("decoder has not been injected"))
+	}
+	if req.AdmissionRequest == nil {
+		return ErrorResponse(http.StatusBadRequest, errors.New("got an empty AdmissionRequest"))
+	}
+
+	// Get the object in the request
+	obj := h.validator.DeepCopyObject().(Validator)
+
+	var err error
+	switch req.AdmissionRequest.Operation {
+	case admissionv1beta1.Create:
+		if err := h.decoder.Decode(req, obj); err != nil {
+			return ErrorResponse(http.StatusBadRequest, err)
+		}
+		err = obj.ValidateCreate()
+	case admissionv1beta1.Update:
+		oldObj := h.validator.DeepCopyObject()
+		if err := h.decoder.Decode(req, obj); err != nil {
+			return ErrorResponse(http.StatusBadRequest, err)
+		}
+		if err := DecodeOldObject(h.decoder, req, oldObj); err != nil {
+			return ErrorResponse(http.StatusBadRequest, err)
+		}
+		err = obj.ValidateUpdate(oldObj)
+	case admissionv1beta1.Delete:
+		// The API server only sends the deleted object as the OldObject of the request
+		// from Kubernetes 1.15, so it may be left empty.
+		if len(req.AdmissionRequest.OldObject.Raw) > 0 {
+			if err := DecodeOldObject(h.decoder, req, obj); err != nil {
+				return ErrorResponse(http.StatusBadRequest, err)
+			}
+		}
+		err = obj.ValidateDelete()
+	default:
+		return ValidationResponse(true, "")
+	}
+
+	if err != nil {
+		return ValidationResponse(false, err.Error())
+	}
+	return ValidationResponse(true, "")
+}
@@ -31,6 +31,9 @@ import (
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
 	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 	atypes "sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
@@ -69,6 +72,9 @@ type Webhook struct {
 	// NamespaceSelector maps to the NamespaceSelector field in admissionregistrationv1beta1.Webhook
 	// This optional.
 	NamespaceSelector *metav1.LabelSelector
+	// SideEffects maps to the SideEffects field in admissionregistrationv1beta1.Webhook
+	// This optional. If set to None, none of the Handlers may have side effects.
+	SideEffects *admissionregistrationv1beta1.SideEffectClass
 	// Handlers contains a list of handlers. Each handler may only contains the business logic for its own feature.
 	// For example, feature foo and bar can be in the same webhook if all the other configurations are the same.
 	// The handler will be invoked sequentially as the order in the list.
@@ -77,6 +83,14 @@ type Webhook struct {
 	Handlers []Handler
 
 	once sync.Once
+
+	// cache, config, scheme and stop are injected to build the Reader returned by ReaderFromContext.
+	cache      cache.Cache
+	config     *rest.Config
+	scheme     *runtime.Scheme
+	stop       <-chan struct{}
+	reader     *syncedReader
+	readerOnce sync.Once
 }
 
 func (w *Webhook) setDefaults() {
@@ -114,6 +128,10 @@ func (w *Webhook) Handle(ctx context.Context, req atypes.Request) atypes.Respons
 	if req.AdmissionRequest == nil {
 		return ErrorResponse(http.StatusBadRequest, errors.New("got an empty AdmissionRequest"))
 	}
+	ctx = withDryRun(ctx, req)
+	if r := w.getReader(); r != nil {
+		ctx = withReader(ctx, r)
+	}
 	var resp atypes.Response
 	switch w.Type {
 	case types.WebhookTypeMutating:
@@ -177,6 +195,19 @@ func (w *Webhook) handleValidating(ctx context.Context, req atypes.Request) atyp
 	}
 }
 
+// getReader returns the Reader of the webhook, or nil if no cache was injected.  The Reader
+// starts waiting for the cache to sync when it is first used, i.e. once the webhook is served.
+func (w *Webhook) getReader() *syncedReader {
+	w.readerOnce.Do(func() {
+		if w.cache == nil {
+			return
+		}
+		w.reader = &syncedReader{cache: w.cache, config: w.config, scheme: w.scheme}
+		go w.reader.waitForCacheSync(w.stop)
+	})
+	return w.reader
+}
+
 func setStatusOKInAdmissionResponse(resp *admissionv1beta1.AdmissionResponse) {
 	if resp == nil {
 		return
@@ -231,6 +262,13 @@ func (w *Webhook) Validate() error {
 	if len(w.Handlers) == 0 {
 		return errors.New("field Handler should not be empty")
 	}
+	if w.SideEffects != nil && *w.SideEffects == admissionregistrationv1beta1.SideEffectClassNone {
+		for _, handler := range w.Handlers {
+			if hasSideEffects(handler) {
+				return fmt.Errorf("handler %T has side effects, but the webhook declares SideEffects None", handler)
+			}
+		}
+	}
 	return nil
 }
 
@@ -257,3 +295,53 @@ func (w *Webhook) InjectDecoder(d atypes.Decoder) error {
 	}
 	return nil
 }
+
+var _ inject.Cache = &Webhook{}
+
+// InjectCache injects the cache read by the Reader returned by ReaderFromContext, and
+// injects it into the handlers
+func (w *Webhook) InjectCache(c cache.Cache) error {
+	w.cache = c
+	for _, handler := range w.Handlers {
+		if _, err := inject.CacheInto(c, handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ inject.Config = &Webhook{}
+
+// InjectConfig injects the config used by the Reader returned by ReaderFromContext to
+// read from the API server until the cache has synced, and injects it into the handlers
+func (w *Webhook) InjectConfig(config *rest.Config) error {
+	w.config = config
+	for _, handler := range w.Handlers {
+		if _, err := inject.ConfigInto(config, handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ inject.Scheme = &Webhook{}
+
+// InjectScheme injects the scheme used by the Reader returned by ReaderFromContext, and
+// injects it into the handlers
+func (w *Webhook) InjectScheme(s *runtime.Scheme) error {
+	w.scheme = s
+	for _, handler := range w.Handlers {
+		if _, err := inject.SchemeInto(s, handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ inject.Stoppable = &Webhook{}
+
+// InjectStopChannel injects the stop channel closing which stops waiting for the cache to sync
+func (w *Webhook) InjectStopChannel(stop <-chan struct{}) error {
+	w.stop = stop
+	return nil
+}
@@ -313,6 +313,7 @@ func (s *Server) admissionWebhook(path string, wh *admission.Webhook) (*admissio
 		Rules:             wh.Rules,
 		FailurePolicy:     wh.FailurePolicy,
 		NamespaceSelector: wh.NamespaceSelector,
+		SideEffects:       wh.SideEffects,
 		ClientConfig: admissionregistration.WebhookClientConfig{
 			// The reason why we assign an empty byte array to CABundle is that
 			// CABundle field will be updated by the Provisioner.
@@ -0,0 +1,41 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package certrotation provides a Runnable that manages the CA and serving certificate of a
+webhook server in a Secret, without depending on an external certificate manager.
+
+The Rotator creates the certificates if they don't exist, rotates them before they
+expire, writes them to the CertDir of the webhook server and publishes the CA bundle to
+the webhook configurations and CRDs that call the webhook server.
+
+	err := mgr.Add(&certrotation.Rotator{
+		SecretKey: types.NamespacedName{Namespace: "system", Name: "webhook-server-cert"},
+		DNSName:   "webhook-service.system.svc",
+		CertDir:   "/tmp/cert",
+		Webhooks: []certrotation.WebhookInfo{
+			{Type: types.WebhookTypeValidating, Name: "validating-webhook-configuration"},
+			{Type: types.WebhookTypeConversion, Name: "cronjobs.batch.example.com"},
+		},
+	})
+*/
+package certrotation
+
+import (
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var log = logf.KBLog.WithName("certrotation")
@@ -0,0 +1,312 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certrotation
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/internal/cert/generator"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/internal/cert/writer"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/internal/cert/writer/atomic"
+	webhooktypes "sigs.k8s.io/controller-runtime/pkg/webhook/types"
+)
+
+const (
+	// DefaultRotateBefore is how long before the serving certificate expires it is rotated,
+	// if RotateBefore is not set.
+	DefaultRotateBefore = 90 * 24 * time.Hour
+	// DefaultCheckInterval is how often the certificates are checked, if CheckInterval is
+	// not set.
+	DefaultCheckInterval = 12 * time.Hour
+)
+
+// WebhookInfo identifies an object that the CA bundle is published to.
+type WebhookInfo struct {
+	// Type is the type of the webhook.  WebhookTypeMutating and WebhookTypeValidating
+	// refer to a MutatingWebhookConfiguration and a ValidatingWebhookConfiguration, and
+	// WebhookTypeConversion refers to a CustomResourceDefinition with a conversion webhook.
+	Type webhooktypes.WebhookType
+	// Name is the name of the object.
+	Name string
+}
+
+// Rotator manages the CA and serving certificate of a webhook server in a Secret.
+type Rotator struct {
+	// Client is used to read and write the Secret and the webhook objects.
+	// It is injected by the Manager if not set.
+	Client client.Client
+
+	// SecretKey is the Secret the certificates are stored in.
+	SecretKey types.NamespacedName
+
+	// DNSName is the DNS name the serving certificate is for, e.g. the name of the
+	// Service of the webhook server.
+	DNSName string
+
+	// CertDir is the directory the serving certificate and key are written to.  It should
+	// be the CertDir of the webhook server.  If empty, the certificates are only stored
+	// in the Secret.
+	CertDir string
+
+	// Webhooks are the objects the CA bundle is published to.
+	Webhooks []WebhookInfo
+
+	// RotateBefore is how long before the serving certificate expires it is rotated.
+	// Defaults to DefaultRotateBefore.
+	RotateBefore time.Duration
+
+	// CheckInterval is how often the certificates are checked.
+	// Defaults to DefaultCheckInterval.
+	CheckInterval time.Duration
+
+	// certGenerator generates the certificates.  It is a self-signed CA generator.
+	certGenerator generator.CertGenerator
+}
+
+var _ manager.Runnable = &Rotator{}
+var _ inject.Client = &Rotator{}
+
+// InjectClient injects the client into the Rotator, unless one was already set.
+func (r *Rotator) InjectClient(c client.Client) error {
+	if r.Client == nil {
+		r.Client = c
+	}
+	return nil
+}
+
+func (r *Rotator) setDefaults() {
+	if r.RotateBefore == 0 {
+		r.RotateBefore = DefaultRotateBefore
+	}
+	if r.CheckInterval == 0 {
+		r.CheckInterval = DefaultCheckInterval
+	}
+	if r.certGenerator == nil {
+		r.certGenerator = &generator.SelfSignedCertGenerator{}
+	}
+}
+
+func (r *Rotator) validate() error {
+	if r.Client == nil {
+		return errors.New("client must be set in Rotator")
+	}
+	if len(r.SecretKey.Name) == 0 || len(r.SecretKey.Namespace) == 0 {
+		return errors.New("secretKey must be set in Rotator")
+	}
+	if len(r.DNSName) == 0 {
+		return errors.New("dnsName must be set in Rotator")
+	}
+	return nil
+}
+
+// Start ensures the certificates are valid and published, and then re-checks them every
+// CheckInterval until stop is closed.  It returns an error if the first check fails.
+func (r *Rotator) Start(stop <-chan struct{}) error {
+	r.setDefaults()
+	if err := r.validate(); err != nil {
+		return err
+	}
+	if err := r.Ensure(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(r.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.Ensure(); err != nil {
+				log.Error(err, "unable to rotate certificates", "secret", r.SecretKey)
+			}
+		case <-stop:
+			return nil
+		}
+	}
+}
+
+// Ensure creates the certificates if they don't exist and rotates them if they are invalid
+// or expire within RotateBefore.  It then writes them to CertDir and publishes the CA
+// bundle to the Webhooks.
+func (r *Rotator) Ensure() error {
+	r.setDefaults()
+	certs, err := r.ensureSecret()
+	if err != nil {
+		return err
+	}
+	if len(r.CertDir) != 0 {
+		if err := r.writeCertDir(certs); err != nil {
+			return err
+		}
+	}
+	for _, wh := range r.Webhooks {
+		if err := r.publishCABundle(wh, certs.CACert); err != nil {
+			return fmt.Errorf("unable to publish the CA bundle to %s: %v", wh.Name, err)
+		}
+	}
+	return nil
+}
+
+func (r *Rotator) ensureSecret() (*generator.Artifacts, error) {
+	secret := &corev1.Secret{}
+	err := r.Client.Get(context.TODO(), r.SecretKey, secret)
+	if apierrors.IsNotFound(err) {
+		certs, err := r.certGenerator.Generate(r.DNSName)
+		if err != nil {
+			return nil, err
+		}
+		log.Info("creating certificates", "secret", r.SecretKey)
+		err = r.Client.Create(context.TODO(), certsToSecret(certs, r.SecretKey))
+		if !apierrors.IsAlreadyExists(err) {
+			return certs, err
+		}
+		// Another replica created the Secret first, so use its certificates.
+		err = r.Client.Get(context.TODO(), r.SecretKey, secret)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	certs := secretToCerts(secret)
+	if generator.ValidCACert(certs.Key, certs.Cert, certs.CACert, r.DNSName, time.Now().Add(r.RotateBefore)) {
+		return certs, nil
+	}
+
+	// Reuse the CA if it is still valid, so that the published CA bundle keeps working.
+	r.certGenerator.SetCA(certs.CAKey, certs.CACert)
+	certs, err = r.certGenerator.Generate(r.DNSName)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("rotating certificates", "secret", r.SecretKey)
+	secret.Data = certsToSecret(certs, r.SecretKey).Data
+	if err := r.Client.Update(context.TODO(), secret); err != nil {
+		return nil, err
+	}
+	return certs, nil
+}
+
+func (r *Rotator) writeCertDir(certs *generator.Artifacts) error {
+	if err := os.MkdirAll(r.CertDir, 0755); err != nil {
+		return fmt.Errorf("can't create dir: %v", r.CertDir)
+	}
+	aw, err := atomic.NewAtomicWriter(r.CertDir, log.WithName("atomic-writer").
+		WithValues("task", "rotating webhook certificates"))
+	if err != nil {
+		return err
+	}
+	// The CA key is only kept in the Secret.
+	return aw.Write(map[string]atomic.FileProjection{
+		writer.CACertName:     {Data: certs.CACert, Mode: 0644},
+		writer.ServerCertName: {Data: certs.Cert, Mode: 0644},
+		writer.ServerKeyName:  {Data: certs.Key, Mode: 0600},
+	})
+}
+
+func (r *Rotator) publishCABundle(wh WebhookInfo, caCert []byte) error {
+	key := types.NamespacedName{Name: wh.Name}
+	switch wh.Type {
+	case webhooktypes.WebhookTypeMutating:
+		cfg := &admissionregistrationv1beta1.MutatingWebhookConfiguration{}
+		if err := r.Client.Get(context.TODO(), key, cfg); err != nil {
+			return err
+		}
+		if !injectCABundle(cfg.Webhooks, caCert) {
+			return nil
+		}
+		return r.Client.Update(context.TODO(), cfg)
+	case webhooktypes.WebhookTypeValidating:
+		cfg := &admissionregistrationv1beta1.ValidatingWebhookConfiguration{}
+		if err := r.Client.Get(context.TODO(), key, cfg); err != nil {
+			return err
+		}
+		if !injectCABundle(cfg.Webhooks, caCert) {
+			return nil
+		}
+		return r.Client.Update(context.TODO(), cfg)
+	case webhooktypes.WebhookTypeConversion:
+		// The vendored apiextensions API predates conversion webhooks, so the CRD is
+		// updated as unstructured.
+		crd := &unstructured.Unstructured{}
+		crd.SetAPIVersion("apiextensions.k8s.io/v1beta1")
+		crd.SetKind("CustomResourceDefinition")
+		if err := r.Client.Get(context.TODO(), key, crd); err != nil {
+			return err
+		}
+		path := []string{"spec", "conversion", "webhookClientConfig", "caBundle"}
+		encoded := base64.StdEncoding.EncodeToString(caCert)
+		if current, _, _ := unstructured.NestedString(crd.Object, path...); current == encoded {
+			return nil
+		}
+		if err := unstructured.SetNestedField(crd.Object, encoded, path...); err != nil {
+			return err
+		}
+		return r.Client.Update(context.TODO(), crd)
+	default:
+		return fmt.Errorf("unsupported webhook type %v", wh.Type)
+	}
+}
+
+// injectCABundle sets the CA bundle of webhooks to caCert, and returns true if any of them changed.
+func injectCABundle(webhooks []admissionregistrationv1beta1.Webhook, caCert []byte) bool {
+	changed := false
+	for i := range webhooks {
+		if !bytes.Equal(webhooks[i].ClientConfig.CABundle, caCert) {
+			webhooks[i].ClientConfig.CABundle = caCert
+			changed = true
+		}
+	}
+	return changed
+}
+
+func secretToCerts(secret *corev1.Secret) *generator.Artifacts {
+	return &generator.Artifacts{
+		CAKey:  secret.Data[writer.CAKeyName],
+		CACert: secret.Data[writer.CACertName],
+		Cert:   secret.Data[writer.ServerCertName],
+		Key:    secret.Data[writer.ServerKeyName],
+	}
+}
+
+func certsToSecret(certs *generator.Artifacts, key types.NamespacedName) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+		},
+		Data: map[string][]byte{
+			writer.CAKeyName:      certs.CAKey,
+			writer.CACertName:     certs.CACert,
+			writer.ServerKeyName:  certs.Key,
+			writer.ServerCertName: certs.Cert,
+		},
+	}
+}
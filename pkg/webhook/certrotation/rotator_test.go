@@ -0,0 +1,173 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certrotation_test
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/certrotation"
+	webhooktypes "sigs.k8s.io/controller-runtime/pkg/webhook/types"
+)
+
+var _ = Describe("Rotator", func() {
+	var (
+		c         client.Client
+		r         *certrotation.Rotator
+		certDir   string
+		secretKey = types.NamespacedName{Namespace: "system", Name: "webhook-server-cert"}
+	)
+
+	getSecret := func() *corev1.Secret {
+		secret := &corev1.Secret{}
+		Expect(c.Get(context.TODO(), secretKey, secret)).To(Succeed())
+		return secret
+	}
+
+	BeforeEach(func() {
+		var err error
+		certDir, err = ioutil.TempDir("", "certrotation")
+		Expect(err).NotTo(HaveOccurred())
+
+		crd := &unstructured.Unstructured{}
+		crd.SetAPIVersion("apiextensions.k8s.io/v1beta1")
+		crd.SetKind("CustomResourceDefinition")
+		crd.SetName("cronjobs.batch.example.com")
+		Expect(unstructured.SetNestedField(crd.Object, "Webhook", "spec", "conversion", "strategy")).To(Succeed())
+
+		c = fake.NewFakeClient(
+			&admissionregistrationv1beta1.MutatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "mutating"},
+				Webhooks:   []admissionregistrationv1beta1.Webhook{{Name: "a.example.com"}, {Name: "b.example.com"}},
+			},
+			&admissionregistrationv1beta1.ValidatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "validating"},
+				Webhooks:   []admissionregistrationv1beta1.Webhook{{Name: "c.example.com"}},
+			},
+			crd,
+		)
+		r = &certrotation.Rotator{
+			SecretKey: secretKey,
+			DNSName:   "webhook-service.system.svc",
+			CertDir:   certDir,
+			Webhooks: []certrotation.WebhookInfo{
+				{Type: webhooktypes.WebhookTypeMutating, Name: "mutating"},
+				{Type: webhooktypes.WebhookTypeValidating, Name: "validating"},
+				{Type: webhooktypes.WebhookTypeConversion, Name: "cronjobs.batch.example.com"},
+			},
+		}
+		Expect(r.InjectClient(c)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(certDir)
+	})
+
+	It("should create the certificates in the Secret and the CertDir", func() {
+		Expect(r.Ensure()).To(Succeed())
+
+		secret := getSecret()
+		Expect(secret.Data).To(HaveKey("ca-key.pem"))
+		_, err := tls.X509KeyPair(secret.Data["cert.pem"], secret.Data["key.pem"])
+		Expect(err).NotTo(HaveOccurred())
+
+		certPEM, err := ioutil.ReadFile(path.Join(certDir, "cert.pem"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(certPEM).To(Equal(secret.Data["cert.pem"]))
+		_, err = os.Stat(path.Join(certDir, "ca-key.pem"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("should publish the CA bundle to the webhooks", func() {
+		Expect(r.Ensure()).To(Succeed())
+		caCert := getSecret().Data["ca-cert.pem"]
+
+		mutating := &admissionregistrationv1beta1.MutatingWebhookConfiguration{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: "mutating"}, mutating)).To(Succeed())
+		for _, wh := range mutating.Webhooks {
+			Expect(wh.ClientConfig.CABundle).To(Equal(caCert))
+		}
+
+		validating := &admissionregistrationv1beta1.ValidatingWebhookConfiguration{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: "validating"}, validating)).To(Succeed())
+		Expect(validating.Webhooks[0].ClientConfig.CABundle).To(Equal(caCert))
+
+		crd := &unstructured.Unstructured{}
+		crd.SetAPIVersion("apiextensions.k8s.io/v1beta1")
+		crd.SetKind("CustomResourceDefinition")
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: "cronjobs.batch.example.com"}, crd)).To(Succeed())
+		caBundle, _, err := unstructured.NestedString(crd.Object, "spec", "conversion", "webhookClientConfig", "caBundle")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(caBundle).To(Equal(base64.StdEncoding.EncodeToString(caCert)))
+	})
+
+	It("should keep valid certificates", func() {
+		Expect(r.Ensure()).To(Succeed())
+		before := getSecret()
+
+		Expect(r.Ensure()).To(Succeed())
+		Expect(getSecret().Data).To(Equal(before.Data))
+	})
+
+	It("should rotate certificates that expire within RotateBefore, keeping the CA", func() {
+		Expect(r.Ensure()).To(Succeed())
+		before := getSecret()
+
+		// The serving certificate is valid for a year, so it always expires within two.
+		r.RotateBefore = 2 * 365 * 24 * time.Hour
+		Expect(r.Ensure()).To(Succeed())
+		after := getSecret()
+		Expect(after.Data["cert.pem"]).NotTo(Equal(before.Data["cert.pem"]))
+		Expect(after.Data["ca-cert.pem"]).To(Equal(before.Data["ca-cert.pem"]))
+	})
+
+	It("should regenerate certificates for a different DNS name", func() {
+		Expect(r.Ensure()).To(Succeed())
+		before := getSecret()
+
+		r.DNSName = "other-service.system.svc"
+		Expect(r.Ensure()).To(Succeed())
+		Expect(getSecret().Data["cert.pem"]).NotTo(Equal(before.Data["cert.pem"]))
+	})
+
+	It("should stop when the stop channel is closed", func(done Done) {
+		stop := make(chan struct{})
+		close(stop)
+		Expect(r.Start(stop)).To(Succeed())
+		getSecret()
+		close(done)
+	})
+
+	It("should fail to start without a Secret", func() {
+		r.SecretKey = types.NamespacedName{}
+		Expect(r.Start(make(chan struct{}))).To(MatchError(ContainSubstring("secretKey must be set")))
+	})
+})
@@ -0,0 +1,38 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"net/http"
+)
+
+// ContextFunc returns the context to serve the request r with, derived from ctx, the context of r.
+// It may add values for the request, e.g. a request ID, authentication info or a tenant, which the
+// handlers of the webhooks get from the context passed to Handle.
+type ContextFunc func(ctx context.Context, r *http.Request) context.Context
+
+// WithContextFunc returns a http.Handler which serves the requests with h, with the context returned
+// by f.  If f is nil, h is returned.
+func WithContextFunc(h http.Handler, f ContextFunc) http.Handler {
+	if f == nil {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(f(r.Context(), r)))
+	})
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/internal/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/types"
+)
+
+const (
+	// DefaultPath is the path a conversion Webhook is served at if Path is not set.
+	DefaultPath = "/convert"
+	// DefaultName is the name of a conversion Webhook if Name is not set.
+	DefaultName = "conversion"
+)
+
+// Hub marks the version of a kind that every other version converts to and from.
+type Hub interface {
+	runtime.Object
+	Hub()
+}
+
+// Convertible is implemented by every version of a kind other than the Hub.
+type Convertible interface {
+	runtime.Object
+	ConvertTo(dst Hub) error
+	ConvertFrom(src Hub) error
+}
+
+// Webhook is a CRD conversion webhook that converts between the versions of the kinds
+// registered in its scheme, using their Hub and Convertible implementations.
+type Webhook struct {
+	// Name is the name of the webhook, used to label its metrics.
+	// Defaults to DefaultName.
+	Name string
+	// Path is the path this webhook will serve.
+	// Defaults to DefaultPath.
+	Path string
+	// Scheme is the scheme the converted kinds are registered in.
+	// If not set, it is injected by the webhook server.
+	Scheme *runtime.Scheme
+
+	once sync.Once
+}
+
+func (wh *Webhook) setDefaults() {
+	if len(wh.Name) == 0 {
+		wh.Name = DefaultName
+	}
+	if len(wh.Path) == 0 {
+		wh.Path = DefaultPath
+	}
+}
+
+// GetName returns the name of the webhook.
+func (wh *Webhook) GetName() string {
+	wh.once.Do(wh.setDefaults)
+	return wh.Name
+}
+
+// GetPath returns the path that the webhook registered.
+func (wh *Webhook) GetPath() string {
+	wh.once.Do(wh.setDefaults)
+	return wh.Path
+}
+
+// GetType returns the type of the webhook.
+func (wh *Webhook) GetType() types.WebhookType {
+	return types.WebhookTypeConversion
+}
+
+// Handler returns a http.Handler for the webhook.
+func (wh *Webhook) Handler() http.Handler {
+	wh.once.Do(wh.setDefaults)
+	return wh
+}
+
+// Validate validates if the webhook is valid.
+func (wh *Webhook) Validate() error {
+	wh.once.Do(wh.setDefaults)
+	return nil
+}
+
+var _ inject.Scheme = &Webhook{}
+
+// InjectScheme injects the scheme into the webhook, unless one was already set.
+func (wh *Webhook) InjectScheme(s *runtime.Scheme) error {
+	if wh.Scheme == nil {
+		wh.Scheme = s
+	}
+	return nil
+}
+
+var _ http.Handler = &Webhook{}
+
+func (wh *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	startTS := time.Now()
+	defer func() {
+		metrics.RequestLatency.WithLabelValues(wh.GetName()).Observe(time.Now().Sub(startTS).Seconds())
+	}()
+
+	review := &ConversionReview{}
+	if r.Body == nil {
+		err := errors.New("request body is empty")
+		log.Error(err, "bad request")
+		wh.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+		log.Error(err, "unable to decode the request")
+		wh.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if review.Request == nil {
+		err := errors.New("got an empty ConversionRequest")
+		log.Error(err, "bad request")
+		wh.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	resp, err := wh.handleConvertRequest(review.Request)
+	if err != nil {
+		log.Error(err, "failed to convert", "request", review.Request.UID)
+		resp = &ConversionResponse{
+			Result: metav1.Status{
+				Status:  metav1.StatusFailure,
+				Code:    http.StatusUnprocessableEntity,
+				Message: err.Error(),
+			},
+		}
+	}
+	resp.UID = review.Request.UID
+	wh.writeResponse(w, resp)
+}
+
+func (wh *Webhook) handleConvertRequest(req *ConversionRequest) (*ConversionResponse, error) {
+	if wh.Scheme == nil {
+		return nil, errors.New("conversion webhook has no scheme")
+	}
+	gv, err := schema.ParseGroupVersion(req.DesiredAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	converted := make([]runtime.RawExtension, 0, len(req.Objects))
+	for _, raw := range req.Objects {
+		src, err := wh.decode(raw.Raw)
+		if err != nil {
+			return nil, err
+		}
+		srcGVK := src.GetObjectKind().GroupVersionKind()
+		if srcGVK.GroupVersion() == gv {
+			return nil, fmt.Errorf("conversion is not allowed between the same version %s", srcGVK)
+		}
+		dstGVK := gv.WithKind(srcGVK.Kind)
+		dst, err := wh.Scheme.New(dstGVK)
+		if err != nil {
+			return nil, err
+		}
+		if err := wh.convert(src, dst); err != nil {
+			return nil, err
+		}
+		dst.GetObjectKind().SetGroupVersionKind(dstGVK)
+		out, err := json.Marshal(dst)
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, runtime.RawExtension{Raw: out})
+	}
+	return &ConversionResponse{
+		ConvertedObjects: converted,
+		Result: metav1.Status{
+			Status: metav1.StatusSuccess,
+			Code:   http.StatusOK,
+		},
+	}, nil
+}
+
+// decode decodes raw into a new object of the kind registered in the scheme for its
+// apiVersion and kind.
+func (wh *Webhook) decode(raw []byte) (runtime.Object, error) {
+	typeMeta := &metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, typeMeta); err != nil {
+		return nil, err
+	}
+	gvk := typeMeta.GroupVersionKind()
+	if gvk.Kind == "" {
+		return nil, errors.New("object to convert is missing a kind")
+	}
+	obj, err := wh.Scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, obj); err != nil {
+		return nil, err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	return obj, nil
+}
+
+// convert converts src into dst, directly if either of them is the hub or through
+// the hub of their kind otherwise.
+func (wh *Webhook) convert(src, dst runtime.Object) error {
+	srcGVK := src.GetObjectKind().GroupVersionKind()
+	srcHub, srcIsHub := src.(Hub)
+	dstHub, dstIsHub := dst.(Hub)
+	srcConvertible, srcIsConvertible := src.(Convertible)
+	dstConvertible, dstIsConvertible := dst.(Convertible)
+
+	switch {
+	case srcIsHub && dstIsConvertible:
+		return dstConvertible.ConvertFrom(srcHub)
+	case dstIsHub && srcIsConvertible:
+		return srcConvertible.ConvertTo(dstHub)
+	case srcIsConvertible && dstIsConvertible:
+		hub, err := wh.hubFor(srcGVK.GroupKind())
+		if err != nil {
+			return err
+		}
+		if err := srcConvertible.ConvertTo(hub); err != nil {
+			return fmt.Errorf("failed to convert %s to the hub version: %v", srcGVK, err)
+		}
+		return dstConvertible.ConvertFrom(hub)
+	default:
+		return fmt.Errorf("%T and %T are not convertible, they must implement Hub or Convertible", src, dst)
+	}
+}
+
+// hubFor returns a new object of the hub version of gk registered in the scheme.
+func (wh *Webhook) hubFor(gk schema.GroupKind) (Hub, error) {
+	var hub Hub
+	for gvk := range wh.Scheme.AllKnownTypes() {
+		if gvk.GroupKind() != gk {
+			continue
+		}
+		obj, err := wh.Scheme.New(gvk)
+		if err != nil {
+			return nil, err
+		}
+		h, ok := obj.(Hub)
+		if !ok {
+			continue
+		}
+		if hub != nil {
+			return nil, fmt.Errorf("multiple hub versions are registered for %s", gk)
+		}
+		hub = h
+	}
+	if hub == nil {
+		return nil, fmt.Errorf("no hub version is registered for %s", gk)
+	}
+	return hub, nil
+}
+
+func (wh *Webhook) writeError(w http.ResponseWriter, code int32, err error) {
+	wh.writeResponse(w, &ConversionResponse{
+		Result: metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    code,
+			Message: err.Error(),
+		},
+	})
+}
+
+func (wh *Webhook) writeResponse(w http.ResponseWriter, resp *ConversionResponse) {
+	if resp.Result.Status == metav1.StatusSuccess {
+		metrics.TotalRequests.WithLabelValues(wh.GetName(), "true").Inc()
+	} else {
+		metrics.TotalRequests.WithLabelValues(wh.GetName(), "false").Inc()
+	}
+
+	review := ConversionReview{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apiextensions.k8s.io/v1beta1",
+			Kind:       "ConversionReview",
+		},
+		Response: resp,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		log.Error(err, "unable to encode the response")
+	}
+}
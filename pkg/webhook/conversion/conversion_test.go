@@ -0,0 +1,233 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+)
+
+var (
+	v1GV = schema.GroupVersion{Group: "jobs.example.com", Version: "v1"}
+	v2GV = schema.GroupVersion{Group: "jobs.example.com", Version: "v2"}
+	v3GV = schema.GroupVersion{Group: "jobs.example.com", Version: "v3"}
+)
+
+// externalJobV1 stores the command as a single string.
+type externalJobV1 struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Command           string `json:"command"`
+}
+
+func (j *externalJobV1) DeepCopyObject() runtime.Object {
+	c := *j
+	j.ObjectMeta.DeepCopyInto(&c.ObjectMeta)
+	return &c
+}
+
+func (j *externalJobV1) ConvertTo(dst conversion.Hub) error {
+	hub := dst.(*externalJobV2)
+	hub.ObjectMeta = j.ObjectMeta
+	hub.Args = []string{j.Command}
+	return nil
+}
+
+func (j *externalJobV1) ConvertFrom(src conversion.Hub) error {
+	hub := src.(*externalJobV2)
+	j.ObjectMeta = hub.ObjectMeta
+	if len(hub.Args) > 0 {
+		j.Command = hub.Args[0]
+	}
+	return nil
+}
+
+// externalJobV2 is the hub version.
+type externalJobV2 struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Args              []string `json:"args"`
+}
+
+func (j *externalJobV2) DeepCopyObject() runtime.Object {
+	c := *j
+	j.ObjectMeta.DeepCopyInto(&c.ObjectMeta)
+	c.Args = append([]string(nil), j.Args...)
+	return &c
+}
+
+func (*externalJobV2) Hub() {}
+
+// externalJobV3 names the first argument the entrypoint.
+type externalJobV3 struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Entrypoint        string `json:"entrypoint"`
+}
+
+func (j *externalJobV3) DeepCopyObject() runtime.Object {
+	c := *j
+	j.ObjectMeta.DeepCopyInto(&c.ObjectMeta)
+	return &c
+}
+
+func (j *externalJobV3) ConvertTo(dst conversion.Hub) error {
+	hub := dst.(*externalJobV2)
+	hub.ObjectMeta = j.ObjectMeta
+	hub.Args = []string{j.Entrypoint}
+	return nil
+}
+
+func (j *externalJobV3) ConvertFrom(src conversion.Hub) error {
+	hub := src.(*externalJobV2)
+	j.ObjectMeta = hub.ObjectMeta
+	if len(hub.Args) > 0 {
+		j.Entrypoint = hub.Args[0]
+	}
+	return nil
+}
+
+var _ = Describe("Conversion Webhook", func() {
+	var wh *conversion.Webhook
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		scheme.AddKnownTypeWithName(v1GV.WithKind("Job"), &externalJobV1{})
+		scheme.AddKnownTypeWithName(v2GV.WithKind("Job"), &externalJobV2{})
+		scheme.AddKnownTypeWithName(v3GV.WithKind("Job"), &externalJobV3{})
+		wh = &conversion.Webhook{}
+		Expect(wh.InjectScheme(scheme)).To(Succeed())
+	})
+
+	convert := func(desired schema.GroupVersion, objs ...runtime.Object) *conversion.ConversionResponse {
+		req := &conversion.ConversionRequest{UID: "uid", DesiredAPIVersion: desired.String()}
+		for _, obj := range objs {
+			raw, err := json.Marshal(obj)
+			Expect(err).NotTo(HaveOccurred())
+			req.Objects = append(req.Objects, runtime.RawExtension{Raw: raw})
+		}
+		body, err := json.Marshal(conversion.ConversionReview{Request: req})
+		Expect(err).NotTo(HaveOccurred())
+
+		w := httptest.NewRecorder()
+		wh.ServeHTTP(w, httptest.NewRequest(http.MethodPost, conversion.DefaultPath, bytes.NewReader(body)))
+		review := &conversion.ConversionReview{}
+		Expect(json.NewDecoder(w.Body).Decode(review)).To(Succeed())
+		Expect(review.Kind).To(Equal("ConversionReview"))
+		Expect(review.Response).NotTo(BeNil())
+		Expect(review.Response.UID).To(BeEquivalentTo("uid"))
+		return review.Response
+	}
+
+	v1Job := func(name, command string) *externalJobV1 {
+		return &externalJobV1{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1GV.String(), Kind: "Job"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Command:    command,
+		}
+	}
+
+	It("should default the name and path", func() {
+		Expect(wh.GetName()).To(Equal(conversion.DefaultName))
+		Expect(wh.GetPath()).To(Equal(conversion.DefaultPath))
+		Expect(wh.Validate()).To(Succeed())
+	})
+
+	It("should convert a spoke version to the hub", func() {
+		resp := convert(v2GV, v1Job("foo", "sleep"))
+		Expect(resp.Result.Status).To(Equal(metav1.StatusSuccess))
+		Expect(resp.ConvertedObjects).To(HaveLen(1))
+
+		job := &externalJobV2{}
+		Expect(json.Unmarshal(resp.ConvertedObjects[0].Raw, job)).To(Succeed())
+		Expect(job.APIVersion).To(Equal(v2GV.String()))
+		Expect(job.Kind).To(Equal("Job"))
+		Expect(job.Name).To(Equal("foo"))
+		Expect(job.Args).To(Equal([]string{"sleep"}))
+	})
+
+	It("should convert the hub to a spoke version", func() {
+		resp := convert(v1GV, &externalJobV2{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v2GV.String(), Kind: "Job"},
+			ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+			Args:       []string{"sleep", "10"},
+		})
+		Expect(resp.Result.Status).To(Equal(metav1.StatusSuccess))
+
+		job := &externalJobV1{}
+		Expect(json.Unmarshal(resp.ConvertedObjects[0].Raw, job)).To(Succeed())
+		Expect(job.APIVersion).To(Equal(v1GV.String()))
+		Expect(job.Command).To(Equal("sleep"))
+	})
+
+	It("should convert between spoke versions through the hub", func() {
+		resp := convert(v3GV, v1Job("foo", "sleep"), v1Job("bar", "true"))
+		Expect(resp.Result.Status).To(Equal(metav1.StatusSuccess))
+		Expect(resp.ConvertedObjects).To(HaveLen(2))
+
+		for i, expected := range []string{"sleep", "true"} {
+			job := &externalJobV3{}
+			Expect(json.Unmarshal(resp.ConvertedObjects[i].Raw, job)).To(Succeed())
+			Expect(job.APIVersion).To(Equal(v3GV.String()))
+			Expect(job.Entrypoint).To(Equal(expected))
+		}
+	})
+
+	It("should fail to convert to the same version", func() {
+		resp := convert(v1GV, v1Job("foo", "sleep"))
+		Expect(resp.Result.Status).To(Equal(metav1.StatusFailure))
+		Expect(resp.Result.Message).To(ContainSubstring("same version"))
+	})
+
+	It("should fail to convert a kind that is not registered", func() {
+		resp := convert(v2GV, &externalJobV1{
+			TypeMeta: metav1.TypeMeta{APIVersion: v1GV.String(), Kind: "CronJob"},
+		})
+		Expect(resp.Result.Status).To(Equal(metav1.StatusFailure))
+		Expect(resp.Result.Message).To(ContainSubstring("CronJob"))
+	})
+
+	It("should fail a request without a ConversionRequest", func() {
+		w := httptest.NewRecorder()
+		wh.ServeHTTP(w, httptest.NewRequest(http.MethodPost, conversion.DefaultPath, bytes.NewBufferString("{}")))
+		review := &conversion.ConversionReview{}
+		Expect(json.NewDecoder(w.Body).Decode(review)).To(Succeed())
+		Expect(review.Response.Result.Status).To(Equal(metav1.StatusFailure))
+		Expect(review.Response.Result.Code).To(BeEquivalentTo(http.StatusBadRequest))
+	})
+
+	It("should fail if no hub version is registered", func() {
+		scheme := runtime.NewScheme()
+		scheme.AddKnownTypeWithName(v1GV.WithKind("Job"), &externalJobV1{})
+		scheme.AddKnownTypeWithName(v3GV.WithKind("Job"), &externalJobV3{})
+		wh = &conversion.Webhook{Scheme: scheme}
+
+		resp := convert(v3GV, v1Job("foo", "sleep"))
+		Expect(resp.Result.Status).To(Equal(metav1.StatusFailure))
+		Expect(resp.Result.Message).To(Equal(fmt.Sprintf("no hub version is registered for %s", v1GV.WithKind("Job").GroupKind())))
+	})
+})
@@ -0,0 +1,46 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package conversion provides a webhook that converts custom resources between the
+versions of a multi-version CRD.
+
+Conversion follows the hub and spoke model: one version of a kind is the hub and
+implements Hub, and every other version implements Convertible by converting to and
+from the hub.  All versions must be registered in the scheme.
+
+	func (*CronJobV2) Hub() {}
+
+	func (src *CronJobV1) ConvertTo(dst conversion.Hub) error {
+		// convert src to the hub version
+	}
+
+	func (dst *CronJobV1) ConvertFrom(src conversion.Hub) error {
+		// convert the hub version to dst
+	}
+
+The webhook is registered with the webhook server at DefaultPath, and the server
+provides it with the manager's scheme.
+
+	err := srv.Register(&conversion.Webhook{})
+*/
+package conversion
+
+import (
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var log = logf.KBLog.WithName("conversion")
@@ -0,0 +1,56 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// The vendored apiextensions API predates CRD conversion webhooks, so the wire types
+// of apiextensions.k8s.io/v1beta1 ConversionReview are declared here.
+
+// ConversionReview describes a conversion request/response.
+type ConversionReview struct {
+	metav1.TypeMeta `json:",inline"`
+	// Request describes the attributes for the conversion request.
+	Request *ConversionRequest `json:"request,omitempty"`
+	// Response describes the attributes for the conversion response.
+	Response *ConversionResponse `json:"response,omitempty"`
+}
+
+// ConversionRequest describes the conversion request parameters.
+type ConversionRequest struct {
+	// UID is an identifier for the individual request/response.
+	UID types.UID `json:"uid"`
+	// DesiredAPIVersion is the version to convert given objects to. e.g. "myapi.example.com/v1"
+	DesiredAPIVersion string `json:"desiredAPIVersion"`
+	// Objects is the list of CR objects to be converted.
+	Objects []runtime.RawExtension `json:"objects"`
+}
+
+// ConversionResponse describes a conversion response.
+type ConversionResponse struct {
+	// UID is an identifier for the individual request/response, copied from the request.
+	UID types.UID `json:"uid"`
+	// ConvertedObjects is the list of converted version of `request.objects`, in the
+	// same order.
+	ConvertedObjects []runtime.RawExtension `json:"convertedObjects"`
+	// Result contains the result of conversion with extra details if the conversion failed.
+	Result metav1.Status `json:"result"`
+}
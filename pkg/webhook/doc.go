@@ -17,7 +17,7 @@ limitations under the License.
 /*
 Package webhook provides methods to build and bootstrap a webhook server.
 
-Currently, it only supports admission webhooks. It will support CRD conversion webhooks in the near future.
+It supports admission webhooks, and CRD conversion webhooks via the conversion package.
 
 Build webhooks
 
@@ -84,6 +84,14 @@ Start the server by starting the manager
 	if err != nil {
 		// handle error
 	}
+
+Serve a webhook without a Manager, e.g. on an existing mux.
+
+	handler, err := NewStandaloneWebhook(webhook2, StandaloneOptions{})
+	if err != nil {
+		// handle error
+	}
+	mux.Handle("/validating-deployment", handler)
 */
 package webhook
 
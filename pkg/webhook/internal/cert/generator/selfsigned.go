@@ -75,7 +75,10 @@ func (cp *SelfSignedCertGenerator) Generate(commonName string) (*Artifacts, erro
 	signedCert, err := cert.NewSignedCert(
 		cert.Config{
 			CommonName: commonName,
-			Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+			// Clients verify the DNS name against the subject alternative names,
+			// the common name is ignored.
+			AltNames: cert.AltNames{DNSNames: []string{commonName}},
+			Usages:   []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
 		},
 		key, signingCert, signingKey,
 	)
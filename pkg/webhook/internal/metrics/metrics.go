@@ -42,10 +42,44 @@ var (
 		},
 		[]string{"webhook"},
 	)
+
+	// HandlerLatency is a prometheus metric which is a histogram of the latency
+	// of each handler in an admission handler chain.
+	HandlerLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "controller_runtime_webhook_handler_latency_seconds",
+			Help: "Histogram of the latency of each handler in an admission handler chain",
+		},
+		[]string{"webhook", "handler"},
+	)
+
+	// RequestIDs is a prometheus metric which counts the admission requests by the
+	// source of their correlation ID, i.e. whether it was propagated from the
+	// request or generated.
+	RequestIDs = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "controller_runtime_webhook_request_ids_total",
+			Help: "Total number of admission requests by the source of their correlation ID",
+		},
+		[]string{"webhook", "source"},
+	)
+
+	// QuotaRequests is a prometheus metric which counts the creations checked against
+	// each quota of a quota handler, and whether they were allowed.
+	QuotaRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "controller_runtime_webhook_quota_requests_total",
+			Help: "Total number of creations checked against each quota",
+		},
+		[]string{"quota", "allowed"},
+	)
 )
 
 func init() {
-	metrics.Registry.MustRegister(
-		TotalRequests,
-		RequestLatency)
+	metrics.AddDefaultViews(
+		metrics.View{Collector: TotalRequests},
+		metrics.View{Collector: RequestLatency},
+		metrics.View{Collector: HandlerLatency},
+		metrics.View{Collector: RequestIDs},
+		metrics.View{Collector: QuotaRequests})
 }
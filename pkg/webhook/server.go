@@ -18,6 +18,7 @@ package webhook
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -27,6 +28,9 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
@@ -59,6 +63,10 @@ type ServerOptions struct {
 	// If false, the server will install the webhook config objects. It is defaulted to false.
 	DisableWebhookConfigInstaller *bool
 
+	// ContextFunc, if set, returns the context of each request served by the server, so that
+	// per-request values can be injected into the contexts passed to the handlers of the webhooks.
+	ContextFunc ContextFunc
+
 	// BootstrapOptions contains the options for bootstrapping the admission server.
 	*BootstrapOptions
 }
@@ -187,6 +195,13 @@ func (s *Server) Handle(pattern string, handler http.Handler) {
 }
 
 var _ manager.Runnable = &Server{}
+var _ manager.PhasedRunnable = &Server{}
+
+// StartPhase implements manager.PhasedRunnable.  The server is started before the Controllers, and whether
+// the Manager is leader or not, since the API server calls the webhooks of every replica.
+func (s *Server) StartPhase() manager.StartPhase {
+	return manager.WebhooksPhase
+}
 
 // Start runs the server.
 // It will install the webhook related resources depend on the server configuration.
@@ -210,22 +225,45 @@ func (s *Server) Start(stop <-chan struct{}) error {
 }
 
 func (s *Server) run(stop <-chan struct{}) error {
+	// Watch the certificates, so that they are reloaded when they are rotated, e.g.
+	// by updating a mounted secret.
+	watcher, err := certwatcher.New(
+		path.Join(s.CertDir, writer.ServerCertName), path.Join(s.CertDir, writer.ServerKeyName))
+	if err != nil {
+		return err
+	}
+
+	watcherStop := make(chan struct{})
+	defer close(watcherStop)
+	// errCh is buffered so that neither the watcher nor the server block once run has returned.
+	errCh := make(chan error, 2)
+	go func() {
+		if err := watcher.Start(watcherStop); err != nil {
+			errCh <- err
+		}
+	}()
+
 	srv := &http.Server{
 		Addr:    fmt.Sprintf(":%v", s.Port),
-		Handler: s.sMux,
-	}
-	errCh := make(chan error)
-	serveFn := func() {
-		errCh <- srv.ListenAndServeTLS(path.Join(s.CertDir, writer.ServerCertName), path.Join(s.CertDir, writer.ServerKeyName))
+		Handler: WithContextFunc(s.sMux, s.ContextFunc),
+		TLSConfig: &tls.Config{
+			GetCertificate: watcher.GetCertificate,
+		},
 	}
+	go func() {
+		// The certificate is served from TLSConfig, so that it can be reloaded when rotated.
+		if err := srv.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+		}
+	}()
 
-	go serveFn()
+	// TODO(mengqiy): add jitter to the timer
+	// Could use https://godoc.org/k8s.io/apimachinery/pkg/util/wait#Jitter
+	ticker := time.NewTicker(6 * 30 * 24 * time.Hour)
+	defer ticker.Stop()
 	for {
-		// TODO(mengqiy): add jitter to the timer
-		// Could use https://godoc.org/k8s.io/apimachinery/pkg/util/wait#Jitter
-		timer := time.Tick(6 * 30 * 24 * time.Hour)
 		select {
-		case <-timer:
+		case <-ticker.C:
 			changed, err := s.RefreshCert()
 			if err != nil {
 				log.Error(err, "encountering error when refreshing the certificate")
@@ -234,15 +272,13 @@ func (s *Server) run(stop <-chan struct{}) error {
 			if !changed {
 				continue
 			}
-			log.Info("server is shutting down to reload the certificates.")
-			err = srv.Shutdown(context.Background())
-			if err != nil {
-				log.Error(err, "encountering error when shutting down")
+			log.Info("reloading the rotated certificates")
+			if err := watcher.ReadCertificate(); err != nil {
+				log.Error(err, "encountering error when reloading the certificates")
 				return err
 			}
-			go serveFn()
 		case <-stop:
-			return nil
+			return srv.Shutdown(context.Background())
 		case e := <-errCh:
 			return e
 		}
@@ -279,6 +315,54 @@ func (s *Server) InjectClient(c client.Client) error {
 	return nil
 }
 
+var _ inject.Cache = &Server{}
+
+// InjectCache injects the cache into the webhooks
+func (s *Server) InjectCache(c cache.Cache) error {
+	for _, wh := range s.registry {
+		if _, err := inject.CacheInto(c, wh.Handler()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ inject.Config = &Server{}
+
+// InjectConfig injects the config into the webhooks
+func (s *Server) InjectConfig(config *rest.Config) error {
+	for _, wh := range s.registry {
+		if _, err := inject.ConfigInto(config, wh.Handler()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ inject.Stoppable = &Server{}
+
+// InjectStopChannel injects the stop channel into the webhooks
+func (s *Server) InjectStopChannel(stop <-chan struct{}) error {
+	for _, wh := range s.registry {
+		if _, err := inject.StopChannelInto(stop, wh.Handler()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ inject.Scheme = &Server{}
+
+// InjectScheme injects the scheme into the server
+func (s *Server) InjectScheme(scheme *runtime.Scheme) error {
+	for _, wh := range s.registry {
+		if _, err := inject.SchemeInto(scheme, wh.Handler()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 var _ inject.Decoder = &Server{}
 
 // InjectDecoder injects the client into the server
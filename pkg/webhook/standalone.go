@@ -0,0 +1,77 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"errors"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/types"
+)
+
+// StandaloneOptions are options for a webhook served without a Manager.
+type StandaloneOptions struct {
+	// Scheme is the scheme used to decode the objects in admission requests.
+	// Defaults to the kubernetes client-go scheme.
+	Scheme *runtime.Scheme
+
+	// Client is injected into the handlers of the webhook.
+	// This is optional, since handlers may not need a client.
+	Client client.Client
+
+	// ContextFunc, if set, returns the context of each request served by the webhook, so that
+	// per-request values can be injected into the contexts passed to its handlers.
+	ContextFunc ContextFunc
+}
+
+// NewStandaloneWebhook returns a http.Handler for wh that can be mounted on a user
+// provided mux or server, e.g. behind an existing HTTPS terminator, instead of being
+// served by a Server run by a Manager.  The decoder built from options.Scheme and
+// options.Client are injected into the handlers of wh.
+//
+// Since no webhook configuration is installed for a standalone webhook, its Rules may
+// be empty.
+func NewStandaloneWebhook(wh *admission.Webhook, options StandaloneOptions) (http.Handler, error) {
+	if wh.Type != types.WebhookTypeMutating && wh.Type != types.WebhookTypeValidating {
+		return nil, errors.New("webhook Type must be WebhookTypeMutating or WebhookTypeValidating")
+	}
+	if len(wh.Handlers) == 0 {
+		return nil, errors.New("field Handlers should not be empty")
+	}
+
+	if options.Scheme == nil {
+		options.Scheme = scheme.Scheme
+	}
+	decoder, err := admission.NewDecoder(options.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := inject.DecoderInto(decoder, wh); err != nil {
+		return nil, err
+	}
+	if options.Client != nil {
+		if _, err := inject.ClientInto(options.Client, wh); err != nil {
+			return nil, err
+		}
+	}
+	return WithContextFunc(wh.Handler(), options.ContextFunc), nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	atypes "sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/types"
+)
+
+type labelValidator struct {
+	decoder atypes.Decoder
+}
+
+func (v *labelValidator) Handle(_ context.Context, req atypes.Request) atypes.Response {
+	pod := &corev1.Pod{}
+	if err := v.decoder.Decode(req, pod); err != nil {
+		return admission.ErrorResponse(http.StatusBadRequest, err)
+	}
+	if _, ok := pod.Labels["app"]; !ok {
+		return admission.ValidationResponse(false, "missing app label")
+	}
+	return admission.ValidationResponse(true, "")
+}
+
+func (v *labelValidator) InjectDecoder(d atypes.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+var _ = Describe("NewStandaloneWebhook", func() {
+	review := func(srv *httptest.Server, pod *corev1.Pod) *admissionv1beta1.AdmissionResponse {
+		raw, err := json.Marshal(pod)
+		Expect(err).NotTo(HaveOccurred())
+		body, err := json.Marshal(admissionv1beta1.AdmissionReview{
+			Request: &admissionv1beta1.AdmissionRequest{
+				UID:       "uid",
+				Operation: admissionv1beta1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		resp, err := http.Post(srv.URL+"/validate-pods", "application/json", bytes.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		ar := &admissionv1beta1.AdmissionReview{}
+		Expect(json.NewDecoder(resp.Body).Decode(ar)).To(Succeed())
+		return ar.Response
+	}
+
+	It("should serve the webhook on a user provided mux", func() {
+		handler, err := NewStandaloneWebhook(&admission.Webhook{
+			Name:     "validate-pods.example.com",
+			Type:     types.WebhookTypeValidating,
+			Handlers: []admission.Handler{&labelValidator{}},
+		}, StandaloneOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		mux := http.NewServeMux()
+		mux.Handle("/validate-pods", handler)
+		srv := httptest.NewServer(mux)
+		defer srv.Close()
+
+		resp := review(srv, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo", Labels: map[string]string{"app": "foo"}}})
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.UID).To(BeEquivalentTo("uid"))
+
+		resp = review(srv, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo"}})
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Reason).To(BeEquivalentTo("missing app label"))
+	})
+
+	It("should serve the requests with the context returned by the ContextFunc", func() {
+		type tenantKey struct{}
+		handler, err := NewStandaloneWebhook(&admission.Webhook{
+			Name: "validate-pods.example.com",
+			Type: types.WebhookTypeValidating,
+			Handlers: []admission.Handler{admission.HandlerFunc(func(ctx context.Context, _ atypes.Request) atypes.Response {
+				tenant, _ := ctx.Value(tenantKey{}).(string)
+				return admission.ValidationResponse(tenant == "foo", "unknown tenant")
+			})},
+		}, StandaloneOptions{
+			ContextFunc: func(ctx context.Context, r *http.Request) context.Context {
+				return context.WithValue(ctx, tenantKey{}, r.Header.Get("X-Tenant"))
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		mux := http.NewServeMux()
+		mux.Handle("/validate-pods", handler)
+		// Set the header the ContextFunc reads, as a proxy in front of the webhook could
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Header.Set("X-Tenant", "foo")
+			mux.ServeHTTP(w, r)
+		}))
+		defer srv.Close()
+
+		resp := review(srv, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo"}})
+		Expect(resp.Allowed).To(BeTrue())
+	})
+
+	It("should return an error if the webhook has no type", func() {
+		_, err := NewStandaloneWebhook(&admission.Webhook{
+			Handlers: []admission.Handler{&labelValidator{}},
+		}, StandaloneOptions{})
+		Expect(err).To(MatchError(ContainSubstring("Type")))
+	})
+
+	It("should return an error if the webhook has no handlers", func() {
+		_, err := NewStandaloneWebhook(&admission.Webhook{
+			Type: types.WebhookTypeMutating,
+		}, StandaloneOptions{})
+		Expect(err).To(MatchError(ContainSubstring("Handlers")))
+	})
+})
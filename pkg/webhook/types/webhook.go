@@ -25,4 +25,6 @@ const (
 	WebhookTypeMutating WebhookType = iota
 	// WebhookTypeValidating represents validating type webhook
 	WebhookTypeValidating
+	// WebhookTypeConversion represents CRD conversion type webhook
+	WebhookTypeConversion
 )